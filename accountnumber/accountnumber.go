@@ -0,0 +1,94 @@
+// Package accountnumber generates and checks the public numbers of accounts.
+// Numbers look like IBANs: the prefix SB, two check digits and twelve random
+// digits, such as SB06123456789012. The check digits follow ISO 7064
+// MOD 97-10, so a single mistyped digit or two swapped adjacent digits are
+// caught before any lookup.
+package accountnumber
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// Prefix starts every account number
+	Prefix = "SB"
+	// Length is the length of a normalized account number
+	Length = len(Prefix) + 2 + bbanLength
+
+	bbanLength = 12
+)
+
+// ErrInvalid is returned for strings that are not a valid account number
+var ErrInvalid = errors.New("invalid account number")
+
+var bbanLimit = big.NewInt(1_000_000_000_000)
+
+// Generate returns a new random account number
+func Generate() (string, error) {
+	n, err := rand.Int(rand.Reader, bbanLimit)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate account number: %w", err)
+	}
+
+	return fromBBAN(fmt.Sprintf("%0*d", bbanLength, n))
+}
+
+// fromBBAN returns the account number ending in the twelve digits of bban
+func fromBBAN(bban string) (string, error) {
+	if len(bban) != bbanLength || !isDigits(bban) {
+		return "", ErrInvalid
+	}
+
+	check := 98 - mod97(bban+Prefix+"00")
+	return fmt.Sprintf("%s%02d%s", Prefix, check, bban), nil
+}
+
+// Normalize removes spaces from value and upper-cases it, so that numbers
+// can be typed in groups like SB05 1234 5678 9012
+func Normalize(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// Validate checks the format and check digits of a normalized account number
+func Validate(number string) error {
+	if len(number) != Length || !strings.HasPrefix(number, Prefix) || !isDigits(number[len(Prefix):]) {
+		return ErrInvalid
+	}
+
+	// moving the prefix and check digits to the end leaves a remainder of 1
+	if mod97(number[4:]+number[:4]) != 1 {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+// mod97 returns the remainder of value divided by 97, with the letters of
+// value read as the numbers 10 to 35
+func mod97(value string) int {
+	remainder := 0
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		}
+	}
+
+	return remainder
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return value != ""
+}
@@ -0,0 +1,37 @@
+package accountnumber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		number, err := Generate()
+		require.NoError(t, err)
+		require.Len(t, number, Length)
+		require.NoError(t, Validate(number))
+		require.False(t, seen[number])
+		seen[number] = true
+	}
+}
+
+func TestValidate(t *testing.T) {
+	number, err := fromBBAN("123456789012")
+	require.NoError(t, err)
+	require.Equal(t, "SB06123456789012", number)
+	require.NoError(t, Validate(number))
+
+	require.NoError(t, Validate(Normalize(" sb06 1234 5678 9012")))
+
+	// a mistyped digit
+	require.ErrorIs(t, Validate("SB06123456789013"), ErrInvalid)
+	// two swapped digits
+	require.ErrorIs(t, Validate("SB06213456789012"), ErrInvalid)
+	require.ErrorIs(t, Validate("XX06123456789012"), ErrInvalid)
+	require.ErrorIs(t, Validate("SB0612345678901"), ErrInvalid)
+	require.ErrorIs(t, Validate("42"), ErrInvalid)
+	require.ErrorIs(t, Validate(""), ErrInvalid)
+}
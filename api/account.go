@@ -1,79 +1,86 @@
 package api
 
 import (
-	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/service"
 	"github.com/techschool/simplebank/token"
 )
 
+// accountResponse renders the balance of an account as money. Accounts are
+// known to clients by their public number, the internal id is not exposed.
+type accountResponse struct {
+	Number      string      `json:"number"`
+	Owner       string      `json:"owner"`
+	Balance     money.Money `json:"balance"`
+	Currency    string      `json:"currency"`
+	ProductCode string      `json:"product_code"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{
+		Number:      account.Number,
+		Owner:       account.Owner,
+		Balance:     account.BalanceMoney(),
+		Currency:    account.Currency,
+		ProductCode: account.ProductCode,
+		CreatedAt:   account.CreatedAt,
+	}
+}
+
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"currency"`
+	Product  string `json:"product" binding:"omitempty,alphanum"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
 	var request createAccountRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, bindingError(err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	args := db.CreateAccountParams{
+	account, err := server.service.CreateAccount(ctx, service.CreateAccountParams{
 		Owner:    authPayload.Username,
 		Currency: request.Currency,
-		Balance:  0,
-	}
-
-	account, err := server.store.CreateAccount(ctx, args)
-
+		Product:  request.Product,
+	})
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusBadRequest, errorResponse(err))
-				return
-			}
-		}
+		handleError(ctx, err)
+		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type getAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+	Number string `uri:"number" binding:"required,account_number"`
 }
 
 func (server *Server) getAccount(ctx *gin.Context) {
-	var accountID getAccountRequest
+	var uri getAccountRequest
 
-	if err := ctx.ShouldBindUri(&accountID); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, accountID.ID)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
+	account, err := server.service.GetAccount(ctx, authPayload.Username, uri.Number)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, err)
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		ctx.JSON(http.StatusBadRequest, gin.H{})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type listAccountsRequest struct {
@@ -85,28 +92,26 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 	var pageOptions listAccountsRequest
 
 	if err := ctx.ShouldBindQuery(&pageOptions); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, bindingError(err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	args := db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  pageOptions.PageSize,
-		Offset: (pageOptions.PageID - 1) * pageOptions.PageSize,
-	}
-
-	account, err := server.store.ListAccounts(ctx, args)
-
+	accounts, err := server.service.ListAccounts(ctx, service.ListAccountsParams{
+		Owner:    authPayload.Username,
+		PageID:   pageOptions.PageID,
+		PageSize: pageOptions.PageSize,
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	response := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		response[i] = newAccountResponse(account)
+	}
+
+	ctx.JSON(http.StatusOK, response)
 }
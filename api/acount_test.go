@@ -1,12 +1,15 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
 	mockdb "github.com/techschool/simplebank/db/mock"
 	db "github.com/techschool/simplebank/db/sqlc"
 	"github.com/techschool/simplebank/util"
@@ -22,7 +25,7 @@ func TestGetAccountAPI(t *testing.T) {
 
 	// build STUB
 	store.EXPECT().
-		GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+		GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).
 		Times(1).
 		Return(account, nil)
 
@@ -30,21 +33,47 @@ func TestGetAccountAPI(t *testing.T) {
 	server := newTestServer(t, store)
 	recorder := httptest.NewRecorder()
 
-	url := fmt.Sprintf("/accounts/%d", account.ID)
+	url := fmt.Sprintf("/accounts/%s", account.Number)
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	require.NoError(t, err)
 
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, account.Owner, time.Minute)
 	server.router.ServeHTTP(recorder, request)
 
 	// Check response
 	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, account.Number, body["number"])
+	require.NotContains(t, body, "id")
+}
+
+func TestGetAccountAPIInvalidNumber(t *testing.T) {
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	// the check digits of SB12000000000007 with two digits swapped
+	request, err := http.NewRequest(http.MethodGet, "/accounts/SB21000000000007", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
 }
 
 func randomAccount() db.Account {
 	return db.Account{
-		ID:       util.RandomInt(1, 1000),
-		Owner:    util.RandomOwner(),
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		ID:          util.RandomInt(1, 1000),
+		Owner:       util.RandomOwner(),
+		Balance:     util.RandomMoney(),
+		Currency:    util.RandomCurrency(),
+		ProductCode: "checking",
+		Number:      util.RandomAccountNumber(),
 	}
 }
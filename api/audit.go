@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/token"
+)
+
+type listAuditEventsRequest struct {
+	Actor        string    `form:"actor"`
+	Action       string    `form:"action"`
+	ResourceType string    `form:"resource_type"`
+	ResourceID   string    `form:"resource_id"`
+	From         time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To           time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	PageID       int32     `form:"page_id" binding:"required,min=1"`
+	PageSize     int32     `form:"page_size" binding:"required,min=1,max=100"`
+}
+
+func (server *Server) listAuditEvents(ctx *gin.Context) {
+	var request listAuditEventsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	events, err := server.service.ListAuditEvents(ctx, authPayload.Username, service.ListAuditEventsParams{
+		Actor:        request.Actor,
+		Action:       request.Action,
+		ResourceType: request.ResourceType,
+		ResourceID:   request.ResourceID,
+		From:         request.From,
+		To:           request.To,
+		PageID:       request.PageID,
+		PageSize:     request.PageSize,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+func (server *Server) verifyAuditChain(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.service.VerifyAuditChain(ctx, authPayload.Username)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/token"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListAuditEventsAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	depositor, _ := randomUser(t)
+
+	events := []db.AuditEvent{
+		{ID: 1, Actor: depositor.Username, Action: "user.created", Diff: json.RawMessage(`{"after":{}}`), Hash: util.RandomString(64)},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?page_id=1&page_size=10&actor=" + depositor.Username + "&from=2024-01-01T00:00:00Z",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, banker.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+
+				arg := db.ListAuditEventsParams{
+					Actor:    nullString(depositor.Username),
+					FromTime: nullTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+					Limit:    10,
+					Offset:   0,
+				}
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Eq(arg)).Times(1).Return(events, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []db.AuditEvent
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, events, got)
+			},
+		},
+		{
+			name:  "NotBanker",
+			query: "?page_id=1&page_size=10",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, depositor.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodePermissionDenied)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			query:     "?page_id=1&page_size=10",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidTime",
+			query: "?page_id=1&page_size=10&from=yesterday",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, banker.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			stubTx(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/audit/events"+tc.query, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: true}
+}
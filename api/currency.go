@@ -0,0 +1,11 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, server.service.ListCurrencies())
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListCurrenciesAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/currencies", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var currencies []util.Currency
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &currencies))
+	require.Equal(t, util.Currencies().Enabled(), currencies)
+}
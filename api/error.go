@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/techschool/simplebank/apperr"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemDetails is the RFC 9457 body used for every error response
+type problemDetails struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail"`
+	Instance string         `json:"instance,omitempty"`
+	Code     apperr.Code    `json:"code"`
+	Details  map[string]any `json:"details,omitempty"`
+}
+
+// handleError renders err as problem details and aborts the request.
+// Errors outside the catalogue are hidden behind INTERNAL and attached to
+// the context for the logger middleware.
+func handleError(ctx *gin.Context, err error) {
+	appErr := apperr.From(err)
+	if appErr.Code == apperr.CodeInternal {
+		ctx.Error(err)
+	}
+
+	status := appErr.Code.HTTPStatus()
+	ctx.Header("Content-Type", problemContentType)
+	ctx.AbortWithStatusJSON(status, problemDetails{
+		Type:     fmt.Sprintf("urn:simplebank:error:%s", appErr.Code),
+		Title:    appErr.Code.Title(),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: ctx.Request.URL.Path,
+		Code:     appErr.Code,
+		Details:  appErr.Details,
+	})
+}
+
+// bindingError converts a request binding failure into INVALID_ARGUMENT,
+// listing the rule each field broke.
+func bindingError(err error) error {
+	appErr := apperr.New(apperr.CodeInvalidArgument, "invalid request parameters")
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return appErr.WithDetail("body", "request could not be parsed")
+	}
+
+	fields := make(map[string]string, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields[fieldErr.Field()] = fmt.Sprintf("failed on the '%s' rule", fieldErr.Tag())
+	}
+
+	return appErr.WithDetail("fields", fields)
+}
+
+// invalidAmountError reports an amount that cannot be read in its currency
+func invalidAmountError(err error) error {
+	return apperr.New(apperr.CodeInvalidAmount, "invalid amount").WithDetail("amount", err.Error())
+}
+
+func unauthenticatedError(message string) error {
+	return apperr.New(apperr.CodeUnauthenticated, message)
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/kyc"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/token"
+)
+
+// kycSubmissionResponse leaves out the encrypted identity details
+type kycSubmissionResponse struct {
+	ID           int64      `json:"id"`
+	Username     string     `json:"username"`
+	Tier         string     `json:"tier"`
+	Status       string     `json:"status"`
+	DocumentType string     `json:"document_type"`
+	Country      string     `json:"country"`
+	Reviewer     string     `json:"reviewer,omitempty"`
+	Reason       string     `json:"reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+}
+
+func newKYCSubmissionResponse(submission db.KycSubmission) kycSubmissionResponse {
+	response := kycSubmissionResponse{
+		ID:           submission.ID,
+		Username:     submission.Username,
+		Tier:         submission.Tier,
+		Status:       submission.Status,
+		DocumentType: submission.DocumentType,
+		Country:      submission.Country,
+		Reviewer:     submission.Reviewer.String,
+		Reason:       submission.Reason,
+		CreatedAt:    submission.CreatedAt,
+	}
+	if submission.DecidedAt.Valid {
+		response.DecidedAt = &submission.DecidedAt.Time
+	}
+
+	return response
+}
+
+type submitKYCRequest struct {
+	Tier           string `json:"tier" binding:"required,oneof=basic full"`
+	LegalName      string `json:"legal_name" binding:"required,max=200"`
+	DateOfBirth    string `json:"date_of_birth" binding:"required,datetime=2006-01-02"`
+	DocumentType   string `json:"document_type" binding:"required,oneof=passport national_id driver_license"`
+	DocumentNumber string `json:"document_number" binding:"required,max=50"`
+	Address        string `json:"address" binding:"required,max=500"`
+	Country        string `json:"country" binding:"required,iso3166_1_alpha2"`
+}
+
+func (server *Server) submitKYC(ctx *gin.Context) {
+	var request submitKYCRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	submission, err := server.service.SubmitKYC(ctx, service.SubmitKYCParams{
+		Username:     authPayload.Username,
+		Tier:         request.Tier,
+		DocumentType: request.DocumentType,
+		Country:      request.Country,
+		Identity: service.KYCIdentity{
+			LegalName:      request.LegalName,
+			DateOfBirth:    request.DateOfBirth,
+			DocumentNumber: request.DocumentNumber,
+			Address:        request.Address,
+		},
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newKYCSubmissionResponse(submission))
+}
+
+type kycStatusResponse struct {
+	KYCTier    string                 `json:"kyc_tier"`
+	Submission *kycSubmissionResponse `json:"submission,omitempty"`
+}
+
+func (server *Server) getKYCStatus(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	status, err := server.service.GetKYCStatus(ctx, authPayload.Username)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response := kycStatusResponse{KYCTier: status.Tier}
+	if status.Submission != nil {
+		submission := newKYCSubmissionResponse(*status.Submission)
+		response.Submission = &submission
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+type listKYCSubmissionsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+func (server *Server) listKYCSubmissions(ctx *gin.Context) {
+	var request listKYCSubmissionsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	if request.Status == "" {
+		request.Status = kyc.StatusPending
+	}
+
+	submissions, err := server.service.ListKYCSubmissions(ctx, service.ListKYCSubmissionsParams{
+		Status:   request.Status,
+		PageID:   request.PageID,
+		PageSize: request.PageSize,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response := make([]kycSubmissionResponse, len(submissions))
+	for i, submission := range submissions {
+		response[i] = newKYCSubmissionResponse(submission)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+type kycSubmissionURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// kycIdentityResponse is a submission with its identity details in clear
+type kycIdentityResponse struct {
+	kycSubmissionResponse
+	Identity service.KYCIdentity `json:"identity"`
+}
+
+func (server *Server) getKYCSubmission(ctx *gin.Context) {
+	var uri kycSubmissionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	submission, identity, err := server.service.GetKYCIdentity(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, kycIdentityResponse{
+		kycSubmissionResponse: newKYCSubmissionResponse(submission),
+		Identity:              identity,
+	})
+}
+
+func (server *Server) approveKYCSubmission(ctx *gin.Context) {
+	var uri kycSubmissionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	submission, err := server.service.ApproveKYCSubmission(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newKYCSubmissionResponse(submission))
+}
+
+type rejectKYCSubmissionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+func (server *Server) rejectKYCSubmission(ctx *gin.Context) {
+	var uri kycSubmissionURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	var request rejectKYCSubmissionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	submission, err := server.service.RejectKYCSubmission(ctx, authPayload.Username, uri.ID, request.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newKYCSubmissionResponse(submission))
+}
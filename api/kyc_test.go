@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/kyc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestKYCAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	depositor, _ := randomUser(t)
+	depositor.KycTier = kyc.TierUnverified
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	stubTx(store)
+	server := newTestServer(t, store)
+
+	serve := func(method string, path string, username string, body any) *httptest.ResponseRecorder {
+		var buffer bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buffer).Encode(body))
+		}
+
+		request, err := http.NewRequest(method, path, &buffer)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	details := map[string]any{
+		"tier":            kyc.TierBasic,
+		"legal_name":      "Jane Q. Public",
+		"date_of_birth":   "1988-02-29",
+		"document_type":   kyc.DocumentPassport,
+		"document_number": "X1234567",
+		"address":         "1 Main Street, Springfield",
+		"country":         "US",
+	}
+
+	// a body that does not validate never reaches the store
+	invalid := map[string]any{"tier": kyc.TierBasic, "legal_name": "Jane", "date_of_birth": "29/02/1988"}
+	recorder := serve(http.MethodPost, "/kyc/submissions", depositor.Username, invalid)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+
+	var submission db.KycSubmission
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+	store.EXPECT().
+		CreateKYCSubmission(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateKYCSubmissionParams) (db.KycSubmission, error) {
+			submission = db.KycSubmission{
+				ID:             7,
+				Username:       arg.Username,
+				Tier:           arg.Tier,
+				Status:         kyc.StatusPending,
+				DocumentType:   arg.DocumentType,
+				Country:        arg.Country,
+				LegalName:      arg.LegalName,
+				DateOfBirth:    arg.DateOfBirth,
+				DocumentNumber: arg.DocumentNumber,
+				Address:        arg.Address,
+			}
+			return submission, nil
+		})
+
+	recorder = serve(http.MethodPost, "/kyc/submissions", depositor.Username, details)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), "X1234567")
+
+	var submitted map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &submitted))
+	require.Equal(t, kyc.StatusPending, submitted["status"])
+	require.NotContains(t, submitted, "legal_name")
+
+	// depositors cannot reach the review queue
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+	recorder = serve(http.MethodGet, "/admin/kyc-submissions/7", depositor.Username, nil)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodePermissionDenied)
+
+	// bankers see the identity details in clear
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+	store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(submission, nil)
+	recorder = serve(http.MethodGet, "/admin/kyc-submissions/7", banker.Username, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var reviewed struct {
+		Status   string            `json:"status"`
+		Identity map[string]string `json:"identity"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &reviewed))
+	require.Equal(t, kyc.StatusPending, reviewed.Status)
+	require.Equal(t, map[string]string{
+		"legal_name":      "Jane Q. Public",
+		"date_of_birth":   "1988-02-29",
+		"document_number": "X1234567",
+		"address":         "1 Main Street, Springfield",
+	}, reviewed.Identity)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+	store.EXPECT().GetLatestKYCSubmission(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(submission, nil)
+	recorder = serve(http.MethodGet, "/kyc", depositor.Username, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{
+		"kyc_tier": "unverified",
+		"submission": {
+			"id": 7,
+			"username": "`+depositor.Username+`",
+			"tier": "basic",
+			"status": "pending",
+			"document_type": "passport",
+			"country": "US",
+			"created_at": "0001-01-01T00:00:00Z"
+		}
+	}`, recorder.Body.String())
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ledgerReportRequest struct {
+	AsOf time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// asOf returns the requested time, or now if none was given
+func (request ledgerReportRequest) asOf() time.Time {
+	if request.AsOf.IsZero() {
+		return time.Now()
+	}
+
+	return request.AsOf
+}
+
+func (server *Server) getTrialBalance(ctx *gin.Context) {
+	var request ledgerReportRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	trialBalance, err := server.service.GetTrialBalance(ctx, request.asOf())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trialBalance)
+}
+
+func (server *Server) getBalanceSheet(ctx *gin.Context) {
+	var request ledgerReportRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	balanceSheet, err := server.service.GetBalanceSheet(ctx, request.asOf())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, balanceSheet)
+}
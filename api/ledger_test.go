@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/ledger"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLedgerReportsAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	depositor, _ := randomUser(t)
+
+	asOf := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	rows := []db.GetTrialBalanceRow{
+		{Code: ledger.CodeCash, Type: ledger.TypeAsset, Currency: util.USD, Debits: 100},
+		{Code: ledger.CodeCustomerDeposits, Type: ledger.TypeLiability, Currency: util.USD, Credits: 100},
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "TrialBalance",
+			path:     "/admin/ledger/trial-balance?as_of=2024-04-01T00:00:00Z",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetTrialBalance(gomock.Any(), gomock.Eq(asOf)).Times(1).Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var trialBalance ledger.TrialBalance
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &trialBalance))
+				require.Len(t, trialBalance.Lines, 2)
+				require.Equal(t, []ledger.TrialBalanceTotal{
+					{Currency: util.USD, Debits: 100, Credits: 100, Balanced: true},
+				}, trialBalance.Totals)
+			},
+		},
+		{
+			name:     "BalanceSheet",
+			path:     "/admin/ledger/balance-sheet",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetTrialBalance(gomock.Any(), gomock.Any()).Times(1).Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var balanceSheet ledger.BalanceSheet
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &balanceSheet))
+				require.Equal(t, []ledger.BalanceSheetCurrency{
+					{Currency: util.USD, Assets: 100, Liabilities: 100, Balanced: true},
+				}, balanceSheet.Currencies)
+			},
+		},
+		{
+			name:     "NotBanker",
+			path:     "/admin/ledger/trial-balance",
+			username: depositor.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().GetTrialBalance(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodePermissionDenied)
+			},
+		},
+		{
+			name:     "InvalidAsOf",
+			path:     "/admin/ledger/trial-balance?as_of=today",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetTrialBalance(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/token"
+)
+
+// limitUsageResponse renders the limit, usage and remaining allowance of
+// amount limits as money and of count limits as a number of transfers
+type limitUsageResponse struct {
+	Name      string `json:"name"`
+	Limit     any    `json:"limit"`
+	Used      any    `json:"used"`
+	Remaining any    `json:"remaining"`
+}
+
+func newLimitUsageResponse(usage db.LimitUsage) limitUsageResponse {
+	if usage.IsCount() {
+		return limitUsageResponse{
+			Name:      usage.Name,
+			Limit:     usage.Limit,
+			Used:      usage.Used,
+			Remaining: usage.Remaining,
+		}
+	}
+
+	return limitUsageResponse{
+		Name:      usage.Name,
+		Limit:     money.New(usage.Limit, usage.Currency),
+		Used:      money.New(usage.Used, usage.Currency),
+		Remaining: money.New(usage.Remaining, usage.Currency),
+	}
+}
+
+func (server *Server) getTransferLimits(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	usages, err := server.service.GetTransferLimits(ctx, authPayload.Username, uri.Number)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response := make([]limitUsageResponse, len(usages))
+	for i, usage := range usages {
+		response[i] = newLimitUsageResponse(usage)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// setTransferLimitRequest sets the limits of one scope. Amounts are decimal
+// strings in Currency, limits left out do not apply at this scope.
+type setTransferLimitRequest struct {
+	Scope         string `json:"scope" binding:"required,oneof=global product tier user"`
+	Subject       string `json:"subject" binding:"required_unless=Scope global,excluded_if=Scope global"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	PerTransfer   string `json:"per_transfer"`
+	DailyAmount   string `json:"daily_amount"`
+	MonthlyAmount string `json:"monthly_amount"`
+	DailyCount    *int32 `json:"daily_count" binding:"omitempty,min=0"`
+	MonthlyCount  *int32 `json:"monthly_count" binding:"omitempty,min=0"`
+}
+
+func (server *Server) setTransferLimit(ctx *gin.Context) {
+	var request setTransferLimitRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	arg := db.UpsertTransferLimitParams{
+		Scope:        request.Scope,
+		Subject:      request.Subject,
+		Currency:     request.Currency,
+		DailyCount:   nullInt32(request.DailyCount),
+		MonthlyCount: nullInt32(request.MonthlyCount),
+	}
+
+	amounts := []struct {
+		value string
+		field *sql.NullInt64
+	}{
+		{request.PerTransfer, &arg.PerTransfer},
+		{request.DailyAmount, &arg.DailyAmount},
+		{request.MonthlyAmount, &arg.MonthlyAmount},
+	}
+	for _, amount := range amounts {
+		if amount.value == "" {
+			continue
+		}
+		m, err := money.Parse(amount.value, request.Currency)
+		if err != nil {
+			handleError(ctx, invalidAmountError(err))
+			return
+		}
+		*amount.field = sql.NullInt64{Int64: m.Amount, Valid: true}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	limit, err := server.service.SetTransferLimit(ctx, authPayload.Username, arg)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+func nullInt32(value *int32) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+
+	return sql.NullInt32{Int32: *value, Valid: true}
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetTransferLimitsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount()
+	account.Owner = user.Username
+	account.Currency = util.USD
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(1).Return(account, nil)
+	store.EXPECT().
+		ListApplicableTransferLimits(gomock.Any(), gomock.Eq(db.ListApplicableTransferLimitsParams{
+			Currency:    util.USD,
+			ProductCode: account.ProductCode,
+			Username:    user.Username,
+		})).
+		Times(1).
+		Return([]db.TransferLimit{
+			{Scope: db.LimitScopeGlobal, Currency: util.USD, DailyAmount: sql.NullInt64{Int64: 100_000, Valid: true}},
+			{Scope: db.LimitScopeUser, Subject: user.Username, Currency: util.USD, DailyCount: sql.NullInt32{Int32: 5, Valid: true}},
+		}, nil)
+	store.EXPECT().
+		GetTransferUsage(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.GetTransferUsageRow{DailyAmount: 25_050, DailyCount: 2}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%s/limits", account.Number), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var usages []map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &usages))
+	require.Len(t, usages, 2)
+	require.Equal(t, db.LimitDailyAmount, usages[0]["name"])
+	require.Equal(t, "749.50", usages[0]["remaining"].(map[string]any)["amount"])
+	require.Equal(t, db.LimitDailyCount, usages[1]["name"])
+	require.Equal(t, float64(3), usages[1]["remaining"])
+}
+
+func TestSetTransferLimitAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	depositor, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: banker.Username,
+			body: map[string]any{
+				"scope":        db.LimitScopeUser,
+				"subject":      depositor.Username,
+				"currency":     util.USD,
+				"per_transfer": "250.00",
+				"daily_count":  10,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+
+				arg := db.UpsertTransferLimitParams{
+					Scope:       db.LimitScopeUser,
+					Subject:     depositor.Username,
+					Currency:    util.USD,
+					PerTransfer: sql.NullInt64{Int64: 25_000, Valid: true},
+					DailyCount:  sql.NullInt32{Int32: 10, Valid: true},
+				}
+				store.EXPECT().
+					UpsertTransferLimit(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.TransferLimit{ID: 1, Scope: arg.Scope, Subject: arg.Subject, Currency: arg.Currency}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "TooManyDecimals",
+			username: banker.Username,
+			body: map[string]any{
+				"scope":        db.LimitScopeGlobal,
+				"currency":     util.USD,
+				"daily_amount": "1.001",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidAmount)
+			},
+		},
+		{
+			name:     "GlobalWithSubject",
+			username: banker.Username,
+			body: map[string]any{
+				"scope":    db.LimitScopeGlobal,
+				"subject":  depositor.Username,
+				"currency": util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnknownTier",
+			username: banker.Username,
+			body: map[string]any{
+				"scope":    db.LimitScopeTier,
+				"subject":  "gold",
+				"currency": util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+			},
+		},
+		{
+			name:     "NotBanker",
+			username: depositor.Username,
+			body: map[string]any{
+				"scope":    db.LimitScopeGlobal,
+				"currency": util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodePermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubTx(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/admin/transfer-limits", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/logging"
+	"github.com/techschool/simplebank/token"
+)
+
+// loggerMiddleware assigns a request id and writes one structured log line per request
+func loggerMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		startTime := time.Now()
+
+		requestID := ctx.GetHeader(logging.RequestIDHeader)
+		if requestID == "" {
+			requestID = logging.NewRequestID()
+		}
+		ctx.Header(logging.RequestIDHeader, requestID)
+		ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), requestID))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+
+		attrs := []slog.Attr{
+			slog.String("protocol", "http"),
+			slog.String("request_id", requestID),
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(startTime)),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			attrs = append(attrs, slog.String("username", payload.(*token.Payload).Username))
+		}
+
+		attrs = append(attrs, logging.TraceAttrs(ctx.Request.Context())...)
+
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", ctx.Errors.String()))
+		}
+
+		logger.LogAttrs(ctx.Request.Context(), level, "received an HTTP request", attrs...)
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/logging"
+)
+
+func TestLoggerMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", logging.FormatJSON)
+	require.NoError(t, err)
+
+	server := newTestServer(t, nil)
+	router := gin.New()
+	router.Use(loggerMiddleware(logger))
+	router.GET("/auth", authMiddleware(server.tokenMaker), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/auth", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", time.Minute)
+	request.Header.Set(logging.RequestIDHeader, "request-1")
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "request-1", recorder.Header().Get(logging.RequestIDHeader))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "request-1", entry["request_id"])
+	require.Equal(t, "user", entry["username"])
+	require.Equal(t, float64(http.StatusOK), entry["status"])
+	require.Equal(t, "/auth", entry["path"])
+	require.NotContains(t, buf.String(), request.Header.Get(authorizationHeaderKey))
+}
@@ -1,14 +1,22 @@
 package api
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
 	db "github.com/techschool/simplebank/db/sqlc"
 	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
@@ -17,7 +25,7 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 		AccessTokenDuration: time.Minute,
 	}
 
-	server, err := NewServer(store, config)
+	server, err := NewServer(store, config, nil)
 	require.NoError(t, err)
 	return server
 }
@@ -27,3 +35,62 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func requireProblemCode(t *testing.T, recorder *httptest.ResponseRecorder, code apperr.Code) {
+	require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+	var problem problemDetails
+	err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+	require.NoError(t, err)
+	require.Equal(t, code, problem.Code)
+	require.Equal(t, recorder.Code, problem.Status)
+}
+
+// stubTx runs transactions directly against store and accepts any audit
+// event or outbox row written through them. Nobody subscribes to webhooks.
+func stubTx(store *mockdb.MockStore) {
+	store.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, _ db.TxOptions, fn func(db.Querier) error) error {
+			return fn(store)
+		})
+	store.EXPECT().
+		StatementTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, fn func(db.StatementQuerier) error) error {
+			return fn(store)
+		})
+	store.EXPECT().LockAuditChain(gomock.Any()).AnyTimes().Return(nil)
+	store.EXPECT().GetLastAuditEvent(gomock.Any()).AnyTimes().Return(db.AuditEvent{}, sql.ErrNoRows)
+	store.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).AnyTimes().Return(db.AuditEvent{}, nil)
+	store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).AnyTimes().Return(db.Outbox{}, nil)
+	store.EXPECT().
+		ListWebhookSubscriptionsForEvent(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return([]db.WebhookSubscription{}, nil)
+}
+
+type eqTransferTxParamsMatcher struct {
+	arg db.TransferTxParams
+}
+
+func (e eqTransferTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.TransferTxParams)
+	if !ok {
+		return false
+	}
+
+	return e.arg.FromAccountID == arg.FromAccountID &&
+		e.arg.ToAccountID == arg.ToAccountID &&
+		e.arg.Amount == arg.Amount
+}
+
+func (e eqTransferTxParamsMatcher) String() string {
+	return fmt.Sprintf("matches transfer of %d from %d to %d", e.arg.Amount, e.arg.FromAccountID, e.arg.ToAccountID)
+}
+
+// EqTransferTxParams matches the accounts and amount of a transfer, ignoring its hooks
+func EqTransferTxParams(arg db.TransferTxParams) gomock.Matcher {
+	return eqTransferTxParamsMatcher{arg}
+}
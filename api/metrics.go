@@ -0,0 +1,24 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/metrics"
+)
+
+// metricsMiddleware records the count and latency of every request by route
+func metricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		startTime := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		m.ObserveHTTP(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(startTime))
+	}
+}
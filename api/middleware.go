@@ -1,12 +1,12 @@
 package api
 
 import (
-	"errors"
 	"fmt"
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/audit"
+	"github.com/techschool/simplebank/service"
 	"github.com/techschool/simplebank/token"
 )
 
@@ -21,29 +21,26 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
 		if len(authorizationHeader) == 0 {
-			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			handleError(ctx, unauthenticatedError("authorization header is not provided"))
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
-			err := errors.New("invalid authorization header format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			handleError(ctx, unauthenticatedError("invalid authorization header format"))
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("invalid authorization header %s", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			handleError(ctx, unauthenticatedError(fmt.Sprintf("unsupported authorization type %s", authorizationType)))
 			return
 		}
 
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			handleError(ctx, unauthenticatedError(err.Error()))
 			return
 		}
 
@@ -51,3 +48,28 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// clientMiddleware stores the caller's IP and user agent for the audit log
+func clientMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(audit.WithClient(ctx.Request.Context(), audit.Client{
+			IP:        ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+		}))
+		ctx.Next()
+	}
+}
+
+// roleMiddleware only lets users with role through. It must run after authMiddleware.
+func roleMiddleware(service *service.Service, role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		if err := service.RequireRole(ctx, authPayload.Username, role); err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/token"
+)
+
+type payeeResponse struct {
+	ID            int64  `json:"id"`
+	Nickname      string `json:"nickname"`
+	AccountNumber string `json:"account_number"`
+	Currency      string `json:"currency"`
+	// Active is false until ActiveFrom, transfers to the payee fail before
+	Active     bool      `json:"active"`
+	ActiveFrom time.Time `json:"active_from"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newPayeeResponse(payee db.Payee) payeeResponse {
+	return payeeResponse{
+		ID:            payee.ID,
+		Nickname:      payee.Nickname,
+		AccountNumber: payee.AccountNumber,
+		Currency:      payee.Currency,
+		Active:        service.PayeeActive(payee, time.Now()),
+		ActiveFrom:    payee.ActiveFrom,
+		CreatedAt:     payee.CreatedAt,
+	}
+}
+
+// createPayeeRequest names the account either by its number or by the
+// username and currency of its owner
+type createPayeeRequest struct {
+	Nickname      string `json:"nickname" binding:"required,nickname"`
+	AccountNumber string `json:"account_number" binding:"required_without=Username,excluded_with=Username,omitempty,account_number"`
+	Username      string `json:"username" binding:"required_without=AccountNumber,omitempty,existing_username"`
+	Currency      string `json:"currency" binding:"required_with=Username,excluded_with=AccountNumber,omitempty,currency"`
+}
+
+func (server *Server) createPayee(ctx *gin.Context) {
+	var request createPayeeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.service.CreatePayee(ctx, service.CreatePayeeParams{
+		Owner:         authPayload.Username,
+		Nickname:      request.Nickname,
+		AccountNumber: request.AccountNumber,
+		Username:      request.Username,
+		Currency:      request.Currency,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPayeeResponse(payee))
+}
+
+type payeeIDRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getPayee(ctx *gin.Context) {
+	var request payeeIDRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.service.GetPayee(ctx, authPayload.Username, request.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPayeeResponse(payee))
+}
+
+type listPayeesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=10"`
+}
+
+func (server *Server) listPayees(ctx *gin.Context) {
+	var request listPayeesRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payees, err := server.service.ListPayees(ctx, service.ListPayeesParams{
+		Owner:    authPayload.Username,
+		PageID:   request.PageID,
+		PageSize: request.PageSize,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response := make([]payeeResponse, len(payees))
+	for i, payee := range payees {
+		response[i] = newPayeeResponse(payee)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+type updatePayeeRequest struct {
+	Nickname string `json:"nickname" binding:"required,nickname"`
+}
+
+func (server *Server) updatePayee(ctx *gin.Context) {
+	var uri payeeIDRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	var request updatePayeeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.service.RenamePayee(ctx, authPayload.Username, uri.ID, request.Nickname)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPayeeResponse(payee))
+}
+
+type confirmPayeeRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+func (server *Server) confirmPayee(ctx *gin.Context) {
+	var uri payeeIDRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	var request confirmPayeeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.service.ConfirmPayee(ctx, authPayload.Username, uri.ID, request.Password)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPayeeResponse(payee))
+}
+
+func (server *Server) deletePayee(ctx *gin.Context) {
+	var request payeeIDRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if err := server.service.DeletePayee(ctx, authPayload.Username, request.ID); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreatePayeeAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	recipient := randomAccount()
+	recipient.ProductCode = service.DefaultProduct
+
+	createPayee := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			CreatePayee(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CreatePayeeParams) (db.Payee, error) {
+				require.Equal(t, user.Username, arg.Owner)
+				require.Equal(t, recipient.ID, arg.AccountID)
+				require.Equal(t, recipient.Number, arg.AccountNumber)
+				require.Equal(t, recipient.Currency, arg.Currency)
+				require.True(t, arg.ActiveFrom.After(time.Now()))
+				return db.Payee{
+					ID:            1,
+					Owner:         arg.Owner,
+					Nickname:      arg.Nickname,
+					AccountID:     arg.AccountID,
+					AccountNumber: arg.AccountNumber,
+					Currency:      arg.Currency,
+					ActiveFrom:    arg.ActiveFrom,
+				}, nil
+			})
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "ByAccountNumber",
+			body: gin.H{"nickname": "Rent", "account_number": recipient.Number},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(recipient.Number)).Times(1).Return(recipient, nil)
+				createPayee(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response payeeResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "Rent", response.Nickname)
+				require.Equal(t, recipient.Number, response.AccountNumber)
+				require.False(t, response.Active)
+			},
+		},
+		{
+			name: "ByUsername",
+			body: gin.H{"nickname": "Rent", "username": recipient.Owner, "currency": recipient.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAliasLookup(gomock.Any(), gomock.Any()).Times(1).Return(db.AliasLookup{}, sql.ErrNoRows)
+				store.EXPECT().CountAliasLookup(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(recipient.Owner)).
+					Times(1).
+					Return(db.User{Username: recipient.Owner}, nil)
+				store.EXPECT().
+					GetAccountByOwner(gomock.Any(), gomock.Eq(db.GetAccountByOwnerParams{
+						Owner:       recipient.Owner,
+						Currency:    recipient.Currency,
+						ProductCode: service.DefaultProduct,
+					})).
+					Times(1).
+					Return(recipient, nil)
+				createPayee(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNumberAndUsername",
+			body: gin.H{"nickname": "Rent", "account_number": recipient.Number, "username": recipient.Owner, "currency": recipient.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+			},
+		},
+		{
+			name: "UsernameWithoutCurrency",
+			body: gin.H{"nickname": "Rent", "username": recipient.Owner},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAccount",
+			body: gin.H{"nickname": "Rent"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OwnAccount",
+			body: gin.H{"nickname": "Savings", "account_number": recipient.Number},
+			buildStubs: func(store *mockdb.MockStore) {
+				own := recipient
+				own.Owner = user.Username
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(recipient.Number)).Times(1).Return(own, nil)
+				store.EXPECT().CreatePayee(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			tc.buildStubs(store)
+			stubTx(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/payees", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPayeeAPI(t *testing.T) {
+	user, password := randomUser(t)
+	otherUser, _ := randomUser(t)
+
+	fromAccount := randomAccount()
+	fromAccount.Owner = user.Username
+	fromAccount.Currency = util.USD
+	fromAccount.Balance = 1000
+	toAccount := randomAccount()
+	toAccount.Currency = util.USD
+
+	payee := db.Payee{
+		ID:            5,
+		Owner:         user.Username,
+		Nickname:      "Landlord",
+		AccountID:     toAccount.ID,
+		AccountNumber: toAccount.Number,
+		Currency:      util.USD,
+		ActiveFrom:    time.Now().Add(time.Hour),
+	}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	stubTx(store)
+	server := newTestServer(t, store)
+
+	serve := func(method string, path string, username string, body any) *httptest.ResponseRecorder {
+		var buffer bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buffer).Encode(body))
+		}
+
+		request, err := http.NewRequest(method, path, &buffer)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	transfer := gin.H{
+		"from_account_number": fromAccount.Number,
+		"payee_id":            payee.ID,
+		"amount":              "1.00",
+		"currency":            util.USD,
+	}
+
+	// payees of other users do not exist for the caller
+	store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+	recorder := serve(http.MethodGet, "/payees/5", otherUser.Username, nil)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodePayeeNotFound)
+
+	// a new payee cannot receive money during its cooling-off period
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(fromAccount.Number)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(payee, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+	recorder = serve(http.MethodPost, "/transfer", user.Username, transfer)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodePayeeCoolingOff)
+
+	// confirming needs the password of the owner
+	store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(2).Return(payee, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(user, nil)
+	recorder = serve(http.MethodPost, "/payees/5/confirm", user.Username, gin.H{"password": "wrong"})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodeInvalidCredentials)
+
+	confirmed := payee
+	confirmed.ActiveFrom = time.Now().Add(-time.Second)
+	store.EXPECT().ConfirmPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(confirmed, nil)
+	recorder = serve(http.MethodPost, "/payees/5/confirm", user.Username, gin.H{"password": password})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response payeeResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.True(t, response.Active)
+
+	// once active, transfers by payee id go to its account
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(fromAccount.Number)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetPayee(gomock.Any(), gomock.Eq(payee.ID)).Times(1).Return(confirmed, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), EqTransferTxParams(db.TransferTxParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        100,
+		})).
+		Times(1).
+		Return(db.TransferTxResult{Amount: money.New(100, util.USD), FromAccount: fromAccount, ToAccount: toAccount}, nil)
+	recorder = serve(http.MethodPost, "/transfer", user.Username, transfer)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// an account number and a payee id at once are ambiguous
+	transfer["to_account_number"] = toAccount.Number
+	recorder = serve(http.MethodPost, "/transfer", user.Username, transfer)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+}
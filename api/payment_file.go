@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/apperr"
+	"github.com/techschool/simplebank/token"
+)
+
+// maxPaymentFileSize bounds the pain.001 documents accepted in one request
+const maxPaymentFileSize = 10 << 20
+
+// importPaymentFile executes the payments of a pain.001 XML request body
+func (server *Server) importPaymentFile(ctx *gin.Context) {
+	switch ctx.ContentType() {
+	case "application/xml", "text/xml":
+	default:
+		handleError(ctx, apperr.New(apperr.CodeInvalidArgument, "payment files must be sent as application/xml").
+			WithDetail("content_type", ctx.ContentType()))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxPaymentFileSize)
+	report, err := server.service.ImportPaymentFile(ctx, authPayload.Username, body)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+const testPain001 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>MSG-1</MsgId>
+      <CreDtTm>2024-03-15T09:30:00</CreDtTm>
+      <NbOfTxs>2</NbOfTxs>
+      <CtrlSum>%[3]d</CtrlSum>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>BATCH-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <DbtrAcct><Id><Othr><Id>%[1]s</Id></Othr></Id></DbtrAcct>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-1</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="USD">10</InstdAmt></Amt>
+        <CdtrAcct><Id><Othr><Id>%[2]s</Id></Othr></Id></CdtrAcct>
+      </CdtTrfTxInf>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-2</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="USD">%[4]d</InstdAmt></Amt>
+        <CdtrAcct><Id><Othr><Id>%[2]s</Id></Othr></Id></CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`
+
+func TestImportPaymentFileAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	account1 := randomAccount()
+	account1.Owner = user.Username
+	account1.Currency = util.USD
+	account1.Balance = 100_00
+
+	account2 := randomAccount()
+	account2.Currency = util.USD
+
+	// the second payment is more than the balance left after the first
+	document := fmt.Sprintf(testPain001, account1.Number, account2.Number, 10+95, 95)
+
+	testCases := []struct {
+		name          string
+		body          string
+		contentType   string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "PartiallyAccepted",
+			body:        document,
+			contentType: "application/xml",
+			username:    user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				debited := account1
+				debited.Balance -= 10_00
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account1.Number)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account2.Number)).Times(1).Return(account2, nil)
+				gomock.InOrder(
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil),
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(debited, nil),
+				)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().
+					CreatePaymentFile(gomock.Any(), gomock.Eq(db.CreatePaymentFileParams{
+						Owner:            user.Username,
+						MessageID:        "MSG-1",
+						NumberOfPayments: 2,
+						ControlSum:       sql.NullString{String: "105", Valid: true},
+					})).
+					Times(1).
+					Return(db.PaymentFile{ID: 3, Owner: user.Username, MessageID: "MSG-1"}, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), EqTransferTxParams(db.TransferTxParams{
+						FromAccountID: account1.ID,
+						ToAccountID:   account2.ID,
+						Amount:        10_00,
+					})).
+					Times(1).
+					Return(db.TransferTxResult{Transfer: db.Transfer{ID: 42}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var report service.PaymentFileReport
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+				require.Equal(t, int64(3), report.PaymentFileID)
+				require.Equal(t, 1, report.Accepted)
+				require.Equal(t, 1, report.Rejected)
+				require.Len(t, report.Payments, 2)
+
+				require.Equal(t, "E2E-1", report.Payments[0].EndToEndID)
+				require.Equal(t, service.PaymentAccepted, report.Payments[0].Status)
+				require.Equal(t, int64(42), report.Payments[0].TransferID)
+
+				require.Equal(t, "E2E-2", report.Payments[1].EndToEndID)
+				require.Equal(t, service.PaymentRejected, report.Payments[1].Status)
+				require.Equal(t, apperr.CodeInsufficientFunds, report.Payments[1].ReasonCode)
+			},
+		},
+		{
+			name:        "NotOwned",
+			body:        document,
+			contentType: "application/xml",
+			username:    "someone_else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account1.Number)).Times(1).Return(account1, nil)
+				store.EXPECT().CreatePaymentFile(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeAccountNotOwned)
+			},
+		},
+		{
+			name:        "Duplicate",
+			body:        document,
+			contentType: "application/xml",
+			username:    user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account1.Number)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account2.Number)).Times(1).Return(account2, nil)
+				store.EXPECT().
+					CreatePaymentFile(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PaymentFile{}, &pq.Error{Code: "23505"})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodePaymentFileDuplicate)
+			},
+		},
+		{
+			name:        "Malformed",
+			body:        strings.Replace(document, "<NbOfTxs>2</NbOfTxs>", "<NbOfTxs>3</NbOfTxs>", 1),
+			contentType: "application/xml",
+			username:    user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodePaymentFileInvalid)
+			},
+		},
+		{
+			name:        "ContentType",
+			body:        document,
+			contentType: "application/json",
+			username:    user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			stubTx(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/payment-files", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", tc.contentType)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/token"
+)
+
+// recipientResponse lets senders confirm who an alias belongs to without
+// learning their full name or account
+type recipientResponse struct {
+	Alias    string `json:"alias"`
+	Currency string `json:"currency"`
+	Name     string `json:"name"`
+}
+
+func newRecipientResponse(recipient service.Recipient) recipientResponse {
+	return recipientResponse{
+		Alias:    recipient.Alias,
+		Currency: recipient.Currency,
+		Name:     recipient.MaskedName,
+	}
+}
+
+type lookupRecipientRequest struct {
+	Alias    string `form:"alias" binding:"required,alias"`
+	Currency string `form:"currency" binding:"required,currency"`
+}
+
+func (server *Server) lookupRecipient(ctx *gin.Context) {
+	var request lookupRecipientRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	recipient, err := server.service.LookupRecipient(ctx, authPayload.Username, request.Alias, request.Currency)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newRecipientResponse(recipient))
+}
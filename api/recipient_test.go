@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecipientAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+	recipient.FullName = "Ada Lovelace"
+	recipient.Phone = util.RandomPhone()
+
+	fromAccount := randomAccount()
+	fromAccount.Owner = user.Username
+	fromAccount.Currency = util.EUR
+	fromAccount.Balance = 1000
+	toAccount := randomAccount()
+	toAccount.Owner = recipient.Username
+	toAccount.Currency = util.EUR
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	stubTx(store)
+	server := newTestServer(t, store)
+
+	serve := func(method string, path string, body any) *httptest.ResponseRecorder {
+		var buffer bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buffer).Encode(body))
+		}
+
+		request, err := http.NewRequest(method, path, &buffer)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	lookup := func(alias string, currency string) *httptest.ResponseRecorder {
+		query := url.Values{"alias": {alias}, "currency": {currency}}
+		return serve(http.MethodGet, "/recipients?"+query.Encode(), nil)
+	}
+	countLookup := func(lookups int32) {
+		store.EXPECT().
+			CountAliasLookup(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(db.AliasLookup{Username: user.Username, WindowStart: time.Now(), Lookups: lookups}, nil)
+	}
+	defaultAccount := gomock.Eq(db.GetAccountByOwnerParams{
+		Owner:       recipient.Username,
+		Currency:    util.EUR,
+		ProductCode: service.DefaultProduct,
+	})
+
+	// a phone alias resolves to the masked name of its owner
+	countLookup(1)
+	store.EXPECT().
+		GetUserByPhone(gomock.Any(), gomock.Eq(db.GetUserByPhoneParams{Phone: recipient.Phone})).
+		Times(1).
+		Return(recipient, nil)
+	store.EXPECT().GetAccountByOwner(gomock.Any(), defaultAccount).Times(1).Return(toAccount, nil)
+	recorder := lookup(recipient.Phone, util.EUR)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response recipientResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, recipientResponse{Alias: recipient.Phone, Currency: util.EUR, Name: "A** L*******"}, response)
+	require.NotContains(t, recorder.Body.String(), recipient.Username)
+
+	// users without an account in the currency look like unknown aliases
+	countLookup(2)
+	store.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Eq(db.GetUserByEmailParams{Email: recipient.Email})).
+		Times(1).
+		Return(recipient, nil)
+	store.EXPECT().GetAccountByOwner(gomock.Any(), defaultAccount).Times(1).Return(db.Account{}, sql.ErrNoRows)
+	recorder = lookup(recipient.Email, util.EUR)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodeRecipientNotFound)
+
+	// lookups stop once the window is used up
+	countLookup(service.DefaultAliasLookupLimit + 1)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+	recorder = lookup(recipient.Username, util.EUR)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodeRateLimited)
+
+	recorder = lookup("Ada Lovelace", util.EUR)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// transfers to an alias go to the default account in their currency,
+	// and only count against the lookup limit if the alias does not resolve
+	transfer := gin.H{
+		"from_account_number": fromAccount.Number,
+		"to_alias":            recipient.Username,
+		"amount":              "1.00",
+		"currency":            util.EUR,
+	}
+	store.EXPECT().
+		GetAliasLookup(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(db.AliasLookup{Username: user.Username, WindowStart: time.Now(), Lookups: 3}, nil)
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(fromAccount.Number)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+	store.EXPECT().GetAccountByOwner(gomock.Any(), defaultAccount).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), EqTransferTxParams(db.TransferTxParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        100,
+		})).
+		Times(1).
+		Return(db.TransferTxResult{Amount: money.New(100, util.EUR), FromAccount: fromAccount, ToAccount: toAccount}, nil)
+	recorder = serve(http.MethodPost, "/transfer", transfer)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// an alias together with an account number is ambiguous
+	transfer["to_account_number"] = toAccount.Number
+	recorder = serve(http.MethodPost, "/transfer", transfer)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/service"
+)
+
+type listUserScreeningsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+func (server *Server) listUserScreenings(ctx *gin.Context) {
+	var uri getUserRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	var request listUserScreeningsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	screenings, err := server.service.ListUserScreenings(ctx, service.ListUserScreeningsParams{
+		Username: uri.Username,
+		PageID:   request.PageID,
+		PageSize: request.PageSize,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, screenings)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/sanctions"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListUserScreeningsAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	depositor, _ := randomUser(t)
+
+	screening := db.UserScreening{
+		ID:          4,
+		Username:    depositor.Username,
+		ListVersion: "abc",
+		Status:      sanctions.StatusFlagged,
+		Score:       0.95,
+		MatchedUid:  "1001",
+		MatchedName: "PETROVSKY, Ivan Sergeyevich",
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			query:    "page_id=2&page_size=5",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().
+					ListUserScreenings(gomock.Any(), gomock.Eq(db.ListUserScreeningsParams{
+						Username: depositor.Username,
+						Limit:    5,
+						Offset:   5,
+					})).
+					Times(1).
+					Return([]db.UserScreening{screening}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var screenings []db.UserScreening
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &screenings))
+				require.Equal(t, []db.UserScreening{screening}, screenings)
+			},
+		},
+		{
+			name:     "UserNotFound",
+			query:    "page_id=1&page_size=5",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().ListUserScreenings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeUserNotFound)
+			},
+		},
+		{
+			name:     "NotBanker",
+			query:    "page_id=1&page_size=5",
+			username: depositor.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().ListUserScreenings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodePermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%s/screenings?%s", depositor.Username, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
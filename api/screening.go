@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/fraud"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/token"
+)
+
+// transferReviewResponse renders the amount of a held transfer as money
+type transferReviewResponse struct {
+	db.TransferReview
+	Amount money.Money `json:"amount"`
+}
+
+func newTransferReviewResponse(review db.TransferReview) transferReviewResponse {
+	return transferReviewResponse{
+		TransferReview: review,
+		Amount:         money.New(review.Amount, review.Currency),
+	}
+}
+
+type listTransferReviewsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=1,max=50"`
+}
+
+func (server *Server) listTransferReviews(ctx *gin.Context) {
+	var request listTransferReviewsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	if request.Status == "" {
+		request.Status = fraud.ReviewPending
+	}
+
+	reviews, err := server.service.ListTransferReviews(ctx, service.ListTransferReviewsParams{
+		Status:   request.Status,
+		PageID:   request.PageID,
+		PageSize: request.PageSize,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response := make([]transferReviewResponse, len(reviews))
+	for i, review := range reviews {
+		response[i] = newTransferReviewResponse(review)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+type transferReviewURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) approveTransferReview(ctx *gin.Context) {
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	review, err := server.service.ApproveTransferReview(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTransferReviewResponse(review))
+}
+
+type rejectTransferReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+func (server *Server) rejectTransferReview(ctx *gin.Context) {
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	var request rejectTransferReviewRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	review, err := server.service.RejectTransferReview(ctx, authPayload.Username, uri.ID, request.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTransferReviewResponse(review))
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/fraud"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransferReviewsAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+	depositor, _ := randomUser(t)
+
+	review := db.TransferReview{
+		ID:            9,
+		Owner:         depositor.Username,
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        1_000_000,
+		Currency:      util.USD,
+		Status:        fraud.ReviewPending,
+		Hits:          json.RawMessage(`[{"rule":"structuring","decision":"review","reason":"3 transfers"}]`),
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		path          string
+		body          map[string]any
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "List",
+			method:   http.MethodGet,
+			path:     "/admin/transfer-reviews?page_id=1&page_size=5",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().
+					ListTransferReviews(gomock.Any(), gomock.Eq(db.ListTransferReviewsParams{
+						Status: fraud.ReviewPending,
+						Limit:  5,
+						Offset: 0,
+					})).
+					Times(1).
+					Return([]db.TransferReview{review}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var reviews []map[string]any
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &reviews))
+				require.Len(t, reviews, 1)
+				require.Equal(t, "10000.00", reviews[0]["amount"].(map[string]any)["amount"])
+				require.Equal(t, "structuring", reviews[0]["hits"].([]any)[0].(map[string]any)["rule"])
+			},
+		},
+		{
+			name:     "ListInvalidStatus",
+			method:   http.MethodGet,
+			path:     "/admin/transfer-reviews?status=held&page_id=1&page_size=5",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().ListTransferReviews(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Reject",
+			method:   http.MethodPost,
+			path:     "/admin/transfer-reviews/9/reject",
+			body:     map[string]any{"reason": "mule account"},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+
+				rejected := review
+				rejected.Status = fraud.ReviewRejected
+				rejected.Reviewer = sql.NullString{String: banker.Username, Valid: true}
+				store.EXPECT().DecideTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(rejected, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rejected map[string]any
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rejected))
+				require.Equal(t, fraud.ReviewRejected, rejected["status"])
+			},
+		},
+		{
+			name:     "RejectWithoutReason",
+			method:   http.MethodPost,
+			path:     "/admin/transfer-reviews/9/reject",
+			body:     map[string]any{},
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ApproveDecided",
+			method:   http.MethodPost,
+			path:     "/admin/transfer-reviews/9/approve",
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker.Username)).Times(1).Return(banker, nil)
+
+				approved := review
+				approved.Status = fraud.ReviewApproved
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(approved, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeTransferReviewDecided)
+			},
+		},
+		{
+			name:     "NotBanker",
+			method:   http.MethodPost,
+			path:     "/admin/transfer-reviews/9/approve",
+			username: depositor.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodePermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubTx(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			request, err := http.NewRequest(tc.method, tc.path, &body)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
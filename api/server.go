@@ -2,40 +2,73 @@ package api
 
 import (
 	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/metrics"
+	"github.com/techschool/simplebank/service"
 	"github.com/techschool/simplebank/token"
+	"github.com/techschool/simplebank/tracing"
 	"github.com/techschool/simplebank/util"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type Server struct {
 	config     util.Config
-	store      db.Store
+	service    *service.Service
 	router     *gin.Engine
 	tokenMaker token.Maker
 }
 
-func NewServer(store db.Store, config util.Config) (*Server, error) {
+func NewServer(store db.Store, config util.Config, metrics *metrics.Metrics) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricalKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker %v", err)
 	}
 
+	service, err := service.New(store, tokenMaker, config, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create service: %w", err)
+	}
+
 	server := &Server{
 		config:     config,
-		store:      store,
-		router:     gin.Default(),
+		service:    service,
+		router:     gin.New(),
 		tokenMaker: tokenMaker,
 	}
+	// let handlers pass the gin context on and keep request scoped values
+	server.router.ContextWithFallback = true
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("username", validUsername)
+		v.RegisterValidation("existing_username", validExistingUsername)
+		v.RegisterValidation("full_name", validFullName)
+		v.RegisterValidation("password", validPassword)
+		v.RegisterValidation("email_address", validEmail)
+		v.RegisterValidation("phone", validPhone)
+		v.RegisterValidation("alias", validAlias)
+		v.RegisterValidation("account_number", validAccountNumber)
+		v.RegisterValidation("nickname", validNickname)
+		v.RegisterValidation("webhook_url", validWebhookURL)
+		v.RegisterValidation("webhook_event_type", validWebhookEventType)
 	}
 
+	server.router.Use(
+		otelgin.Middleware(tracing.ServiceName),
+		loggerMiddleware(slog.Default()),
+		metricsMiddleware(metrics),
+		clientMiddleware(),
+		gin.Recovery(),
+	)
 	server.SetupRouter()
 	return server, nil
 }
@@ -44,20 +77,69 @@ func (server *Server) SetupRouter() {
 	server.router.POST("/user", server.createUser)
 	server.router.POST("/user/login", server.loginUser)
 	server.router.POST("/user/token/refresh", server.refreshUserToken)
+	server.router.GET("/currencies", server.listCurrencies)
 
 	protectedRouted := server.router.Group("/").Use(authMiddleware(server.tokenMaker))
 
-	server.router.POST("/user/:username", server.getUser)
+	protectedRouted.GET("/user/:username", server.getUser)
 	protectedRouted.POST("/accounts", server.createAccount)
 	protectedRouted.GET("/accounts", server.listAccounts)
-	protectedRouted.GET("/accounts/:id", server.getAccount)
+	protectedRouted.GET("/accounts/:number", server.getAccount)
+	protectedRouted.GET("/accounts/:number/statement", server.getStatement)
+	protectedRouted.GET("/accounts/:number/limits", server.getTransferLimits)
 	protectedRouted.POST("/transfer", server.createTransfer)
+	protectedRouted.POST("/payees", server.createPayee)
+	protectedRouted.GET("/payees", server.listPayees)
+	protectedRouted.GET("/payees/:id", server.getPayee)
+	protectedRouted.PATCH("/payees/:id", server.updatePayee)
+	protectedRouted.DELETE("/payees/:id", server.deletePayee)
+	protectedRouted.POST("/payees/:id/confirm", server.confirmPayee)
+	protectedRouted.GET("/recipients", server.lookupRecipient)
+	protectedRouted.GET("/kyc", server.getKYCStatus)
+	protectedRouted.POST("/kyc/submissions", server.submitKYC)
+	protectedRouted.POST("/payment-files", server.importPaymentFile)
+	protectedRouted.POST("/webhooks", server.createWebhook)
+	protectedRouted.GET("/webhooks", server.listWebhooks)
+	protectedRouted.DELETE("/webhooks/:id", server.deleteWebhook)
+	protectedRouted.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+	protectedRouted.POST("/webhooks/deliveries/:id/replay", server.replayWebhookDelivery)
+
+	auditRoutes := server.router.Group("/audit").Use(
+		authMiddleware(server.tokenMaker),
+		roleMiddleware(server.service, util.BankerRole),
+	)
+	auditRoutes.GET("/events", server.listAuditEvents)
+	auditRoutes.GET("/verify", server.verifyAuditChain)
+
+	adminRoutes := server.router.Group("/admin").Use(
+		authMiddleware(server.tokenMaker),
+		roleMiddleware(server.service, util.BankerRole),
+	)
+	adminRoutes.GET("/ledger/trial-balance", server.getTrialBalance)
+	adminRoutes.GET("/ledger/balance-sheet", server.getBalanceSheet)
+	adminRoutes.PUT("/transfer-limits", server.setTransferLimit)
+	adminRoutes.GET("/transfer-reviews", server.listTransferReviews)
+	adminRoutes.POST("/transfer-reviews/:id/approve", server.approveTransferReview)
+	adminRoutes.POST("/transfer-reviews/:id/reject", server.rejectTransferReview)
+	adminRoutes.GET("/users/:username/screenings", server.listUserScreenings)
+	adminRoutes.GET("/kyc-submissions", server.listKYCSubmissions)
+	adminRoutes.GET("/kyc-submissions/:id", server.getKYCSubmission)
+	adminRoutes.POST("/kyc-submissions/:id/approve", server.approveKYCSubmission)
+	adminRoutes.POST("/kyc-submissions/:id/reject", server.rejectKYCSubmission)
 }
 
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// fieldName reports validation errors with the name clients sent the field as
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "uri", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
 }
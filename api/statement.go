@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/iso20022"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/statement"
+	"github.com/techschool/simplebank/token"
+)
+
+const statementDateFormat = "2006-01-02"
+
+type getStatementRequest struct {
+	From   time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To     time.Time `form:"to" binding:"required,gtefield=From" time_format:"2006-01-02" time_utc:"1"`
+	Format string    `form:"format" binding:"omitempty,oneof=csv ofx json camt053"`
+}
+
+func (server *Server) getStatement(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	var request getStatementRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	if request.Format == "" {
+		request.Format = statement.FormatJSON
+	}
+
+	response := &statementResponse{
+		ctx:         ctx,
+		contentType: statement.ContentType(request.Format),
+		filename: fmt.Sprintf("statement-%s-%s-%s.%s", uri.Number,
+			request.From.Format(statementDateFormat), request.To.Format(statementDateFormat), request.Format),
+	}
+
+	var writer statement.Writer
+	if request.Format == iso20022.FormatCamt053 {
+		response.contentType = iso20022.Camt053ContentType
+		response.filename = strings.TrimSuffix(response.filename, request.Format) + "xml"
+		writer = iso20022.NewCamt053Writer(response)
+	} else {
+		var err error
+		writer, err = statement.NewWriter(request.Format, response)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := server.service.WriteStatement(ctx, service.StatementParams{
+		Owner:         authPayload.Username,
+		AccountNumber: uri.Number,
+		From:          request.From,
+		// to is the last day included in the statement
+		To: request.To.AddDate(0, 0, 1),
+	}, writer)
+	if err != nil {
+		if !response.started {
+			handleError(ctx, err)
+			return
+		}
+
+		// too late for an error response, cut the body short instead
+		ctx.Error(err)
+		ctx.Abort()
+	}
+}
+
+// statementResponse only commits the status and headers on the first write,
+// so that errors found before the statement starts still get a problem response
+type statementResponse struct {
+	ctx         *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (response *statementResponse) Write(p []byte) (int, error) {
+	if !response.started {
+		response.started = true
+		response.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", response.filename))
+		response.ctx.Header("Content-Type", response.contentType)
+		response.ctx.Status(http.StatusOK)
+	}
+
+	return response.ctx.Writer.Write(p)
+}
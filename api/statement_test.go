@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetStatementAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount()
+	account.Owner = user.Username
+	account.Balance = 200
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	entries := []db.StatementEntry{
+		{ID: 1, Amount: 50, CreatedAt: from.Add(time.Hour)},
+		{ID: 2, Amount: -20, CreatedAt: from.Add(2 * time.Hour)},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "CSV",
+			query:    "?from=2024-03-01&to=2024-03-31&format=csv",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				buildStatementStubs(store, account, from, to, entries)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "statement-"+account.Number)
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 5)
+				// opening balance is the current balance less everything since from
+				require.Equal(t, "1.20", records[1][7])
+				require.Equal(t, "1.70", records[2][7])
+				require.Equal(t, "1.50", records[3][7])
+				require.Equal(t, []string{"", "", "Closing balance", "", "", "", "", "1.50"}, records[4])
+			},
+		},
+		{
+			name:     "Camt053",
+			query:    "?from=2024-03-01&to=2024-03-31&format=camt053",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				buildStatementStubs(store, account, from, to, entries)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), ".xml")
+
+				var document struct {
+					Balances []struct {
+						Code   string `xml:"Tp>CdOrPrtry>Cd"`
+						Amount string `xml:"Amt"`
+					} `xml:"BkToCstmrStmt>Stmt>Bal"`
+					Entries []string `xml:"BkToCstmrStmt>Stmt>Ntry>NtryRef"`
+				}
+				require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &document))
+				require.Len(t, document.Balances, 2)
+				require.Equal(t, "OPBD", document.Balances[0].Code)
+				require.Equal(t, "1.20", document.Balances[0].Amount)
+				require.Equal(t, "CLBD", document.Balances[1].Code)
+				require.Equal(t, "1.50", document.Balances[1].Amount)
+				require.Equal(t, []string{"1", "2"}, document.Entries)
+			},
+		},
+		{
+			name:     "NotOwned",
+			query:    "?from=2024-03-01&to=2024-03-31",
+			username: "someone_else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(1).Return(account, nil)
+				store.EXPECT().StreamStatementEntries(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeAccountNotOwned)
+				require.Empty(t, recorder.Header().Get("Content-Disposition"))
+			},
+		},
+		{
+			name:     "ToBeforeFrom",
+			query:    "?from=2024-03-31&to=2024-03-01",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnsupportedFormat",
+			query:    "?from=2024-03-01&to=2024-03-31&format=pdf",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			stubTx(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%s/statement%s", account.Number, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// buildStatementStubs serves entries for the statement of account from to to.
+// The current balance is 200, with 80 booked since from and 50 since to.
+func buildStatementStubs(store *mockdb.MockStore, account db.Account, from, to time.Time, entries []db.StatementEntry) {
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(1).Return(account, nil)
+	store.EXPECT().
+		SumEntriesSince(gomock.Any(), gomock.Eq(db.SumEntriesSinceParams{AccountID: account.ID, CreatedAt: from})).
+		Times(1).
+		Return(int64(80), nil)
+	store.EXPECT().
+		SumEntriesSince(gomock.Any(), gomock.Eq(db.SumEntriesSinceParams{AccountID: account.ID, CreatedAt: to})).
+		Times(1).
+		Return(int64(50), nil)
+	store.EXPECT().
+		StreamStatementEntries(gomock.Any(), gomock.Eq(db.StreamStatementEntriesParams{AccountID: account.ID, From: from, To: to}), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _ db.StreamStatementEntriesParams, fn func(db.StatementEntry) error) error {
+			for _, entry := range entries {
+				if err := fn(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
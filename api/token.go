@@ -20,42 +20,18 @@ func (server *Server) refreshUserToken(ctx *gin.Context) {
 	var req RenewAccessTokenRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-	}
-
-	rereshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
-
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	session, err := server.store.GetSession(ctx, rereshPayload.ID)
-
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	if session.IsBlocked {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		handleError(ctx, bindingError(err))
 		return
 	}
 
-	if session.RefreshToken != req.RefreshToken {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	token, accessPayload, err := server.tokenMaker.CreateToken(rereshPayload.Username, server.config.AccessTokenDuration)
-
+	result, err := server.service.RenewAccessToken(ctx, req.RefreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		handleError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, RenewAccessTokenResponse{
-		AccessToken:          token,
-		AccessTokenExpiresAt: accessPayload.ExpiredAt.Time,
+		AccessToken:          result.AccessToken,
+		AccessTokenExpiresAt: result.AccessTokenExpiresAt,
 	})
 }
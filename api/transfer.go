@@ -1,85 +1,161 @@
 package api
 
 import (
-	"database/sql"
-	"fmt"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/techschool/simplebank/apperr"
 	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/service"
 	"github.com/techschool/simplebank/token"
 )
 
 type transferRequest struct {
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Currency      string `json:"currency" binding:"required,currency"`
+	// Amount is a decimal in the major unit of Currency, such as "12.34"
+	Amount            string `json:"amount" binding:"required"`
+	FromAccountNumber string `json:"from_account_number" binding:"required,account_number"`
+	// ToAccountNumber, PayeeID or ToAlias names the receiving account. gRPC
+	// has no transfer call, so payees are only paid through this request.
+	ToAccountNumber string `json:"to_account_number" binding:"required_without_all=PayeeID ToAlias,excluded_with=PayeeID ToAlias,omitempty,account_number"`
+	PayeeID         int64  `json:"payee_id" binding:"excluded_with=ToAlias,omitempty,min=1"`
+	ToAlias         string `json:"to_alias" binding:"omitempty,alias"`
+	Currency        string `json:"currency" binding:"required,currency"`
 }
 
-func (server *Server) createTransfer(ctx *gin.Context) {
-	var req transferRequest
-
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
+// transferResponse renders the amount and balances of a transfer as money
+// and names its accounts by their public number. Only the sender's side is
+// shown in full, the recipient may have been found by alias or payee and is
+// named by account number alone.
+type transferResponse struct {
+	Transfer     transferRecord     `json:"transfer"`
+	Amount       money.Money        `json:"amount"`
+	JournalEntry journalEntryRecord `json:"journal_entry"`
+	FromAccount  accountResponse    `json:"from_account"`
+	ToAccount    recipientRecord    `json:"to_account"`
+	FromEntry    entryRecord        `json:"from_entry"`
+}
 
-	FromAccount, FromValid := server.validAccount(ctx, req.FromAccountID, req.Currency)
-	if !FromValid {
-		return
-	}
+type recipientRecord struct {
+	Number   string `json:"number"`
+	Currency string `json:"currency"`
+}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+type transferRecord struct {
+	ID                int64     `json:"id"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	Amount            int64     `json:"amount"`
+	CreatedAt         time.Time `json:"created_at"`
+}
 
-	if FromAccount.Owner != authPayload.Username {
-		ctx.JSON(http.StatusBadRequest, gin.H{})
-		return
-	}
+type journalEntryRecord struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	TransferID  int64     `json:"transfer_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
 
-	_, ToValid := server.validAccount(ctx, req.ToAccountID, req.Currency)
+type entryRecord struct {
+	ID            int64     `json:"id"`
+	AccountNumber string    `json:"account_number"`
+	Amount        int64     `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
 
-	if !ToValid {
-		return
+func newTransferResponse(result db.TransferTxResult) transferResponse {
+	return transferResponse{
+		Transfer: transferRecord{
+			ID:                result.Transfer.ID,
+			FromAccountNumber: result.FromAccount.Number,
+			ToAccountNumber:   result.ToAccount.Number,
+			Amount:            result.Transfer.Amount,
+			CreatedAt:         result.Transfer.CreatedAt,
+		},
+		Amount:       result.Amount,
+		JournalEntry: newJournalEntryRecord(result.JournalEntry),
+		FromAccount:  newAccountResponse(result.FromAccount),
+		ToAccount: recipientRecord{
+			Number:   result.ToAccount.Number,
+			Currency: result.ToAccount.Currency,
+		},
+		FromEntry: newEntryRecord(result.FromEntry, result.FromAccount),
 	}
+}
 
-	args := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+func newJournalEntryRecord(journalEntry db.JournalEntry) journalEntryRecord {
+	return journalEntryRecord{
+		ID:          journalEntry.ID,
+		Kind:        journalEntry.Kind,
+		Description: journalEntry.Description,
+		TransferID:  journalEntry.TransferID.Int64,
+		CreatedAt:   journalEntry.CreatedAt,
 	}
+}
 
-	result, err := server.store.TransferTx(ctx, args)
+// heldTransferResponse tells the sender that a transfer waits in the review
+// queue and will be made once a banker approves it
+type heldTransferResponse struct {
+	Status   string `json:"status"`
+	ReviewID int64  `json:"review_id"`
+	Message  string `json:"message"`
+}
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
+func newHeldTransferResponse(err error) heldTransferResponse {
+	appErr := apperr.From(err)
+	reviewID, _ := appErr.Details["review_id"].(int64)
+
+	return heldTransferResponse{
+		Status:   "held",
+		ReviewID: reviewID,
+		Message:  appErr.Message,
 	}
+}
 
-	ctx.JSON(http.StatusOK, result)
+func newEntryRecord(entry db.Entry, account db.Account) entryRecord {
+	return entryRecord{
+		ID:            entry.ID,
+		AccountNumber: account.Number,
+		Amount:        entry.Amount,
+		CreatedAt:     entry.CreatedAt,
+	}
 }
 
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
-	account, err := server.store.GetAccount(ctx, accountID)
+func (server *Server) createTransfer(ctx *gin.Context) {
+	var req transferRequest
 
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	amount, err := money.Parse(req.Amount, req.Currency)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return account, false
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return account, false
+		handleError(ctx, invalidAmountError(err))
+		return
 	}
 
-	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return account, false
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.service.CreateTransfer(ctx, service.CreateTransferParams{
+		Owner:             authPayload.Username,
+		FromAccountNumber: req.FromAccountNumber,
+		ToAccountNumber:   req.ToAccountNumber,
+		PayeeID:           req.PayeeID,
+		ToAlias:           req.ToAlias,
+		Amount:            amount,
+	})
+	if errors.Is(err, service.ErrTransferHeld) {
+		ctx.JSON(http.StatusAccepted, newHeldTransferResponse(err))
+		return
+	}
+	if err != nil {
+		handleError(ctx, err)
+		return
 	}
 
-	return account, true
+	ctx.JSON(http.StatusOK, newTransferResponse(result))
 }
@@ -6,13 +6,18 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
 	mockdb "github.com/techschool/simplebank/db/mock"
 	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/fraud"
+	"github.com/techschool/simplebank/money"
 	"github.com/techschool/simplebank/token"
 	"github.com/techschool/simplebank/util"
 	"go.uber.org/mock/gomock"
@@ -20,11 +25,20 @@ import (
 
 func TestTransferAPI(t *testing.T) {
 	amount := int64(10)
+	decimal := money.New(amount, util.USD).Decimal()
+
+	user1, _ := randomUser(t)
 
 	account1 := randomAccount()
 	account2 := randomAccount()
 	account3 := randomAccount()
 
+	account1.Owner = user1.Username
+	account3.Owner = user1.Username
+
+	account1.Balance = amount * 10
+	account3.Balance = amount * 10
+
 	account1.Currency = util.USD
 	account2.Currency = util.USD
 	account3.Currency = util.EUR
@@ -39,13 +53,13 @@ func TestTransferAPI(t *testing.T) {
 		{
 			name: "OK",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
@@ -56,28 +70,143 @@ func TestTransferAPI(t *testing.T) {
 					ToAccountID:   account2.ID,
 					Amount:        amount,
 				}
-				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+				store.EXPECT().
+					TransferTx(gomock.Any(), EqTransferTxParams(arg)).
+					Times(1).
+					Return(db.TransferTxResult{
+						Transfer: db.Transfer{ID: 9, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: amount},
+						Amount:   money.New(amount, util.USD),
+						JournalEntry: db.JournalEntry{
+							ID:         4,
+							Kind:       db.JournalKindTransfer,
+							TransferID: sql.NullInt64{Int64: 9, Valid: true},
+						},
+						FromAccount: account1,
+						ToAccount:   account2,
+					}, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Transfer struct {
+						FromAccountNumber string `json:"from_account_number"`
+						ToAccountNumber   string `json:"to_account_number"`
+					} `json:"transfer"`
+					Amount       money.Money    `json:"amount"`
+					JournalEntry map[string]any `json:"journal_entry"`
+					FromAccount  map[string]any `json:"from_account"`
+					ToAccount    map[string]any `json:"to_account"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, float64(9), response.JournalEntry["transfer_id"])
+				require.Equal(t, money.New(amount, util.USD), response.Amount)
+				require.Equal(t, account1.Number, response.Transfer.FromAccountNumber)
+				require.Equal(t, account2.Number, response.Transfer.ToAccountNumber)
+				require.Equal(t, account1.Number, response.FromAccount["number"])
+				require.NotContains(t, response.FromAccount, "id")
+				require.Equal(t, map[string]any{"number": account2.Number, "currency": util.USD}, response.ToAccount)
+				require.NotContains(t, recorder.Body.String(), "to_entry")
+				require.NotContains(t, recorder.Body.String(), account2.Owner)
 			},
 		},
 		{
-			name: "FromAccountNotFound",
+			name: "TooManyDecimals",
+			body: gin.H{
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              "0.101",
+				"currency":            util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidAmount)
+			},
+		},
+		{
+			name: "NumericAmount",
+			body: gin.H{
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              amount,
+				"currency":            util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{
+				"from_account_number": account2.Number,
+				"to_account_number":   account1.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientFunds",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              money.New(account1.Balance+1, util.USD).Decimal(),
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(0)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInsufficientFunds)
+			},
+		},
+		{
+			name: "FromAccountNotFound",
+			body: gin.H{
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account1.Number)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
@@ -85,17 +214,17 @@ func TestTransferAPI(t *testing.T) {
 		{
 			name: "ToAccountNotFound",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account2.Number)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -105,13 +234,13 @@ func TestTransferAPI(t *testing.T) {
 		{
 			name: "FromAccountCurrencyMismatch",
 			body: gin.H{
-				"from_account_id": account3.ID,
-				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"from_account_number": account3.Number,
+				"to_account_number":   account2.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
@@ -125,13 +254,13 @@ func TestTransferAPI(t *testing.T) {
 		{
 			name: "ToAccountCurrencyMismatch",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account3.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"from_account_number": account1.Number,
+				"to_account_number":   account3.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
@@ -140,18 +269,39 @@ func TestTransferAPI(t *testing.T) {
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeCurrencyMismatch)
+			},
+		},
+		{
+			name: "InvalidAccountNumber",
+			body: gin.H{
+				"from_account_number": account1.Number,
+				"to_account_number":   "SB00000000000000",
+				"amount":              decimal,
+				"currency":            util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
 			},
 		},
 		{
 			name: "InvalidCurrency",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        "XYZ",
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              decimal,
+				"currency":            "XYZ",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -164,13 +314,13 @@ func TestTransferAPI(t *testing.T) {
 		{
 			name: "NegativeAmount",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account2.ID,
-				"amount":          -amount,
-				"currency":        util.USD,
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              "-" + decimal,
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -183,13 +333,13 @@ func TestTransferAPI(t *testing.T) {
 		{
 			name: "GetAccountError",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrConnDone)
@@ -202,13 +352,13 @@ func TestTransferAPI(t *testing.T) {
 		{
 			name: "TransferTxError",
 			body: gin.H{
-				"from_account_id": account1.ID,
-				"to_account_id":   account2.ID,
-				"amount":          amount,
-				"currency":        util.USD,
+				"from_account_number": account1.Number,
+				"to_account_number":   account2.Number,
+				"amount":              decimal,
+				"currency":            util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
@@ -216,7 +366,9 @@ func TestTransferAPI(t *testing.T) {
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrTxDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInternal)
+				require.NotContains(t, recorder.Body.String(), sql.ErrTxDone.Error())
 			},
 		},
 	}
@@ -230,6 +382,10 @@ func TestTransferAPI(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			stubTx(store)
+			for _, account := range []db.Account{account1, account2, account3} {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).AnyTimes().Return(account, nil)
+			}
 
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -248,3 +404,61 @@ func TestTransferAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestTransferHeldAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	fromAccount := randomAccount()
+	toAccount := randomAccount()
+	fromAccount.Owner = user.Username
+	fromAccount.Balance = 1000
+	fromAccount.Currency = util.USD
+	toAccount.Currency = util.USD
+
+	rulesFile := filepath.Join(t.TempDir(), "fraud_rules.yaml")
+	rules := "rules:\n  - name: blocklist\n    type: blocklist\n    decision: review\n    accounts: [" + toAccount.Number + "]\n"
+	require.NoError(t, os.WriteFile(rulesFile, []byte(rules), 0o600))
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(fromAccount.Number)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(toAccount.Number)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		CreateTransferReview(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TransferReview{ID: 9, Status: fraud.ReviewPending}, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+	stubTx(store)
+
+	server, err := NewServer(store, util.Config{
+		TokenSymmetricalKey: util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		FraudRulesFile:      rulesFile,
+	}, nil)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(gin.H{
+		"from_account_number": fromAccount.Number,
+		"to_account_number":   toAccount.Number,
+		"amount":              "1.00",
+		"currency":            util.USD,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfer", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.NotEqual(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+	var response heldTransferResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, "held", response.Status)
+	require.Equal(t, int64(9), response.ReviewID)
+}
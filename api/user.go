@@ -1,37 +1,41 @@
 package api
 
 import (
-	"database/sql"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 	db "github.com/techschool/simplebank/db/sqlc"
-	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/token"
 )
 
 type UserResponse struct {
 	Username         string    `json:"username"`
 	FullName         string    `json:"full_name"`
 	Email            string    `json:"email"`
+	Phone            string    `json:"phone,omitempty"`
+	KYCTier          string    `json:"kyc_tier"`
 	PasswordChangeAt time.Time `json:"password_change_at"`
 	CreatedAt        time.Time `json:"created_at"`
 }
 
 type createUserRequest struct {
-	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
-	FullName string `json:"full_name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+	Username string `json:"username" binding:"required,username"`
+	Password string `json:"password" binding:"required,password"`
+	FullName string `json:"full_name" binding:"required,full_name"`
+	Email    string `json:"email" binding:"required,email_address"`
+	Phone    string `json:"phone" binding:"omitempty,phone"`
 }
 
 func newUserResponse(user db.User) UserResponse {
 	return UserResponse{
 		Username:         user.Username,
-		FullName:         user.Username,
+		FullName:         user.FullName,
 		Email:            user.Email,
+		Phone:            user.Phone,
+		KYCTier:          user.KycTier,
 		CreatedAt:        user.CreatedAt,
 		PasswordChangeAt: user.PasswordChangeAt,
 	}
@@ -40,39 +44,19 @@ func newUserResponse(user db.User) UserResponse {
 func (server *Server) createUser(ctx *gin.Context) {
 	var user createUserRequest
 	if err := ctx.ShouldBindJSON(&user); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, bindingError(err))
 		return
 	}
 
-	hashedPassword, err := util.HashPassword(user.Password)
-
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "Could not hash password",
-		})
-
-		return
-	}
-
-	args := db.CreateUserParams{
-		Username:       user.Username,
-		HashedPassword: hashedPassword,
-		FullName:       user.FullName,
-		Email:          user.Email,
-	}
-
-	userRecord, err := server.store.CreateUser(ctx, args)
-
+	userRecord, err := server.service.CreateUser(ctx, service.CreateUserParams{
+		Username: user.Username,
+		Password: user.Password,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Phone:    user.Phone,
+	})
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "unique_violation":
-				ctx.JSON(http.StatusBadRequest, errorResponse(err))
-				return
-			}
-		}
-		
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, err)
 		return
 	}
 
@@ -82,25 +66,28 @@ func (server *Server) createUser(ctx *gin.Context) {
 }
 
 type getUserRequest struct {
-	Username string `uri:"username" binding:"required,alphanum"`
+	Username string `uri:"username" binding:"required,existing_username"`
 }
 
+// getUser returns the profile of the authenticated user, the personal data
+// of other users is never handed out
 func (server *Server) getUser(ctx *gin.Context) {
 	var user getUserRequest
 
 	if err := ctx.ShouldBindUri(&user); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, bindingError(err))
 		return
 	}
 
-	userRecord, err := server.store.GetUser(ctx, user.Username)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if user.Username != authPayload.Username {
+		handleError(ctx, service.ErrPermissionDenied)
+		return
+	}
 
+	userRecord, err := server.service.GetUser(ctx, user.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		handleError(ctx, err)
 		return
 	}
 
@@ -110,8 +97,8 @@ func (server *Server) getUser(ctx *gin.Context) {
 }
 
 type LoginUserRequest struct {
-	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	Username string `json:"username" binding:"required,existing_username"`
+	Password string `json:"password" binding:"required,password"`
 }
 
 type LoginUserResponse struct {
@@ -127,62 +114,27 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	var req LoginUserRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-	}
-
-	user, err := server.store.GetUser(ctx, req.Username)
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		handleError(ctx, bindingError(err))
 		return
 	}
 
-	err = util.CheckPassword(req.Password, user.HashedPassword)
-
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	token, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
-
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
-
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		Username:     user.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    ctx.Request.UserAgent(),
-		ClientIp:     ctx.ClientIP(),
-		ExpiresAt:    time.Now().Add(server.config.RefreshTokenDuration),
+	result, err := server.service.LoginUser(ctx, service.LoginUserParams{
+		Username:  req.Username,
+		Password:  req.Password,
+		UserAgent: ctx.Request.UserAgent(),
+		ClientIP:  ctx.ClientIP(),
 	})
-
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		handleError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, LoginUserResponse{
-		SessionId:             session.ID,
-		AccessToken:           token,
-		AccessTokenExpiresAt:  accessPayload.ExpiredAt.Time,
-		RefreshToken:          refreshToken,
-		RefreshTokenExpiresAt: refreshPayload.ExpiredAt.Time,
-		User:                  newUserResponse(user),
+		SessionId:             result.SessionID,
+		AccessToken:           result.AccessToken,
+		AccessTokenExpiresAt:  result.AccessTokenExpiresAt,
+		RefreshToken:          result.RefreshToken,
+		RefreshTokenExpiresAt: result.RefreshTokenExpiresAt,
+		User:                  newUserResponse(result.User),
 	})
 }
@@ -2,13 +2,33 @@ package api
 
 import (
 	"github.com/go-playground/validator/v10"
-	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/val"
 )
 
-var validCurrency validator.Func = func(fl validator.FieldLevel) bool {
-	if currency, ok := fl.Field().Interface().(string); ok {
-		return util.IsValidCurrency(currency)
-	}
+// validatorFunc adapts a val check into a gin binding tag
+func validatorFunc(check func(string) error) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		if value, ok := fl.Field().Interface().(string); ok {
+			return check(value) == nil
+		}
 
-	return false
+		return false
+	}
 }
+
+var (
+	validCurrency         = validatorFunc(val.ValidateCurrency)
+	validUsername         = validatorFunc(val.ValidateUsername)
+	validExistingUsername = validatorFunc(val.ValidateExistingUsername)
+	validFullName         = validatorFunc(val.ValidateFullName)
+	validPassword         = validatorFunc(val.ValidatePassword)
+	validEmail            = validatorFunc(val.ValidateEmail)
+	validPhone            = validatorFunc(val.ValidatePhone)
+	validAlias            = validatorFunc(val.ValidateAlias)
+	validNickname         = validatorFunc(val.ValidateNickname)
+
+	validAccountNumber = validatorFunc(val.ValidateAccountNumber)
+
+	validWebhookURL       = validatorFunc(val.ValidateWebhookURL)
+	validWebhookEventType = validatorFunc(val.ValidateWebhookEventType)
+)
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/token"
+)
+
+type WebhookSubscriptionResponse struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Secret is only returned when the subscription is created
+	Secret string `json:"secret,omitempty"`
+}
+
+func newWebhookSubscriptionResponse(subscription db.WebhookSubscription) WebhookSubscriptionResponse {
+	return WebhookSubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,webhook_url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,webhook_event_type"`
+}
+
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var request createWebhookRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	subscription, err := server.service.CreateWebhookSubscription(ctx, service.CreateWebhookSubscriptionParams{
+		Owner:      authPayload.Username,
+		URL:        request.URL,
+		EventTypes: request.EventTypes,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response := newWebhookSubscriptionResponse(subscription)
+	response.Secret = subscription.Secret
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	subscriptions, err := server.service.ListWebhookSubscriptions(ctx, authPayload.Username)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response := make([]WebhookSubscriptionResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		response[i] = newWebhookSubscriptionResponse(subscription)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+type webhookIDRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var request webhookIDRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if err := server.service.DisableWebhookSubscription(ctx, authPayload.Username, request.ID); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+type listWebhookDeliveriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=1,max=100"`
+}
+
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookIDRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	var request listWebhookDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	deliveries, err := server.service.ListWebhookDeliveries(ctx, service.ListWebhookDeliveriesParams{
+		Owner:          authPayload.Username,
+		SubscriptionID: uri.ID,
+		PageID:         request.PageID,
+		PageSize:       request.PageSize,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (server *Server) replayWebhookDelivery(ctx *gin.Context) {
+	var request webhookIDRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		handleError(ctx, bindingError(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	delivery, err := server.service.ReplayWebhookDelivery(ctx, authPayload.Username, request.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, delivery)
+}
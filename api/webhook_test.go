@@ -0,0 +1,232 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/webhook"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	url := "https://merchant.example.com/hooks"
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"url":         url,
+				"event_types": []string{webhook.EventEntryCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.Equal(t, url, arg.Url)
+						require.Equal(t, []string{webhook.EventEntryCreated}, arg.EventTypes)
+						return db.WebhookSubscription{
+							ID:         1,
+							Owner:      arg.Owner,
+							Url:        arg.Url,
+							Secret:     arg.Secret,
+							EventTypes: arg.EventTypes,
+							IsActive:   true,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response WebhookSubscriptionResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, url, response.URL)
+				require.NotEmpty(t, response.Secret)
+			},
+		},
+		{
+			name: "InvalidEventType",
+			body: gin.H{
+				"url":         url,
+				"event_types": []string{"account.deleted"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeInvalidArgument)
+			},
+		},
+		{
+			name: "InvalidURL",
+			body: gin.H{
+				"url":         "merchant.example.com",
+				"event_types": []string{webhook.EventEntryCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoEventTypes",
+			body: gin.H{
+				"url":         url,
+				"event_types": []string{},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			stubTx(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReplayWebhookDeliveryAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	otherUser, _ := randomUser(t)
+
+	subscription := db.WebhookSubscription{ID: 3, Owner: user.Username, IsActive: true}
+	delivery := db.WebhookDelivery{
+		ID:             9,
+		SubscriptionID: subscription.ID,
+		EventType:      webhook.EventEntryCreated,
+		Payload:        json.RawMessage(`{"amount":10}`),
+		Status:         webhook.StatusFailed,
+	}
+
+	testCases := []struct {
+		name          string
+		deliveryID    int64
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			deliveryID: delivery.ID,
+			username:   user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+				store.EXPECT().GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).Times(1).Return(subscription, nil)
+				store.EXPECT().
+					CreateWebhookDelivery(gomock.Any(), gomock.Eq(db.CreateWebhookDeliveryParams{
+						SubscriptionID: delivery.SubscriptionID,
+						EventID:        delivery.EventID,
+						EventType:      delivery.EventType,
+						Payload:        delivery.Payload,
+					})).
+					Times(1).
+					Return(db.WebhookDelivery{ID: 10, SubscriptionID: subscription.ID, Status: webhook.StatusPending}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var replay db.WebhookDelivery
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &replay))
+				require.Equal(t, int64(10), replay.ID)
+				require.Equal(t, webhook.StatusPending, replay.Status)
+			},
+		},
+		{
+			name:       "NotOwned",
+			deliveryID: delivery.ID,
+			username:   otherUser.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+				store.EXPECT().GetWebhookSubscription(gomock.Any(), gomock.Eq(subscription.ID)).Times(1).Return(subscription, nil)
+				store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireProblemCode(t, recorder, apperr.CodeWebhookDeliveryNotFound)
+			},
+		},
+		{
+			name:       "NotFound",
+			deliveryID: delivery.ID,
+			username:   user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Any()).Times(1).Return(db.WebhookDelivery{}, sql.ErrNoRows)
+				store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			stubTx(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/webhooks/deliveries/%d/replay", tc.deliveryID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
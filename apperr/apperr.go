@@ -0,0 +1,89 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// Error is an error with a stable code that is safe to show to clients
+type Error struct {
+	Code    Code           `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+	cause   error
+}
+
+// New creates an error with the given code and message
+func New(code Code, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Newf creates an error with the given code and a formatted message
+func Newf(code Code, format string, args ...any) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Internal wraps an unexpected error. The cause is kept for logging but
+// never rendered to clients.
+func Internal(cause error) *Error {
+	return &Error{
+		Code:    CodeInternal,
+		Message: "internal server error",
+		cause:   cause,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.cause)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying cause of the error
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an *Error with the same code, so that
+// copies made by WithDetail still match their catalogue entry.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetail returns a copy of the error with an extra detail attached
+func (e *Error) WithDetail(key string, value any) *Error {
+	clone := *e
+	clone.Details = maps.Clone(e.Details)
+	if clone.Details == nil {
+		clone.Details = make(map[string]any)
+	}
+	clone.Details[key] = value
+
+	return &clone
+}
+
+// WithCause returns a copy of the error that wraps cause
+func (e *Error) WithCause(cause error) *Error {
+	clone := *e
+	clone.cause = cause
+
+	return &clone
+}
+
+// From returns the first *Error in the chain of err, or wraps err as an
+// internal error.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return Internal(err)
+}
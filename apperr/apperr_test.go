@@ -0,0 +1,44 @@
+package apperr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestErrorIs(t *testing.T) {
+	errNotFound := New(CodeAccountNotFound, "account not found")
+
+	err := fmt.Errorf("lookup failed: %w", errNotFound.WithDetail("account_id", 1))
+	require.ErrorIs(t, err, errNotFound)
+	require.NotErrorIs(t, err, New(CodeUserNotFound, "user not found"))
+
+	require.Empty(t, errNotFound.Details)
+	require.Equal(t, 1, From(err).Details["account_id"])
+}
+
+func TestFromHidesInternalErrors(t *testing.T) {
+	err := From(sql.ErrConnDone)
+
+	require.Equal(t, CodeInternal, err.Code)
+	require.NotContains(t, err.Message, sql.ErrConnDone.Error())
+	require.True(t, errors.Is(err, sql.ErrConnDone))
+}
+
+func TestCatalogue(t *testing.T) {
+	require.Equal(t, http.StatusNotFound, CodeAccountNotFound.HTTPStatus())
+	require.Equal(t, codes.NotFound, CodeAccountNotFound.GRPCCode())
+
+	unknown := Code("UNKNOWN")
+	require.Equal(t, http.StatusInternalServerError, unknown.HTTPStatus())
+	require.Equal(t, codes.Internal, unknown.GRPCCode())
+
+	for code := range catalogue {
+		require.NotEmpty(t, code.Title())
+	}
+}
@@ -0,0 +1,140 @@
+package apperr
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Code is a stable, machine readable error identifier
+type Code string
+
+const (
+	CodeInternal         Code = "INTERNAL"
+	CodeInvalidArgument  Code = "INVALID_ARGUMENT"
+	CodeUnauthenticated  Code = "UNAUTHENTICATED"
+	CodePermissionDenied Code = "PERMISSION_DENIED"
+	CodeRateLimited      Code = "RATE_LIMITED"
+
+	CodeUserNotFound       Code = "USER_NOT_FOUND"
+	CodeUserAlreadyExists  Code = "USER_ALREADY_EXISTS"
+	CodeInvalidCredentials Code = "INVALID_CREDENTIALS"
+	CodeUserBlocked        Code = "USER_BLOCKED"
+
+	CodeKYCTierRequired       Code = "KYC_TIER_REQUIRED"
+	CodeKYCSubmissionNotFound Code = "KYC_SUBMISSION_NOT_FOUND"
+	CodeKYCSubmissionPending  Code = "KYC_SUBMISSION_PENDING"
+	CodeKYCSubmissionDecided  Code = "KYC_SUBMISSION_DECIDED"
+
+	CodeInvalidToken    Code = "INVALID_TOKEN"
+	CodeSessionNotFound Code = "SESSION_NOT_FOUND"
+	CodeSessionBlocked  Code = "SESSION_BLOCKED"
+	CodeSessionMismatch Code = "SESSION_MISMATCH"
+	CodeSessionExpired  Code = "SESSION_EXPIRED"
+
+	CodeAccountNotFound      Code = "ACCOUNT_NOT_FOUND"
+	CodeAccountAlreadyExists Code = "ACCOUNT_ALREADY_EXISTS"
+	CodeAccountNotOwned      Code = "ACCOUNT_NOT_OWNED"
+	CodeProductNotFound      Code = "PRODUCT_NOT_FOUND"
+	CodeCurrencyMismatch     Code = "CURRENCY_MISMATCH"
+	CodeInsufficientFunds    Code = "INSUFFICIENT_FUNDS"
+	CodeSameAccount          Code = "SAME_ACCOUNT"
+	CodeInvalidAmount        Code = "INVALID_AMOUNT"
+	CodeLimitExceeded        Code = "LIMIT_EXCEEDED"
+	CodeTransferHeld         Code = "TRANSFER_HELD"
+	CodeTransferDenied       Code = "TRANSFER_DENIED"
+
+	CodePayeeNotFound      Code = "PAYEE_NOT_FOUND"
+	CodePayeeAlreadyExists Code = "PAYEE_ALREADY_EXISTS"
+	CodePayeeCoolingOff    Code = "PAYEE_COOLING_OFF"
+	CodeRecipientNotFound  Code = "RECIPIENT_NOT_FOUND"
+
+	CodeTransferReviewNotFound Code = "TRANSFER_REVIEW_NOT_FOUND"
+	CodeTransferReviewDecided  Code = "TRANSFER_REVIEW_DECIDED"
+
+	CodePaymentFileInvalid   Code = "PAYMENT_FILE_INVALID"
+	CodePaymentFileDuplicate Code = "PAYMENT_FILE_DUPLICATE"
+
+	CodeWebhookNotFound         Code = "WEBHOOK_NOT_FOUND"
+	CodeWebhookDeliveryNotFound Code = "WEBHOOK_DELIVERY_NOT_FOUND"
+)
+
+type entry struct {
+	httpStatus int
+	grpcCode   codes.Code
+	title      string
+}
+
+// catalogue maps every code to its transport status and a short title
+var catalogue = map[Code]entry{
+	CodeInternal:         {http.StatusInternalServerError, codes.Internal, "Internal error"},
+	CodeInvalidArgument:  {http.StatusBadRequest, codes.InvalidArgument, "Invalid argument"},
+	CodeUnauthenticated:  {http.StatusUnauthorized, codes.Unauthenticated, "Unauthenticated"},
+	CodePermissionDenied: {http.StatusForbidden, codes.PermissionDenied, "Permission denied"},
+	CodeRateLimited:      {http.StatusTooManyRequests, codes.ResourceExhausted, "Too many requests"},
+
+	CodeUserNotFound:       {http.StatusNotFound, codes.NotFound, "User not found"},
+	CodeUserAlreadyExists:  {http.StatusForbidden, codes.AlreadyExists, "User already exists"},
+	CodeInvalidCredentials: {http.StatusUnauthorized, codes.Unauthenticated, "Invalid credentials"},
+	CodeUserBlocked:        {http.StatusForbidden, codes.PermissionDenied, "User blocked"},
+
+	CodeKYCTierRequired:       {http.StatusForbidden, codes.FailedPrecondition, "Higher KYC tier required"},
+	CodeKYCSubmissionNotFound: {http.StatusNotFound, codes.NotFound, "KYC submission not found"},
+	CodeKYCSubmissionPending:  {http.StatusConflict, codes.AlreadyExists, "KYC submission already pending"},
+	CodeKYCSubmissionDecided:  {http.StatusConflict, codes.FailedPrecondition, "KYC submission already decided"},
+
+	CodeInvalidToken:    {http.StatusUnauthorized, codes.Unauthenticated, "Invalid token"},
+	CodeSessionNotFound: {http.StatusUnauthorized, codes.Unauthenticated, "Session not found"},
+	CodeSessionBlocked:  {http.StatusUnauthorized, codes.Unauthenticated, "Session blocked"},
+	CodeSessionMismatch: {http.StatusUnauthorized, codes.Unauthenticated, "Session mismatch"},
+	CodeSessionExpired:  {http.StatusUnauthorized, codes.Unauthenticated, "Session expired"},
+
+	CodeAccountNotFound:      {http.StatusNotFound, codes.NotFound, "Account not found"},
+	CodeAccountAlreadyExists: {http.StatusForbidden, codes.AlreadyExists, "Account already exists"},
+	CodeAccountNotOwned:      {http.StatusUnauthorized, codes.PermissionDenied, "Account not owned"},
+	CodeProductNotFound:      {http.StatusNotFound, codes.NotFound, "Product not found"},
+	CodeCurrencyMismatch:     {http.StatusBadRequest, codes.FailedPrecondition, "Currency mismatch"},
+	CodeInsufficientFunds:    {http.StatusBadRequest, codes.FailedPrecondition, "Insufficient funds"},
+	CodeSameAccount:          {http.StatusBadRequest, codes.InvalidArgument, "Same account"},
+	CodeInvalidAmount:        {http.StatusBadRequest, codes.InvalidArgument, "Invalid amount"},
+	CodeLimitExceeded:        {http.StatusForbidden, codes.FailedPrecondition, "Transfer limit exceeded"},
+	CodeTransferHeld:         {http.StatusConflict, codes.FailedPrecondition, "Transfer held for review"},
+	CodeTransferDenied:       {http.StatusForbidden, codes.PermissionDenied, "Transfer denied"},
+
+	CodePayeeNotFound:      {http.StatusNotFound, codes.NotFound, "Payee not found"},
+	CodePayeeAlreadyExists: {http.StatusConflict, codes.AlreadyExists, "Payee already exists"},
+	CodePayeeCoolingOff:    {http.StatusConflict, codes.FailedPrecondition, "Payee not active yet"},
+	CodeRecipientNotFound:  {http.StatusNotFound, codes.NotFound, "Recipient not found"},
+
+	CodeTransferReviewNotFound: {http.StatusNotFound, codes.NotFound, "Transfer review not found"},
+	CodeTransferReviewDecided:  {http.StatusConflict, codes.FailedPrecondition, "Transfer review already decided"},
+
+	CodePaymentFileInvalid:   {http.StatusBadRequest, codes.InvalidArgument, "Invalid payment file"},
+	CodePaymentFileDuplicate: {http.StatusConflict, codes.AlreadyExists, "Duplicate payment file"},
+
+	CodeWebhookNotFound:         {http.StatusNotFound, codes.NotFound, "Webhook not found"},
+	CodeWebhookDeliveryNotFound: {http.StatusNotFound, codes.NotFound, "Webhook delivery not found"},
+}
+
+func lookup(code Code) entry {
+	if e, ok := catalogue[code]; ok {
+		return e
+	}
+
+	return catalogue[CodeInternal]
+}
+
+// HTTPStatus returns the HTTP status code for code
+func (code Code) HTTPStatus() int {
+	return lookup(code).httpStatus
+}
+
+// GRPCCode returns the gRPC status code for code
+func (code Code) GRPCCode() codes.Code {
+	return lookup(code).grpcCode
+}
+
+// Title returns a short human readable summary of code
+func (code Code) Title() string {
+	return lookup(code).title
+}
@@ -0,0 +1,214 @@
+// Package audit writes the append-only audit log. Every event is chained to
+// its predecessor with a sha256 hash so that any later edit or deletion of a
+// row can be detected by Verify.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionUserCreated         = "user.created"
+	ActionUserScreened        = "user.screened"
+	ActionUserBlocked         = "user.screening_blocked"
+	ActionKYCSubmitted        = "kyc.submitted"
+	ActionKYCViewed           = "kyc.identity_viewed"
+	ActionKYCApproved         = "kyc.approved"
+	ActionKYCRejected         = "kyc.rejected"
+	ActionLoginSucceeded      = "user.login_succeeded"
+	ActionLoginFailed         = "user.login_failed"
+	ActionTokenRenewed        = "session.token_renewed"
+	ActionAccountCreated      = "account.created"
+	ActionBalanceRepaired     = "account.balance_repaired"
+	ActionInterestPosted      = "account.interest_posted"
+	ActionTransferCreated     = "transfer.created"
+	ActionTransferHeld        = "transfer.held"
+	ActionTransferDenied      = "transfer.denied"
+	ActionTransferApproved    = "transfer_review.approved"
+	ActionTransferRejected    = "transfer_review.rejected"
+	ActionPaymentFileImported = "payment_file.imported"
+	ActionPayeeCreated        = "payee.created"
+	ActionPayeeRenamed        = "payee.renamed"
+	ActionPayeeConfirmed      = "payee.confirmed"
+	ActionPayeeDeleted        = "payee.deleted"
+	ActionTransferLimitSet    = "transfer_limit.set"
+	ActionWebhookCreated      = "webhook.created"
+	ActionWebhookDisabled     = "webhook.disabled"
+	ActionWebhookReplayed     = "webhook.delivery_replayed"
+	ActionEventsListed        = "audit.events_listed"
+	ActionChainVerified       = "audit.chain_verified"
+)
+
+// Resource types recorded in the audit log
+const (
+	ResourceUser        = "user"
+	ResourceKYC         = "kyc_submission"
+	ResourceSession     = "session"
+	ResourceAccount     = "account"
+	ResourceTransfer    = "transfer"
+	ResourceReview      = "transfer_review"
+	ResourcePaymentFile = "payment_file"
+	ResourcePayee       = "payee"
+	ResourceLimit       = "transfer_limit"
+	ResourceWebhook     = "webhook"
+	ResourceAudit       = "audit"
+)
+
+// Event describes a change to be appended to the audit log. The client IP and
+// user agent are taken from the context, see WithClient.
+type Event struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	// Diff is marshalled to JSON. It should describe the change without
+	// secrets such as passwords or tokens.
+	Diff any
+}
+
+// Record appends event to the audit log. q should belong to the transaction
+// making the audited change so both commit or roll back together.
+func Record(ctx context.Context, q db.Querier, event Event) (db.AuditEvent, error) {
+	diff, err := marshalDiff(event.Diff)
+	if err != nil {
+		return db.AuditEvent{}, err
+	}
+
+	// serialise writers so that every event sees the hash of its predecessor
+	if err := q.LockAuditChain(ctx); err != nil {
+		return db.AuditEvent{}, fmt.Errorf("cannot lock audit chain: %w", err)
+	}
+
+	var prevHash string
+	last, err := q.GetLastAuditEvent(ctx)
+	switch {
+	case err == nil:
+		prevHash = last.Hash
+	case !errors.Is(err, sql.ErrNoRows):
+		return db.AuditEvent{}, fmt.Errorf("cannot get last audit event: %w", err)
+	}
+
+	client := ClientFromContext(ctx)
+	arg := db.CreateAuditEventParams{
+		Actor:        event.Actor,
+		Action:       event.Action,
+		ResourceType: event.ResourceType,
+		ResourceID:   event.ResourceID,
+		ClientIp:     client.IP,
+		UserAgent:    client.UserAgent,
+		Diff:         diff,
+		PrevHash:     prevHash,
+		// postgres keeps microseconds, truncate so the stored row hashes the same
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	arg.Hash, err = Hash(db.AuditEvent{
+		Actor:        arg.Actor,
+		Action:       arg.Action,
+		ResourceType: arg.ResourceType,
+		ResourceID:   arg.ResourceID,
+		ClientIp:     arg.ClientIp,
+		UserAgent:    arg.UserAgent,
+		Diff:         arg.Diff,
+		PrevHash:     arg.PrevHash,
+		CreatedAt:    arg.CreatedAt,
+	})
+	if err != nil {
+		return db.AuditEvent{}, err
+	}
+
+	auditEvent, err := q.CreateAuditEvent(ctx, arg)
+	if err != nil {
+		return db.AuditEvent{}, fmt.Errorf("cannot create audit event: %w", err)
+	}
+
+	return auditEvent, nil
+}
+
+// hashInput fixes the field order of the hashed document
+type hashInput struct {
+	PrevHash     string          `json:"prev_hash"`
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	ClientIP     string          `json:"client_ip"`
+	UserAgent    string          `json:"user_agent"`
+	Diff         json.RawMessage `json:"diff"`
+	CreatedAt    string          `json:"created_at"`
+}
+
+// Hash computes the chain hash of event from its predecessor hash and fields.
+// The id and stored hash of event are ignored.
+func Hash(event db.AuditEvent) (string, error) {
+	diff, err := canonicalJSON(event.Diff)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(hashInput{
+		PrevHash:     event.PrevHash,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		ResourceType: event.ResourceType,
+		ResourceID:   event.ResourceID,
+		ClientIP:     event.ClientIp,
+		UserAgent:    event.UserAgent,
+		Diff:         diff,
+		CreatedAt:    event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot encode audit event: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func marshalDiff(diff any) (json.RawMessage, error) {
+	if diff == nil {
+		return json.RawMessage("{}"), nil
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode audit diff: %w", err)
+	}
+
+	return canonicalJSON(data)
+}
+
+// canonicalJSON re-encodes data with sorted keys and no insignificant
+// whitespace. jsonb does not preserve either, so the hash is always taken
+// over this form.
+func canonicalJSON(data json.RawMessage) (json.RawMessage, error) {
+	if len(data) == 0 {
+		return json.RawMessage("{}"), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("cannot decode audit diff: %w", err)
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode audit diff: %w", err)
+	}
+
+	return canonical, nil
+}
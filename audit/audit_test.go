@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+// memoryChain backs the audit queries of a mock store with a slice
+func memoryChain(store *mockdb.MockStore) *[]db.AuditEvent {
+	var events []db.AuditEvent
+
+	store.EXPECT().LockAuditChain(gomock.Any()).AnyTimes().Return(nil)
+	store.EXPECT().
+		GetLastAuditEvent(gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(context.Context) (db.AuditEvent, error) {
+			if len(events) == 0 {
+				return db.AuditEvent{}, sql.ErrNoRows
+			}
+			return events[len(events)-1], nil
+		})
+	store.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+			event := db.AuditEvent{
+				ID:           int64(len(events) + 1),
+				Actor:        arg.Actor,
+				Action:       arg.Action,
+				ResourceType: arg.ResourceType,
+				ResourceID:   arg.ResourceID,
+				ClientIp:     arg.ClientIp,
+				UserAgent:    arg.UserAgent,
+				Diff:         arg.Diff,
+				PrevHash:     arg.PrevHash,
+				Hash:         arg.Hash,
+				CreatedAt:    arg.CreatedAt,
+			}
+			events = append(events, event)
+			return event, nil
+		})
+	store.EXPECT().
+		ListAuditEventsAfter(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.ListAuditEventsAfterParams) ([]db.AuditEvent, error) {
+			page := []db.AuditEvent{}
+			for _, event := range events {
+				if event.ID > arg.AfterID && len(page) < int(arg.Limit) {
+					page = append(page, event)
+				}
+			}
+			return page, nil
+		})
+
+	return &events
+}
+
+func recordRandomEvents(t *testing.T, store db.Querier, n int) {
+	ctx := WithClient(context.Background(), Client{IP: "10.0.0.1", UserAgent: "test"})
+
+	for i := 0; i < n; i++ {
+		_, err := Record(ctx, store, Event{
+			Actor:        util.RandomOwner(),
+			Action:       ActionTransferCreated,
+			ResourceType: ResourceTransfer,
+			ResourceID:   util.RandomString(6),
+			Diff:         map[string]any{"amount": util.RandomMoney(), "currency": util.RandomCurrency()},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestRecordChainsEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	events := memoryChain(store)
+
+	recordRandomEvents(t, store, 3)
+
+	require.Len(t, *events, 3)
+	require.Empty(t, (*events)[0].PrevHash)
+	require.Equal(t, "10.0.0.1", (*events)[0].ClientIp)
+	require.Equal(t, "test", (*events)[0].UserAgent)
+	for i := 1; i < len(*events); i++ {
+		require.Equal(t, (*events)[i-1].Hash, (*events)[i].PrevHash)
+		require.NotEqual(t, (*events)[i-1].Hash, (*events)[i].Hash)
+	}
+}
+
+func TestHashIgnoresJSONFormatting(t *testing.T) {
+	event := db.AuditEvent{
+		Actor:     util.RandomOwner(),
+		Action:    ActionAccountCreated,
+		Diff:      json.RawMessage(`{"b": 1, "a": {"y": 2.50, "x": "z"}}`),
+		CreatedAt: time.Now().Truncate(time.Microsecond),
+	}
+
+	hash1, err := Hash(event)
+	require.NoError(t, err)
+
+	// jsonb reorders keys and drops whitespace, and times come back in another zone
+	event.Diff = json.RawMessage(`{"a":{"x":"z","y":2.50},"b":1}`)
+	event.CreatedAt = event.CreatedAt.In(time.FixedZone("EAT", 3*60*60))
+	hash2, err := Hash(event)
+	require.NoError(t, err)
+	require.Equal(t, hash1, hash2)
+
+	event.Diff = json.RawMessage(`{"a":{"x":"z","y":2.50},"b":2}`)
+	hash3, err := Hash(event)
+	require.NoError(t, err)
+	require.NotEqual(t, hash1, hash3)
+}
+
+func TestVerify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tamper   func(events []db.AuditEvent)
+		valid    bool
+		brokenAt int64
+	}{
+		{
+			name:   "Intact",
+			tamper: func(events []db.AuditEvent) {},
+			valid:  true,
+		},
+		{
+			name: "EditedDiff",
+			tamper: func(events []db.AuditEvent) {
+				events[4].Diff = json.RawMessage(`{"amount":1}`)
+			},
+			brokenAt: 5,
+		},
+		{
+			name: "DeletedEvent",
+			tamper: func(events []db.AuditEvent) {
+				copy(events[2:], events[3:])
+				events[len(events)-1] = db.AuditEvent{ID: 1 << 40}
+			},
+			brokenAt: 4,
+		},
+		{
+			name: "RehashedEvent",
+			tamper: func(events []db.AuditEvent) {
+				events[1].Actor = "mallory"
+				events[1].Hash, _ = Hash(events[1])
+			},
+			brokenAt: 3,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			events := memoryChain(store)
+
+			// more events than fit in one page
+			recordRandomEvents(t, store, verifyPageSize+10)
+			tc.tamper(*events)
+
+			result, err := Verify(context.Background(), store)
+			require.NoError(t, err)
+			require.Equal(t, tc.valid, result.Valid)
+			require.Equal(t, tc.brokenAt, result.BrokenAt)
+			if tc.valid {
+				require.Equal(t, int64(verifyPageSize+10), result.Checked)
+			} else {
+				require.NotEmpty(t, result.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package audit
+
+import "context"
+
+// Client identifies where a request came from
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx carrying client
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client stored in ctx, or an empty client
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+	return client
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+const verifyPageSize = 500
+
+// VerifyResult reports the outcome of walking the audit chain
+type VerifyResult struct {
+	Valid   bool  `json:"valid"`
+	Checked int64 `json:"checked"`
+	LastID  int64 `json:"last_id"`
+	// BrokenAt is the id of the first event whose hash does not match
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Verify recomputes the hash of every event in order and checks that each
+// one links to its predecessor
+func Verify(ctx context.Context, q db.Querier) (VerifyResult, error) {
+	result := VerifyResult{Valid: true}
+	var prevHash string
+
+	for {
+		events, err := q.ListAuditEventsAfter(ctx, db.ListAuditEventsAfterParams{
+			AfterID: result.LastID,
+			Limit:   verifyPageSize,
+		})
+		if err != nil {
+			return result, fmt.Errorf("cannot list audit events: %w", err)
+		}
+
+		for _, event := range events {
+			if reason, err := checkEvent(event, prevHash); err != nil {
+				return result, err
+			} else if reason != "" {
+				result.Valid = false
+				result.BrokenAt = event.ID
+				result.Reason = reason
+				return result, nil
+			}
+
+			prevHash = event.Hash
+			result.LastID = event.ID
+			result.Checked++
+		}
+
+		if len(events) < verifyPageSize {
+			return result, nil
+		}
+	}
+}
+
+func checkEvent(event db.AuditEvent, prevHash string) (string, error) {
+	if event.PrevHash != prevHash {
+		return "prev_hash does not match the previous event", nil
+	}
+
+	hash, err := Hash(event)
+	if err != nil {
+		return "", err
+	}
+
+	if hash != event.Hash {
+		return "hash does not match the event content", nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,92 @@
+// Package crypt encrypts personal data before it is stored, so that a copy
+// of the database alone does not reveal it. Each record is sealed under a
+// data key of its own, which a master key wraps, see Envelope.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size in bytes of an AES-256 key
+const KeySize = 32
+
+// version is the first byte of every sealed value, so that the format can
+// change without losing what was sealed before
+const version byte = 1
+
+// ErrDecrypt is returned when a sealed value was altered, sealed with
+// another key or for other associated data
+var ErrDecrypt = errors.New("cannot decrypt value")
+
+// Cipher seals values with AES-256-GCM under a single key
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a KeySize byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// ParseKey decodes a base64 encoded key
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	return key, nil
+}
+
+// Seal encrypts plaintext with a random nonce. Associated data is not
+// stored but must be given again to Open, which binds the value to the
+// record and column it belongs to.
+func (c *Cipher) Seal(plaintext []byte, associatedData []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	sealed := make([]byte, 1+nonceSize, 1+nonceSize+len(plaintext)+c.aead.Overhead())
+	sealed[0] = version
+
+	nonce := sealed[1:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	return c.aead.Seal(sealed, nonce, plaintext, associatedData), nil
+}
+
+// Open decrypts a value sealed with the same key and associated data
+func (c *Cipher) Open(sealed []byte, associatedData []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < 1+nonceSize || sealed[0] != version {
+		return nil, ErrDecrypt
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[1:1+nonceSize], sealed[1+nonceSize:], associatedData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,62 @@
+package crypt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomCipher(t *testing.T) *Cipher {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	c, err := NewCipher(key)
+	require.NoError(t, err)
+	return c
+}
+
+func TestSealOpen(t *testing.T) {
+	c := randomCipher(t)
+	aad := []byte("kyc_submissions.legal_name:alice")
+
+	sealed, err := c.Seal([]byte("Alice Example"), aad)
+	require.NoError(t, err)
+	require.NotContains(t, string(sealed), "Alice")
+
+	again, err := c.Seal([]byte("Alice Example"), aad)
+	require.NoError(t, err)
+	require.NotEqual(t, sealed, again)
+
+	plaintext, err := c.Open(sealed, aad)
+	require.NoError(t, err)
+	require.Equal(t, "Alice Example", string(plaintext))
+
+	_, err = c.Open(sealed, []byte("kyc_submissions.legal_name:bob"))
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = randomCipher(t).Open(sealed, aad)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = c.Open(sealed, aad)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = c.Open([]byte{version}, aad)
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestParseKey(t *testing.T) {
+	key := make([]byte, KeySize)
+	parsed, err := ParseKey(base64.StdEncoding.EncodeToString(key))
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString(key[:16]))
+	require.Error(t, err)
+
+	_, err = ParseKey("not base64!")
+	require.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package crypt
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// dataKeyAssociatedData is bound to every wrapped data key, so that no other
+// value sealed under a master key can pass for one
+var dataKeyAssociatedData = []byte("data_key")
+
+// Envelope encrypts every record under a data key of its own, which is
+// stored next to the record wrapped by the active master key. Rotating the
+// master key only rewraps data keys, the records themselves stay as they are.
+type Envelope struct {
+	keyring  *Keyring
+	indexKey []byte
+}
+
+// NewEnvelope creates an envelope over the master keys of keyring. indexKey
+// computes blind indexes and must not change once values are indexed.
+func NewEnvelope(keyring *Keyring, indexKey []byte) (*Envelope, error) {
+	if len(indexKey) != KeySize {
+		return nil, fmt.Errorf("blind index key must be %d bytes, got %d", KeySize, len(indexKey))
+	}
+
+	return &Envelope{keyring: keyring, indexKey: indexKey}, nil
+}
+
+// Keyring returns the master keys of the envelope
+func (envelope *Envelope) Keyring() *Keyring {
+	return envelope.keyring
+}
+
+// DataKey encrypts the personal data of one record
+type DataKey struct {
+	key    []byte
+	cipher *Cipher
+	// Wrapped is the key sealed by the master key MasterKeyID, to be stored
+	// with the record
+	Wrapped     []byte
+	MasterKeyID string
+}
+
+// NewDataKey generates a data key wrapped by the active master key
+func (envelope *Envelope) NewDataKey() (DataKey, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return DataKey{}, fmt.Errorf("cannot generate data key: %w", err)
+	}
+
+	return envelope.wrap(key)
+}
+
+// UnwrapDataKey recovers the data key of a record from its wrapped form
+func (envelope *Envelope) UnwrapDataKey(wrapped []byte, masterKeyID string) (DataKey, error) {
+	master, err := envelope.keyring.cipher(masterKeyID)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	key, err := master.Open(wrapped, dataKeyAssociatedData)
+	if err != nil {
+		return DataKey{}, fmt.Errorf("cannot unwrap data key: %w", err)
+	}
+
+	c, err := NewCipher(key)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	return DataKey{key: key, cipher: c, Wrapped: wrapped, MasterKeyID: masterKeyID}, nil
+}
+
+// Rewrap wraps dataKey again by the active master key. Values sealed with
+// it can still be opened.
+func (envelope *Envelope) Rewrap(dataKey DataKey) (DataKey, error) {
+	return envelope.wrap(dataKey.key)
+}
+
+func (envelope *Envelope) wrap(key []byte) (DataKey, error) {
+	c, err := NewCipher(key)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	activeID := envelope.keyring.ActiveID()
+	master, err := envelope.keyring.cipher(activeID)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	wrapped, err := master.Seal(key, dataKeyAssociatedData)
+	if err != nil {
+		return DataKey{}, fmt.Errorf("cannot wrap data key: %w", err)
+	}
+
+	return DataKey{key: key, cipher: c, Wrapped: wrapped, MasterKeyID: activeID}, nil
+}
+
+// Seal encrypts a value of the record, see Cipher.Seal
+func (dataKey DataKey) Seal(plaintext []byte, associatedData []byte) ([]byte, error) {
+	return dataKey.cipher.Seal(plaintext, associatedData)
+}
+
+// Open decrypts a value of the record, see Cipher.Open
+func (dataKey DataKey) Open(sealed []byte, associatedData []byte) ([]byte, error) {
+	return dataKey.cipher.Open(sealed, associatedData)
+}
+
+// BlindIndex returns a keyed hash of value that can be stored next to its
+// encrypted form to look it up or keep it unique without decrypting
+func (envelope *Envelope) BlindIndex(value string) []byte {
+	mac := hmac.New(sha256.New, envelope.indexKey)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
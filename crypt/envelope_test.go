@@ -0,0 +1,135 @@
+package crypt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomKey(t *testing.T) []byte {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func newTestEnvelope(t *testing.T, keys ...[]byte) *Envelope {
+	keyring, err := NewKeyring(keys...)
+	require.NoError(t, err)
+
+	envelope, err := NewEnvelope(keyring, make([]byte, KeySize))
+	require.NoError(t, err)
+	return envelope
+}
+
+func TestEnvelopeRotation(t *testing.T) {
+	oldKey, newKey := randomKey(t), randomKey(t)
+	aad := []byte("full_name:alice")
+
+	before := newTestEnvelope(t, oldKey)
+	dataKey, err := before.NewDataKey()
+	require.NoError(t, err)
+	require.Equal(t, KeyID(oldKey), dataKey.MasterKeyID)
+
+	sealed, err := dataKey.Seal([]byte("Alice Example"), aad)
+	require.NoError(t, err)
+
+	// after a rotation the old key still unwraps, new data keys use the new one
+	after := newTestEnvelope(t, newKey, oldKey)
+	unwrapped, err := after.UnwrapDataKey(dataKey.Wrapped, dataKey.MasterKeyID)
+	require.NoError(t, err)
+
+	rewrapped, err := after.Rewrap(unwrapped)
+	require.NoError(t, err)
+	require.Equal(t, KeyID(newKey), rewrapped.MasterKeyID)
+	require.NotEqual(t, dataKey.Wrapped, rewrapped.Wrapped)
+
+	// the record itself is untouched by the rewrap
+	retired := newTestEnvelope(t, newKey)
+	unwrapped, err = retired.UnwrapDataKey(rewrapped.Wrapped, rewrapped.MasterKeyID)
+	require.NoError(t, err)
+	plaintext, err := unwrapped.Open(sealed, aad)
+	require.NoError(t, err)
+	require.Equal(t, "Alice Example", string(plaintext))
+
+	_, err = retired.UnwrapDataKey(dataKey.Wrapped, dataKey.MasterKeyID)
+	require.Error(t, err)
+
+	// a value sealed under a master key is no data key
+	forged, err := NewCipher(newKey)
+	require.NoError(t, err)
+	wrapped, err := forged.Seal(randomKey(t), []byte("full_name:alice"))
+	require.NoError(t, err)
+	_, err = retired.UnwrapDataKey(wrapped, KeyID(newKey))
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestBlindIndex(t *testing.T) {
+	envelope := newTestEnvelope(t, randomKey(t))
+
+	index := envelope.BlindIndex("alice@example.com")
+	require.Len(t, index, 32)
+	require.Equal(t, index, envelope.BlindIndex("alice@example.com"))
+	require.NotEqual(t, index, envelope.BlindIndex("bob@example.com"))
+
+	other, err := NewEnvelope(envelope.Keyring(), randomKey(t))
+	require.NoError(t, err)
+	require.NotEqual(t, index, other.BlindIndex("alice@example.com"))
+
+	_, err = NewEnvelope(envelope.Keyring(), []byte("short"))
+	require.Error(t, err)
+}
+
+func TestKeyringOpenWithAnyKey(t *testing.T) {
+	oldKey, newKey := randomKey(t), randomKey(t)
+	aad := []byte("legal_name:alice")
+
+	c, err := NewCipher(oldKey)
+	require.NoError(t, err)
+	sealed, err := c.Seal([]byte("Alice Example"), aad)
+	require.NoError(t, err)
+
+	keyring, err := NewKeyring(newKey, oldKey)
+	require.NoError(t, err)
+	require.Equal(t, KeyID(newKey), keyring.ActiveID())
+
+	plaintext, err := keyring.OpenWithAnyKey(sealed, aad)
+	require.NoError(t, err)
+	require.Equal(t, "Alice Example", string(plaintext))
+
+	keyring, err = NewKeyring(newKey)
+	require.NoError(t, err)
+	_, err = keyring.OpenWithAnyKey(sealed, aad)
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = NewKeyring()
+	require.Error(t, err)
+}
+
+func TestLoadKeys(t *testing.T) {
+	first, second := randomKey(t), randomKey(t)
+	encoded := []string{base64.StdEncoding.EncodeToString(first), base64.StdEncoding.EncodeToString(second)}
+
+	keys, err := ParseKeys(encoded[0] + ", " + encoded[1])
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{first, second}, keys)
+
+	_, err = ParseKeys(encoded[0] + ",")
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "keys")
+	content := "# active key first\n" + encoded[0] + "\n\n" + encoded[1] + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	keys, err = LoadKeyFile(path)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{first, second}, keys)
+
+	require.NoError(t, os.WriteFile(path, []byte(encoded[0]+"\nnot a key\n"), 0o600))
+	_, err = LoadKeyFile(path)
+	require.ErrorContains(t, err, ":2:")
+}
@@ -0,0 +1,118 @@
+package crypt
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Keyring holds the master keys that wrap data keys. The first key is
+// active and wraps every new data key, the others only unwrap data keys
+// that were wrapped before a rotation.
+type Keyring struct {
+	activeID string
+	ciphers  map[string]*Cipher
+}
+
+// NewKeyring creates a keyring of keys, the first one active
+func NewKeyring(keys ...[]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring needs at least one master key")
+	}
+
+	keyring := &Keyring{
+		activeID: KeyID(keys[0]),
+		ciphers:  make(map[string]*Cipher, len(keys)),
+	}
+	for _, key := range keys {
+		c, err := NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		keyring.ciphers[KeyID(key)] = c
+	}
+
+	return keyring, nil
+}
+
+// ParseKeys decodes a comma separated list of base64 encoded keys
+func ParseKeys(encoded string) ([][]byte, error) {
+	var keys [][]byte
+	for _, field := range strings.Split(encoded, ",") {
+		key, err := ParseKey(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// LoadKeyFile reads one base64 encoded key per line from path. Blank lines
+// and lines starting with # are skipped.
+func LoadKeyFile(path string) ([][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open key file: %w", err)
+	}
+	defer file.Close()
+
+	var keys [][]byte
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, err := ParseKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read key file: %w", err)
+	}
+
+	return keys, nil
+}
+
+// KeyID names a key without revealing it, so that records can tell which
+// master key wrapped their data key
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ActiveID returns the id of the key that wraps new data keys
+func (keyring *Keyring) ActiveID() string {
+	return keyring.activeID
+}
+
+// cipher returns the master key with id
+func (keyring *Keyring) cipher(id string) (*Cipher, error) {
+	c, ok := keyring.ciphers[id]
+	if !ok {
+		return nil, fmt.Errorf("master key %q is not in the keyring", id)
+	}
+
+	return c, nil
+}
+
+// OpenWithAnyKey decrypts a value sealed directly under one of the master
+// keys, as personal data was before it had data keys of its own
+func (keyring *Keyring) OpenWithAnyKey(sealed []byte, associatedData []byte) ([]byte, error) {
+	for _, c := range keyring.ciphers {
+		if plaintext, err := c.Open(sealed, associatedData); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, ErrDecrypt
+}
@@ -0,0 +1,35 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "resource_type" varchar NOT NULL,
+  "resource_id" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "diff" jsonb NOT NULL DEFAULT '{}',
+  "prev_hash" varchar NOT NULL,
+  "hash" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "audit_events" ("actor");
+
+CREATE INDEX ON "audit_events" ("action");
+
+CREATE INDEX ON "audit_events" ("resource_type", "resource_id");
+
+CREATE INDEX ON "audit_events" ("created_at");
+
+COMMENT ON COLUMN "audit_events"."hash" IS 'sha256 of prev_hash and the event fields';
+
+CREATE FUNCTION "reject_audit_event_change"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_events_append_only"
+BEFORE UPDATE OR DELETE ON "audit_events"
+FOR EACH ROW EXECUTE FUNCTION "reject_audit_event_change"();
@@ -0,0 +1,19 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "outbox" ("status", "next_attempt_at");
+
+CREATE INDEX ON "outbox" ("aggregate_type", "aggregate_id", "id");
+
+COMMENT ON COLUMN "outbox"."status" IS 'pending, published or dead';
@@ -0,0 +1,36 @@
+CREATE TABLE "webhook_subscriptions" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL,
+  "event_id" uuid NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "response_status" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_subscriptions" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id");
+
+CREATE INDEX ON "webhook_subscriptions" ("owner");
+
+CREATE INDEX ON "webhook_deliveries" ("subscription_id", "id");
+
+CREATE INDEX ON "webhook_deliveries" ("status", "next_attempt_at");
+
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, succeeded or failed';
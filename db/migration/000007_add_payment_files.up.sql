@@ -0,0 +1,14 @@
+CREATE TABLE "payment_files" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "message_id" varchar NOT NULL,
+  "number_of_payments" int NOT NULL,
+  "control_sum" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "payment_files" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+CREATE UNIQUE INDEX ON "payment_files" ("owner", "message_id");
+
+COMMENT ON COLUMN "payment_files"."message_id" IS 'GrpHdr/MsgId of the pain.001 message, unique per owner';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "postings";
+DROP TABLE IF EXISTS "journal_entries";
+DROP TABLE IF EXISTS "ledger_accounts";
+DROP FUNCTION IF EXISTS "check_journal_entry_balanced";
+DROP FUNCTION IF EXISTS "reject_ledger_change";
@@ -0,0 +1,110 @@
+CREATE TABLE "ledger_accounts" (
+  "id" bigserial PRIMARY KEY,
+  "code" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "account_id" bigint UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "journal_entries" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "description" varchar NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "postings" (
+  "id" bigserial PRIMARY KEY,
+  "journal_entry_id" bigint NOT NULL,
+  "ledger_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "ledger_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "journal_entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("journal_entry_id") REFERENCES "journal_entries" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("ledger_account_id") REFERENCES "ledger_accounts" ("id");
+
+CREATE UNIQUE INDEX "ledger_accounts_system_code" ON "ledger_accounts" ("code", "currency") WHERE "account_id" IS NULL;
+
+CREATE INDEX ON "journal_entries" ("transfer_id");
+
+CREATE INDEX ON "postings" ("journal_entry_id");
+
+CREATE INDEX ON "postings" ("ledger_account_id", "created_at");
+
+COMMENT ON COLUMN "ledger_accounts"."code" IS 'chart of accounts code, shared by all customer accounts';
+
+COMMENT ON COLUMN "ledger_accounts"."type" IS 'asset, liability, equity, income or expense';
+
+COMMENT ON COLUMN "ledger_accounts"."account_id" IS 'customer account mirrored by this ledger account';
+
+COMMENT ON COLUMN "postings"."amount" IS 'positive for a debit, negative for a credit';
+
+CREATE FUNCTION "check_journal_entry_balanced"() RETURNS trigger AS $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "postings"
+    WHERE "journal_entry_id" = NEW."journal_entry_id"
+    GROUP BY "currency"
+    HAVING SUM("amount") <> 0
+  ) THEN
+    RAISE EXCEPTION 'journal entry % does not balance', NEW."journal_entry_id";
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER "postings_balanced"
+AFTER INSERT ON "postings"
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION "check_journal_entry_balanced"();
+
+CREATE FUNCTION "reject_ledger_change"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "journal_entries_append_only"
+BEFORE UPDATE OR DELETE ON "journal_entries"
+FOR EACH ROW EXECUTE FUNCTION "reject_ledger_change"();
+
+CREATE TRIGGER "postings_append_only"
+BEFORE UPDATE OR DELETE ON "postings"
+FOR EACH ROW EXECUTE FUNCTION "reject_ledger_change"();
+
+-- bring the balances held so far into the ledger against cash
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency", "account_id")
+SELECT '2000', 'Customer account ' || "id", 'liability', "currency", "id"
+FROM "accounts";
+
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency")
+SELECT DISTINCT '1000', 'Cash', 'asset', "currency"
+FROM "accounts";
+
+INSERT INTO "journal_entries" ("kind", "description")
+SELECT 'opening_balance', 'Balances held before the general ledger was introduced'
+WHERE EXISTS (SELECT 1 FROM "accounts" WHERE "balance" <> 0);
+
+INSERT INTO "postings" ("journal_entry_id", "ledger_account_id", "amount", "currency")
+SELECT j."id", l."id", -a."balance", a."currency"
+FROM "accounts" a
+JOIN "ledger_accounts" l ON l."account_id" = a."id"
+CROSS JOIN (SELECT MAX("id") AS "id" FROM "journal_entries" WHERE "kind" = 'opening_balance') j
+WHERE a."balance" <> 0
+UNION ALL
+SELECT j."id", l."id", SUM(a."balance"), a."currency"
+FROM "accounts" a
+JOIN "ledger_accounts" l ON l."code" = '1000' AND l."currency" = a."currency" AND l."account_id" IS NULL
+CROSS JOIN (SELECT MAX("id") AS "id" FROM "journal_entries" WHERE "kind" = 'opening_balance') j
+GROUP BY j."id", l."id", a."currency"
+HAVING SUM(a."balance") <> 0;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "interest_accruals";
+DROP TABLE IF EXISTS "interest_postings";
+DROP TABLE IF EXISTS "interest_rates";
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_product_key";
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "product_code";
+DROP TABLE IF EXISTS "products";
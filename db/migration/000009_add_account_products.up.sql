@@ -0,0 +1,77 @@
+CREATE TABLE "products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_rates" (
+  "id" bigserial PRIMARY KEY,
+  "product_code" varchar NOT NULL,
+  "annual_rate_bps" int NOT NULL,
+  "effective_from" date NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_postings" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "period" date NOT NULL,
+  "accrued" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "carried" bigint NOT NULL,
+  "journal_entry_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_accruals" (
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate_bps" int NOT NULL,
+  "accrued" bigint NOT NULL,
+  "posting_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "accrual_date")
+);
+
+INSERT INTO "products" ("code", "name") VALUES
+  ('checking', 'Checking account'),
+  ('savings', 'Savings account');
+
+INSERT INTO "interest_rates" ("product_code", "annual_rate_bps", "effective_from") VALUES
+  ('checking', 0, '2000-01-01'),
+  ('savings', 200, '2000-01-01');
+
+ALTER TABLE "accounts" ADD COLUMN "product_code" varchar NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product_code") REFERENCES "products" ("code");
+
+ALTER TABLE "accounts" DROP CONSTRAINT "owner_currency_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_product_key" UNIQUE ("owner", "currency", "product_code");
+
+ALTER TABLE "interest_rates" ADD FOREIGN KEY ("product_code") REFERENCES "products" ("code");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("posting_id") REFERENCES "interest_postings" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("journal_entry_id") REFERENCES "journal_entries" ("id");
+
+CREATE UNIQUE INDEX ON "interest_rates" ("product_code", "effective_from");
+
+CREATE UNIQUE INDEX ON "interest_postings" ("account_id", "period");
+
+CREATE INDEX ON "interest_accruals" ("accrual_date") WHERE "posting_id" IS NULL;
+
+COMMENT ON COLUMN "interest_rates"."annual_rate_bps" IS 'annual rate in basis points, applies from effective_from until the next rate of the product';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'balance at the end of accrual_date';
+
+COMMENT ON COLUMN "interest_accruals"."accrued" IS 'balance * annual_rate_bps, in 1/3650000 of the minor unit (actual/365)';
+
+COMMENT ON COLUMN "interest_postings"."period" IS 'first day of the month posted';
+
+COMMENT ON COLUMN "interest_postings"."carried" IS 'accrued interest below one minor unit, carried into the next period';
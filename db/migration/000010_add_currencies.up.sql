@@ -0,0 +1,28 @@
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY,
+  "exponent" int NOT NULL,
+  "symbol" varchar NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "currencies_code_iso4217" CHECK ("code" ~ '^[A-Z]{3}$'),
+  CONSTRAINT "currencies_exponent_range" CHECK ("exponent" BETWEEN 0 AND 4)
+);
+
+INSERT INTO "currencies" ("code", "exponent", "symbol") VALUES
+  ('EUR', 2, '€'),
+  ('KES', 2, 'KSh'),
+  ('USD', 2, '$');
+
+-- keep whatever else accounts already hold, but closed to new business
+INSERT INTO "currencies" ("code", "exponent", "symbol", "enabled")
+SELECT DISTINCT "currency", 2, "currency", false
+FROM "accounts"
+WHERE "currency" NOT IN (SELECT "code" FROM "currencies");
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+
+COMMENT ON COLUMN "currencies"."exponent" IS 'number of minor-unit digits, amounts are stored in minor units';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'whether new accounts and transfers may use the currency';
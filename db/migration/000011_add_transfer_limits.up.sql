@@ -0,0 +1,36 @@
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "scope" varchar NOT NULL,
+  "subject" varchar NOT NULL DEFAULT '',
+  "currency" varchar NOT NULL,
+  "per_transfer" bigint,
+  "daily_amount" bigint,
+  "monthly_amount" bigint,
+  "daily_count" int,
+  "monthly_count" int,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "transfer_limits_scope" CHECK (
+    ("scope" = 'global' AND "subject" = '') OR
+    ("scope" IN ('product', 'user') AND "subject" <> '')
+  ),
+  CONSTRAINT "transfer_limits_positive" CHECK (
+    COALESCE("per_transfer", 0) >= 0 AND
+    COALESCE("daily_amount", 0) >= 0 AND
+    COALESCE("monthly_amount", 0) >= 0 AND
+    COALESCE("daily_count", 0) >= 0 AND
+    COALESCE("monthly_count", 0) >= 0
+  )
+);
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+CREATE UNIQUE INDEX ON "transfer_limits" ("scope", "subject", "currency");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+COMMENT ON COLUMN "transfer_limits"."scope" IS 'global, product or user; user overrides product, which overrides global';
+
+COMMENT ON COLUMN "transfer_limits"."subject" IS 'product code or username the limits apply to, empty for global';
+
+COMMENT ON COLUMN "transfer_limits"."per_transfer" IS 'in minor units, null falls through to the next scope';
@@ -0,0 +1,38 @@
+CREATE TABLE "transfer_reviews" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "hits" jsonb NOT NULL,
+  "reviewer" varchar,
+  "reason" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "decided_at" timestamptz,
+  CONSTRAINT "transfer_reviews_amount_positive" CHECK ("amount" > 0)
+);
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("reviewer") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfer_reviews" ("status", "id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "to_account_id");
+
+COMMENT ON COLUMN "transfer_reviews"."status" IS 'pending, approved or rejected';
+
+COMMENT ON COLUMN "transfer_reviews"."hits" IS 'rules that held the transfer, with their decision and reason';
+
+COMMENT ON COLUMN "transfer_reviews"."transfer_id" IS 'transfer made when the review was approved';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "user_screenings";
+DROP INDEX IF EXISTS "users_screening_list_version_idx";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "screened_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "screening_list_version";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "screening_status";
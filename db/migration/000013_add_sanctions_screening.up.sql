@@ -0,0 +1,28 @@
+ALTER TABLE "users" ADD COLUMN "screening_status" varchar NOT NULL DEFAULT 'unscreened';
+
+ALTER TABLE "users" ADD COLUMN "screening_list_version" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "users" ADD COLUMN "screened_at" timestamptz;
+
+CREATE TABLE "user_screenings" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "list_version" varchar NOT NULL,
+  "status" varchar NOT NULL,
+  "score" double precision NOT NULL,
+  "matched_uid" varchar NOT NULL DEFAULT '',
+  "matched_name" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "user_screenings" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE INDEX ON "user_screenings" ("username", "id");
+
+CREATE INDEX ON "users" ("screening_list_version");
+
+COMMENT ON COLUMN "users"."screening_status" IS 'unscreened, clear, flagged or blocked';
+
+COMMENT ON COLUMN "users"."screening_list_version" IS 'sha256 of the sanctions list the user was last screened against';
+
+COMMENT ON COLUMN "user_screenings"."score" IS 'similarity of the full name to the closest listed name, from 0 to 1';
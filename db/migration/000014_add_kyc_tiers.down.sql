@@ -0,0 +1,9 @@
+DELETE FROM "transfer_limits" WHERE "scope" = 'tier';
+ALTER TABLE "transfer_limits" DROP CONSTRAINT IF EXISTS "transfer_limits_scope";
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_scope" CHECK (
+  ("scope" = 'global' AND "subject" = '') OR
+  ("scope" IN ('product', 'user') AND "subject" <> '')
+);
+DROP TABLE IF EXISTS "kyc_submissions";
+ALTER TABLE "currencies" DROP COLUMN IF EXISTS "min_kyc_tier";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "kyc_tier";
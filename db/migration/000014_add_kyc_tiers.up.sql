@@ -0,0 +1,78 @@
+ALTER TABLE "users" ADD COLUMN "kyc_tier" varchar NOT NULL DEFAULT 'unverified';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_kyc_tier" CHECK ("kyc_tier" IN ('unverified', 'basic', 'full'));
+
+-- users who signed up before KYC tiers keep using the EUR and KES accounts
+-- they already hold, new users start unverified
+UPDATE "users" SET "kyc_tier" = 'basic';
+
+ALTER TABLE "currencies" ADD COLUMN "min_kyc_tier" varchar NOT NULL DEFAULT 'basic';
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currencies_min_kyc_tier" CHECK ("min_kyc_tier" IN ('unverified', 'basic', 'full'));
+
+UPDATE "currencies" SET "min_kyc_tier" = 'unverified' WHERE "code" = 'USD';
+
+CREATE TABLE "kyc_submissions" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "tier" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "document_type" varchar NOT NULL,
+  "country" varchar NOT NULL,
+  "legal_name" bytea NOT NULL,
+  "date_of_birth" bytea NOT NULL,
+  "document_number" bytea NOT NULL,
+  "address" bytea NOT NULL,
+  "reviewer" varchar,
+  "reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "decided_at" timestamptz,
+  CONSTRAINT "kyc_submissions_tier" CHECK ("tier" IN ('basic', 'full')),
+  CONSTRAINT "kyc_submissions_status" CHECK ("status" IN ('pending', 'approved', 'rejected'))
+);
+
+ALTER TABLE "kyc_submissions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "kyc_submissions" ADD FOREIGN KEY ("reviewer") REFERENCES "users" ("username");
+
+-- a user waits for one submission at a time
+CREATE UNIQUE INDEX ON "kyc_submissions" ("username") WHERE "status" = 'pending';
+
+CREATE INDEX ON "kyc_submissions" ("status", "id");
+
+ALTER TABLE "transfer_limits" DROP CONSTRAINT "transfer_limits_scope";
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_scope" CHECK (
+  ("scope" = 'global' AND "subject" = '') OR
+  ("scope" IN ('product', 'tier', 'user') AND "subject" <> '')
+);
+
+-- unverified users may move small amounts, basic ones more; full tier users
+-- are only bound by the limits of their product
+INSERT INTO "transfer_limits" ("scope", "subject", "currency", "per_transfer", "daily_amount", "monthly_amount")
+SELECT 'tier', "tier", "code", "per_transfer", "daily_amount", "monthly_amount"
+FROM (VALUES
+  ('unverified', 'USD', 50000, 100000, 300000),
+  ('basic', 'EUR', 500000, 1000000, 5000000),
+  ('basic', 'KES', 50000000, 100000000, 500000000),
+  ('basic', 'USD', 500000, 1000000, 5000000)
+) AS "tier_limits" ("tier", "currency", "per_transfer", "daily_amount", "monthly_amount")
+JOIN "currencies" ON "code" = "currency";
+
+COMMENT ON COLUMN "users"."kyc_tier" IS 'unverified, basic or full; raised when a banker approves a KYC submission';
+
+COMMENT ON COLUMN "currencies"."min_kyc_tier" IS 'lowest KYC tier allowed to open accounts and send transfers in the currency';
+
+COMMENT ON COLUMN "transfer_limits"."scope" IS 'global, product, tier or user; product overrides global, tier caps both and user overrides the others';
+
+COMMENT ON COLUMN "transfer_limits"."subject" IS 'product code, KYC tier or username the limits apply to, empty for global';
+
+COMMENT ON COLUMN "kyc_submissions"."tier" IS 'tier the user asks for';
+
+COMMENT ON COLUMN "kyc_submissions"."legal_name" IS 'encrypted, see package crypt';
+
+COMMENT ON COLUMN "kyc_submissions"."date_of_birth" IS 'encrypted, see package crypt';
+
+COMMENT ON COLUMN "kyc_submissions"."document_number" IS 'encrypted, see package crypt';
+
+COMMENT ON COLUMN "kyc_submissions"."address" IS 'encrypted, see package crypt';
@@ -0,0 +1,15 @@
+-- personal data cannot be decrypted here: roll back only before the rekey
+-- job encrypted anyone, encrypted users share an empty email that the
+-- unique constraint below rejects
+DROP INDEX IF EXISTS "kyc_submissions_master_key_id_idx";
+ALTER TABLE "kyc_submissions" DROP COLUMN IF EXISTS "master_key_id";
+ALTER TABLE "kyc_submissions" DROP COLUMN IF EXISTS "data_key";
+DROP INDEX IF EXISTS "users_master_key_id_idx";
+DROP INDEX IF EXISTS "users_email_index_idx";
+DROP INDEX IF EXISTS "users_email_key";
+ALTER TABLE "users" ADD CONSTRAINT "users_email_key" UNIQUE ("email");
+ALTER TABLE "users" DROP COLUMN IF EXISTS "master_key_id";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "data_key";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_index";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_sealed";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "full_name_sealed";
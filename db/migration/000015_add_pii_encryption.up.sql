@@ -0,0 +1,36 @@
+ALTER TABLE "users" ADD COLUMN "full_name_sealed" bytea;
+
+ALTER TABLE "users" ADD COLUMN "email_sealed" bytea;
+
+ALTER TABLE "users" ADD COLUMN "email_index" bytea;
+
+ALTER TABLE "users" ADD COLUMN "data_key" bytea;
+
+ALTER TABLE "users" ADD COLUMN "master_key_id" varchar NOT NULL DEFAULT '';
+
+-- encrypted users keep an empty email, uniqueness moves to the blind index
+ALTER TABLE "users" DROP CONSTRAINT "users_email_key";
+
+CREATE UNIQUE INDEX "users_email_key" ON "users" ("email") WHERE "email" <> '';
+
+CREATE UNIQUE INDEX ON "users" ("email_index");
+
+CREATE INDEX ON "users" ("master_key_id");
+
+ALTER TABLE "kyc_submissions" ADD COLUMN "data_key" bytea;
+
+ALTER TABLE "kyc_submissions" ADD COLUMN "master_key_id" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "kyc_submissions" ("master_key_id");
+
+COMMENT ON COLUMN "users"."full_name" IS 'empty once the user is encrypted into full_name_sealed';
+
+COMMENT ON COLUMN "users"."email" IS 'empty once the user is encrypted into email_sealed';
+
+COMMENT ON COLUMN "users"."email_index" IS 'HMAC of the email, see crypt.Envelope.BlindIndex';
+
+COMMENT ON COLUMN "users"."data_key" IS 'data key of the user wrapped by the master key master_key_id';
+
+COMMENT ON COLUMN "users"."master_key_id" IS 'empty while the user is stored in plaintext';
+
+COMMENT ON COLUMN "kyc_submissions"."master_key_id" IS 'empty while the identity details are sealed directly under a master key';
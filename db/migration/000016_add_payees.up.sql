@@ -0,0 +1,22 @@
+CREATE TABLE "payees" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "nickname" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "active_from" timestamptz NOT NULL,
+  "confirmed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "payees" ("owner", "account_id");
+
+CREATE UNIQUE INDEX ON "payees" ("owner", "nickname");
+
+COMMENT ON COLUMN "payees"."currency" IS 'currency of the account, which never changes';
+
+COMMENT ON COLUMN "payees"."active_from" IS 'transfers to the payee are allowed from then on, once the cooling-off period ends or the owner confirms it';
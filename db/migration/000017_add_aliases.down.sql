@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "alias_lookups";
+DROP INDEX IF EXISTS "users_phone_index_idx";
+DROP INDEX IF EXISTS "users_phone_key";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "phone_index";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "phone_sealed";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "phone";
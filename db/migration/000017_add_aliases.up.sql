@@ -0,0 +1,23 @@
+ALTER TABLE "users" ADD COLUMN "phone" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "users" ADD COLUMN "phone_sealed" bytea;
+
+ALTER TABLE "users" ADD COLUMN "phone_index" bytea;
+
+CREATE UNIQUE INDEX "users_phone_key" ON "users" ("phone") WHERE "phone" <> '';
+
+CREATE UNIQUE INDEX ON "users" ("phone_index");
+
+CREATE TABLE "alias_lookups" (
+  "username" varchar PRIMARY KEY,
+  "window_start" timestamptz NOT NULL DEFAULT (now()),
+  "lookups" int NOT NULL DEFAULT 1
+);
+
+ALTER TABLE "alias_lookups" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "users"."phone" IS 'E.164 number, empty if the user has none or once encrypted into phone_sealed';
+
+COMMENT ON COLUMN "users"."phone_index" IS 'HMAC of the phone number, see crypt.Envelope.BlindIndex';
+
+COMMENT ON TABLE "alias_lookups" IS 'recipients resolved by each user from an alias in the current rate limit window';
@@ -0,0 +1,3 @@
+ALTER TABLE "payees" DROP COLUMN IF EXISTS "account_number";
+DROP INDEX IF EXISTS "accounts_number_key";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "number";
@@ -0,0 +1,41 @@
+ALTER TABLE "accounts" ADD COLUMN "number" varchar;
+
+CREATE UNIQUE INDEX "accounts_number_key" ON "accounts" ("number");
+
+-- number the accounts opened so far like accountnumber.Generate, drawing
+-- again on the rare collision
+DO $$
+DECLARE
+  account_id bigint;
+  bban varchar;
+BEGIN
+  FOR account_id IN SELECT "id" FROM "accounts" WHERE "number" IS NULL ORDER BY "id" LOOP
+    LOOP
+      bban := lpad(floor(random() * 1e12)::bigint::text, 12, '0');
+      BEGIN
+        UPDATE "accounts"
+        SET "number" = 'SB' || lpad((98 - (bban || '281100')::numeric % 97)::text, 2, '0') || bban
+        WHERE "id" = account_id;
+        EXIT;
+      EXCEPTION WHEN unique_violation THEN
+        NULL;
+      END;
+    END LOOP;
+  END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "accounts" ALTER COLUMN "number" SET NOT NULL;
+
+ALTER TABLE "payees" ADD COLUMN "account_number" varchar;
+
+UPDATE "payees" p
+SET "account_number" = a."number"
+FROM "accounts" a
+WHERE a."id" = p."account_id";
+
+ALTER TABLE "payees" ALTER COLUMN "account_number" SET NOT NULL;
+
+COMMENT ON COLUMN "accounts"."number" IS 'public account number shown to customers instead of the id, see accountnumber.Validate';
+
+COMMENT ON COLUMN "payees"."account_number" IS 'public number of account_id, which never changes';
@@ -0,0 +1,5 @@
+COMMENT ON COLUMN "payment_files"."control_sum" IS NULL;
+
+ALTER TABLE "payment_files"
+  ALTER COLUMN "control_sum" TYPE bigint USING (round(coalesce("control_sum", 0) * 100)),
+  ALTER COLUMN "control_sum" SET NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE "payment_files"
+  ALTER COLUMN "control_sum" TYPE numeric USING ("control_sum" / 100.0),
+  ALTER COLUMN "control_sum" DROP NOT NULL;
+
+COMMENT ON COLUMN "payment_files"."control_sum" IS 'GrpHdr/CtrlSum of the pain.001 message as sent, null if the message had none';
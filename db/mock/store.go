@@ -12,6 +12,7 @@ package mockdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	db "github.com/techschool/simplebank/db/sqlc"
@@ -56,242 +57,1534 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// ClaimOutboxEvents mocks base method.
+func (m *MockStore) ClaimOutboxEvents(arg0 context.Context, arg1 db.ClaimOutboxEventsParams) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEvents indicates an expected call of ClaimOutboxEvents.
+func (mr *MockStoreMockRecorder) ClaimOutboxEvents(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEvents", reflect.TypeOf((*MockStore)(nil).ClaimOutboxEvents), arg0, arg1)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(arg0 context.Context, arg1 db.ClaimWebhookDeliveriesParams) ([]db.ClaimWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ClaimWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), arg0, arg1)
+}
+
+// ConfirmPayee mocks base method.
+func (m *MockStore) ConfirmPayee(arg0 context.Context, arg1 int64) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPayee", arg0, arg1)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmPayee indicates an expected call of ConfirmPayee.
+func (mr *MockStoreMockRecorder) ConfirmPayee(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPayee", reflect.TypeOf((*MockStore)(nil).ConfirmPayee), arg0, arg1)
+}
+
+// CountAliasLookup mocks base method.
+func (m *MockStore) CountAliasLookup(arg0 context.Context, arg1 db.CountAliasLookupParams) (db.AliasLookup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAliasLookup", arg0, arg1)
+	ret0, _ := ret[0].(db.AliasLookup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAliasLookup indicates an expected call of CountAliasLookup.
+func (mr *MockStoreMockRecorder) CountAliasLookup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAliasLookup", reflect.TypeOf((*MockStore)(nil).CountAliasLookup), arg0, arg1)
+}
+
+// CountTransfersBetweenAccounts mocks base method.
+func (m *MockStore) CountTransfersBetweenAccounts(arg0 context.Context, arg1 db.CountTransfersBetweenAccountsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransfersBetweenAccounts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransfersBetweenAccounts indicates an expected call of CountTransfersBetweenAccounts.
+func (mr *MockStoreMockRecorder) CountTransfersBetweenAccounts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransfersBetweenAccounts", reflect.TypeOf((*MockStore)(nil).CountTransfersBetweenAccounts), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockStore) CreateAuditEvent(arg0 context.Context, arg1 db.CreateAuditEventParams) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockStoreMockRecorder) CreateAuditEvent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockStore)(nil).CreateAuditEvent), arg0, arg1)
+}
+
+// CreateCustomerLedgerAccount mocks base method.
+func (m *MockStore) CreateCustomerLedgerAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomerLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCustomerLedgerAccount indicates an expected call of CreateCustomerLedgerAccount.
+func (mr *MockStoreMockRecorder) CreateCustomerLedgerAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomerLedgerAccount", reflect.TypeOf((*MockStore)(nil).CreateCustomerLedgerAccount), arg0, arg1)
+}
+
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+}
+
+// CreateInterestAccrual mocks base method.
+func (m *MockStore) CreateInterestAccrual(arg0 context.Context, arg1 db.CreateInterestAccrualParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestAccrual", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestAccrual indicates an expected call of CreateInterestAccrual.
+func (mr *MockStoreMockRecorder) CreateInterestAccrual(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestAccrual", reflect.TypeOf((*MockStore)(nil).CreateInterestAccrual), arg0, arg1)
+}
+
+// CreateInterestPosting mocks base method.
+func (m *MockStore) CreateInterestPosting(arg0 context.Context, arg1 db.CreateInterestPostingParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestPosting indicates an expected call of CreateInterestPosting.
+func (mr *MockStoreMockRecorder) CreateInterestPosting(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestPosting", reflect.TypeOf((*MockStore)(nil).CreateInterestPosting), arg0, arg1)
+}
+
+// CreateInterestRate mocks base method.
+func (m *MockStore) CreateInterestRate(arg0 context.Context, arg1 db.CreateInterestRateParams) (db.InterestRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestRate", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestRate indicates an expected call of CreateInterestRate.
+func (mr *MockStoreMockRecorder) CreateInterestRate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestRate", reflect.TypeOf((*MockStore)(nil).CreateInterestRate), arg0, arg1)
+}
+
+// CreateJournalEntry mocks base method.
+func (m *MockStore) CreateJournalEntry(arg0 context.Context, arg1 db.CreateJournalEntryParams) (db.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJournalEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJournalEntry indicates an expected call of CreateJournalEntry.
+func (mr *MockStoreMockRecorder) CreateJournalEntry(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJournalEntry", reflect.TypeOf((*MockStore)(nil).CreateJournalEntry), arg0, arg1)
+}
+
+// CreateKYCSubmission mocks base method.
+func (m *MockStore) CreateKYCSubmission(arg0 context.Context, arg1 db.CreateKYCSubmissionParams) (db.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKYCSubmission", arg0, arg1)
+	ret0, _ := ret[0].(db.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKYCSubmission indicates an expected call of CreateKYCSubmission.
+func (mr *MockStoreMockRecorder) CreateKYCSubmission(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKYCSubmission", reflect.TypeOf((*MockStore)(nil).CreateKYCSubmission), arg0, arg1)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(arg0 context.Context, arg1 db.CreateOutboxEventParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), arg0, arg1)
+}
+
+// CreatePayee mocks base method.
+func (m *MockStore) CreatePayee(arg0 context.Context, arg1 db.CreatePayeeParams) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayee", arg0, arg1)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayee indicates an expected call of CreatePayee.
+func (mr *MockStoreMockRecorder) CreatePayee(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayee", reflect.TypeOf((*MockStore)(nil).CreatePayee), arg0, arg1)
+}
+
+// CreatePaymentFile mocks base method.
+func (m *MockStore) CreatePaymentFile(arg0 context.Context, arg1 db.CreatePaymentFileParams) (db.PaymentFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentFile", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentFile indicates an expected call of CreatePaymentFile.
+func (mr *MockStoreMockRecorder) CreatePaymentFile(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentFile", reflect.TypeOf((*MockStore)(nil).CreatePaymentFile), arg0, arg1)
+}
+
+// CreatePosting mocks base method.
+func (m *MockStore) CreatePosting(arg0 context.Context, arg1 db.CreatePostingParams) (db.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePosting", arg0, arg1)
+	ret0, _ := ret[0].(db.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePosting indicates an expected call of CreatePosting.
+func (mr *MockStoreMockRecorder) CreatePosting(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePosting", reflect.TypeOf((*MockStore)(nil).CreatePosting), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateSystemLedgerAccount mocks base method.
+func (m *MockStore) CreateSystemLedgerAccount(arg0 context.Context, arg1 db.CreateSystemLedgerAccountParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSystemLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSystemLedgerAccount indicates an expected call of CreateSystemLedgerAccount.
+func (mr *MockStoreMockRecorder) CreateSystemLedgerAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSystemLedgerAccount", reflect.TypeOf((*MockStore)(nil).CreateSystemLedgerAccount), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateTransferReview mocks base method.
+func (m *MockStore) CreateTransferReview(arg0 context.Context, arg1 db.CreateTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReview indicates an expected call of CreateTransferReview.
+func (mr *MockStoreMockRecorder) CreateTransferReview(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReview", reflect.TypeOf((*MockStore)(nil).CreateTransferReview), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserScreening mocks base method.
+func (m *MockStore) CreateUserScreening(arg0 context.Context, arg1 db.CreateUserScreeningParams) (db.UserScreening, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserScreening", arg0, arg1)
+	ret0, _ := ret[0].(db.UserScreening)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserScreening indicates an expected call of CreateUserScreening.
+func (mr *MockStoreMockRecorder) CreateUserScreening(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserScreening", reflect.TypeOf((*MockStore)(nil).CreateUserScreening), arg0, arg1)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(arg0 context.Context, arg1 db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0, arg1)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockStore) CreateWebhookSubscription(arg0 context.Context, arg1 db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockStoreMockRecorder) CreateWebhookSubscription(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockStore)(nil).CreateWebhookSubscription), arg0, arg1)
+}
+
+// DecideKYCSubmission mocks base method.
+func (m *MockStore) DecideKYCSubmission(arg0 context.Context, arg1 db.DecideKYCSubmissionParams) (db.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideKYCSubmission", arg0, arg1)
+	ret0, _ := ret[0].(db.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideKYCSubmission indicates an expected call of DecideKYCSubmission.
+func (mr *MockStoreMockRecorder) DecideKYCSubmission(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideKYCSubmission", reflect.TypeOf((*MockStore)(nil).DecideKYCSubmission), arg0, arg1)
+}
+
+// DecideTransferReview mocks base method.
+func (m *MockStore) DecideTransferReview(arg0 context.Context, arg1 db.DecideTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferReview indicates an expected call of DecideTransferReview.
+func (mr *MockStoreMockRecorder) DecideTransferReview(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferReview", reflect.TypeOf((*MockStore)(nil).DecideTransferReview), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeletePayee mocks base method.
+func (m *MockStore) DeletePayee(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePayee", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePayee indicates an expected call of DeletePayee.
+func (mr *MockStoreMockRecorder) DeletePayee(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePayee", reflect.TypeOf((*MockStore)(nil).DeletePayee), arg0, arg1)
+}
+
+// DeleteSession mocks base method.
+func (m *MockStore) DeleteSession(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockStoreMockRecorder) DeleteSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStore)(nil).DeleteSession), arg0, arg1)
+}
+
+// DisableWebhookSubscription mocks base method.
+func (m *MockStore) DisableWebhookSubscription(arg0 context.Context, arg1 int64) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableWebhookSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableWebhookSubscription indicates an expected call of DisableWebhookSubscription.
+func (mr *MockStoreMockRecorder) DisableWebhookSubscription(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableWebhookSubscription", reflect.TypeOf((*MockStore)(nil).DisableWebhookSubscription), arg0, arg1)
+}
+
+// ExecTx mocks base method.
+func (m *MockStore) ExecTx(arg0 context.Context, arg1 db.TxOptions, arg2 func(db.Querier) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStoreMockRecorder) ExecTx(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), arg0, arg1, arg2)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountByNumber mocks base method.
+func (m *MockStore) GetAccountByNumber(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockStoreMockRecorder) GetAccountByNumber(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockStore)(nil).GetAccountByNumber), arg0, arg1)
+}
+
+// GetAccountByOwner mocks base method.
+func (m *MockStore) GetAccountByOwner(arg0 context.Context, arg1 db.GetAccountByOwnerParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByOwner", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByOwner indicates an expected call of GetAccountByOwner.
+func (mr *MockStoreMockRecorder) GetAccountByOwner(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByOwner", reflect.TypeOf((*MockStore)(nil).GetAccountByOwner), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetAliasLookup mocks base method.
+func (m *MockStore) GetAliasLookup(arg0 context.Context, arg1 string) (db.AliasLookup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAliasLookup", arg0, arg1)
+	ret0, _ := ret[0].(db.AliasLookup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAliasLookup indicates an expected call of GetAliasLookup.
+func (mr *MockStoreMockRecorder) GetAliasLookup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAliasLookup", reflect.TypeOf((*MockStore)(nil).GetAliasLookup), arg0, arg1)
+}
+
+// GetCustomerLedgerAccount mocks base method.
+func (m *MockStore) GetCustomerLedgerAccount(arg0 context.Context, arg1 int64) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerLedgerAccount indicates an expected call of GetCustomerLedgerAccount.
+func (mr *MockStoreMockRecorder) GetCustomerLedgerAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerLedgerAccount", reflect.TypeOf((*MockStore)(nil).GetCustomerLedgerAccount), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetKYCCapability mocks base method.
+func (m *MockStore) GetKYCCapability(arg0 context.Context, arg1 db.GetKYCCapabilityParams) (db.GetKYCCapabilityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKYCCapability", arg0, arg1)
+	ret0, _ := ret[0].(db.GetKYCCapabilityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKYCCapability indicates an expected call of GetKYCCapability.
+func (mr *MockStoreMockRecorder) GetKYCCapability(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKYCCapability", reflect.TypeOf((*MockStore)(nil).GetKYCCapability), arg0, arg1)
+}
+
+// GetKYCSubmission mocks base method.
+func (m *MockStore) GetKYCSubmission(arg0 context.Context, arg1 int64) (db.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKYCSubmission", arg0, arg1)
+	ret0, _ := ret[0].(db.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKYCSubmission indicates an expected call of GetKYCSubmission.
+func (mr *MockStoreMockRecorder) GetKYCSubmission(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKYCSubmission", reflect.TypeOf((*MockStore)(nil).GetKYCSubmission), arg0, arg1)
+}
+
+// GetLastAuditEvent mocks base method.
+func (m *MockStore) GetLastAuditEvent(arg0 context.Context) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastAuditEvent", arg0)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastAuditEvent indicates an expected call of GetLastAuditEvent.
+func (mr *MockStoreMockRecorder) GetLastAuditEvent(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAuditEvent", reflect.TypeOf((*MockStore)(nil).GetLastAuditEvent), arg0)
+}
+
+// GetLastInterestPosting mocks base method.
+func (m *MockStore) GetLastInterestPosting(arg0 context.Context, arg1 int64) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastInterestPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastInterestPosting indicates an expected call of GetLastInterestPosting.
+func (mr *MockStoreMockRecorder) GetLastInterestPosting(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastInterestPosting", reflect.TypeOf((*MockStore)(nil).GetLastInterestPosting), arg0, arg1)
+}
+
+// GetLatestKYCSubmission mocks base method.
+func (m *MockStore) GetLatestKYCSubmission(arg0 context.Context, arg1 string) (db.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestKYCSubmission", arg0, arg1)
+	ret0, _ := ret[0].(db.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestKYCSubmission indicates an expected call of GetLatestKYCSubmission.
+func (mr *MockStoreMockRecorder) GetLatestKYCSubmission(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestKYCSubmission", reflect.TypeOf((*MockStore)(nil).GetLatestKYCSubmission), arg0, arg1)
+}
+
+// GetLedgerAccount mocks base method.
+func (m *MockStore) GetLedgerAccount(arg0 context.Context, arg1 int64) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerAccount indicates an expected call of GetLedgerAccount.
+func (mr *MockStoreMockRecorder) GetLedgerAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerAccount", reflect.TypeOf((*MockStore)(nil).GetLedgerAccount), arg0, arg1)
+}
+
+// GetOutboxEvent mocks base method.
+func (m *MockStore) GetOutboxEvent(arg0 context.Context, arg1 int64) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxEvent indicates an expected call of GetOutboxEvent.
+func (mr *MockStoreMockRecorder) GetOutboxEvent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxEvent", reflect.TypeOf((*MockStore)(nil).GetOutboxEvent), arg0, arg1)
+}
+
+// GetPayee mocks base method.
+func (m *MockStore) GetPayee(arg0 context.Context, arg1 int64) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayee", arg0, arg1)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayee indicates an expected call of GetPayee.
+func (mr *MockStoreMockRecorder) GetPayee(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayee", reflect.TypeOf((*MockStore)(nil).GetPayee), arg0, arg1)
+}
+
+// GetPaymentFile mocks base method.
+func (m *MockStore) GetPaymentFile(arg0 context.Context, arg1 int64) (db.PaymentFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentFile", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentFile indicates an expected call of GetPaymentFile.
+func (mr *MockStoreMockRecorder) GetPaymentFile(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentFile", reflect.TypeOf((*MockStore)(nil).GetPaymentFile), arg0, arg1)
+}
+
+// GetProduct mocks base method.
+func (m *MockStore) GetProduct(arg0 context.Context, arg1 string) (db.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProduct", arg0, arg1)
+	ret0, _ := ret[0].(db.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProduct indicates an expected call of GetProduct.
+func (mr *MockStoreMockRecorder) GetProduct(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProduct", reflect.TypeOf((*MockStore)(nil).GetProduct), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetSystemLedgerAccount mocks base method.
+func (m *MockStore) GetSystemLedgerAccount(arg0 context.Context, arg1 db.GetSystemLedgerAccountParams) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSystemLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSystemLedgerAccount indicates an expected call of GetSystemLedgerAccount.
+func (mr *MockStoreMockRecorder) GetSystemLedgerAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemLedgerAccount", reflect.TypeOf((*MockStore)(nil).GetSystemLedgerAccount), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferReview mocks base method.
+func (m *MockStore) GetTransferReview(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReview indicates an expected call of GetTransferReview.
+func (mr *MockStoreMockRecorder) GetTransferReview(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReview", reflect.TypeOf((*MockStore)(nil).GetTransferReview), arg0, arg1)
+}
+
+// GetTransferUsage mocks base method.
+func (m *MockStore) GetTransferUsage(arg0 context.Context, arg1 db.GetTransferUsageParams) (db.GetTransferUsageRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferUsage", arg0, arg1)
+	ret0, _ := ret[0].(db.GetTransferUsageRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferUsage indicates an expected call of GetTransferUsage.
+func (mr *MockStoreMockRecorder) GetTransferUsage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferUsage", reflect.TypeOf((*MockStore)(nil).GetTransferUsage), arg0, arg1)
+}
+
+// GetTrialBalance mocks base method.
+func (m *MockStore) GetTrialBalance(arg0 context.Context, arg1 time.Time) ([]db.GetTrialBalanceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrialBalance", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetTrialBalanceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrialBalance indicates an expected call of GetTrialBalance.
+func (mr *MockStoreMockRecorder) GetTrialBalance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrialBalance", reflect.TypeOf((*MockStore)(nil).GetTrialBalance), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 db.GetUserByEmailParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserByPhone mocks base method.
+func (m *MockStore) GetUserByPhone(arg0 context.Context, arg1 db.GetUserByPhoneParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByPhone", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByPhone indicates an expected call of GetUserByPhone.
+func (mr *MockStoreMockRecorder) GetUserByPhone(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByPhone", reflect.TypeOf((*MockStore)(nil).GetUserByPhone), arg0, arg1)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0, arg1)
+}
+
+// GetWebhookSubscription mocks base method.
+func (m *MockStore) GetWebhookSubscription(arg0 context.Context, arg1 int64) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscription indicates an expected call of GetWebhookSubscription.
+func (mr *MockStoreMockRecorder) GetWebhookSubscription(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscription", reflect.TypeOf((*MockStore)(nil).GetWebhookSubscription), arg0, arg1)
+}
+
+// IsUserBlocked mocks base method.
+func (m *MockStore) IsUserBlocked(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUserBlocked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUserBlocked indicates an expected call of IsUserBlocked.
+func (mr *MockStoreMockRecorder) IsUserBlocked(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserBlocked", reflect.TypeOf((*MockStore)(nil).IsUserBlocked), arg0, arg1)
+}
+
+// ListAccountDrift mocks base method.
+func (m *MockStore) ListAccountDrift(arg0 context.Context) ([]db.ListAccountDriftRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountDrift", arg0)
+	ret0, _ := ret[0].([]db.ListAccountDriftRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountDrift indicates an expected call of ListAccountDrift.
+func (mr *MockStoreMockRecorder) ListAccountDrift(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountDrift", reflect.TypeOf((*MockStore)(nil).ListAccountDrift), arg0)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListAccrualCandidates mocks base method.
+func (m *MockStore) ListAccrualCandidates(arg0 context.Context, arg1 db.ListAccrualCandidatesParams) ([]db.ListAccrualCandidatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccrualCandidates", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAccrualCandidatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccrualCandidates indicates an expected call of ListAccrualCandidates.
+func (mr *MockStoreMockRecorder) ListAccrualCandidates(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccrualCandidates", reflect.TypeOf((*MockStore)(nil).ListAccrualCandidates), arg0, arg1)
+}
+
+// ListApplicableTransferLimits mocks base method.
+func (m *MockStore) ListApplicableTransferLimits(arg0 context.Context, arg1 db.ListApplicableTransferLimitsParams) ([]db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicableTransferLimits", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicableTransferLimits indicates an expected call of ListApplicableTransferLimits.
+func (mr *MockStoreMockRecorder) ListApplicableTransferLimits(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicableTransferLimits", reflect.TypeOf((*MockStore)(nil).ListApplicableTransferLimits), arg0, arg1)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockStore) ListAuditEvents(arg0 context.Context, arg1 db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockStoreMockRecorder) ListAuditEvents(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockStore)(nil).ListAuditEvents), arg0, arg1)
+}
+
+// ListAuditEventsAfter mocks base method.
+func (m *MockStore) ListAuditEventsAfter(arg0 context.Context, arg1 db.ListAuditEventsAfterParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEventsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEventsAfter indicates an expected call of ListAuditEventsAfter.
+func (mr *MockStoreMockRecorder) ListAuditEventsAfter(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEventsAfter", reflect.TypeOf((*MockStore)(nil).ListAuditEventsAfter), arg0, arg1)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockStore) ListCurrencies(arg0 context.Context) ([]db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", arg0)
+	ret0, _ := ret[0].([]db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockStoreMockRecorder) ListCurrencies(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockStore)(nil).ListCurrencies), arg0)
+}
+
+// ListCurrencyTotals mocks base method.
+func (m *MockStore) ListCurrencyTotals(arg0 context.Context) ([]db.ListCurrencyTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencyTotals", arg0)
+	ret0, _ := ret[0].([]db.ListCurrencyTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencyTotals indicates an expected call of ListCurrencyTotals.
+func (mr *MockStoreMockRecorder) ListCurrencyTotals(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencyTotals", reflect.TypeOf((*MockStore)(nil).ListCurrencyTotals), arg0)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListInterestAccruals mocks base method.
+func (m *MockStore) ListInterestAccruals(arg0 context.Context, arg1 db.ListInterestAccrualsParams) ([]db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInterestAccruals", arg0, arg1)
+	ret0, _ := ret[0].([]db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInterestAccruals indicates an expected call of ListInterestAccruals.
+func (mr *MockStoreMockRecorder) ListInterestAccruals(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInterestAccruals", reflect.TypeOf((*MockStore)(nil).ListInterestAccruals), arg0, arg1)
+}
+
+// ListInterestRates mocks base method.
+func (m *MockStore) ListInterestRates(arg0 context.Context, arg1 string) ([]db.InterestRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInterestRates", arg0, arg1)
+	ret0, _ := ret[0].([]db.InterestRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInterestRates indicates an expected call of ListInterestRates.
+func (mr *MockStoreMockRecorder) ListInterestRates(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInterestRates", reflect.TypeOf((*MockStore)(nil).ListInterestRates), arg0, arg1)
+}
+
+// ListKYCSubmissions mocks base method.
+func (m *MockStore) ListKYCSubmissions(arg0 context.Context, arg1 db.ListKYCSubmissionsParams) ([]db.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKYCSubmissions", arg0, arg1)
+	ret0, _ := ret[0].([]db.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKYCSubmissions indicates an expected call of ListKYCSubmissions.
+func (mr *MockStoreMockRecorder) ListKYCSubmissions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKYCSubmissions", reflect.TypeOf((*MockStore)(nil).ListKYCSubmissions), arg0, arg1)
+}
+
+// ListKYCSubmissionsToReencrypt mocks base method.
+func (m *MockStore) ListKYCSubmissionsToReencrypt(arg0 context.Context, arg1 db.ListKYCSubmissionsToReencryptParams) ([]db.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKYCSubmissionsToReencrypt", arg0, arg1)
+	ret0, _ := ret[0].([]db.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKYCSubmissionsToReencrypt indicates an expected call of ListKYCSubmissionsToReencrypt.
+func (mr *MockStoreMockRecorder) ListKYCSubmissionsToReencrypt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKYCSubmissionsToReencrypt", reflect.TypeOf((*MockStore)(nil).ListKYCSubmissionsToReencrypt), arg0, arg1)
+}
+
+// ListLedgerDrift mocks base method.
+func (m *MockStore) ListLedgerDrift(arg0 context.Context) ([]db.ListLedgerDriftRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerDrift", arg0)
+	ret0, _ := ret[0].([]db.ListLedgerDriftRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLedgerDrift indicates an expected call of ListLedgerDrift.
+func (mr *MockStoreMockRecorder) ListLedgerDrift(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerDrift", reflect.TypeOf((*MockStore)(nil).ListLedgerDrift), arg0)
+}
+
+// ListPayees mocks base method.
+func (m *MockStore) ListPayees(arg0 context.Context, arg1 db.ListPayeesParams) ([]db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayees", arg0, arg1)
+	ret0, _ := ret[0].([]db.Payee)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 any) *gomock.Call {
+// ListPayees indicates an expected call of ListPayees.
+func (mr *MockStoreMockRecorder) ListPayees(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayees", reflect.TypeOf((*MockStore)(nil).ListPayees), arg0, arg1)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+// ListPostings mocks base method.
+func (m *MockStore) ListPostings(arg0 context.Context, arg1 int64) ([]db.Posting, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "ListPostings", arg0, arg1)
+	ret0, _ := ret[0].([]db.Posting)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 any) *gomock.Call {
+// ListPostings indicates an expected call of ListPostings.
+func (mr *MockStoreMockRecorder) ListPostings(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostings", reflect.TypeOf((*MockStore)(nil).ListPostings), arg0, arg1)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+// ListProducts mocks base method.
+func (m *MockStore) ListProducts(arg0 context.Context) ([]db.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "ListProducts", arg0)
+	ret0, _ := ret[0].([]db.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 any) *gomock.Call {
+// ListProducts indicates an expected call of ListProducts.
+func (mr *MockStoreMockRecorder) ListProducts(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockStore)(nil).ListProducts), arg0)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+// ListTransferLimits mocks base method.
+func (m *MockStore) ListTransferLimits(arg0 context.Context) ([]db.TransferLimit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "ListTransferLimits", arg0)
+	ret0, _ := ret[0].([]db.TransferLimit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 any) *gomock.Call {
+// ListTransferLimits indicates an expected call of ListTransferLimits.
+func (mr *MockStoreMockRecorder) ListTransferLimits(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferLimits", reflect.TypeOf((*MockStore)(nil).ListTransferLimits), arg0)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// ListTransferReviews mocks base method.
+func (m *MockStore) ListTransferReviews(arg0 context.Context, arg1 db.ListTransferReviewsParams) ([]db.TransferReview, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ListTransferReviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferReview)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
+// ListTransferReviews indicates an expected call of ListTransferReviews.
+func (mr *MockStoreMockRecorder) ListTransferReviews(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferReviews", reflect.TypeOf((*MockStore)(nil).ListTransferReviews), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 any) *gomock.Call {
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
 }
 
-// DeleteSession mocks base method.
-func (m *MockStore) DeleteSession(arg0 context.Context, arg1 uuid.UUID) error {
+// ListTransfersFromAccountSince mocks base method.
+func (m *MockStore) ListTransfersFromAccountSince(arg0 context.Context, arg1 db.ListTransfersFromAccountSinceParams) ([]db.Transfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListTransfersFromAccountSince", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteSession indicates an expected call of DeleteSession.
-func (mr *MockStoreMockRecorder) DeleteSession(arg0, arg1 any) *gomock.Call {
+// ListTransfersFromAccountSince indicates an expected call of ListTransfersFromAccountSince.
+func (mr *MockStoreMockRecorder) ListTransfersFromAccountSince(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStore)(nil).DeleteSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersFromAccountSince", reflect.TypeOf((*MockStore)(nil).ListTransfersFromAccountSince), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+// ListUnbalancedTransfers mocks base method.
+func (m *MockStore) ListUnbalancedTransfers(arg0 context.Context) ([]db.ListUnbalancedTransfersRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ListUnbalancedTransfers", arg0)
+	ret0, _ := ret[0].([]db.ListUnbalancedTransfersRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+// ListUnbalancedTransfers indicates an expected call of ListUnbalancedTransfers.
+func (mr *MockStoreMockRecorder) ListUnbalancedTransfers(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnbalancedTransfers", reflect.TypeOf((*MockStore)(nil).ListUnbalancedTransfers), arg0)
 }
 
-// GetAccountForUpdate mocks base method.
-func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+// ListUnpostedInterest mocks base method.
+func (m *MockStore) ListUnpostedInterest(arg0 context.Context, arg1 time.Time) ([]db.ListUnpostedInterestRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ListUnpostedInterest", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUnpostedInterestRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 any) *gomock.Call {
+// ListUnpostedInterest indicates an expected call of ListUnpostedInterest.
+func (mr *MockStoreMockRecorder) ListUnpostedInterest(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpostedInterest", reflect.TypeOf((*MockStore)(nil).ListUnpostedInterest), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// ListUnpostedInterestAccrualsForUpdate mocks base method.
+func (m *MockStore) ListUnpostedInterestAccrualsForUpdate(arg0 context.Context, arg1 db.ListUnpostedInterestAccrualsForUpdateParams) ([]db.InterestAccrual, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "ListUnpostedInterestAccrualsForUpdate", arg0, arg1)
+	ret0, _ := ret[0].([]db.InterestAccrual)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 any) *gomock.Call {
+// ListUnpostedInterestAccrualsForUpdate indicates an expected call of ListUnpostedInterestAccrualsForUpdate.
+func (mr *MockStoreMockRecorder) ListUnpostedInterestAccrualsForUpdate(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpostedInterestAccrualsForUpdate", reflect.TypeOf((*MockStore)(nil).ListUnpostedInterestAccrualsForUpdate), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+// ListUserScreenings mocks base method.
+func (m *MockStore) ListUserScreenings(arg0 context.Context, arg1 db.ListUserScreeningsParams) ([]db.UserScreening, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "ListUserScreenings", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserScreening)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 any) *gomock.Call {
+// ListUserScreenings indicates an expected call of ListUserScreenings.
+func (mr *MockStoreMockRecorder) ListUserScreenings(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserScreenings", reflect.TypeOf((*MockStore)(nil).ListUserScreenings), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// ListUsersToReencrypt mocks base method.
+func (m *MockStore) ListUsersToReencrypt(arg0 context.Context, arg1 db.ListUsersToReencryptParams) ([]db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "ListUsersToReencrypt", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 any) *gomock.Call {
+// ListUsersToReencrypt indicates an expected call of ListUsersToReencrypt.
+func (mr *MockStoreMockRecorder) ListUsersToReencrypt(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersToReencrypt", reflect.TypeOf((*MockStore)(nil).ListUsersToReencrypt), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// ListUsersToScreen mocks base method.
+func (m *MockStore) ListUsersToScreen(arg0 context.Context, arg1 db.ListUsersToScreenParams) ([]db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ListUsersToScreen", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 any) *gomock.Call {
+// ListUsersToScreen indicates an expected call of ListUsersToScreen.
+func (mr *MockStoreMockRecorder) ListUsersToScreen(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersToScreen", reflect.TypeOf((*MockStore)(nil).ListUsersToScreen), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 any) *gomock.Call {
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// ListWebhookSubscriptions mocks base method.
+func (m *MockStore) ListWebhookSubscriptions(arg0 context.Context, arg1 string) ([]db.WebhookSubscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookSubscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 any) *gomock.Call {
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockStoreMockRecorder) ListWebhookSubscriptions(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockStore)(nil).ListWebhookSubscriptions), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// ListWebhookSubscriptionsForEvent mocks base method.
+func (m *MockStore) ListWebhookSubscriptionsForEvent(arg0 context.Context, arg1 db.ListWebhookSubscriptionsForEventParams) ([]db.WebhookSubscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptionsForEvent", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookSubscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 any) *gomock.Call {
+// ListWebhookSubscriptionsForEvent indicates an expected call of ListWebhookSubscriptionsForEvent.
+func (mr *MockStoreMockRecorder) ListWebhookSubscriptionsForEvent(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptionsForEvent", reflect.TypeOf((*MockStore)(nil).ListWebhookSubscriptionsForEvent), arg0, arg1)
+}
+
+// LockAuditChain mocks base method.
+func (m *MockStore) LockAuditChain(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAuditChain", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAuditChain indicates an expected call of LockAuditChain.
+func (mr *MockStoreMockRecorder) LockAuditChain(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAuditChain", reflect.TypeOf((*MockStore)(nil).LockAuditChain), arg0)
+}
+
+// MarkInterestAccrualsPosted mocks base method.
+func (m *MockStore) MarkInterestAccrualsPosted(arg0 context.Context, arg1 db.MarkInterestAccrualsPostedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInterestAccrualsPosted", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkInterestAccrualsPosted indicates an expected call of MarkInterestAccrualsPosted.
+func (mr *MockStoreMockRecorder) MarkInterestAccrualsPosted(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInterestAccrualsPosted", reflect.TypeOf((*MockStore)(nil).MarkInterestAccrualsPosted), arg0, arg1)
+}
+
+// MarkOutboxEventFailed mocks base method.
+func (m *MockStore) MarkOutboxEventFailed(arg0 context.Context, arg1 db.MarkOutboxEventFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventFailed indicates an expected call of MarkOutboxEventFailed.
+func (mr *MockStoreMockRecorder) MarkOutboxEventFailed(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventFailed", reflect.TypeOf((*MockStore)(nil).MarkOutboxEventFailed), arg0, arg1)
+}
+
+// MarkOutboxEventPublished mocks base method.
+func (m *MockStore) MarkOutboxEventPublished(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventPublished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventPublished indicates an expected call of MarkOutboxEventPublished.
+func (mr *MockStoreMockRecorder) MarkOutboxEventPublished(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventPublished", reflect.TypeOf((*MockStore)(nil).MarkOutboxEventPublished), arg0, arg1)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockStore) MarkWebhookDeliveryFailed(arg0 context.Context, arg1 db.MarkWebhookDeliveryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliveryFailed(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliveryFailed), arg0, arg1)
+}
+
+// MarkWebhookDeliverySucceeded mocks base method.
+func (m *MockStore) MarkWebhookDeliverySucceeded(arg0 context.Context, arg1 db.MarkWebhookDeliverySucceededParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliverySucceeded", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliverySucceeded indicates an expected call of MarkWebhookDeliverySucceeded.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliverySucceeded(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliverySucceeded", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliverySucceeded), arg0, arg1)
+}
+
+// StatementTx mocks base method.
+func (m *MockStore) StatementTx(arg0 context.Context, arg1 func(db.StatementQuerier) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatementTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StatementTx indicates an expected call of StatementTx.
+func (mr *MockStoreMockRecorder) StatementTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatementTx", reflect.TypeOf((*MockStore)(nil).StatementTx), arg0, arg1)
+}
+
+// StreamStatementEntries mocks base method.
+func (m *MockStore) StreamStatementEntries(arg0 context.Context, arg1 db.StreamStatementEntriesParams, arg2 func(db.StatementEntry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamStatementEntries", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamStatementEntries indicates an expected call of StreamStatementEntries.
+func (mr *MockStoreMockRecorder) StreamStatementEntries(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamStatementEntries", reflect.TypeOf((*MockStore)(nil).StreamStatementEntries), arg0, arg1, arg2)
+}
+
+// SumAccountEntries mocks base method.
+func (m *MockStore) SumAccountEntries(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumAccountEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumAccountEntries indicates an expected call of SumAccountEntries.
+func (mr *MockStoreMockRecorder) SumAccountEntries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumAccountEntries", reflect.TypeOf((*MockStore)(nil).SumAccountEntries), arg0, arg1)
+}
+
+// SumEntriesSince mocks base method.
+func (m *MockStore) SumEntriesSince(arg0 context.Context, arg1 db.SumEntriesSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesSince indicates an expected call of SumEntriesSince.
+func (mr *MockStoreMockRecorder) SumEntriesSince(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesSince", reflect.TypeOf((*MockStore)(nil).SumEntriesSince), arg0, arg1)
 }
 
 // TransferTx mocks base method.
@@ -324,6 +1617,36 @@ func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
 }
 
+// UpdateKYCSubmissionEncryption mocks base method.
+func (m *MockStore) UpdateKYCSubmissionEncryption(arg0 context.Context, arg1 db.UpdateKYCSubmissionEncryptionParams) (db.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKYCSubmissionEncryption", arg0, arg1)
+	ret0, _ := ret[0].(db.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateKYCSubmissionEncryption indicates an expected call of UpdateKYCSubmissionEncryption.
+func (mr *MockStoreMockRecorder) UpdateKYCSubmissionEncryption(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKYCSubmissionEncryption", reflect.TypeOf((*MockStore)(nil).UpdateKYCSubmissionEncryption), arg0, arg1)
+}
+
+// UpdatePayeeNickname mocks base method.
+func (m *MockStore) UpdatePayeeNickname(arg0 context.Context, arg1 db.UpdatePayeeNicknameParams) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayeeNickname", arg0, arg1)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePayeeNickname indicates an expected call of UpdatePayeeNickname.
+func (mr *MockStoreMockRecorder) UpdatePayeeNickname(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayeeNickname", reflect.TypeOf((*MockStore)(nil).UpdatePayeeNickname), arg0, arg1)
+}
+
 // UpdateSessionIsBlocked mocks base method.
 func (m *MockStore) UpdateSessionIsBlocked(arg0 context.Context, arg1 db.UpdateSessionIsBlockedParams) (db.Session, error) {
 	m.ctrl.T.Helper()
@@ -338,3 +1661,63 @@ func (mr *MockStoreMockRecorder) UpdateSessionIsBlocked(arg0, arg1 any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionIsBlocked", reflect.TypeOf((*MockStore)(nil).UpdateSessionIsBlocked), arg0, arg1)
 }
+
+// UpdateUserEncryption mocks base method.
+func (m *MockStore) UpdateUserEncryption(arg0 context.Context, arg1 db.UpdateUserEncryptionParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserEncryption", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserEncryption indicates an expected call of UpdateUserEncryption.
+func (mr *MockStoreMockRecorder) UpdateUserEncryption(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserEncryption", reflect.TypeOf((*MockStore)(nil).UpdateUserEncryption), arg0, arg1)
+}
+
+// UpdateUserKYCTier mocks base method.
+func (m *MockStore) UpdateUserKYCTier(arg0 context.Context, arg1 db.UpdateUserKYCTierParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserKYCTier", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserKYCTier indicates an expected call of UpdateUserKYCTier.
+func (mr *MockStoreMockRecorder) UpdateUserKYCTier(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserKYCTier", reflect.TypeOf((*MockStore)(nil).UpdateUserKYCTier), arg0, arg1)
+}
+
+// UpdateUserScreening mocks base method.
+func (m *MockStore) UpdateUserScreening(arg0 context.Context, arg1 db.UpdateUserScreeningParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserScreening", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserScreening indicates an expected call of UpdateUserScreening.
+func (mr *MockStoreMockRecorder) UpdateUserScreening(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserScreening", reflect.TypeOf((*MockStore)(nil).UpdateUserScreening), arg0, arg1)
+}
+
+// UpsertTransferLimit mocks base method.
+func (m *MockStore) UpsertTransferLimit(arg0 context.Context, arg1 db.UpsertTransferLimitParams) (db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTransferLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTransferLimit indicates an expected call of UpsertTransferLimit.
+func (mr *MockStoreMockRecorder) UpsertTransferLimit(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTransferLimit", reflect.TypeOf((*MockStore)(nil).UpsertTransferLimit), arg0, arg1)
+}
@@ -2,15 +2,21 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product_code,
+  number
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetAccount :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountByNumber :one
+SELECT * FROM accounts
+WHERE number = $1 LIMIT 1;
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1
@@ -37,4 +43,9 @@ RETURNING *;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetAccountByOwner :one
+SELECT * FROM accounts
+WHERE owner = $1 AND currency = $2 AND product_code = $3
+LIMIT 1;
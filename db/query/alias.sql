@@ -0,0 +1,24 @@
+-- name: CountAliasLookup :one
+-- Counts a lookup of username, starting a new window once the current one
+-- started before expired_before
+INSERT INTO alias_lookups (
+  username,
+  window_start,
+  lookups
+) VALUES (
+  sqlc.arg(username), now(), 1
+)
+ON CONFLICT (username) DO UPDATE SET
+  window_start = CASE
+    WHEN alias_lookups.window_start < sqlc.arg(expired_before) THEN now()
+    ELSE alias_lookups.window_start
+  END,
+  lookups = CASE
+    WHEN alias_lookups.window_start < sqlc.arg(expired_before) THEN 1
+    ELSE alias_lookups.lookups + 1
+  END
+RETURNING *;
+
+-- name: GetAliasLookup :one
+SELECT * FROM alias_lookups
+WHERE username = $1 LIMIT 1;
@@ -16,3 +16,7 @@ WHERE account_id = $1
 ORDER BY id
 LIMIT $2
 OFFSET $3;
+
+-- name: SumEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = $1 AND created_at >= $2;
@@ -0,0 +1,119 @@
+-- name: GetProduct :one
+SELECT * FROM products
+WHERE code = $1 LIMIT 1;
+
+-- name: ListProducts :many
+SELECT * FROM products
+ORDER BY code;
+
+-- name: CreateInterestRate :one
+INSERT INTO interest_rates (
+  product_code,
+  annual_rate_bps,
+  effective_from
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: ListInterestRates :many
+SELECT * FROM interest_rates
+WHERE product_code = $1
+ORDER BY effective_from;
+
+-- name: ListAccrualCandidates :many
+-- Accounts earning interest on accrual_date that have not accrued it yet,
+-- with their balance at the end of that day. Days of a month that was
+-- already posted are left alone.
+WITH rates AS (
+  SELECT DISTINCT ON (product_code) product_code, annual_rate_bps
+  FROM interest_rates
+  WHERE effective_from <= sqlc.arg(accrual_date)::date
+  ORDER BY product_code, effective_from DESC
+)
+SELECT
+  a.id,
+  a.currency,
+  r.annual_rate_bps,
+  (a.balance - COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id AND e.created_at >= sqlc.arg(day_end)
+  ), 0))::bigint AS balance
+FROM accounts a
+JOIN rates r ON r.product_code = a.product_code
+WHERE r.annual_rate_bps > 0
+  AND a.created_at < sqlc.arg(day_end)
+  AND NOT EXISTS (
+    SELECT 1 FROM interest_accruals i
+    WHERE i.account_id = a.id AND i.accrual_date = sqlc.arg(accrual_date)::date
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM interest_postings p
+    WHERE p.account_id = a.id AND p.period >= date_trunc('month', sqlc.arg(accrual_date)::date)
+  )
+ORDER BY a.id;
+
+-- name: CreateInterestAccrual :execrows
+INSERT INTO interest_accruals (
+  account_id,
+  accrual_date,
+  balance,
+  annual_rate_bps,
+  accrued
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+LIMIT $2;
+
+-- name: ListUnpostedInterest :many
+-- Unposted accruals of every account summed by month, for the months
+-- before the one starting at before
+SELECT
+  i.account_id,
+  a.number AS account_number,
+  a.currency,
+  date_trunc('month', i.accrual_date)::date AS period,
+  SUM(i.accrued)::bigint AS accrued
+FROM interest_accruals i
+JOIN accounts a ON a.id = i.account_id
+WHERE i.posting_id IS NULL AND i.accrual_date < sqlc.arg(before)::date
+GROUP BY i.account_id, a.number, a.currency, date_trunc('month', i.accrual_date)
+ORDER BY i.account_id, period;
+
+-- name: ListUnpostedInterestAccrualsForUpdate :many
+SELECT * FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND posting_id IS NULL
+  AND accrual_date >= sqlc.arg(period)::date
+  AND accrual_date < (sqlc.arg(period)::date + interval '1 month')
+ORDER BY accrual_date
+FOR UPDATE;
+
+-- name: GetLastInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = $1
+ORDER BY period DESC
+LIMIT 1;
+
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+  account_id,
+  period,
+  accrued,
+  amount,
+  carried,
+  journal_entry_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: MarkInterestAccrualsPosted :exec
+UPDATE interest_accruals
+SET posting_id = sqlc.arg(posting_id)
+WHERE account_id = sqlc.arg(account_id)
+  AND accrual_date = ANY(sqlc.arg(accrual_dates)::date[]);
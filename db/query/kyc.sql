@@ -0,0 +1,75 @@
+-- name: CreateKYCSubmission :one
+INSERT INTO kyc_submissions (
+  username,
+  tier,
+  document_type,
+  country,
+  legal_name,
+  date_of_birth,
+  document_number,
+  address,
+  data_key,
+  master_key_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetKYCSubmission :one
+SELECT * FROM kyc_submissions
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLatestKYCSubmission :one
+SELECT * FROM kyc_submissions
+WHERE username = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListKYCSubmissions :many
+SELECT * FROM kyc_submissions
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: DecideKYCSubmission :one
+UPDATE kyc_submissions
+SET
+  status = sqlc.arg(status),
+  reviewer = sqlc.arg(reviewer),
+  reason = sqlc.arg(reason),
+  decided_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: ListKYCSubmissionsToReencrypt :many
+SELECT * FROM kyc_submissions
+WHERE master_key_id <> sqlc.arg(master_key_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateKYCSubmissionEncryption :one
+UPDATE kyc_submissions
+SET
+  legal_name = sqlc.arg(legal_name),
+  date_of_birth = sqlc.arg(date_of_birth),
+  document_number = sqlc.arg(document_number),
+  address = sqlc.arg(address),
+  data_key = sqlc.arg(data_key),
+  master_key_id = sqlc.arg(master_key_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateUserKYCTier :one
+UPDATE users
+SET kyc_tier = sqlc.arg(kyc_tier)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: GetKYCCapability :one
+-- The tier of a user next to the lowest tier allowed to use a currency
+SELECT
+  users.kyc_tier,
+  currencies.min_kyc_tier
+FROM users, currencies
+WHERE users.username = sqlc.arg(username) AND currencies.code = sqlc.arg(currency);
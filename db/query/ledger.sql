@@ -0,0 +1,88 @@
+-- name: CreateSystemLedgerAccount :exec
+INSERT INTO ledger_accounts (
+  code,
+  name,
+  type,
+  currency
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (code, currency) WHERE account_id IS NULL DO NOTHING;
+
+-- name: GetSystemLedgerAccount :one
+SELECT * FROM ledger_accounts
+WHERE code = $1 AND currency = $2 AND account_id IS NULL
+LIMIT 1;
+
+-- name: CreateCustomerLedgerAccount :exec
+INSERT INTO ledger_accounts (
+  code,
+  name,
+  type,
+  currency,
+  account_id
+)
+SELECT '2000', 'Customer account ' || a.id, 'liability', a.currency, a.id
+FROM accounts a
+WHERE a.id = sqlc.arg(account_id)
+ON CONFLICT (account_id) DO NOTHING;
+
+-- name: GetCustomerLedgerAccount :one
+SELECT * FROM ledger_accounts
+WHERE account_id = sqlc.arg(account_id)::bigint
+LIMIT 1;
+
+-- name: GetLedgerAccount :one
+SELECT * FROM ledger_accounts
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+  kind,
+  description,
+  transfer_id
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: CreatePosting :one
+INSERT INTO postings (
+  journal_entry_id,
+  ledger_account_id,
+  amount,
+  currency
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListPostings :many
+SELECT * FROM postings
+WHERE journal_entry_id = $1
+ORDER BY id;
+
+-- name: GetTrialBalance :many
+-- Customer ledger accounts share their chart code and roll up into it.
+SELECT
+  l.code,
+  l.type,
+  l.currency,
+  COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0)::bigint AS debits,
+  COALESCE(-SUM(p.amount) FILTER (WHERE p.amount < 0), 0)::bigint AS credits
+FROM ledger_accounts l
+LEFT JOIN postings p ON p.ledger_account_id = l.id AND p.created_at < sqlc.arg(as_of)
+GROUP BY l.code, l.type, l.currency
+ORDER BY l.currency, l.code;
+
+-- name: ListLedgerDrift :many
+-- A customer account holds the credit balance of its ledger account.
+SELECT
+  a.id,
+  a.currency,
+  a.balance,
+  COALESCE(-SUM(p.amount), 0)::bigint AS ledger_balance
+FROM accounts a
+LEFT JOIN ledger_accounts l ON l.account_id = a.id
+LEFT JOIN postings p ON p.ledger_account_id = l.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(-SUM(p.amount), 0)
+ORDER BY a.id;
@@ -0,0 +1,45 @@
+-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+  scope,
+  subject,
+  currency,
+  per_transfer,
+  daily_amount,
+  monthly_amount,
+  daily_count,
+  monthly_count
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (scope, subject, currency) DO UPDATE SET
+  per_transfer = EXCLUDED.per_transfer,
+  daily_amount = EXCLUDED.daily_amount,
+  monthly_amount = EXCLUDED.monthly_amount,
+  daily_count = EXCLUDED.daily_count,
+  monthly_count = EXCLUDED.monthly_count,
+  updated_at = now()
+RETURNING *;
+
+-- name: ListTransferLimits :many
+SELECT * FROM transfer_limits
+ORDER BY scope, subject, currency;
+
+-- name: ListApplicableTransferLimits :many
+SELECT * FROM transfer_limits
+WHERE currency = sqlc.arg(currency) AND (
+  scope = 'global' OR
+  (scope = 'product' AND subject = sqlc.arg(product_code)::varchar) OR
+  (scope = 'tier' AND subject = (SELECT kyc_tier FROM users WHERE users.username = sqlc.arg(username)::varchar)) OR
+  (scope = 'user' AND subject = sqlc.arg(username)::varchar)
+);
+
+-- name: GetTransferUsage :one
+-- Outgoing transfers of an account since the start of the month, and the
+-- part of them since the start of the day
+SELECT
+  COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(day_start)), 0)::bigint AS daily_amount,
+  COUNT(*) FILTER (WHERE created_at >= sqlc.arg(day_start))::int AS daily_count,
+  COALESCE(SUM(amount), 0)::bigint AS monthly_amount,
+  COUNT(*)::int AS monthly_count
+FROM transfers
+WHERE from_account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(month_start);
@@ -0,0 +1,57 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox (
+  aggregate_type,
+  aggregate_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox
+WHERE id = $1 LIMIT 1;
+
+-- name: ClaimOutboxEvents :many
+-- Only the oldest pending event of each aggregate is due, so events of one
+-- aggregate are published in order. Claimed events are not due again until
+-- lease_until, so they can be published without holding a lock, and stay
+-- pending so that they keep holding back their aggregate.
+WITH due AS (
+  SELECT o.id FROM outbox o
+  WHERE o.status = 'pending'
+    AND o.next_attempt_at <= now()
+    AND NOT EXISTS (
+      SELECT 1 FROM outbox p
+      WHERE p.aggregate_type = o.aggregate_type
+        AND p.aggregate_id = o.aggregate_id
+        AND p.status = 'pending'
+        AND p.id < o.id
+    )
+  ORDER BY o.id
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE outbox
+SET next_attempt_at = sqlc.arg(lease_until)
+FROM due
+WHERE outbox.id = due.id
+RETURNING outbox.*;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox
+SET
+  status = 'published',
+  attempts = attempts + 1,
+  last_error = '',
+  published_at = now()
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET
+  status = sqlc.arg(status),
+  attempts = attempts + 1,
+  last_error = sqlc.arg(last_error),
+  next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,41 @@
+-- name: CreatePayee :one
+INSERT INTO payees (
+  owner,
+  nickname,
+  account_id,
+  account_number,
+  currency,
+  active_from
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetPayee :one
+SELECT * FROM payees
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPayees :many
+SELECT * FROM payees
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdatePayeeNickname :one
+UPDATE payees
+SET nickname = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ConfirmPayee :one
+-- Payees whose cooling-off period is already over keep their active_from
+UPDATE payees
+SET
+  active_from = LEAST(active_from, now()),
+  confirmed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePayee :exec
+DELETE FROM payees
+WHERE id = $1;
@@ -0,0 +1,13 @@
+-- name: CreatePaymentFile :one
+INSERT INTO payment_files (
+  owner,
+  message_id,
+  number_of_payments,
+  control_sum
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetPaymentFile :one
+SELECT * FROM payment_files
+WHERE id = $1 LIMIT 1;
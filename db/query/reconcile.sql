@@ -0,0 +1,57 @@
+-- name: ListAccountDrift :many
+SELECT
+  a.id,
+  a.owner,
+  a.currency,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListCurrencyTotals :many
+SELECT
+  a.currency,
+  COUNT(*)::bigint AS accounts,
+  COALESCE(SUM(a.balance), 0)::bigint AS balance_total,
+  COALESCE(SUM(t.total), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN (
+  SELECT account_id, SUM(amount) AS total
+  FROM entries
+  GROUP BY account_id
+) t ON t.account_id = a.id
+GROUP BY a.currency
+ORDER BY a.currency;
+
+-- name: ListUnbalancedTransfers :many
+-- A transfer and its entries are written in one transaction and share
+-- created_at, so exactly one debit and one credit entry must match it.
+SELECT
+  t.id,
+  t.from_account_id,
+  t.to_account_id,
+  t.amount,
+  fa.currency AS from_currency,
+  ta.currency AS to_currency,
+  COUNT(e.id) FILTER (WHERE e.account_id = t.from_account_id)::int AS debit_entries,
+  COUNT(e.id) FILTER (WHERE e.account_id = t.to_account_id)::int AS credit_entries,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+LEFT JOIN entries e ON e.created_at = t.created_at AND (
+  (e.account_id = t.from_account_id AND e.amount = -t.amount) OR
+  (e.account_id = t.to_account_id AND e.amount = t.amount)
+)
+GROUP BY t.id, fa.currency, ta.currency
+HAVING COUNT(e.id) FILTER (WHERE e.account_id = t.from_account_id) <> 1
+  OR COUNT(e.id) FILTER (WHERE e.account_id = t.to_account_id) <> 1
+  OR fa.currency <> ta.currency
+ORDER BY t.id;
+
+-- name: SumAccountEntries :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = $1;
@@ -0,0 +1,38 @@
+-- name: CreateUserScreening :one
+INSERT INTO user_screenings (
+  username,
+  list_version,
+  status,
+  score,
+  matched_uid,
+  matched_name
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListUserScreenings :many
+SELECT * FROM user_screenings
+WHERE username = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateUserScreening :one
+UPDATE users
+SET
+  screening_status = sqlc.arg(screening_status),
+  screening_list_version = sqlc.arg(screening_list_version),
+  screened_at = now()
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: ListUsersToScreen :many
+SELECT * FROM users
+WHERE screening_list_version <> sqlc.arg(list_version)
+ORDER BY username
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
+
+-- name: IsUserBlocked :one
+SELECT screening_status = 'blocked' AS blocked FROM users
+WHERE username = $1;
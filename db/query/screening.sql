@@ -0,0 +1,42 @@
+-- name: ListTransfersFromAccountSince :many
+SELECT * FROM transfers
+WHERE from_account_id = $1 AND created_at >= sqlc.arg(since)
+ORDER BY id;
+
+-- name: CountTransfersBetweenAccounts :one
+SELECT count(*) FROM transfers
+WHERE from_account_id = $1 AND to_account_id = $2;
+
+-- name: CreateTransferReview :one
+INSERT INTO transfer_reviews (
+  owner,
+  from_account_id,
+  to_account_id,
+  amount,
+  currency,
+  hits
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetTransferReview :one
+SELECT * FROM transfer_reviews
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTransferReviews :many
+SELECT * FROM transfer_reviews
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: DecideTransferReview :one
+UPDATE transfer_reviews
+SET
+  status = sqlc.arg(status),
+  reviewer = sqlc.arg(reviewer),
+  reason = sqlc.arg(reason),
+  transfer_id = sqlc.narg(transfer_id),
+  decided_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
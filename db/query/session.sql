@@ -22,6 +22,13 @@ SET
 WHERE id = $1
 RETURNING *;
 
+-- name: BlockUserSessions :exec
+-- Blocks every session of a user, so that their refresh tokens stop working
+UPDATE sessions
+SET
+  is_blocked = true
+WHERE username = $1 AND NOT is_blocked;
+
 -- name: DeleteSession :exec
 DELETE FROM sessions
 WHERE id = $1;
\ No newline at end of file
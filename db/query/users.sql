@@ -3,11 +3,55 @@ INSERT INTO users (
   username,
   hashed_password,
   full_name,
-  email
+  email,
+  phone,
+  full_name_sealed,
+  email_sealed,
+  email_index,
+  phone_sealed,
+  phone_index,
+  data_key,
+  master_key_id
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: GetUserByEmail :one
+-- Encrypted users are found by the blind index of their email, the others
+-- by the email itself
+SELECT * FROM users
+WHERE email_index = sqlc.arg(email_index) OR (email <> '' AND email = sqlc.arg(email))
+LIMIT 1;
+
+-- name: GetUserByPhone :one
+-- Phone numbers are matched like emails, see GetUserByEmail
+SELECT * FROM users
+WHERE phone_index = sqlc.arg(phone_index) OR (phone <> '' AND phone = sqlc.arg(phone))
+LIMIT 1;
+
+-- name: ListUsersToReencrypt :many
+SELECT * FROM users
+WHERE master_key_id <> sqlc.arg(master_key_id)
+ORDER BY username
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateUserEncryption :one
+UPDATE users
+SET
+  full_name = '',
+  email = '',
+  phone = '',
+  full_name_sealed = sqlc.arg(full_name_sealed),
+  email_sealed = sqlc.arg(email_sealed),
+  email_index = sqlc.arg(email_index),
+  phone_sealed = sqlc.arg(phone_sealed),
+  phone_index = sqlc.arg(phone_index),
+  data_key = sqlc.arg(data_key),
+  master_key_id = sqlc.arg(master_key_id)
+WHERE username = sqlc.arg(username)
+RETURNING *;
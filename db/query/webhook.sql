@@ -0,0 +1,101 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  owner,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE owner = $1 AND is_active
+ORDER BY id;
+
+-- name: ListWebhookSubscriptionsForEvent :many
+SELECT * FROM webhook_subscriptions
+WHERE owner = $1 AND is_active AND sqlc.arg(event_type)::varchar = ANY(event_types)
+ORDER BY id;
+
+-- name: DisableWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET is_active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  subscription_id,
+  event_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimWebhookDeliveries :many
+-- Claimed deliveries are not due again until lease_until, so they can be sent
+-- without holding a lock. Deliveries of a dispatcher that dies before marking
+-- them are retried once the lease runs out.
+WITH due AS (
+  SELECT d.id
+  FROM webhook_deliveries d
+  JOIN webhook_subscriptions s ON s.id = d.subscription_id
+  WHERE d.status = 'pending'
+    AND d.next_attempt_at <= now()
+    AND s.is_active
+  ORDER BY d.id
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = sqlc.arg(lease_until)
+FROM due, webhook_subscriptions s
+WHERE d.id = due.id
+  AND s.id = d.subscription_id
+RETURNING
+  d.id,
+  d.subscription_id,
+  d.event_id,
+  d.event_type,
+  d.payload,
+  d.attempts,
+  d.created_at,
+  s.url,
+  s.secret;
+
+-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE webhook_deliveries
+SET
+  status = 'succeeded',
+  attempts = attempts + 1,
+  response_status = $2,
+  last_error = '',
+  delivered_at = now()
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET
+  status = sqlc.arg(status),
+  attempts = attempts + 1,
+  response_status = sqlc.arg(response_status),
+  last_error = sqlc.arg(last_error),
+  next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
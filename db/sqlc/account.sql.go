@@ -13,7 +13,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, product_code, number
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +30,8 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ProductCode,
+		&i.Number,
 	)
 	return i, err
 }
@@ -38,20 +40,30 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product_code,
+  number
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+  $1, $2, $3, $4, $5
+) RETURNING id, owner, balance, currency, created_at, product_code, number
 `
 
 type CreateAccountParams struct {
-	Owner    string `json:"owner"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	Owner       string `json:"owner"`
+	Balance     int64  `json:"balance"`
+	Currency    string `json:"currency"`
+	ProductCode string `json:"product_code"`
+	Number      string `json:"number"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.ProductCode,
+		arg.Number,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +71,8 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ProductCode,
+		&i.Number,
 	)
 	return i, err
 }
@@ -74,7 +88,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, product_code, number FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -87,12 +101,61 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ProductCode,
+		&i.Number,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency, created_at, product_code, number FROM accounts
+WHERE number = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, number string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByNumber, number)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.ProductCode,
+		&i.Number,
+	)
+	return i, err
+}
+
+const getAccountByOwner = `-- name: GetAccountByOwner :one
+SELECT id, owner, balance, currency, created_at, product_code, number FROM accounts
+WHERE owner = $1 AND currency = $2 AND product_code = $3
+LIMIT 1
+`
+
+type GetAccountByOwnerParams struct {
+	Owner       string `json:"owner"`
+	Currency    string `json:"currency"`
+	ProductCode string `json:"product_code"`
+}
+
+func (q *Queries) GetAccountByOwner(ctx context.Context, arg GetAccountByOwnerParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByOwner, arg.Owner, arg.Currency, arg.ProductCode)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.ProductCode,
+		&i.Number,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, product_code, number FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -106,12 +169,14 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ProductCode,
+		&i.Number,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, product_code, number FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -139,6 +204,8 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.ProductCode,
+			&i.Number,
 		); err != nil {
 			return nil, err
 		}
@@ -157,7 +224,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, product_code, number
 `
 
 type UpdateAccountParams struct {
@@ -174,6 +241,8 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ProductCode,
+		&i.Number,
 	)
 	return i, err
 }
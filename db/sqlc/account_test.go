@@ -7,16 +7,32 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/kyc"
 	"github.com/techschool/simplebank/util"
 )
 
 func createRandomAccount(t *testing.T) Account {
+	return createRandomAccountWithCurrency(t, util.RandomCurrency())
+}
+
+// createRandomAccountWithCurrency creates an account that can take part in
+// transfers with the other accounts of currency. Its owner is fully
+// verified so that no KYC tier limit applies.
+func createRandomAccountWithCurrency(t *testing.T, currency string) Account {
 	user := createRandomUser(t)
+	_, err := testQueries.UpdateUserKYCTier(context.Background(), UpdateUserKYCTierParams{
+		KycTier:  kyc.TierFull,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
 
+	// the balance covers the few small transfers a test makes
 	arg := CreateAccountParams{
-		Owner:    user.Username,
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Owner:       user.Username,
+		Balance:     util.RandomInt(100, 1000),
+		Currency:    currency,
+		ProductCode: "checking",
+		Number:      util.RandomAccountNumber(),
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -26,6 +42,8 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.ProductCode, account.ProductCode)
+	require.Equal(t, arg.Number, account.Number)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -50,6 +68,16 @@ func TestGetAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
+func TestGetAccountByNumber(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2, err := testQueries.GetAccountByNumber(context.Background(), account1.Number)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+
+	_, err = testQueries.GetAccountByNumber(context.Background(), util.RandomAccountNumber())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
 func TestUpdateAccount(t *testing.T) {
 	account1 := createRandomAccount(t)
 
@@ -0,0 +1,57 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: alias.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const countAliasLookup = `-- name: CountAliasLookup :one
+INSERT INTO alias_lookups (
+  username,
+  window_start,
+  lookups
+) VALUES (
+  $1, now(), 1
+)
+ON CONFLICT (username) DO UPDATE SET
+  window_start = CASE
+    WHEN alias_lookups.window_start < $2 THEN now()
+    ELSE alias_lookups.window_start
+  END,
+  lookups = CASE
+    WHEN alias_lookups.window_start < $2 THEN 1
+    ELSE alias_lookups.lookups + 1
+  END
+RETURNING username, window_start, lookups
+`
+
+type CountAliasLookupParams struct {
+	Username      string    `json:"username"`
+	ExpiredBefore time.Time `json:"expired_before"`
+}
+
+// Counts a lookup of username, starting a new window once the current one
+// started before expired_before
+func (q *Queries) CountAliasLookup(ctx context.Context, arg CountAliasLookupParams) (AliasLookup, error) {
+	row := q.db.QueryRowContext(ctx, countAliasLookup, arg.Username, arg.ExpiredBefore)
+	var i AliasLookup
+	err := row.Scan(&i.Username, &i.WindowStart, &i.Lookups)
+	return i, err
+}
+
+const getAliasLookup = `-- name: GetAliasLookup :one
+SELECT username, window_start, lookups FROM alias_lookups
+WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) GetAliasLookup(ctx context.Context, username string) (AliasLookup, error) {
+	row := q.db.QueryRowContext(ctx, getAliasLookup, username)
+	var i AliasLookup
+	err := row.Scan(&i.Username, &i.WindowStart, &i.Lookups)
+	return i, err
+}
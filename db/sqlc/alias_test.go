@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountAliasLookup(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CountAliasLookupParams{
+		Username:      user.Username,
+		ExpiredBefore: time.Now().Add(-time.Hour),
+	}
+	for i := 1; i <= 3; i++ {
+		lookup, err := testQueries.CountAliasLookup(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, int32(i), lookup.Lookups)
+	}
+
+	// a window that started before expired_before is replaced
+	arg.ExpiredBefore = time.Now().Add(time.Minute)
+	lookup, err := testQueries.CountAliasLookup(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), lookup.Lookups)
+	require.WithinDuration(t, time.Now(), lookup.WindowStart, time.Minute)
+}
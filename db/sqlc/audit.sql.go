@@ -0,0 +1,223 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: audit.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const createAuditEvent = `-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  actor,
+  action,
+  resource_type,
+  resource_id,
+  client_ip,
+  user_agent,
+  diff,
+  prev_hash,
+  hash,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, actor, action, resource_type, resource_id, client_ip, user_agent, diff, prev_hash, hash, created_at
+`
+
+type CreateAuditEventParams struct {
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	ClientIp     string          `json:"client_ip"`
+	UserAgent    string          `json:"user_agent"`
+	Diff         json.RawMessage `json:"diff"`
+	PrevHash     string          `json:"prev_hash"`
+	Hash         string          `json:"hash"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error) {
+	row := q.db.QueryRowContext(ctx, createAuditEvent,
+		arg.Actor,
+		arg.Action,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.ClientIp,
+		arg.UserAgent,
+		arg.Diff,
+		arg.PrevHash,
+		arg.Hash,
+		arg.CreatedAt,
+	)
+	var i AuditEvent
+	err := row.Scan(
+		&i.ID,
+		&i.Actor,
+		&i.Action,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.ClientIp,
+		&i.UserAgent,
+		&i.Diff,
+		&i.PrevHash,
+		&i.Hash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLastAuditEvent = `-- name: GetLastAuditEvent :one
+SELECT id, actor, action, resource_type, resource_id, client_ip, user_agent, diff, prev_hash, hash, created_at FROM audit_events
+ORDER BY id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastAuditEvent(ctx context.Context) (AuditEvent, error) {
+	row := q.db.QueryRowContext(ctx, getLastAuditEvent)
+	var i AuditEvent
+	err := row.Scan(
+		&i.ID,
+		&i.Actor,
+		&i.Action,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.ClientIp,
+		&i.UserAgent,
+		&i.Diff,
+		&i.PrevHash,
+		&i.Hash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT id, actor, action, resource_type, resource_id, client_ip, user_agent, diff, prev_hash, hash, created_at FROM audit_events
+WHERE
+  ($1::varchar IS NULL OR actor = $1) AND
+  ($2::varchar IS NULL OR action = $2) AND
+  ($3::varchar IS NULL OR resource_type = $3) AND
+  ($4::varchar IS NULL OR resource_id = $4) AND
+  ($5::timestamptz IS NULL OR created_at >= $5) AND
+  ($6::timestamptz IS NULL OR created_at < $6)
+ORDER BY id
+LIMIT $8
+OFFSET $7
+`
+
+type ListAuditEventsParams struct {
+	Actor        sql.NullString `json:"actor"`
+	Action       sql.NullString `json:"action"`
+	ResourceType sql.NullString `json:"resource_type"`
+	ResourceID   sql.NullString `json:"resource_id"`
+	FromTime     sql.NullTime   `json:"from_time"`
+	ToTime       sql.NullTime   `json:"to_time"`
+	Offset       int32          `json:"offset"`
+	Limit        int32          `json:"limit"`
+}
+
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEvents,
+		arg.Actor,
+		arg.Action,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditEvent{}
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ClientIp,
+			&i.UserAgent,
+			&i.Diff,
+			&i.PrevHash,
+			&i.Hash,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditEventsAfter = `-- name: ListAuditEventsAfter :many
+SELECT id, actor, action, resource_type, resource_id, client_ip, user_agent, diff, prev_hash, hash, created_at FROM audit_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListAuditEventsAfterParams struct {
+	AfterID int64 `json:"after_id"`
+	Limit   int32 `json:"limit"`
+}
+
+func (q *Queries) ListAuditEventsAfter(ctx context.Context, arg ListAuditEventsAfterParams) ([]AuditEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEventsAfter, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditEvent{}
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ClientIp,
+			&i.UserAgent,
+			&i.Diff,
+			&i.PrevHash,
+			&i.Hash,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAuditChain = `-- name: LockAuditChain :exec
+SELECT pg_advisory_xact_lock(hashtext('audit_events'))
+`
+
+func (q *Queries) LockAuditChain(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, lockAuditChain)
+	return err
+}
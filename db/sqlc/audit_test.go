@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func createRandomAuditEvent(t *testing.T) AuditEvent {
+	arg := CreateAuditEventParams{
+		Actor:        util.RandomOwner(),
+		Action:       "account.created",
+		ResourceType: "account",
+		ResourceID:   util.RandomString(6),
+		ClientIp:     "127.0.0.1",
+		UserAgent:    "test",
+		Diff:         json.RawMessage(`{"balance":0}`),
+		PrevHash:     util.RandomString(64),
+		Hash:         util.RandomString(64),
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	event, err := testQueries.CreateAuditEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, event.ID)
+	require.Equal(t, arg.Actor, event.Actor)
+	require.Equal(t, arg.Hash, event.Hash)
+	require.JSONEq(t, string(arg.Diff), string(event.Diff))
+	require.WithinDuration(t, arg.CreatedAt, event.CreatedAt, time.Microsecond)
+
+	return event
+}
+
+func TestGetLastAuditEvent(t *testing.T) {
+	createRandomAuditEvent(t)
+	event := createRandomAuditEvent(t)
+
+	last, err := testQueries.GetLastAuditEvent(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, event.ID, last.ID)
+}
+
+func TestListAuditEvents(t *testing.T) {
+	event := createRandomAuditEvent(t)
+
+	events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		Actor:    sql.NullString{String: event.Actor, Valid: true},
+		FromTime: sql.NullTime{Time: event.CreatedAt, Valid: true},
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, event.ID, events[0].ID)
+
+	events, err = testQueries.ListAuditEventsAfter(context.Background(), ListAuditEventsAfterParams{
+		AfterID: event.ID - 1,
+		Limit:   1,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, event.ID, events[0].ID)
+}
+
+func TestAuditEventsAppendOnly(t *testing.T) {
+	event := createRandomAuditEvent(t)
+
+	_, err := testDB.Exec("UPDATE audit_events SET actor = 'mallory' WHERE id = $1", event.ID)
+	require.ErrorContains(t, err, "append-only")
+
+	_, err = testDB.Exec("DELETE FROM audit_events WHERE id = $1", event.ID)
+	require.ErrorContains(t, err, "append-only")
+}
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: currency.sql
+
+package db
+
+import (
+	"context"
+)
+
+const listCurrencies = `-- name: ListCurrencies :many
+SELECT code, exponent, symbol, enabled, created_at, min_kyc_tier FROM currencies
+ORDER BY code
+`
+
+func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Currency{}
+	for rows.Next() {
+		var i Currency
+		if err := rows.Scan(
+			&i.Code,
+			&i.Exponent,
+			&i.Symbol,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.MinKycTier,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+
+	codes := make(map[string]Currency, len(currencies))
+	for _, currency := range currencies {
+		codes[currency.Code] = currency
+	}
+
+	for _, code := range []string{util.EUR, util.KES, util.USD} {
+		require.Contains(t, codes, code)
+		require.True(t, codes[code].Enabled)
+		require.Equal(t, int32(2), codes[code].Exponent)
+	}
+}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
@@ -93,3 +94,20 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	}
 	return items, nil
 }
+
+const sumEntriesSince = `-- name: SumEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = $1 AND created_at >= $2
+`
+
+type SumEntriesSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumEntriesSince, arg.AccountID, arg.CreatedAt)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
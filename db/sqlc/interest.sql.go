@@ -0,0 +1,454 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: interest.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const createInterestAccrual = `-- name: CreateInterestAccrual :execrows
+INSERT INTO interest_accruals (
+  account_id,
+  accrual_date,
+  balance,
+  annual_rate_bps,
+  accrued
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+`
+
+type CreateInterestAccrualParams struct {
+	AccountID     int64     `json:"account_id"`
+	AccrualDate   time.Time `json:"accrual_date"`
+	Balance       int64     `json:"balance"`
+	AnnualRateBps int32     `json:"annual_rate_bps"`
+	Accrued       int64     `json:"accrued"`
+}
+
+func (q *Queries) CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createInterestAccrual,
+		arg.AccountID,
+		arg.AccrualDate,
+		arg.Balance,
+		arg.AnnualRateBps,
+		arg.Accrued,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createInterestPosting = `-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+  account_id,
+  period,
+  accrued,
+  amount,
+  carried,
+  journal_entry_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, account_id, period, accrued, amount, carried, journal_entry_id, created_at
+`
+
+type CreateInterestPostingParams struct {
+	AccountID      int64         `json:"account_id"`
+	Period         time.Time     `json:"period"`
+	Accrued        int64         `json:"accrued"`
+	Amount         int64         `json:"amount"`
+	Carried        int64         `json:"carried"`
+	JournalEntryID sql.NullInt64 `json:"journal_entry_id"`
+}
+
+func (q *Queries) CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error) {
+	row := q.db.QueryRowContext(ctx, createInterestPosting,
+		arg.AccountID,
+		arg.Period,
+		arg.Accrued,
+		arg.Amount,
+		arg.Carried,
+		arg.JournalEntryID,
+	)
+	var i InterestPosting
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Period,
+		&i.Accrued,
+		&i.Amount,
+		&i.Carried,
+		&i.JournalEntryID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createInterestRate = `-- name: CreateInterestRate :one
+INSERT INTO interest_rates (
+  product_code,
+  annual_rate_bps,
+  effective_from
+) VALUES (
+  $1, $2, $3
+) RETURNING id, product_code, annual_rate_bps, effective_from, created_at
+`
+
+type CreateInterestRateParams struct {
+	ProductCode   string    `json:"product_code"`
+	AnnualRateBps int32     `json:"annual_rate_bps"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+func (q *Queries) CreateInterestRate(ctx context.Context, arg CreateInterestRateParams) (InterestRate, error) {
+	row := q.db.QueryRowContext(ctx, createInterestRate, arg.ProductCode, arg.AnnualRateBps, arg.EffectiveFrom)
+	var i InterestRate
+	err := row.Scan(
+		&i.ID,
+		&i.ProductCode,
+		&i.AnnualRateBps,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLastInterestPosting = `-- name: GetLastInterestPosting :one
+SELECT id, account_id, period, accrued, amount, carried, journal_entry_id, created_at FROM interest_postings
+WHERE account_id = $1
+ORDER BY period DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastInterestPosting(ctx context.Context, accountID int64) (InterestPosting, error) {
+	row := q.db.QueryRowContext(ctx, getLastInterestPosting, accountID)
+	var i InterestPosting
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Period,
+		&i.Accrued,
+		&i.Amount,
+		&i.Carried,
+		&i.JournalEntryID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProduct = `-- name: GetProduct :one
+SELECT code, name, created_at FROM products
+WHERE code = $1 LIMIT 1
+`
+
+func (q *Queries) GetProduct(ctx context.Context, code string) (Product, error) {
+	row := q.db.QueryRowContext(ctx, getProduct, code)
+	var i Product
+	err := row.Scan(&i.Code, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const listAccrualCandidates = `-- name: ListAccrualCandidates :many
+WITH rates AS (
+  SELECT DISTINCT ON (product_code) product_code, annual_rate_bps
+  FROM interest_rates
+  WHERE effective_from <= $2::date
+  ORDER BY product_code, effective_from DESC
+)
+SELECT
+  a.id,
+  a.currency,
+  r.annual_rate_bps,
+  (a.balance - COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id AND e.created_at >= $1
+  ), 0))::bigint AS balance
+FROM accounts a
+JOIN rates r ON r.product_code = a.product_code
+WHERE r.annual_rate_bps > 0
+  AND a.created_at < $1
+  AND NOT EXISTS (
+    SELECT 1 FROM interest_accruals i
+    WHERE i.account_id = a.id AND i.accrual_date = $2::date
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM interest_postings p
+    WHERE p.account_id = a.id AND p.period >= date_trunc('month', $2::date)
+  )
+ORDER BY a.id
+`
+
+type ListAccrualCandidatesParams struct {
+	DayEnd      time.Time `json:"day_end"`
+	AccrualDate time.Time `json:"accrual_date"`
+}
+
+type ListAccrualCandidatesRow struct {
+	ID            int64  `json:"id"`
+	Currency      string `json:"currency"`
+	AnnualRateBps int32  `json:"annual_rate_bps"`
+	Balance       int64  `json:"balance"`
+}
+
+// Accounts earning interest on accrual_date that have not accrued it yet,
+// with their balance at the end of that day. Days of a month that was
+// already posted are left alone.
+func (q *Queries) ListAccrualCandidates(ctx context.Context, arg ListAccrualCandidatesParams) ([]ListAccrualCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccrualCandidates, arg.DayEnd, arg.AccrualDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccrualCandidatesRow{}
+	for rows.Next() {
+		var i ListAccrualCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.AnnualRateBps,
+			&i.Balance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInterestAccruals = `-- name: ListInterestAccruals :many
+SELECT account_id, accrual_date, balance, annual_rate_bps, accrued, posting_id, created_at FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+LIMIT $2
+`
+
+type ListInterestAccrualsParams struct {
+	AccountID int64 `json:"account_id"`
+	Limit     int32 `json:"limit"`
+}
+
+func (q *Queries) ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error) {
+	rows, err := q.db.QueryContext(ctx, listInterestAccruals, arg.AccountID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []InterestAccrual{}
+	for rows.Next() {
+		var i InterestAccrual
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.AccrualDate,
+			&i.Balance,
+			&i.AnnualRateBps,
+			&i.Accrued,
+			&i.PostingID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInterestRates = `-- name: ListInterestRates :many
+SELECT id, product_code, annual_rate_bps, effective_from, created_at FROM interest_rates
+WHERE product_code = $1
+ORDER BY effective_from
+`
+
+func (q *Queries) ListInterestRates(ctx context.Context, productCode string) ([]InterestRate, error) {
+	rows, err := q.db.QueryContext(ctx, listInterestRates, productCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []InterestRate{}
+	for rows.Next() {
+		var i InterestRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductCode,
+			&i.AnnualRateBps,
+			&i.EffectiveFrom,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProducts = `-- name: ListProducts :many
+SELECT code, name, created_at FROM products
+ORDER BY code
+`
+
+func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProducts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(&i.Code, &i.Name, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnpostedInterest = `-- name: ListUnpostedInterest :many
+SELECT
+  i.account_id,
+  a.number AS account_number,
+  a.currency,
+  date_trunc('month', i.accrual_date)::date AS period,
+  SUM(i.accrued)::bigint AS accrued
+FROM interest_accruals i
+JOIN accounts a ON a.id = i.account_id
+WHERE i.posting_id IS NULL AND i.accrual_date < $1::date
+GROUP BY i.account_id, a.number, a.currency, date_trunc('month', i.accrual_date)
+ORDER BY i.account_id, period
+`
+
+type ListUnpostedInterestRow struct {
+	AccountID     int64     `json:"account_id"`
+	AccountNumber string    `json:"account_number"`
+	Currency      string    `json:"currency"`
+	Period        time.Time `json:"period"`
+	Accrued       int64     `json:"accrued"`
+}
+
+// Unposted accruals of every account summed by month, for the months
+// before the one starting at before
+func (q *Queries) ListUnpostedInterest(ctx context.Context, before time.Time) ([]ListUnpostedInterestRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnpostedInterest, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnpostedInterestRow{}
+	for rows.Next() {
+		var i ListUnpostedInterestRow
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.AccountNumber,
+			&i.Currency,
+			&i.Period,
+			&i.Accrued,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnpostedInterestAccrualsForUpdate = `-- name: ListUnpostedInterestAccrualsForUpdate :many
+SELECT account_id, accrual_date, balance, annual_rate_bps, accrued, posting_id, created_at FROM interest_accruals
+WHERE account_id = $1
+  AND posting_id IS NULL
+  AND accrual_date >= $2::date
+  AND accrual_date < ($2::date + interval '1 month')
+ORDER BY accrual_date
+FOR UPDATE
+`
+
+type ListUnpostedInterestAccrualsForUpdateParams struct {
+	AccountID int64     `json:"account_id"`
+	Period    time.Time `json:"period"`
+}
+
+func (q *Queries) ListUnpostedInterestAccrualsForUpdate(ctx context.Context, arg ListUnpostedInterestAccrualsForUpdateParams) ([]InterestAccrual, error) {
+	rows, err := q.db.QueryContext(ctx, listUnpostedInterestAccrualsForUpdate, arg.AccountID, arg.Period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []InterestAccrual{}
+	for rows.Next() {
+		var i InterestAccrual
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.AccrualDate,
+			&i.Balance,
+			&i.AnnualRateBps,
+			&i.Accrued,
+			&i.PostingID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markInterestAccrualsPosted = `-- name: MarkInterestAccrualsPosted :exec
+UPDATE interest_accruals
+SET posting_id = $1
+WHERE account_id = $2
+  AND accrual_date = ANY($3::date[])
+`
+
+type MarkInterestAccrualsPostedParams struct {
+	PostingID    sql.NullInt64 `json:"posting_id"`
+	AccountID    int64         `json:"account_id"`
+	AccrualDates []time.Time   `json:"accrual_dates"`
+}
+
+func (q *Queries) MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) error {
+	_, err := q.db.ExecContext(ctx, markInterestAccrualsPosted, arg.PostingID, arg.AccountID, pq.Array(arg.AccrualDates))
+	return err
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func createRandomSavingsAccount(t *testing.T) Account {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:       user.Username,
+		Balance:     util.RandomMoney(),
+		Currency:    util.USD,
+		ProductCode: "savings",
+		Number:      util.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "savings", account.ProductCode)
+
+	return account
+}
+
+func TestAccrueInterestOnce(t *testing.T) {
+	account := createRandomSavingsAccount(t)
+	checking := createRandomAccount(t)
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	arg := ListAccrualCandidatesParams{
+		AccrualDate: day,
+		DayEnd:      day.AddDate(0, 0, 1),
+	}
+
+	candidates, err := testQueries.ListAccrualCandidates(context.Background(), arg)
+	require.NoError(t, err)
+
+	var found *ListAccrualCandidatesRow
+	for i := range candidates {
+		require.NotEqual(t, checking.ID, candidates[i].ID)
+		if candidates[i].ID == account.ID {
+			found = &candidates[i]
+		}
+	}
+	require.NotNil(t, found)
+	require.Equal(t, account.Balance, found.Balance)
+	require.Positive(t, found.AnnualRateBps)
+
+	accrual := CreateInterestAccrualParams{
+		AccountID:     account.ID,
+		AccrualDate:   day,
+		Balance:       found.Balance,
+		AnnualRateBps: found.AnnualRateBps,
+		Accrued:       found.Balance * int64(found.AnnualRateBps),
+	}
+	rows, err := testQueries.CreateInterestAccrual(context.Background(), accrual)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	rows, err = testQueries.CreateInterestAccrual(context.Background(), accrual)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	candidates, err = testQueries.ListAccrualCandidates(context.Background(), arg)
+	require.NoError(t, err)
+	for _, candidate := range candidates {
+		require.NotEqual(t, account.ID, candidate.ID)
+	}
+
+	accruals, err := testQueries.ListInterestAccruals(context.Background(), ListInterestAccrualsParams{
+		AccountID: account.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+	require.Equal(t, accrual.Accrued, accruals[0].Accrued)
+	require.False(t, accruals[0].PostingID.Valid)
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Kinds of journal entries
+const (
+	JournalKindTransfer       = "transfer"
+	JournalKindOpeningBalance = "opening_balance"
+	JournalKindInterest       = "interest"
+)
+
+// ErrUnbalancedJournal is returned for journal entries whose postings do not
+// add up to zero in every currency
+var ErrUnbalancedJournal = errors.New("journal entry does not balance")
+
+// PostingParams is one line of a journal entry. Amount is positive for a
+// debit and negative for a credit.
+type PostingParams struct {
+	LedgerAccountID int64
+	Amount          int64
+	Currency        string
+}
+
+// PostJournalEntryParams contains the input parameters of PostJournalEntry
+type PostJournalEntryParams struct {
+	Kind        string
+	Description string
+	TransferID  sql.NullInt64
+	Postings    []PostingParams
+}
+
+// PostJournalEntryResult is the journal entry written by PostJournalEntry and
+// the customer accounts it changed
+type PostJournalEntryResult struct {
+	JournalEntry JournalEntry
+	Postings     []Posting
+	// Entries holds the entry of every posting to a customer account, in
+	// posting order
+	Entries []Entry
+	// Accounts holds the updated customer accounts by id
+	Accounts map[int64]Account
+}
+
+// Validate checks that arg has at least two non-zero postings which balance
+// in every currency
+func (arg PostJournalEntryParams) Validate() error {
+	if len(arg.Postings) < 2 {
+		return fmt.Errorf("%w: a journal entry needs at least two postings", ErrUnbalancedJournal)
+	}
+
+	totals := make(map[string]int64)
+	for _, posting := range arg.Postings {
+		if posting.Amount == 0 {
+			return fmt.Errorf("%w: posting to ledger account %d has no amount", ErrUnbalancedJournal, posting.LedgerAccountID)
+		}
+		totals[posting.Currency] += posting.Amount
+	}
+
+	for currency, total := range totals {
+		if total != 0 {
+			return fmt.Errorf("%w: %s postings add up to %d", ErrUnbalancedJournal, currency, total)
+		}
+	}
+
+	return nil
+}
+
+// PostJournalEntry writes a balanced journal entry with q, which should
+// belong to a transaction. Postings to the ledger account of a customer
+// account are mirrored as an entry and a balance change on that account. The
+// credit balance of the ledger account is the customer balance, so a debit
+// is a negative entry. Balances are updated in account id order so that
+// concurrent postings cannot deadlock.
+func PostJournalEntry(ctx context.Context, q Querier, arg PostJournalEntryParams) (PostJournalEntryResult, error) {
+	var result PostJournalEntryResult
+
+	if err := arg.Validate(); err != nil {
+		return result, err
+	}
+
+	ledgerAccounts := make([]LedgerAccount, len(arg.Postings))
+	for i, posting := range arg.Postings {
+		ledgerAccount, err := q.GetLedgerAccount(ctx, posting.LedgerAccountID)
+		if err != nil {
+			return result, fmt.Errorf("cannot get ledger account %d: %w", posting.LedgerAccountID, err)
+		}
+
+		if ledgerAccount.Currency != posting.Currency {
+			return result, fmt.Errorf("%w: ledger account %d holds %s, not %s",
+				ErrUnbalancedJournal, ledgerAccount.ID, ledgerAccount.Currency, posting.Currency)
+		}
+		ledgerAccounts[i] = ledgerAccount
+	}
+
+	var err error
+	result.JournalEntry, err = q.CreateJournalEntry(ctx, CreateJournalEntryParams{
+		Kind:        arg.Kind,
+		Description: arg.Description,
+		TransferID:  arg.TransferID,
+	})
+	if err != nil {
+		return result, fmt.Errorf("cannot create journal entry: %w", err)
+	}
+
+	changes := make(map[int64]int64)
+	for i, posting := range arg.Postings {
+		created, err := q.CreatePosting(ctx, CreatePostingParams{
+			JournalEntryID:  result.JournalEntry.ID,
+			LedgerAccountID: posting.LedgerAccountID,
+			Amount:          posting.Amount,
+			Currency:        posting.Currency,
+		})
+		if err != nil {
+			return result, fmt.Errorf("cannot create posting: %w", err)
+		}
+		result.Postings = append(result.Postings, created)
+
+		if !ledgerAccounts[i].AccountID.Valid {
+			continue
+		}
+
+		accountID := ledgerAccounts[i].AccountID.Int64
+		entry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: accountID,
+			Amount:    -posting.Amount,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Entries = append(result.Entries, entry)
+		changes[accountID] -= posting.Amount
+	}
+
+	accountIDs := make([]int64, 0, len(changes))
+	for accountID := range changes {
+		accountIDs = append(accountIDs, accountID)
+	}
+	slices.Sort(accountIDs)
+
+	result.Accounts = make(map[int64]Account, len(accountIDs))
+	for _, accountID := range accountIDs {
+		account, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     accountID,
+			Amount: changes[accountID],
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Accounts[accountID] = account
+	}
+
+	return result, nil
+}
+
+// CustomerLedgerAccount returns the ledger account mirroring a customer
+// account, creating it on first use
+func CustomerLedgerAccount(ctx context.Context, q Querier, accountID int64) (LedgerAccount, error) {
+	if err := q.CreateCustomerLedgerAccount(ctx, accountID); err != nil {
+		return LedgerAccount{}, fmt.Errorf("cannot create ledger account of account %d: %w", accountID, err)
+	}
+
+	ledgerAccount, err := q.GetCustomerLedgerAccount(ctx, accountID)
+	if err != nil {
+		return LedgerAccount{}, fmt.Errorf("cannot get ledger account of account %d: %w", accountID, err)
+	}
+
+	return ledgerAccount, nil
+}
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxPostsJournalEntry(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, JournalKindTransfer, result.JournalEntry.Kind)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.ID, Valid: true}, result.JournalEntry.TransferID)
+	require.Equal(t, "Transfer from account "+account1.Number+" to account "+account2.Number, result.JournalEntry.Description)
+
+	postings, err := store.ListPostings(context.Background(), result.JournalEntry.ID)
+	require.NoError(t, err)
+	require.Len(t, postings, 2)
+
+	ledger1, err := store.GetCustomerLedgerAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, ledger1.ID, postings[0].LedgerAccountID)
+	require.Equal(t, int64(10), postings[0].Amount)
+	require.Equal(t, int64(-10), postings[1].Amount)
+	require.Equal(t, account1.Currency, postings[1].Currency)
+}
+
+func TestTransferTxRejectsCurrencyMismatch(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccountWithCurrency(t, "USD")
+	account2 := createRandomAccountWithCurrency(t, "EUR")
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrUnbalancedJournal)
+
+	updated, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updated.Balance)
+}
+
+func TestPostJournalEntryWithSystemAccount(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	err := store.CreateSystemLedgerAccount(context.Background(), CreateSystemLedgerAccountParams{
+		Code:     "1000",
+		Name:     "Cash",
+		Type:     "asset",
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	cash, err := store.GetSystemLedgerAccount(context.Background(), GetSystemLedgerAccountParams{
+		Code:     "1000",
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	var result PostJournalEntryResult
+	err = store.ExecTx(context.Background(), DefaultTxOptions, func(q Querier) error {
+		customer, err := CustomerLedgerAccount(context.Background(), q, account.ID)
+		if err != nil {
+			return err
+		}
+
+		// a cash deposit credits the customer
+		result, err = PostJournalEntry(context.Background(), q, PostJournalEntryParams{
+			Kind:        "deposit",
+			Description: "Cash deposit",
+			Postings: []PostingParams{
+				{LedgerAccountID: cash.ID, Amount: 25, Currency: account.Currency},
+				{LedgerAccountID: customer.ID, Amount: -25, Currency: account.Currency},
+			},
+		})
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+	require.Equal(t, int64(25), result.Entries[0].Amount)
+	require.Equal(t, account.Balance+25, result.Accounts[account.ID].Balance)
+}
+
+func TestJournalEntryMustBalance(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	// bypass PostJournalEntry, the deferred trigger still refuses the commit
+	err := store.ExecTx(context.Background(), DefaultTxOptions, func(q Querier) error {
+		ledgerAccount, err := CustomerLedgerAccount(context.Background(), q, account.ID)
+		if err != nil {
+			return err
+		}
+
+		journalEntry, err := q.CreateJournalEntry(context.Background(), CreateJournalEntryParams{
+			Kind:        "test",
+			Description: "one sided",
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreatePosting(context.Background(), CreatePostingParams{
+			JournalEntryID:  journalEntry.ID,
+			LedgerAccountID: ledgerAccount.ID,
+			Amount:          10,
+			Currency:        account.Currency,
+		})
+		return err
+	})
+	require.ErrorContains(t, err, "does not balance")
+}
+
+func TestPostJournalEntryParamsValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		postings []PostingParams
+		valid    bool
+	}{
+		{
+			name: "Balanced",
+			postings: []PostingParams{
+				{LedgerAccountID: 1, Amount: 10, Currency: "USD"},
+				{LedgerAccountID: 2, Amount: -7, Currency: "USD"},
+				{LedgerAccountID: 3, Amount: -3, Currency: "USD"},
+			},
+			valid: true,
+		},
+		{
+			name: "OnePosting",
+			postings: []PostingParams{
+				{LedgerAccountID: 1, Amount: 0, Currency: "USD"},
+			},
+		},
+		{
+			name: "ZeroAmount",
+			postings: []PostingParams{
+				{LedgerAccountID: 1, Amount: 0, Currency: "USD"},
+				{LedgerAccountID: 2, Amount: 0, Currency: "USD"},
+			},
+		},
+		{
+			name: "BalancedOverallButNotPerCurrency",
+			postings: []PostingParams{
+				{LedgerAccountID: 1, Amount: 10, Currency: "USD"},
+				{LedgerAccountID: 2, Amount: -10, Currency: "EUR"},
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := PostJournalEntryParams{Postings: tc.postings}.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrUnbalancedJournal)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/techschool/simplebank/kyc"
+)
+
+// KYCTierError is returned when the KYC tier of a user is too low for a
+// currency
+type KYCTierError struct {
+	Username string
+	Currency string
+	// Tier is the tier of the user, Required the lowest one allowed
+	Tier     string
+	Required string
+}
+
+func (err *KYCTierError) Error() string {
+	return fmt.Sprintf("%s requires the %s KYC tier, user %s is %s",
+		err.Currency, err.Required, err.Username, err.Tier)
+}
+
+// CheckKYCTier returns a KYCTierError unless the tier of username allows it
+// to hold and send currency
+func CheckKYCTier(ctx context.Context, q Querier, username string, currency string) error {
+	capability, err := q.GetKYCCapability(ctx, GetKYCCapabilityParams{
+		Username: username,
+		Currency: currency,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get KYC tier of %s: %w", username, err)
+	}
+
+	if !kyc.AtLeast(capability.KycTier, capability.MinKycTier) {
+		return &KYCTierError{
+			Username: username,
+			Currency: currency,
+			Tier:     capability.KycTier,
+			Required: capability.MinKycTier,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,410 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: kyc.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createKYCSubmission = `-- name: CreateKYCSubmission :one
+INSERT INTO kyc_submissions (
+  username,
+  tier,
+  document_type,
+  country,
+  legal_name,
+  date_of_birth,
+  document_number,
+  address,
+  data_key,
+  master_key_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, username, tier, status, document_type, country, legal_name, date_of_birth, document_number, address, reviewer, reason, created_at, decided_at, data_key, master_key_id
+`
+
+type CreateKYCSubmissionParams struct {
+	Username       string `json:"username"`
+	Tier           string `json:"tier"`
+	DocumentType   string `json:"document_type"`
+	Country        string `json:"country"`
+	LegalName      []byte `json:"legal_name"`
+	DateOfBirth    []byte `json:"date_of_birth"`
+	DocumentNumber []byte `json:"document_number"`
+	Address        []byte `json:"address"`
+	DataKey        []byte `json:"data_key"`
+	MasterKeyID    string `json:"master_key_id"`
+}
+
+func (q *Queries) CreateKYCSubmission(ctx context.Context, arg CreateKYCSubmissionParams) (KycSubmission, error) {
+	row := q.db.QueryRowContext(ctx, createKYCSubmission,
+		arg.Username,
+		arg.Tier,
+		arg.DocumentType,
+		arg.Country,
+		arg.LegalName,
+		arg.DateOfBirth,
+		arg.DocumentNumber,
+		arg.Address,
+		arg.DataKey,
+		arg.MasterKeyID,
+	)
+	var i KycSubmission
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Tier,
+		&i.Status,
+		&i.DocumentType,
+		&i.Country,
+		&i.LegalName,
+		&i.DateOfBirth,
+		&i.DocumentNumber,
+		&i.Address,
+		&i.Reviewer,
+		&i.Reason,
+		&i.CreatedAt,
+		&i.DecidedAt,
+		&i.DataKey,
+		&i.MasterKeyID,
+	)
+	return i, err
+}
+
+const decideKYCSubmission = `-- name: DecideKYCSubmission :one
+UPDATE kyc_submissions
+SET
+  status = $1,
+  reviewer = $2,
+  reason = $3,
+  decided_at = now()
+WHERE id = $4 AND status = 'pending'
+RETURNING id, username, tier, status, document_type, country, legal_name, date_of_birth, document_number, address, reviewer, reason, created_at, decided_at, data_key, master_key_id
+`
+
+type DecideKYCSubmissionParams struct {
+	Status   string         `json:"status"`
+	Reviewer sql.NullString `json:"reviewer"`
+	Reason   string         `json:"reason"`
+	ID       int64          `json:"id"`
+}
+
+func (q *Queries) DecideKYCSubmission(ctx context.Context, arg DecideKYCSubmissionParams) (KycSubmission, error) {
+	row := q.db.QueryRowContext(ctx, decideKYCSubmission,
+		arg.Status,
+		arg.Reviewer,
+		arg.Reason,
+		arg.ID,
+	)
+	var i KycSubmission
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Tier,
+		&i.Status,
+		&i.DocumentType,
+		&i.Country,
+		&i.LegalName,
+		&i.DateOfBirth,
+		&i.DocumentNumber,
+		&i.Address,
+		&i.Reviewer,
+		&i.Reason,
+		&i.CreatedAt,
+		&i.DecidedAt,
+		&i.DataKey,
+		&i.MasterKeyID,
+	)
+	return i, err
+}
+
+const getKYCCapability = `-- name: GetKYCCapability :one
+SELECT
+  users.kyc_tier,
+  currencies.min_kyc_tier
+FROM users, currencies
+WHERE users.username = $1 AND currencies.code = $2
+`
+
+type GetKYCCapabilityParams struct {
+	Username string `json:"username"`
+	Currency string `json:"currency"`
+}
+
+type GetKYCCapabilityRow struct {
+	KycTier    string `json:"kyc_tier"`
+	MinKycTier string `json:"min_kyc_tier"`
+}
+
+// The tier of a user next to the lowest tier allowed to use a currency
+func (q *Queries) GetKYCCapability(ctx context.Context, arg GetKYCCapabilityParams) (GetKYCCapabilityRow, error) {
+	row := q.db.QueryRowContext(ctx, getKYCCapability, arg.Username, arg.Currency)
+	var i GetKYCCapabilityRow
+	err := row.Scan(&i.KycTier, &i.MinKycTier)
+	return i, err
+}
+
+const getKYCSubmission = `-- name: GetKYCSubmission :one
+SELECT id, username, tier, status, document_type, country, legal_name, date_of_birth, document_number, address, reviewer, reason, created_at, decided_at, data_key, master_key_id FROM kyc_submissions
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetKYCSubmission(ctx context.Context, id int64) (KycSubmission, error) {
+	row := q.db.QueryRowContext(ctx, getKYCSubmission, id)
+	var i KycSubmission
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Tier,
+		&i.Status,
+		&i.DocumentType,
+		&i.Country,
+		&i.LegalName,
+		&i.DateOfBirth,
+		&i.DocumentNumber,
+		&i.Address,
+		&i.Reviewer,
+		&i.Reason,
+		&i.CreatedAt,
+		&i.DecidedAt,
+		&i.DataKey,
+		&i.MasterKeyID,
+	)
+	return i, err
+}
+
+const getLatestKYCSubmission = `-- name: GetLatestKYCSubmission :one
+SELECT id, username, tier, status, document_type, country, legal_name, date_of_birth, document_number, address, reviewer, reason, created_at, decided_at, data_key, master_key_id FROM kyc_submissions
+WHERE username = $1
+ORDER BY id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestKYCSubmission(ctx context.Context, username string) (KycSubmission, error) {
+	row := q.db.QueryRowContext(ctx, getLatestKYCSubmission, username)
+	var i KycSubmission
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Tier,
+		&i.Status,
+		&i.DocumentType,
+		&i.Country,
+		&i.LegalName,
+		&i.DateOfBirth,
+		&i.DocumentNumber,
+		&i.Address,
+		&i.Reviewer,
+		&i.Reason,
+		&i.CreatedAt,
+		&i.DecidedAt,
+		&i.DataKey,
+		&i.MasterKeyID,
+	)
+	return i, err
+}
+
+const listKYCSubmissions = `-- name: ListKYCSubmissions :many
+SELECT id, username, tier, status, document_type, country, legal_name, date_of_birth, document_number, address, reviewer, reason, created_at, decided_at, data_key, master_key_id FROM kyc_submissions
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3
+`
+
+type ListKYCSubmissionsParams struct {
+	Status string `json:"status"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+func (q *Queries) ListKYCSubmissions(ctx context.Context, arg ListKYCSubmissionsParams) ([]KycSubmission, error) {
+	rows, err := q.db.QueryContext(ctx, listKYCSubmissions, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []KycSubmission{}
+	for rows.Next() {
+		var i KycSubmission
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Tier,
+			&i.Status,
+			&i.DocumentType,
+			&i.Country,
+			&i.LegalName,
+			&i.DateOfBirth,
+			&i.DocumentNumber,
+			&i.Address,
+			&i.Reviewer,
+			&i.Reason,
+			&i.CreatedAt,
+			&i.DecidedAt,
+			&i.DataKey,
+			&i.MasterKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listKYCSubmissionsToReencrypt = `-- name: ListKYCSubmissionsToReencrypt :many
+SELECT id, username, tier, status, document_type, country, legal_name, date_of_birth, document_number, address, reviewer, reason, created_at, decided_at, data_key, master_key_id FROM kyc_submissions
+WHERE master_key_id <> $1
+ORDER BY id
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type ListKYCSubmissionsToReencryptParams struct {
+	MasterKeyID string `json:"master_key_id"`
+	BatchSize   int32  `json:"batch_size"`
+}
+
+func (q *Queries) ListKYCSubmissionsToReencrypt(ctx context.Context, arg ListKYCSubmissionsToReencryptParams) ([]KycSubmission, error) {
+	rows, err := q.db.QueryContext(ctx, listKYCSubmissionsToReencrypt, arg.MasterKeyID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []KycSubmission{}
+	for rows.Next() {
+		var i KycSubmission
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Tier,
+			&i.Status,
+			&i.DocumentType,
+			&i.Country,
+			&i.LegalName,
+			&i.DateOfBirth,
+			&i.DocumentNumber,
+			&i.Address,
+			&i.Reviewer,
+			&i.Reason,
+			&i.CreatedAt,
+			&i.DecidedAt,
+			&i.DataKey,
+			&i.MasterKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateKYCSubmissionEncryption = `-- name: UpdateKYCSubmissionEncryption :one
+UPDATE kyc_submissions
+SET
+  legal_name = $1,
+  date_of_birth = $2,
+  document_number = $3,
+  address = $4,
+  data_key = $5,
+  master_key_id = $6
+WHERE id = $7
+RETURNING id, username, tier, status, document_type, country, legal_name, date_of_birth, document_number, address, reviewer, reason, created_at, decided_at, data_key, master_key_id
+`
+
+type UpdateKYCSubmissionEncryptionParams struct {
+	LegalName      []byte `json:"legal_name"`
+	DateOfBirth    []byte `json:"date_of_birth"`
+	DocumentNumber []byte `json:"document_number"`
+	Address        []byte `json:"address"`
+	DataKey        []byte `json:"data_key"`
+	MasterKeyID    string `json:"master_key_id"`
+	ID             int64  `json:"id"`
+}
+
+func (q *Queries) UpdateKYCSubmissionEncryption(ctx context.Context, arg UpdateKYCSubmissionEncryptionParams) (KycSubmission, error) {
+	row := q.db.QueryRowContext(ctx, updateKYCSubmissionEncryption,
+		arg.LegalName,
+		arg.DateOfBirth,
+		arg.DocumentNumber,
+		arg.Address,
+		arg.DataKey,
+		arg.MasterKeyID,
+		arg.ID,
+	)
+	var i KycSubmission
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Tier,
+		&i.Status,
+		&i.DocumentType,
+		&i.Country,
+		&i.LegalName,
+		&i.DateOfBirth,
+		&i.DocumentNumber,
+		&i.Address,
+		&i.Reviewer,
+		&i.Reason,
+		&i.CreatedAt,
+		&i.DecidedAt,
+		&i.DataKey,
+		&i.MasterKeyID,
+	)
+	return i, err
+}
+
+const updateUserKYCTier = `-- name: UpdateUserKYCTier :one
+UPDATE users
+SET kyc_tier = $1
+WHERE username = $2
+RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index
+`
+
+type UpdateUserKYCTierParams struct {
+	KycTier  string `json:"kyc_tier"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) UpdateUserKYCTier(ctx context.Context, arg UpdateUserKYCTierParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserKYCTier, arg.KycTier, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.ScreeningStatus,
+		&i.ScreeningListVersion,
+		&i.ScreenedAt,
+		&i.KycTier,
+		&i.FullNameSealed,
+		&i.EmailSealed,
+		&i.EmailIndex,
+		&i.DataKey,
+		&i.MasterKeyID,
+		&i.Phone,
+		&i.PhoneSealed,
+		&i.PhoneIndex,
+	)
+	return i, err
+}
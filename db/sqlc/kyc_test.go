@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/kyc"
+	"github.com/techschool/simplebank/util"
+)
+
+func createRandomKYCSubmission(t *testing.T, user User) KycSubmission {
+	arg := CreateKYCSubmissionParams{
+		Username:       user.Username,
+		Tier:           kyc.TierBasic,
+		DocumentType:   kyc.DocumentPassport,
+		Country:        "KE",
+		LegalName:      []byte(util.RandomString(16)),
+		DateOfBirth:    []byte(util.RandomString(16)),
+		DocumentNumber: []byte(util.RandomString(16)),
+		Address:        []byte(util.RandomString(16)),
+	}
+
+	submission, err := testQueries.CreateKYCSubmission(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, kyc.StatusPending, submission.Status)
+	require.Equal(t, arg.LegalName, submission.LegalName)
+	require.False(t, submission.DecidedAt.Valid)
+
+	return submission
+}
+
+func TestKYCSubmissionOnePending(t *testing.T) {
+	user := createRandomUser(t)
+	require.Equal(t, kyc.TierUnverified, user.KycTier)
+
+	submission := createRandomKYCSubmission(t, user)
+
+	_, err := testQueries.CreateKYCSubmission(context.Background(), CreateKYCSubmissionParams{
+		Username:       user.Username,
+		Tier:           kyc.TierFull,
+		DocumentType:   kyc.DocumentPassport,
+		Country:        "KE",
+		LegalName:      submission.LegalName,
+		DateOfBirth:    submission.DateOfBirth,
+		DocumentNumber: submission.DocumentNumber,
+		Address:        submission.Address,
+	})
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	require.Equal(t, "unique_violation", pqErr.Code.Name())
+
+	reviewer := createRandomUser(t)
+	arg := DecideKYCSubmissionParams{
+		ID:       submission.ID,
+		Status:   kyc.StatusRejected,
+		Reviewer: sql.NullString{String: reviewer.Username, Valid: true},
+		Reason:   "document expired",
+	}
+	decided, err := testQueries.DecideKYCSubmission(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, kyc.StatusRejected, decided.Status)
+	require.True(t, decided.DecidedAt.Valid)
+
+	// a decision is made once
+	arg.Status = kyc.StatusApproved
+	_, err = testQueries.DecideKYCSubmission(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// the user may try again once the last submission is decided
+	latest := createRandomKYCSubmission(t, user)
+	got, err := testQueries.GetLatestKYCSubmission(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, latest.ID, got.ID)
+}
+
+func TestTransferTxChecksKYCTier(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccountWithCurrency(t, util.EUR)
+	account2 := createRandomAccountWithCurrency(t, util.EUR)
+
+	_, err := testQueries.UpdateUserKYCTier(context.Background(), UpdateUserKYCTierParams{
+		KycTier:  kyc.TierUnverified,
+		Username: account1.Owner,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	var tierErr *KYCTierError
+	require.ErrorAs(t, err, &tierErr)
+	require.Equal(t, kyc.TierUnverified, tierErr.Tier)
+	require.Equal(t, kyc.TierBasic, tierErr.Required)
+
+	// basic users may send euros within the limits of their tier
+	_, err = testQueries.UpdateUserKYCTier(context.Background(), UpdateUserKYCTierParams{
+		KycTier:  kyc.TierBasic,
+		Username: account1.Owner,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.NoError(t, err)
+
+	usages, err := AccountLimitUsage(context.Background(), store, account1, time.Now())
+	require.NoError(t, err)
+	require.NotEmpty(t, usages)
+}
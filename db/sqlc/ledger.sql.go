@@ -0,0 +1,330 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: ledger.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createCustomerLedgerAccount = `-- name: CreateCustomerLedgerAccount :exec
+INSERT INTO ledger_accounts (
+  code,
+  name,
+  type,
+  currency,
+  account_id
+)
+SELECT '2000', 'Customer account ' || a.id, 'liability', a.currency, a.id
+FROM accounts a
+WHERE a.id = $1
+ON CONFLICT (account_id) DO NOTHING
+`
+
+func (q *Queries) CreateCustomerLedgerAccount(ctx context.Context, accountID int64) error {
+	_, err := q.db.ExecContext(ctx, createCustomerLedgerAccount, accountID)
+	return err
+}
+
+const createJournalEntry = `-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+  kind,
+  description,
+  transfer_id
+) VALUES (
+  $1, $2, $3
+) RETURNING id, kind, description, transfer_id, created_at
+`
+
+type CreateJournalEntryParams struct {
+	Kind        string        `json:"kind"`
+	Description string        `json:"description"`
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+}
+
+func (q *Queries) CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error) {
+	row := q.db.QueryRowContext(ctx, createJournalEntry, arg.Kind, arg.Description, arg.TransferID)
+	var i JournalEntry
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Description,
+		&i.TransferID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPosting = `-- name: CreatePosting :one
+INSERT INTO postings (
+  journal_entry_id,
+  ledger_account_id,
+  amount,
+  currency
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, journal_entry_id, ledger_account_id, amount, currency, created_at
+`
+
+type CreatePostingParams struct {
+	JournalEntryID  int64  `json:"journal_entry_id"`
+	LedgerAccountID int64  `json:"ledger_account_id"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+}
+
+func (q *Queries) CreatePosting(ctx context.Context, arg CreatePostingParams) (Posting, error) {
+	row := q.db.QueryRowContext(ctx, createPosting,
+		arg.JournalEntryID,
+		arg.LedgerAccountID,
+		arg.Amount,
+		arg.Currency,
+	)
+	var i Posting
+	err := row.Scan(
+		&i.ID,
+		&i.JournalEntryID,
+		&i.LedgerAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSystemLedgerAccount = `-- name: CreateSystemLedgerAccount :exec
+INSERT INTO ledger_accounts (
+  code,
+  name,
+  type,
+  currency
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (code, currency) WHERE account_id IS NULL DO NOTHING
+`
+
+type CreateSystemLedgerAccountParams struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) CreateSystemLedgerAccount(ctx context.Context, arg CreateSystemLedgerAccountParams) error {
+	_, err := q.db.ExecContext(ctx, createSystemLedgerAccount,
+		arg.Code,
+		arg.Name,
+		arg.Type,
+		arg.Currency,
+	)
+	return err
+}
+
+const getCustomerLedgerAccount = `-- name: GetCustomerLedgerAccount :one
+SELECT id, code, name, type, currency, account_id, created_at FROM ledger_accounts
+WHERE account_id = $1::bigint
+LIMIT 1
+`
+
+func (q *Queries) GetCustomerLedgerAccount(ctx context.Context, accountID int64) (LedgerAccount, error) {
+	row := q.db.QueryRowContext(ctx, getCustomerLedgerAccount, accountID)
+	var i LedgerAccount
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Type,
+		&i.Currency,
+		&i.AccountID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLedgerAccount = `-- name: GetLedgerAccount :one
+SELECT id, code, name, type, currency, account_id, created_at FROM ledger_accounts
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetLedgerAccount(ctx context.Context, id int64) (LedgerAccount, error) {
+	row := q.db.QueryRowContext(ctx, getLedgerAccount, id)
+	var i LedgerAccount
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Type,
+		&i.Currency,
+		&i.AccountID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSystemLedgerAccount = `-- name: GetSystemLedgerAccount :one
+SELECT id, code, name, type, currency, account_id, created_at FROM ledger_accounts
+WHERE code = $1 AND currency = $2 AND account_id IS NULL
+LIMIT 1
+`
+
+type GetSystemLedgerAccountParams struct {
+	Code     string `json:"code"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetSystemLedgerAccount(ctx context.Context, arg GetSystemLedgerAccountParams) (LedgerAccount, error) {
+	row := q.db.QueryRowContext(ctx, getSystemLedgerAccount, arg.Code, arg.Currency)
+	var i LedgerAccount
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Type,
+		&i.Currency,
+		&i.AccountID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTrialBalance = `-- name: GetTrialBalance :many
+SELECT
+  l.code,
+  l.type,
+  l.currency,
+  COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0)::bigint AS debits,
+  COALESCE(-SUM(p.amount) FILTER (WHERE p.amount < 0), 0)::bigint AS credits
+FROM ledger_accounts l
+LEFT JOIN postings p ON p.ledger_account_id = l.id AND p.created_at < $1
+GROUP BY l.code, l.type, l.currency
+ORDER BY l.currency, l.code
+`
+
+type GetTrialBalanceRow struct {
+	Code     string `json:"code"`
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+	Debits   int64  `json:"debits"`
+	Credits  int64  `json:"credits"`
+}
+
+// Customer ledger accounts share their chart code and roll up into it.
+func (q *Queries) GetTrialBalance(ctx context.Context, asOf time.Time) ([]GetTrialBalanceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrialBalance, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTrialBalanceRow{}
+	for rows.Next() {
+		var i GetTrialBalanceRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Type,
+			&i.Currency,
+			&i.Debits,
+			&i.Credits,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLedgerDrift = `-- name: ListLedgerDrift :many
+SELECT
+  a.id,
+  a.currency,
+  a.balance,
+  COALESCE(-SUM(p.amount), 0)::bigint AS ledger_balance
+FROM accounts a
+LEFT JOIN ledger_accounts l ON l.account_id = a.id
+LEFT JOIN postings p ON p.ledger_account_id = l.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(-SUM(p.amount), 0)
+ORDER BY a.id
+`
+
+type ListLedgerDriftRow struct {
+	ID            int64  `json:"id"`
+	Currency      string `json:"currency"`
+	Balance       int64  `json:"balance"`
+	LedgerBalance int64  `json:"ledger_balance"`
+}
+
+// A customer account holds the credit balance of its ledger account.
+func (q *Queries) ListLedgerDrift(ctx context.Context) ([]ListLedgerDriftRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLedgerDrift)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLedgerDriftRow{}
+	for rows.Next() {
+		var i ListLedgerDriftRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Balance,
+			&i.LedgerBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostings = `-- name: ListPostings :many
+SELECT id, journal_entry_id, ledger_account_id, amount, currency, created_at FROM postings
+WHERE journal_entry_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListPostings(ctx context.Context, journalEntryID int64) ([]Posting, error) {
+	rows, err := q.db.QueryContext(ctx, listPostings, journalEntryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Posting{}
+	for rows.Next() {
+		var i Posting
+		if err := rows.Scan(
+			&i.ID,
+			&i.JournalEntryID,
+			&i.LedgerAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
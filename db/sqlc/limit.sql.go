@@ -0,0 +1,202 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: limit.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const getTransferUsage = `-- name: GetTransferUsage :one
+SELECT
+  COALESCE(SUM(amount) FILTER (WHERE created_at >= $1), 0)::bigint AS daily_amount,
+  COUNT(*) FILTER (WHERE created_at >= $1)::int AS daily_count,
+  COALESCE(SUM(amount), 0)::bigint AS monthly_amount,
+  COUNT(*)::int AS monthly_count
+FROM transfers
+WHERE from_account_id = $2 AND created_at >= $3
+`
+
+type GetTransferUsageParams struct {
+	DayStart   time.Time `json:"day_start"`
+	AccountID  int64     `json:"account_id"`
+	MonthStart time.Time `json:"month_start"`
+}
+
+type GetTransferUsageRow struct {
+	DailyAmount   int64 `json:"daily_amount"`
+	DailyCount    int32 `json:"daily_count"`
+	MonthlyAmount int64 `json:"monthly_amount"`
+	MonthlyCount  int32 `json:"monthly_count"`
+}
+
+// Outgoing transfers of an account since the start of the month, and the
+// part of them since the start of the day
+func (q *Queries) GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, getTransferUsage, arg.DayStart, arg.AccountID, arg.MonthStart)
+	var i GetTransferUsageRow
+	err := row.Scan(
+		&i.DailyAmount,
+		&i.DailyCount,
+		&i.MonthlyAmount,
+		&i.MonthlyCount,
+	)
+	return i, err
+}
+
+const listApplicableTransferLimits = `-- name: ListApplicableTransferLimits :many
+SELECT id, scope, subject, currency, per_transfer, daily_amount, monthly_amount, daily_count, monthly_count, created_at, updated_at FROM transfer_limits
+WHERE currency = $1 AND (
+  scope = 'global' OR
+  (scope = 'product' AND subject = $2::varchar) OR
+  (scope = 'tier' AND subject = (SELECT kyc_tier FROM users WHERE users.username = $3::varchar)) OR
+  (scope = 'user' AND subject = $3::varchar)
+)
+`
+
+type ListApplicableTransferLimitsParams struct {
+	Currency    string `json:"currency"`
+	ProductCode string `json:"product_code"`
+	Username    string `json:"username"`
+}
+
+func (q *Queries) ListApplicableTransferLimits(ctx context.Context, arg ListApplicableTransferLimitsParams) ([]TransferLimit, error) {
+	rows, err := q.db.QueryContext(ctx, listApplicableTransferLimits, arg.Currency, arg.ProductCode, arg.Username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferLimit{}
+	for rows.Next() {
+		var i TransferLimit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Scope,
+			&i.Subject,
+			&i.Currency,
+			&i.PerTransfer,
+			&i.DailyAmount,
+			&i.MonthlyAmount,
+			&i.DailyCount,
+			&i.MonthlyCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransferLimits = `-- name: ListTransferLimits :many
+SELECT id, scope, subject, currency, per_transfer, daily_amount, monthly_amount, daily_count, monthly_count, created_at, updated_at FROM transfer_limits
+ORDER BY scope, subject, currency
+`
+
+func (q *Queries) ListTransferLimits(ctx context.Context) ([]TransferLimit, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferLimits)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferLimit{}
+	for rows.Next() {
+		var i TransferLimit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Scope,
+			&i.Subject,
+			&i.Currency,
+			&i.PerTransfer,
+			&i.DailyAmount,
+			&i.MonthlyAmount,
+			&i.DailyCount,
+			&i.MonthlyCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTransferLimit = `-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+  scope,
+  subject,
+  currency,
+  per_transfer,
+  daily_amount,
+  monthly_amount,
+  daily_count,
+  monthly_count
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (scope, subject, currency) DO UPDATE SET
+  per_transfer = EXCLUDED.per_transfer,
+  daily_amount = EXCLUDED.daily_amount,
+  monthly_amount = EXCLUDED.monthly_amount,
+  daily_count = EXCLUDED.daily_count,
+  monthly_count = EXCLUDED.monthly_count,
+  updated_at = now()
+RETURNING id, scope, subject, currency, per_transfer, daily_amount, monthly_amount, daily_count, monthly_count, created_at, updated_at
+`
+
+type UpsertTransferLimitParams struct {
+	Scope         string        `json:"scope"`
+	Subject       string        `json:"subject"`
+	Currency      string        `json:"currency"`
+	PerTransfer   sql.NullInt64 `json:"per_transfer"`
+	DailyAmount   sql.NullInt64 `json:"daily_amount"`
+	MonthlyAmount sql.NullInt64 `json:"monthly_amount"`
+	DailyCount    sql.NullInt32 `json:"daily_count"`
+	MonthlyCount  sql.NullInt32 `json:"monthly_count"`
+}
+
+func (q *Queries) UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error) {
+	row := q.db.QueryRowContext(ctx, upsertTransferLimit,
+		arg.Scope,
+		arg.Subject,
+		arg.Currency,
+		arg.PerTransfer,
+		arg.DailyAmount,
+		arg.MonthlyAmount,
+		arg.DailyCount,
+		arg.MonthlyCount,
+	)
+	var i TransferLimit
+	err := row.Scan(
+		&i.ID,
+		&i.Scope,
+		&i.Subject,
+		&i.Currency,
+		&i.PerTransfer,
+		&i.DailyAmount,
+		&i.MonthlyAmount,
+		&i.DailyCount,
+		&i.MonthlyCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
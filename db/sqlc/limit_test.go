@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxEnforcesLimits(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	_, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Scope:       LimitScopeUser,
+		Subject:     account1.Owner,
+		Currency:    account1.Currency,
+		PerTransfer: sql.NullInt64{Int64: 10, Valid: true},
+		DailyCount:  sql.NullInt32{Int32: 1, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitPerTransfer, limitErr.Name)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitDailyCount, limitErr.Name)
+	require.Zero(t, limitErr.Remaining)
+
+	usages, err := AccountLimitUsage(context.Background(), store, account1, time.Now())
+	require.NoError(t, err)
+	for _, usage := range usages {
+		if usage.Name == LimitDailyCount {
+			require.Equal(t, int64(1), usage.Used)
+		}
+	}
+
+	// the limits of another user do not apply
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        11,
+	})
+	require.NoError(t, err)
+}
+
+func TestResolveLimits(t *testing.T) {
+	resolved := resolveLimits([]TransferLimit{
+		{Scope: LimitScopeUser, DailyAmount: sql.NullInt64{Int64: 5, Valid: true}},
+		{Scope: LimitScopeGlobal, DailyAmount: sql.NullInt64{Int64: 100, Valid: true}, PerTransfer: sql.NullInt64{Int64: 50, Valid: true}},
+		{Scope: LimitScopeProduct, PerTransfer: sql.NullInt64{Int64: 20, Valid: true}, MonthlyAmount: sql.NullInt64{Int64: 900, Valid: true}},
+		{Scope: LimitScopeTier, PerTransfer: sql.NullInt64{Int64: 10, Valid: true}, DailyAmount: sql.NullInt64{Int64: 30, Valid: true}},
+	})
+
+	require.Equal(t, int64(5), *resolved.dailyAmount)
+	require.Equal(t, int64(10), *resolved.perTransfer)
+	require.Equal(t, int64(900), *resolved.monthlyAmount)
+	require.Nil(t, resolved.dailyCount)
+
+	// a tier limit above a stricter product limit does not raise it
+	resolved = resolveLimits([]TransferLimit{
+		{Scope: LimitScopeProduct, PerTransfer: sql.NullInt64{Int64: 20, Valid: true}},
+		{Scope: LimitScopeTier, PerTransfer: sql.NullInt64{Int64: 500, Valid: true}, DailyCount: sql.NullInt32{Int32: 3, Valid: true}},
+	})
+
+	require.Equal(t, int64(20), *resolved.perTransfer)
+	require.Equal(t, int64(3), *resolved.dailyCount)
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Scopes of transfer limits, from the broadest to the most specific. Tier
+// limits only lower the limits of the broader scopes.
+const (
+	LimitScopeGlobal  = "global"
+	LimitScopeProduct = "product"
+	LimitScopeTier    = "tier"
+	LimitScopeUser    = "user"
+)
+
+// Transfer limits checked on every outgoing transfer of an account
+const (
+	LimitPerTransfer   = "per_transfer"
+	LimitDailyAmount   = "daily_amount"
+	LimitMonthlyAmount = "monthly_amount"
+	LimitDailyCount    = "daily_count"
+	LimitMonthlyCount  = "monthly_count"
+)
+
+// LimitUsage is how much of one limit an account has used in the current
+// window. Amounts are in minor units of Currency, counts are transfers.
+type LimitUsage struct {
+	Name      string `json:"name"`
+	Currency  string `json:"currency"`
+	Limit     int64  `json:"limit"`
+	Used      int64  `json:"used"`
+	Remaining int64  `json:"remaining"`
+}
+
+// IsCount reports whether the limit counts transfers rather than money
+func (usage LimitUsage) IsCount() bool {
+	return usage.Name == LimitDailyCount || usage.Name == LimitMonthlyCount
+}
+
+// LimitExceededError is returned by TransferTx when a transfer would go
+// over a limit of the sending account
+type LimitExceededError struct {
+	LimitUsage
+	// Requested is the amount of the rejected transfer
+	Requested int64
+}
+
+func (err *LimitExceededError) Error() string {
+	if err.IsCount() {
+		return fmt.Sprintf("transfer exceeds the %s limit of %d transfers, %d remaining",
+			err.Name, err.Limit, err.Remaining)
+	}
+
+	return fmt.Sprintf("transfer of %d %s exceeds the %s limit of %d, %d remaining",
+		err.Requested, err.Currency, err.Name, err.Limit, err.Remaining)
+}
+
+// resolvedLimits holds the limits of an account after overrides, a null
+// field means there is no such limit
+type resolvedLimits struct {
+	perTransfer   *int64
+	dailyAmount   *int64
+	monthlyAmount *int64
+	dailyCount    *int64
+	monthlyCount  *int64
+}
+
+// resolveLimits applies the limits of every scope in turn, so that each
+// field is taken from the most specific scope that sets it. Tier limits are
+// a cap instead: they never raise a stricter product or global limit, while
+// user limits still override everything before them.
+func resolveLimits(limits []TransferLimit) resolvedLimits {
+	var resolved resolvedLimits
+
+	for _, scope := range []string{LimitScopeGlobal, LimitScopeProduct, LimitScopeTier, LimitScopeUser} {
+		apply := overrideLimit
+		if scope == LimitScopeTier {
+			apply = capLimit
+		}
+
+		for _, limit := range limits {
+			if limit.Scope != scope {
+				continue
+			}
+			if limit.PerTransfer.Valid {
+				apply(&resolved.perTransfer, limit.PerTransfer.Int64)
+			}
+			if limit.DailyAmount.Valid {
+				apply(&resolved.dailyAmount, limit.DailyAmount.Int64)
+			}
+			if limit.MonthlyAmount.Valid {
+				apply(&resolved.monthlyAmount, limit.MonthlyAmount.Int64)
+			}
+			if limit.DailyCount.Valid {
+				apply(&resolved.dailyCount, int64(limit.DailyCount.Int32))
+			}
+			if limit.MonthlyCount.Valid {
+				apply(&resolved.monthlyCount, int64(limit.MonthlyCount.Int32))
+			}
+		}
+	}
+
+	return resolved
+}
+
+// overrideLimit replaces the limit in field with value
+func overrideLimit(field **int64, value int64) {
+	*field = &value
+}
+
+// capLimit lowers the limit in field to value, or sets it if there is none
+func capLimit(field **int64, value int64) {
+	if *field == nil || value < **field {
+		*field = &value
+	}
+}
+
+// AccountLimitUsage returns every limit that applies to the outgoing
+// transfers of account with what was used of it so far. Days and months
+// are UTC calendar windows around now.
+func AccountLimitUsage(ctx context.Context, q Querier, account Account, now time.Time) ([]LimitUsage, error) {
+	limits, err := q.ListApplicableTransferLimits(ctx, ListApplicableTransferLimitsParams{
+		Currency:    account.Currency,
+		ProductCode: account.ProductCode,
+		Username:    account.Owner,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list transfer limits: %w", err)
+	}
+
+	resolved := resolveLimits(limits)
+
+	year, month, day := now.UTC().Date()
+	usage, err := q.GetTransferUsage(ctx, GetTransferUsageParams{
+		AccountID:  account.ID,
+		DayStart:   time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		MonthStart: time.Date(year, month, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get transfer usage: %w", err)
+	}
+
+	var usages []LimitUsage
+	add := func(name string, limit *int64, used int64) {
+		if limit == nil {
+			return
+		}
+		usages = append(usages, LimitUsage{
+			Name:      name,
+			Currency:  account.Currency,
+			Limit:     *limit,
+			Used:      used,
+			Remaining: max(*limit-used, 0),
+		})
+	}
+	add(LimitPerTransfer, resolved.perTransfer, 0)
+	add(LimitDailyAmount, resolved.dailyAmount, usage.DailyAmount)
+	add(LimitMonthlyAmount, resolved.monthlyAmount, usage.MonthlyAmount)
+	add(LimitDailyCount, resolved.dailyCount, int64(usage.DailyCount))
+	add(LimitMonthlyCount, resolved.monthlyCount, int64(usage.MonthlyCount))
+
+	return usages, nil
+}
+
+// checkTransferLimits returns a LimitExceededError if sending amount from
+// account would go over any of its limits. The account must be locked so
+// that concurrent transfers are counted one after the other.
+func checkTransferLimits(ctx context.Context, q Querier, account Account, amount int64, now time.Time) error {
+	usages, err := AccountLimitUsage(ctx, q, account, now)
+	if err != nil {
+		return err
+	}
+
+	for _, usage := range usages {
+		requested := amount
+		if usage.IsCount() {
+			requested = 1
+		}
+
+		if requested > usage.Remaining {
+			return &LimitExceededError{LimitUsage: usage, Requested: amount}
+		}
+	}
+
+	return nil
+}
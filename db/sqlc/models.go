@@ -5,17 +5,57 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
+	ID          int64     `json:"id"`
+	Owner       string    `json:"owner"`
+	Balance     int64     `json:"balance"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+	ProductCode string    `json:"product_code"`
+	// public account number shown to customers instead of the id, see accountnumber.Validate
+	Number string `json:"number"`
+}
+
+// recipients resolved by each user from an alias in the current rate limit window
+type AliasLookup struct {
+	Username    string    `json:"username"`
+	WindowStart time.Time `json:"window_start"`
+	Lookups     int32     `json:"lookups"`
+}
+
+type AuditEvent struct {
+	ID           int64           `json:"id"`
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	ClientIp     string          `json:"client_ip"`
+	UserAgent    string          `json:"user_agent"`
+	Diff         json.RawMessage `json:"diff"`
+	PrevHash     string          `json:"prev_hash"`
+	// sha256 of prev_hash and the event fields
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Currency struct {
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+	// number of minor-unit digits, amounts are stored in minor units
+	Exponent int32  `json:"exponent"`
+	Symbol   string `json:"symbol"`
+	// whether new accounts and transfers may use the currency
+	Enabled   bool      `json:"enabled"`
 	CreatedAt time.Time `json:"created_at"`
+	// lowest KYC tier allowed to open accounts and send transfers in the currency
+	MinKycTier string `json:"min_kyc_tier"`
 }
 
 type Entry struct {
@@ -26,6 +66,143 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type InterestAccrual struct {
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	// balance at the end of accrual_date
+	Balance       int64 `json:"balance"`
+	AnnualRateBps int32 `json:"annual_rate_bps"`
+	// balance * annual_rate_bps, in 1/3650000 of the minor unit (actual/365)
+	Accrued   int64         `json:"accrued"`
+	PostingID sql.NullInt64 `json:"posting_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type InterestPosting struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// first day of the month posted
+	Period  time.Time `json:"period"`
+	Accrued int64     `json:"accrued"`
+	Amount  int64     `json:"amount"`
+	// accrued interest below one minor unit, carried into the next period
+	Carried        int64         `json:"carried"`
+	JournalEntryID sql.NullInt64 `json:"journal_entry_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type InterestRate struct {
+	ID          int64  `json:"id"`
+	ProductCode string `json:"product_code"`
+	// annual rate in basis points, applies from effective_from until the next rate of the product
+	AnnualRateBps int32     `json:"annual_rate_bps"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type JournalEntry struct {
+	ID          int64         `json:"id"`
+	Kind        string        `json:"kind"`
+	Description string        `json:"description"`
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type KycSubmission struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// tier the user asks for
+	Tier         string `json:"tier"`
+	Status       string `json:"status"`
+	DocumentType string `json:"document_type"`
+	Country      string `json:"country"`
+	// encrypted, see package crypt
+	LegalName []byte `json:"legal_name"`
+	// encrypted, see package crypt
+	DateOfBirth []byte `json:"date_of_birth"`
+	// encrypted, see package crypt
+	DocumentNumber []byte `json:"document_number"`
+	// encrypted, see package crypt
+	Address   []byte         `json:"address"`
+	Reviewer  sql.NullString `json:"reviewer"`
+	Reason    string         `json:"reason"`
+	CreatedAt time.Time      `json:"created_at"`
+	DecidedAt sql.NullTime   `json:"decided_at"`
+	DataKey   []byte         `json:"data_key"`
+	// empty while the identity details are sealed directly under a master key
+	MasterKeyID string `json:"master_key_id"`
+}
+
+type LedgerAccount struct {
+	ID int64 `json:"id"`
+	// chart of accounts code, shared by all customer accounts
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// asset, liability, equity, income or expense
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+	// customer account mirrored by this ledger account
+	AccountID sql.NullInt64 `json:"account_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Outbox struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	// pending, published or dead
+	Status        string       `json:"status"`
+	Attempts      int32        `json:"attempts"`
+	LastError     string       `json:"last_error"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	PublishedAt   sql.NullTime `json:"published_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type Payee struct {
+	ID        int64  `json:"id"`
+	Owner     string `json:"owner"`
+	Nickname  string `json:"nickname"`
+	AccountID int64  `json:"account_id"`
+	// currency of the account, which never changes
+	Currency string `json:"currency"`
+	// transfers to the payee are allowed from then on, once the cooling-off period ends or the owner confirms it
+	ActiveFrom  time.Time    `json:"active_from"`
+	ConfirmedAt sql.NullTime `json:"confirmed_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	// public number of account_id, which never changes
+	AccountNumber string `json:"account_number"`
+}
+
+type PaymentFile struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	// GrpHdr/MsgId of the pain.001 message, unique per owner
+	MessageID        string `json:"message_id"`
+	NumberOfPayments int32  `json:"number_of_payments"`
+	// GrpHdr/CtrlSum of the pain.001 message as sent, null if the message had none
+	ControlSum sql.NullString `json:"control_sum"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type Posting struct {
+	ID              int64 `json:"id"`
+	JournalEntryID  int64 `json:"journal_entry_id"`
+	LedgerAccountID int64 `json:"ledger_account_id"`
+	// positive for a debit, negative for a credit
+	Amount    int64     `json:"amount"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Product struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Session struct {
 	ID           uuid.UUID `json:"id"`
 	Username     string    `json:"username"`
@@ -46,11 +223,108 @@ type Transfer struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type TransferLimit struct {
+	ID int64 `json:"id"`
+	// global, product, tier or user; product overrides global, tier caps both and user overrides the others
+	Scope string `json:"scope"`
+	// product code, KYC tier or username the limits apply to, empty for global
+	Subject  string `json:"subject"`
+	Currency string `json:"currency"`
+	// in minor units, null falls through to the next scope
+	PerTransfer   sql.NullInt64 `json:"per_transfer"`
+	DailyAmount   sql.NullInt64 `json:"daily_amount"`
+	MonthlyAmount sql.NullInt64 `json:"monthly_amount"`
+	DailyCount    sql.NullInt32 `json:"daily_count"`
+	MonthlyCount  sql.NullInt32 `json:"monthly_count"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type TransferReview struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	// pending, approved or rejected
+	Status string `json:"status"`
+	// rules that held the transfer, with their decision and reason
+	Hits     json.RawMessage `json:"hits"`
+	Reviewer sql.NullString  `json:"reviewer"`
+	Reason   string          `json:"reason"`
+	// transfer made when the review was approved
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	DecidedAt  sql.NullTime  `json:"decided_at"`
+}
+
 type User struct {
-	Username         string    `json:"username"`
-	HashedPassword   string    `json:"hashed_password"`
-	FullName         string    `json:"full_name"`
+	Username       string `json:"username"`
+	HashedPassword string `json:"hashed_password"`
+	// empty once the user is encrypted into full_name_sealed
+	FullName string `json:"full_name"`
+	// empty once the user is encrypted into email_sealed
 	Email            string    `json:"email"`
 	PasswordChangeAt time.Time `json:"password_change_at"`
 	CreatedAt        time.Time `json:"created_at"`
+	Role             string    `json:"role"`
+	// unscreened, clear, flagged or blocked
+	ScreeningStatus string `json:"screening_status"`
+	// sha256 of the sanctions list the user was last screened against
+	ScreeningListVersion string       `json:"screening_list_version"`
+	ScreenedAt           sql.NullTime `json:"screened_at"`
+	// unverified, basic or full; raised when a banker approves a KYC submission
+	KycTier        string `json:"kyc_tier"`
+	FullNameSealed []byte `json:"full_name_sealed"`
+	EmailSealed    []byte `json:"email_sealed"`
+	// HMAC of the email, see crypt.Envelope.BlindIndex
+	EmailIndex []byte `json:"email_index"`
+	// data key of the user wrapped by the master key master_key_id
+	DataKey []byte `json:"data_key"`
+	// empty while the user is stored in plaintext
+	MasterKeyID string `json:"master_key_id"`
+	// E.164 number, empty if the user has none or once encrypted into phone_sealed
+	Phone       string `json:"phone"`
+	PhoneSealed []byte `json:"phone_sealed"`
+	// HMAC of the phone number, see crypt.Envelope.BlindIndex
+	PhoneIndex []byte `json:"phone_index"`
+}
+
+type UserScreening struct {
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	ListVersion string `json:"list_version"`
+	Status      string `json:"status"`
+	// similarity of the full name to the closest listed name, from 0 to 1
+	Score       float64   `json:"score"`
+	MatchedUid  string    `json:"matched_uid"`
+	MatchedName string    `json:"matched_name"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	// pending, succeeded or failed
+	Status         string       `json:"status"`
+	Attempts       int32        `json:"attempts"`
+	ResponseStatus int32        `json:"response_status"`
+	LastError      string       `json:"last_error"`
+	NextAttemptAt  time.Time    `json:"next_attempt_at"`
+	DeliveredAt    sql.NullTime `json:"delivered_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	Owner      string    `json:"owner"`
+	Url        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
 }
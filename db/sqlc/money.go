@@ -0,0 +1,8 @@
+package db
+
+import "github.com/techschool/simplebank/money"
+
+// BalanceMoney returns the balance of the account in its currency
+func (account Account) BalanceMoney() money.Money {
+	return money.New(account.Balance, account.Currency)
+}
@@ -0,0 +1,187 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: outbox.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+WITH due AS (
+  SELECT o.id FROM outbox o
+  WHERE o.status = 'pending'
+    AND o.next_attempt_at <= now()
+    AND NOT EXISTS (
+      SELECT 1 FROM outbox p
+      WHERE p.aggregate_type = o.aggregate_type
+        AND p.aggregate_id = o.aggregate_id
+        AND p.status = 'pending'
+        AND p.id < o.id
+    )
+  ORDER BY o.id
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE outbox
+SET next_attempt_at = $1
+FROM due
+WHERE outbox.id = due.id
+RETURNING outbox.id, outbox.aggregate_type, outbox.aggregate_id, outbox.event_type, outbox.payload, outbox.status, outbox.attempts, outbox.last_error, outbox.next_attempt_at, outbox.published_at, outbox.created_at
+`
+
+type ClaimOutboxEventsParams struct {
+	LeaseUntil time.Time `json:"lease_until"`
+	BatchSize  int32     `json:"batch_size"`
+}
+
+// Only the oldest pending event of each aggregate is due, so events of one
+// aggregate are published in order. Claimed events are not due again until
+// lease_until, so they can be published without holding a lock, and stay
+// pending so that they keep holding back their aggregate.
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]Outbox, error) {
+	rows, err := q.db.QueryContext(ctx, claimOutboxEvents, arg.LeaseUntil, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Outbox{}
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.PublishedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createOutboxEvent = `-- name: CreateOutboxEvent :one
+INSERT INTO outbox (
+  aggregate_type,
+  aggregate_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, aggregate_type, aggregate_id, event_type, payload, status, attempts, last_error, next_attempt_at, published_at, created_at
+`
+
+type CreateOutboxEventParams struct {
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (Outbox, error) {
+	row := q.db.QueryRowContext(ctx, createOutboxEvent,
+		arg.AggregateType,
+		arg.AggregateID,
+		arg.EventType,
+		arg.Payload,
+	)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.PublishedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getOutboxEvent = `-- name: GetOutboxEvent :one
+SELECT id, aggregate_type, aggregate_id, event_type, payload, status, attempts, last_error, next_attempt_at, published_at, created_at FROM outbox
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetOutboxEvent(ctx context.Context, id int64) (Outbox, error) {
+	row := q.db.QueryRowContext(ctx, getOutboxEvent, id)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.PublishedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const markOutboxEventFailed = `-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET
+  status = $1,
+  attempts = attempts + 1,
+  last_error = $2,
+  next_attempt_at = $3
+WHERE id = $4
+`
+
+type MarkOutboxEventFailedParams struct {
+	Status        string    `json:"status"`
+	LastError     string    `json:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	ID            int64     `json:"id"`
+}
+
+func (q *Queries) MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventFailed,
+		arg.Status,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :exec
+UPDATE outbox
+SET
+  status = 'published',
+  attempts = attempts + 1,
+  last_error = '',
+  published_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventPublished, id)
+	return err
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func createRandomOutboxEvent(t *testing.T, aggregateID string) Outbox {
+	arg := CreateOutboxEventParams{
+		AggregateType: "account",
+		AggregateID:   aggregateID,
+		EventType:     "AccountCreated",
+		Payload:       json.RawMessage(`{"id":1}`),
+	}
+
+	event, err := testQueries.CreateOutboxEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, event.ID)
+	require.Equal(t, arg.AggregateID, event.AggregateID)
+	require.Equal(t, "pending", event.Status)
+	require.Zero(t, event.Attempts)
+
+	return event
+}
+
+func TestClaimOutboxEventsOrdersPerAggregate(t *testing.T) {
+	aggregateID := util.RandomString(12)
+	first := createRandomOutboxEvent(t, aggregateID)
+	second := createRandomOutboxEvent(t, aggregateID)
+
+	claimedIDs := func() map[int64]bool {
+		events, err := testQueries.ClaimOutboxEvents(context.Background(), ClaimOutboxEventsParams{
+			LeaseUntil: time.Now().Add(time.Minute),
+			BatchSize:  1000,
+		})
+		require.NoError(t, err)
+
+		ids := make(map[int64]bool)
+		for _, event := range events {
+			ids[event.ID] = true
+		}
+		return ids
+	}
+
+	ids := claimedIDs()
+	require.True(t, ids[first.ID])
+	require.False(t, ids[second.ID])
+
+	err := testQueries.MarkOutboxEventPublished(context.Background(), first.ID)
+	require.NoError(t, err)
+
+	ids = claimedIDs()
+	require.False(t, ids[first.ID])
+	require.True(t, ids[second.ID])
+}
+
+func TestMarkOutboxEventFailed(t *testing.T) {
+	event := createRandomOutboxEvent(t, util.RandomString(12))
+
+	nextAttemptAt := time.Now().Add(time.Hour)
+	err := testQueries.MarkOutboxEventFailed(context.Background(), MarkOutboxEventFailedParams{
+		ID:            event.ID,
+		Status:        "dead",
+		LastError:     "consumer is down",
+		NextAttemptAt: nextAttemptAt,
+	})
+	require.NoError(t, err)
+
+	failed, err := testQueries.GetOutboxEvent(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Equal(t, "dead", failed.Status)
+	require.Equal(t, int32(1), failed.Attempts)
+	require.Equal(t, "consumer is down", failed.LastError)
+	require.WithinDuration(t, nextAttemptAt, failed.NextAttemptAt, time.Second)
+}
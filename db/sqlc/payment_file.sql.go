@@ -0,0 +1,67 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: payment_file.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createPaymentFile = `-- name: CreatePaymentFile :one
+INSERT INTO payment_files (
+  owner,
+  message_id,
+  number_of_payments,
+  control_sum
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, owner, message_id, number_of_payments, control_sum, created_at
+`
+
+type CreatePaymentFileParams struct {
+	Owner            string         `json:"owner"`
+	MessageID        string         `json:"message_id"`
+	NumberOfPayments int32          `json:"number_of_payments"`
+	ControlSum       sql.NullString `json:"control_sum"`
+}
+
+func (q *Queries) CreatePaymentFile(ctx context.Context, arg CreatePaymentFileParams) (PaymentFile, error) {
+	row := q.db.QueryRowContext(ctx, createPaymentFile,
+		arg.Owner,
+		arg.MessageID,
+		arg.NumberOfPayments,
+		arg.ControlSum,
+	)
+	var i PaymentFile
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.MessageID,
+		&i.NumberOfPayments,
+		&i.ControlSum,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPaymentFile = `-- name: GetPaymentFile :one
+SELECT id, owner, message_id, number_of_payments, control_sum, created_at FROM payment_files
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPaymentFile(ctx context.Context, id int64) (PaymentFile, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentFile, id)
+	var i PaymentFile
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.MessageID,
+		&i.NumberOfPayments,
+		&i.ControlSum,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func TestCreatePaymentFile(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreatePaymentFileParams{
+		Owner:            user.Username,
+		MessageID:        util.RandomString(12),
+		NumberOfPayments: 3,
+		ControlSum:       sql.NullString{String: "1250.50", Valid: true},
+	}
+
+	paymentFile, err := testQueries.CreatePaymentFile(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, paymentFile.ID)
+	require.Equal(t, arg.MessageID, paymentFile.MessageID)
+	require.Equal(t, arg.ControlSum, paymentFile.ControlSum)
+
+	stored, err := testQueries.GetPaymentFile(context.Background(), paymentFile.ID)
+	require.NoError(t, err)
+	require.Equal(t, paymentFile, stored)
+
+	// a message id can only be imported once per owner
+	_, err = testQueries.CreatePaymentFile(context.Background(), arg)
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	require.Equal(t, "unique_violation", pqErr.Code.Name())
+
+	other := createRandomUser(t)
+	arg.Owner = other.Username
+	_, err = testQueries.CreatePaymentFile(context.Background(), arg)
+	require.NoError(t, err)
+}
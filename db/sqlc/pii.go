@@ -0,0 +1,409 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/techschool/simplebank/crypt"
+)
+
+// errNoEnvelope is returned when personal data must be encrypted or
+// decrypted but the store has no keys
+var errNoEnvelope = errors.New("no PII encryption keys are configured")
+
+// piiQueries encrypts the personal data of users and KYC submissions on
+// its way into the database and decrypts it on its way out, so that callers
+// only ever see plaintext. Without an envelope users are stored in
+// plaintext and KYC submissions are refused.
+type piiQueries struct {
+	*Queries
+	envelope *crypt.Envelope
+}
+
+// piiAssociatedData binds an encrypted column to the user it belongs to, so
+// that values cannot be swapped between rows or columns
+func piiAssociatedData(column string, username string) []byte {
+	return []byte(column + ":" + username)
+}
+
+func (q *piiQueries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	fullName, email, phone := arg.FullName, arg.Email, arg.Phone
+
+	if q.envelope != nil {
+		dataKey, err := q.envelope.NewDataKey()
+		if err != nil {
+			return User{}, err
+		}
+
+		sealed, err := sealUser(q.envelope, dataKey, arg.Username, fullName, email, phone)
+		if err != nil {
+			return User{}, err
+		}
+
+		arg.FullName, arg.Email, arg.Phone = "", "", ""
+		arg.FullNameSealed = sealed.FullNameSealed
+		arg.EmailSealed = sealed.EmailSealed
+		arg.EmailIndex = sealed.EmailIndex
+		arg.PhoneSealed = sealed.PhoneSealed
+		arg.PhoneIndex = sealed.PhoneIndex
+		arg.DataKey = sealed.DataKey
+		arg.MasterKeyID = sealed.MasterKeyID
+	}
+
+	user, err := q.Queries.CreateUser(ctx, arg)
+	if err != nil {
+		return User{}, err
+	}
+
+	user.FullName, user.Email, user.Phone = fullName, email, phone
+	return user, nil
+}
+
+func (q *piiQueries) GetUser(ctx context.Context, username string) (User, error) {
+	return q.openUser(q.Queries.GetUser(ctx, username))
+}
+
+// GetUserByEmail looks users up by the blind index of arg.Email, which
+// sets arg.EmailIndex
+func (q *piiQueries) GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error) {
+	if q.envelope != nil {
+		arg.EmailIndex = q.envelope.BlindIndex(arg.Email)
+	}
+
+	return q.openUser(q.Queries.GetUserByEmail(ctx, arg))
+}
+
+// GetUserByPhone looks users up by the blind index of arg.Phone, which
+// sets arg.PhoneIndex
+func (q *piiQueries) GetUserByPhone(ctx context.Context, arg GetUserByPhoneParams) (User, error) {
+	if q.envelope != nil {
+		arg.PhoneIndex = q.envelope.BlindIndex(arg.Phone)
+	}
+
+	return q.openUser(q.Queries.GetUserByPhone(ctx, arg))
+}
+
+func (q *piiQueries) UpdateUserScreening(ctx context.Context, arg UpdateUserScreeningParams) (User, error) {
+	return q.openUser(q.Queries.UpdateUserScreening(ctx, arg))
+}
+
+func (q *piiQueries) UpdateUserKYCTier(ctx context.Context, arg UpdateUserKYCTierParams) (User, error) {
+	return q.openUser(q.Queries.UpdateUserKYCTier(ctx, arg))
+}
+
+func (q *piiQueries) ListUsersToScreen(ctx context.Context, arg ListUsersToScreenParams) ([]User, error) {
+	users, err := q.Queries.ListUsersToScreen(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		if users[i], err = openUser(q.envelope, users[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return users, nil
+}
+
+func (q *piiQueries) openUser(user User, err error) (User, error) {
+	if err != nil {
+		return User{}, err
+	}
+
+	return openUser(q.envelope, user)
+}
+
+// openUser fills the full name, email and phone of an encrypted user in
+func openUser(envelope *crypt.Envelope, user User) (User, error) {
+	if user.MasterKeyID == "" {
+		return user, nil
+	}
+	if envelope == nil {
+		return User{}, fmt.Errorf("cannot decrypt user %s: %w", user.Username, errNoEnvelope)
+	}
+
+	dataKey, err := envelope.UnwrapDataKey(user.DataKey, user.MasterKeyID)
+	if err != nil {
+		return User{}, fmt.Errorf("cannot decrypt user %s: %w", user.Username, err)
+	}
+
+	fullName, err := dataKey.Open(user.FullNameSealed, piiAssociatedData("full_name", user.Username))
+	if err != nil {
+		return User{}, fmt.Errorf("cannot decrypt full name of %s: %w", user.Username, err)
+	}
+
+	email, err := dataKey.Open(user.EmailSealed, piiAssociatedData("email", user.Username))
+	if err != nil {
+		return User{}, fmt.Errorf("cannot decrypt email of %s: %w", user.Username, err)
+	}
+
+	// users without a phone have nothing sealed
+	var phone []byte
+	if user.PhoneSealed != nil {
+		phone, err = dataKey.Open(user.PhoneSealed, piiAssociatedData("phone", user.Username))
+		if err != nil {
+			return User{}, fmt.Errorf("cannot decrypt phone of %s: %w", user.Username, err)
+		}
+	}
+
+	user.FullName, user.Email, user.Phone = string(fullName), string(email), string(phone)
+	return user, nil
+}
+
+// sealUser encrypts the full name, email and phone of a user under dataKey.
+// An empty phone is left out so that it does not take up a blind index.
+func sealUser(envelope *crypt.Envelope, dataKey crypt.DataKey, username string, fullName string, email string, phone string) (UpdateUserEncryptionParams, error) {
+	fullNameSealed, err := dataKey.Seal([]byte(fullName), piiAssociatedData("full_name", username))
+	if err != nil {
+		return UpdateUserEncryptionParams{}, err
+	}
+
+	emailSealed, err := dataKey.Seal([]byte(email), piiAssociatedData("email", username))
+	if err != nil {
+		return UpdateUserEncryptionParams{}, err
+	}
+
+	var phoneSealed, phoneIndex []byte
+	if phone != "" {
+		phoneSealed, err = dataKey.Seal([]byte(phone), piiAssociatedData("phone", username))
+		if err != nil {
+			return UpdateUserEncryptionParams{}, err
+		}
+		phoneIndex = envelope.BlindIndex(phone)
+	}
+
+	return UpdateUserEncryptionParams{
+		FullNameSealed: fullNameSealed,
+		EmailSealed:    emailSealed,
+		EmailIndex:     envelope.BlindIndex(email),
+		PhoneSealed:    phoneSealed,
+		PhoneIndex:     phoneIndex,
+		DataKey:        dataKey.Wrapped,
+		MasterKeyID:    dataKey.MasterKeyID,
+		Username:       username,
+	}, nil
+}
+
+// ReencryptUser moves a user, as read by ListUsersToReencrypt, onto the
+// active master key. Users in plaintext are encrypted under a new data key,
+// the data key of encrypted users is only rewrapped.
+func ReencryptUser(ctx context.Context, q Querier, envelope *crypt.Envelope, user User) error {
+	var arg UpdateUserEncryptionParams
+
+	if user.MasterKeyID == "" {
+		dataKey, err := envelope.NewDataKey()
+		if err != nil {
+			return err
+		}
+
+		arg, err = sealUser(envelope, dataKey, user.Username, user.FullName, user.Email, user.Phone)
+		if err != nil {
+			return err
+		}
+	} else {
+		dataKey, err := envelope.UnwrapDataKey(user.DataKey, user.MasterKeyID)
+		if err != nil {
+			return fmt.Errorf("cannot rekey user %s: %w", user.Username, err)
+		}
+
+		dataKey, err = envelope.Rewrap(dataKey)
+		if err != nil {
+			return err
+		}
+
+		arg = UpdateUserEncryptionParams{
+			FullNameSealed: user.FullNameSealed,
+			EmailSealed:    user.EmailSealed,
+			EmailIndex:     user.EmailIndex,
+			PhoneSealed:    user.PhoneSealed,
+			PhoneIndex:     user.PhoneIndex,
+			DataKey:        dataKey.Wrapped,
+			MasterKeyID:    dataKey.MasterKeyID,
+			Username:       user.Username,
+		}
+	}
+
+	if _, err := q.UpdateUserEncryption(ctx, arg); err != nil {
+		return fmt.Errorf("cannot rekey user %s: %w", user.Username, err)
+	}
+
+	return nil
+}
+
+// kycIdentityColumns lists the encrypted columns of a KYC submission
+func kycIdentityColumns(submission *KycSubmission) []struct {
+	name  string
+	value *[]byte
+} {
+	return []struct {
+		name  string
+		value *[]byte
+	}{
+		{"legal_name", &submission.LegalName},
+		{"date_of_birth", &submission.DateOfBirth},
+		{"document_number", &submission.DocumentNumber},
+		{"address", &submission.Address},
+	}
+}
+
+// CreateKYCSubmission encrypts the identity details of arg under a new data key
+func (q *piiQueries) CreateKYCSubmission(ctx context.Context, arg CreateKYCSubmissionParams) (KycSubmission, error) {
+	if q.envelope == nil {
+		return KycSubmission{}, fmt.Errorf("cannot encrypt identity details: %w", errNoEnvelope)
+	}
+
+	dataKey, err := q.envelope.NewDataKey()
+	if err != nil {
+		return KycSubmission{}, err
+	}
+
+	plaintext := KycSubmission{
+		LegalName:      arg.LegalName,
+		DateOfBirth:    arg.DateOfBirth,
+		DocumentNumber: arg.DocumentNumber,
+		Address:        arg.Address,
+	}
+	sealed := plaintext
+	for _, column := range kycIdentityColumns(&sealed) {
+		*column.value, err = dataKey.Seal(*column.value, piiAssociatedData(column.name, arg.Username))
+		if err != nil {
+			return KycSubmission{}, fmt.Errorf("cannot encrypt %s: %w", column.name, err)
+		}
+	}
+
+	arg.LegalName = sealed.LegalName
+	arg.DateOfBirth = sealed.DateOfBirth
+	arg.DocumentNumber = sealed.DocumentNumber
+	arg.Address = sealed.Address
+	arg.DataKey = dataKey.Wrapped
+	arg.MasterKeyID = dataKey.MasterKeyID
+
+	submission, err := q.Queries.CreateKYCSubmission(ctx, arg)
+	if err != nil {
+		return KycSubmission{}, err
+	}
+
+	submission.LegalName = plaintext.LegalName
+	submission.DateOfBirth = plaintext.DateOfBirth
+	submission.DocumentNumber = plaintext.DocumentNumber
+	submission.Address = plaintext.Address
+	return submission, nil
+}
+
+func (q *piiQueries) GetKYCSubmission(ctx context.Context, id int64) (KycSubmission, error) {
+	return q.openKYCSubmission(q.Queries.GetKYCSubmission(ctx, id))
+}
+
+func (q *piiQueries) GetLatestKYCSubmission(ctx context.Context, username string) (KycSubmission, error) {
+	return q.openKYCSubmission(q.Queries.GetLatestKYCSubmission(ctx, username))
+}
+
+func (q *piiQueries) DecideKYCSubmission(ctx context.Context, arg DecideKYCSubmissionParams) (KycSubmission, error) {
+	return q.openKYCSubmission(q.Queries.DecideKYCSubmission(ctx, arg))
+}
+
+func (q *piiQueries) ListKYCSubmissions(ctx context.Context, arg ListKYCSubmissionsParams) ([]KycSubmission, error) {
+	submissions, err := q.Queries.ListKYCSubmissions(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range submissions {
+		if submissions[i], err = openKYCSubmission(q.envelope, submissions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return submissions, nil
+}
+
+func (q *piiQueries) openKYCSubmission(submission KycSubmission, err error) (KycSubmission, error) {
+	if err != nil {
+		return KycSubmission{}, err
+	}
+
+	return openKYCSubmission(q.envelope, submission)
+}
+
+// openKYCSubmission decrypts the identity details of a submission. Those
+// without a data key were sealed directly under a master key.
+func openKYCSubmission(envelope *crypt.Envelope, submission KycSubmission) (KycSubmission, error) {
+	if envelope == nil {
+		return KycSubmission{}, fmt.Errorf("cannot decrypt KYC submission %d: %w", submission.ID, errNoEnvelope)
+	}
+
+	open := envelope.Keyring().OpenWithAnyKey
+	if submission.MasterKeyID != "" {
+		dataKey, err := envelope.UnwrapDataKey(submission.DataKey, submission.MasterKeyID)
+		if err != nil {
+			return KycSubmission{}, fmt.Errorf("cannot decrypt KYC submission %d: %w", submission.ID, err)
+		}
+		open = dataKey.Open
+	}
+
+	for _, column := range kycIdentityColumns(&submission) {
+		plaintext, err := open(*column.value, piiAssociatedData(column.name, submission.Username))
+		if err != nil {
+			return KycSubmission{}, fmt.Errorf("cannot decrypt %s of KYC submission %d: %w", column.name, submission.ID, err)
+		}
+		*column.value = plaintext
+	}
+
+	return submission, nil
+}
+
+// ReencryptKYCSubmission moves a submission, as read by
+// ListKYCSubmissionsToReencrypt, onto the active master key. Identity
+// details sealed directly under a master key get a data key of their own,
+// the data key of the others is only rewrapped.
+func ReencryptKYCSubmission(ctx context.Context, q Querier, envelope *crypt.Envelope, submission KycSubmission) error {
+	var dataKey crypt.DataKey
+	var err error
+
+	if submission.MasterKeyID == "" {
+		plaintext, err := openKYCSubmission(envelope, submission)
+		if err != nil {
+			return err
+		}
+
+		dataKey, err = envelope.NewDataKey()
+		if err != nil {
+			return err
+		}
+
+		submission = plaintext
+		for _, column := range kycIdentityColumns(&submission) {
+			*column.value, err = dataKey.Seal(*column.value, piiAssociatedData(column.name, submission.Username))
+			if err != nil {
+				return fmt.Errorf("cannot encrypt %s: %w", column.name, err)
+			}
+		}
+	} else {
+		dataKey, err = envelope.UnwrapDataKey(submission.DataKey, submission.MasterKeyID)
+		if err != nil {
+			return fmt.Errorf("cannot rekey KYC submission %d: %w", submission.ID, err)
+		}
+
+		dataKey, err = envelope.Rewrap(dataKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = q.UpdateKYCSubmissionEncryption(ctx, UpdateKYCSubmissionEncryptionParams{
+		ID:             submission.ID,
+		LegalName:      submission.LegalName,
+		DateOfBirth:    submission.DateOfBirth,
+		DocumentNumber: submission.DocumentNumber,
+		Address:        submission.Address,
+		DataKey:        dataKey.Wrapped,
+		MasterKeyID:    dataKey.MasterKeyID,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot rekey KYC submission %d: %w", submission.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/crypt"
+	"github.com/techschool/simplebank/kyc"
+	"github.com/techschool/simplebank/util"
+)
+
+func newTestEnvelope(t *testing.T, keys ...string) *crypt.Envelope {
+	var masterKeys [][]byte
+	for _, key := range keys {
+		masterKeys = append(masterKeys, []byte(key))
+	}
+
+	keyring, err := crypt.NewKeyring(masterKeys...)
+	require.NoError(t, err)
+
+	envelope, err := crypt.NewEnvelope(keyring, []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	return envelope
+}
+
+func TestEncryptedUser(t *testing.T) {
+	store := NewStore(testDB)
+	store.SetEnvelope(newTestEnvelope(t, util.RandomString(32)))
+
+	arg := CreateUserParams{
+		Username:       util.RandomOwner(),
+		HashedPassword: "secret",
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+		Phone:          util.RandomPhone(),
+	}
+	user, err := store.CreateUser(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.FullName, user.FullName)
+	require.Equal(t, arg.Email, user.Email)
+	require.Equal(t, arg.Phone, user.Phone)
+
+	// the row itself only holds ciphertext
+	raw, err := testQueries.GetUser(context.Background(), arg.Username)
+	require.NoError(t, err)
+	require.Empty(t, raw.FullName)
+	require.Empty(t, raw.Email)
+	require.Empty(t, raw.Phone)
+	require.NotContains(t, string(raw.EmailSealed), arg.Email)
+	require.NotEmpty(t, raw.PhoneIndex)
+	require.NotEmpty(t, raw.MasterKeyID)
+
+	got, err := store.GetUserByEmail(context.Background(), GetUserByEmailParams{Email: arg.Email})
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, got.Username)
+	require.Equal(t, arg.FullName, got.FullName)
+
+	got, err = store.GetUserByPhone(context.Background(), GetUserByPhoneParams{Phone: arg.Phone})
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, got.Username)
+	require.Equal(t, arg.Phone, got.Phone)
+
+	// the blind index keeps emails unique
+	_, err = store.CreateUser(context.Background(), CreateUserParams{
+		Username:       util.RandomOwner(),
+		HashedPassword: "secret",
+		FullName:       util.RandomOwner(),
+		Email:          arg.Email,
+	})
+	require.ErrorContains(t, err, "email_index")
+
+	// transactions encrypt like the store
+	err = store.ExecTx(context.Background(), DefaultTxOptions, func(q Querier) error {
+		got, err = q.GetUser(context.Background(), arg.Username)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, arg.Email, got.Email)
+}
+
+func TestReencrypt(t *testing.T) {
+	oldKey, newKey := util.RandomString(32), util.RandomString(32)
+	before := newTestEnvelope(t, oldKey)
+	after := newTestEnvelope(t, newKey, oldKey)
+	retired := newTestEnvelope(t, newKey)
+
+	// a user created before encryption was turned on
+	plain := createRandomUser(t)
+	require.Empty(t, plain.MasterKeyID)
+
+	store := NewStore(testDB)
+	store.SetEnvelope(before)
+	user, err := store.CreateUser(context.Background(), CreateUserParams{
+		Username:       util.RandomOwner(),
+		HashedPassword: "secret",
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+	})
+	require.NoError(t, err)
+
+	submission, err := store.CreateKYCSubmission(context.Background(), CreateKYCSubmissionParams{
+		Username:       user.Username,
+		Tier:           kyc.TierBasic,
+		DocumentType:   kyc.DocumentPassport,
+		Country:        "KE",
+		LegalName:      []byte(user.FullName),
+		DateOfBirth:    []byte("1990-04-12"),
+		DocumentNumber: []byte("X1234567"),
+		Address:        []byte("1 Main Street"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "X1234567", string(submission.DocumentNumber))
+
+	// identity details sealed directly under a master key, without a data key
+	legacy := CreateKYCSubmissionParams{
+		Username:     plain.Username,
+		Tier:         kyc.TierBasic,
+		DocumentType: kyc.DocumentPassport,
+		Country:      "KE",
+	}
+	master, err := crypt.NewCipher([]byte(oldKey))
+	require.NoError(t, err)
+	for column, field := range map[string]*[]byte{
+		"legal_name":      &legacy.LegalName,
+		"date_of_birth":   &legacy.DateOfBirth,
+		"document_number": &legacy.DocumentNumber,
+		"address":         &legacy.Address,
+	} {
+		*field, err = master.Seal([]byte(column+" of "+plain.Username), piiAssociatedData(column, plain.Username))
+		require.NoError(t, err)
+	}
+	legacySubmission, err := testQueries.CreateKYCSubmission(context.Background(), legacy)
+	require.NoError(t, err)
+
+	for _, username := range []string{plain.Username, user.Username} {
+		raw, err := testQueries.GetUser(context.Background(), username)
+		require.NoError(t, err)
+		require.NoError(t, ReencryptUser(context.Background(), testQueries, after, raw))
+	}
+
+	for _, id := range []int64{submission.ID, legacySubmission.ID} {
+		raw, err := testQueries.GetKYCSubmission(context.Background(), id)
+		require.NoError(t, err)
+		require.NoError(t, ReencryptKYCSubmission(context.Background(), testQueries, after, raw))
+	}
+
+	// the old master key is no longer needed
+	store.SetEnvelope(retired)
+	for _, want := range []User{plain, user} {
+		got, err := store.GetUser(context.Background(), want.Username)
+		require.NoError(t, err)
+		require.Equal(t, crypt.KeyID([]byte(newKey)), got.MasterKeyID)
+		require.Equal(t, want.FullName, got.FullName)
+		require.Equal(t, want.Email, got.Email)
+	}
+
+	got, err := store.GetKYCSubmission(context.Background(), submission.ID)
+	require.NoError(t, err)
+	require.Equal(t, "X1234567", string(got.DocumentNumber))
+	require.Equal(t, user.FullName, string(got.LegalName))
+
+	got, err = store.GetKYCSubmission(context.Background(), legacySubmission.ID)
+	require.NoError(t, err)
+	require.Equal(t, "address of "+plain.Username, string(got.Address))
+}
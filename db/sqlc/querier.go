@@ -6,30 +6,147 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	// Blocks every session of a user, so that their refresh tokens stop working
+	BlockUserSessions(ctx context.Context, username string) error
+	// Only the oldest pending event of each aggregate is due, so events of one
+	// aggregate are published in order. Claimed events are not due again until
+	// lease_until, so they can be published without holding a lock, and stay
+	// pending so that they keep holding back their aggregate.
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]Outbox, error)
+	// Claimed deliveries are not due again until lease_until, so they can be sent
+	// without holding a lock. Deliveries of a dispatcher that dies before marking
+	// them are retried once the lease runs out.
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
+	// Payees whose cooling-off period is already over keep their active_from
+	ConfirmPayee(ctx context.Context, id int64) (Payee, error)
+	// Counts a lookup of username, starting a new window once the current one
+	// started before expired_before
+	CountAliasLookup(ctx context.Context, arg CountAliasLookupParams) (AliasLookup, error)
+	CountTransfersBetweenAccounts(ctx context.Context, arg CountTransfersBetweenAccountsParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateCustomerLedgerAccount(ctx context.Context, accountID int64) error
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (int64, error)
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error)
+	CreateInterestRate(ctx context.Context, arg CreateInterestRateParams) (InterestRate, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateKYCSubmission(ctx context.Context, arg CreateKYCSubmissionParams) (KycSubmission, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (Outbox, error)
+	CreatePayee(ctx context.Context, arg CreatePayeeParams) (Payee, error)
+	CreatePaymentFile(ctx context.Context, arg CreatePaymentFileParams) (PaymentFile, error)
+	CreatePosting(ctx context.Context, arg CreatePostingParams) (Posting, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSystemLedgerAccount(ctx context.Context, arg CreateSystemLedgerAccountParams) error
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserScreening(ctx context.Context, arg CreateUserScreeningParams) (UserScreening, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DecideKYCSubmission(ctx context.Context, arg DecideKYCSubmissionParams) (KycSubmission, error)
+	DecideTransferReview(ctx context.Context, arg DecideTransferReviewParams) (TransferReview, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeletePayee(ctx context.Context, id int64) error
 	DeleteSession(ctx context.Context, id uuid.UUID) error
+	DisableWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByNumber(ctx context.Context, number string) (Account, error)
+	GetAccountByOwner(ctx context.Context, arg GetAccountByOwnerParams) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAliasLookup(ctx context.Context, username string) (AliasLookup, error)
+	GetCustomerLedgerAccount(ctx context.Context, accountID int64) (LedgerAccount, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	// The tier of a user next to the lowest tier allowed to use a currency
+	GetKYCCapability(ctx context.Context, arg GetKYCCapabilityParams) (GetKYCCapabilityRow, error)
+	GetKYCSubmission(ctx context.Context, id int64) (KycSubmission, error)
+	GetLastAuditEvent(ctx context.Context) (AuditEvent, error)
+	GetLastInterestPosting(ctx context.Context, accountID int64) (InterestPosting, error)
+	GetLatestKYCSubmission(ctx context.Context, username string) (KycSubmission, error)
+	GetLedgerAccount(ctx context.Context, id int64) (LedgerAccount, error)
+	GetOutboxEvent(ctx context.Context, id int64) (Outbox, error)
+	GetPayee(ctx context.Context, id int64) (Payee, error)
+	GetPaymentFile(ctx context.Context, id int64) (PaymentFile, error)
+	GetProduct(ctx context.Context, code string) (Product, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSystemLedgerAccount(ctx context.Context, arg GetSystemLedgerAccountParams) (LedgerAccount, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferReview(ctx context.Context, id int64) (TransferReview, error)
+	// Outgoing transfers of an account since the start of the month, and the
+	// part of them since the start of the day
+	GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error)
+	// Customer ledger accounts share their chart code and roll up into it.
+	GetTrialBalance(ctx context.Context, asOf time.Time) ([]GetTrialBalanceRow, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	// Encrypted users are found by the blind index of their email, the others
+	// by the email itself
+	GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error)
+	// Phone numbers are matched like emails, see GetUserByEmail
+	GetUserByPhone(ctx context.Context, arg GetUserByPhoneParams) (User, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	IsUserBlocked(ctx context.Context, username string) (bool, error)
+	ListAccountDrift(ctx context.Context) ([]ListAccountDriftRow, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// Accounts earning interest on accrual_date that have not accrued it yet,
+	// with their balance at the end of that day. Days of a month that was
+	// already posted are left alone.
+	ListAccrualCandidates(ctx context.Context, arg ListAccrualCandidatesParams) ([]ListAccrualCandidatesRow, error)
+	ListApplicableTransferLimits(ctx context.Context, arg ListApplicableTransferLimitsParams) ([]TransferLimit, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListAuditEventsAfter(ctx context.Context, arg ListAuditEventsAfterParams) ([]AuditEvent, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	ListCurrencyTotals(ctx context.Context) ([]ListCurrencyTotalsRow, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error)
+	ListInterestRates(ctx context.Context, productCode string) ([]InterestRate, error)
+	ListKYCSubmissions(ctx context.Context, arg ListKYCSubmissionsParams) ([]KycSubmission, error)
+	ListKYCSubmissionsToReencrypt(ctx context.Context, arg ListKYCSubmissionsToReencryptParams) ([]KycSubmission, error)
+	// A customer account holds the credit balance of its ledger account.
+	ListLedgerDrift(ctx context.Context) ([]ListLedgerDriftRow, error)
+	ListPayees(ctx context.Context, arg ListPayeesParams) ([]Payee, error)
+	ListPostings(ctx context.Context, journalEntryID int64) ([]Posting, error)
+	ListProducts(ctx context.Context) ([]Product, error)
+	ListTransferLimits(ctx context.Context) ([]TransferLimit, error)
+	ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListTransfersFromAccountSince(ctx context.Context, arg ListTransfersFromAccountSinceParams) ([]Transfer, error)
+	// A transfer and its entries are written in one transaction and share
+	// created_at, so exactly one debit and one credit entry must match it.
+	ListUnbalancedTransfers(ctx context.Context) ([]ListUnbalancedTransfersRow, error)
+	// Unposted accruals of every account summed by month, for the months
+	// before the one starting at before
+	ListUnpostedInterest(ctx context.Context, before time.Time) ([]ListUnpostedInterestRow, error)
+	ListUnpostedInterestAccrualsForUpdate(ctx context.Context, arg ListUnpostedInterestAccrualsForUpdateParams) ([]InterestAccrual, error)
+	ListUserScreenings(ctx context.Context, arg ListUserScreeningsParams) ([]UserScreening, error)
+	ListUsersToReencrypt(ctx context.Context, arg ListUsersToReencryptParams) ([]User, error)
+	ListUsersToScreen(ctx context.Context, arg ListUsersToScreenParams) ([]User, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, owner string) ([]WebhookSubscription, error)
+	ListWebhookSubscriptionsForEvent(ctx context.Context, arg ListWebhookSubscriptionsForEventParams) ([]WebhookSubscription, error)
+	LockAuditChain(ctx context.Context) error
+	MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) error
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	MarkWebhookDeliverySucceeded(ctx context.Context, arg MarkWebhookDeliverySucceededParams) error
+	SumAccountEntries(ctx context.Context, accountID int64) (int64, error)
+	SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateKYCSubmissionEncryption(ctx context.Context, arg UpdateKYCSubmissionEncryptionParams) (KycSubmission, error)
+	UpdatePayeeNickname(ctx context.Context, arg UpdatePayeeNicknameParams) (Payee, error)
 	UpdateSessionIsBlocked(ctx context.Context, arg UpdateSessionIsBlockedParams) (Session, error)
+	UpdateUserEncryption(ctx context.Context, arg UpdateUserEncryptionParams) (User, error)
+	UpdateUserKYCTier(ctx context.Context, arg UpdateUserKYCTierParams) (User, error)
+	UpdateUserScreening(ctx context.Context, arg UpdateUserScreeningParams) (User, error)
+	UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error)
 }
 
 var _ Querier = (*Queries)(nil)
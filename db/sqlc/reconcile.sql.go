@@ -0,0 +1,196 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: reconcile.sql
+
+package db
+
+import (
+	"context"
+)
+
+const listAccountDrift = `-- name: ListAccountDrift :many
+SELECT
+  a.id,
+  a.owner,
+  a.currency,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id
+`
+
+type ListAccountDriftRow struct {
+	ID           int64  `json:"id"`
+	Owner        string `json:"owner"`
+	Currency     string `json:"currency"`
+	Balance      int64  `json:"balance"`
+	EntriesTotal int64  `json:"entries_total"`
+}
+
+func (q *Queries) ListAccountDrift(ctx context.Context) ([]ListAccountDriftRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountDrift)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountDriftRow{}
+	for rows.Next() {
+		var i ListAccountDriftRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Currency,
+			&i.Balance,
+			&i.EntriesTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCurrencyTotals = `-- name: ListCurrencyTotals :many
+SELECT
+  a.currency,
+  COUNT(*)::bigint AS accounts,
+  COALESCE(SUM(a.balance), 0)::bigint AS balance_total,
+  COALESCE(SUM(t.total), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN (
+  SELECT account_id, SUM(amount) AS total
+  FROM entries
+  GROUP BY account_id
+) t ON t.account_id = a.id
+GROUP BY a.currency
+ORDER BY a.currency
+`
+
+type ListCurrencyTotalsRow struct {
+	Currency     string `json:"currency"`
+	Accounts     int64  `json:"accounts"`
+	BalanceTotal int64  `json:"balance_total"`
+	EntriesTotal int64  `json:"entries_total"`
+}
+
+func (q *Queries) ListCurrencyTotals(ctx context.Context) ([]ListCurrencyTotalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencyTotals)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCurrencyTotalsRow{}
+	for rows.Next() {
+		var i ListCurrencyTotalsRow
+		if err := rows.Scan(
+			&i.Currency,
+			&i.Accounts,
+			&i.BalanceTotal,
+			&i.EntriesTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnbalancedTransfers = `-- name: ListUnbalancedTransfers :many
+SELECT
+  t.id,
+  t.from_account_id,
+  t.to_account_id,
+  t.amount,
+  fa.currency AS from_currency,
+  ta.currency AS to_currency,
+  COUNT(e.id) FILTER (WHERE e.account_id = t.from_account_id)::int AS debit_entries,
+  COUNT(e.id) FILTER (WHERE e.account_id = t.to_account_id)::int AS credit_entries,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+LEFT JOIN entries e ON e.created_at = t.created_at AND (
+  (e.account_id = t.from_account_id AND e.amount = -t.amount) OR
+  (e.account_id = t.to_account_id AND e.amount = t.amount)
+)
+GROUP BY t.id, fa.currency, ta.currency
+HAVING COUNT(e.id) FILTER (WHERE e.account_id = t.from_account_id) <> 1
+  OR COUNT(e.id) FILTER (WHERE e.account_id = t.to_account_id) <> 1
+  OR fa.currency <> ta.currency
+ORDER BY t.id
+`
+
+type ListUnbalancedTransfersRow struct {
+	ID            int64  `json:"id"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	FromCurrency  string `json:"from_currency"`
+	ToCurrency    string `json:"to_currency"`
+	DebitEntries  int32  `json:"debit_entries"`
+	CreditEntries int32  `json:"credit_entries"`
+	EntriesTotal  int64  `json:"entries_total"`
+}
+
+// A transfer and its entries are written in one transaction and share
+// created_at, so exactly one debit and one credit entry must match it.
+func (q *Queries) ListUnbalancedTransfers(ctx context.Context) ([]ListUnbalancedTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnbalancedTransfers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnbalancedTransfersRow{}
+	for rows.Next() {
+		var i ListUnbalancedTransfersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.FromCurrency,
+			&i.ToCurrency,
+			&i.DebitEntries,
+			&i.CreditEntries,
+			&i.EntriesTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumAccountEntries = `-- name: SumAccountEntries :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = $1
+`
+
+func (q *Queries) SumAccountEntries(ctx context.Context, accountID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumAccountEntries, accountID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
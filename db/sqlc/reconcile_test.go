@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountDrift(t *testing.T) {
+	account := createRandomAccount(t)
+
+	_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account.ID,
+		Amount:    account.Balance,
+	})
+	require.NoError(t, err)
+
+	// the balance matches its only entry until it is changed behind its back
+	requireDrift := func(expected bool) {
+		rows, err := testQueries.ListAccountDrift(context.Background())
+		require.NoError(t, err)
+
+		found := false
+		for _, row := range rows {
+			if row.ID == account.ID {
+				found = true
+				require.Equal(t, account.Balance, row.EntriesTotal)
+			}
+		}
+		require.Equal(t, expected, found)
+	}
+	requireDrift(false)
+
+	_, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: 10,
+	})
+	require.NoError(t, err)
+	requireDrift(true)
+
+	total, err := testQueries.SumAccountEntries(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, total)
+}
+
+func TestListUnbalancedTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	// a transfer without entries
+	orphan, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        5,
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.ListUnbalancedTransfers(context.Background())
+	require.NoError(t, err)
+
+	byID := make(map[int64]ListUnbalancedTransfersRow)
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+	require.NotContains(t, byID, result.Transfer.ID)
+	require.Contains(t, byID, orphan.ID)
+	require.Zero(t, byID[orphan.ID].DebitEntries)
+	require.Zero(t, byID[orphan.ID].CreditEntries)
+}
@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// UserBlockedError is returned when sanctions screening blocked a user
+type UserBlockedError struct {
+	Username string
+}
+
+func (err *UserBlockedError) Error() string {
+	return fmt.Sprintf("user %s is blocked by sanctions screening", err.Username)
+}
+
+// CheckNotBlocked returns a UserBlockedError if sanctions screening blocked
+// username. Users screened after they signed up are only caught here.
+func CheckNotBlocked(ctx context.Context, q Querier, username string) error {
+	blocked, err := q.IsUserBlocked(ctx, username)
+	if err != nil {
+		return fmt.Errorf("cannot get screening status of %s: %w", username, err)
+	}
+
+	if blocked {
+		return &UserBlockedError{Username: username}
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: sanctions.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createUserScreening = `-- name: CreateUserScreening :one
+INSERT INTO user_screenings (
+  username,
+  list_version,
+  status,
+  score,
+  matched_uid,
+  matched_name
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, username, list_version, status, score, matched_uid, matched_name, created_at
+`
+
+type CreateUserScreeningParams struct {
+	Username    string  `json:"username"`
+	ListVersion string  `json:"list_version"`
+	Status      string  `json:"status"`
+	Score       float64 `json:"score"`
+	MatchedUid  string  `json:"matched_uid"`
+	MatchedName string  `json:"matched_name"`
+}
+
+func (q *Queries) CreateUserScreening(ctx context.Context, arg CreateUserScreeningParams) (UserScreening, error) {
+	row := q.db.QueryRowContext(ctx, createUserScreening,
+		arg.Username,
+		arg.ListVersion,
+		arg.Status,
+		arg.Score,
+		arg.MatchedUid,
+		arg.MatchedName,
+	)
+	var i UserScreening
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.ListVersion,
+		&i.Status,
+		&i.Score,
+		&i.MatchedUid,
+		&i.MatchedName,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const isUserBlocked = `-- name: IsUserBlocked :one
+SELECT screening_status = 'blocked' AS blocked FROM users
+WHERE username = $1
+`
+
+func (q *Queries) IsUserBlocked(ctx context.Context, username string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isUserBlocked, username)
+	var blocked bool
+	err := row.Scan(&blocked)
+	return blocked, err
+}
+
+const listUserScreenings = `-- name: ListUserScreenings :many
+SELECT id, username, list_version, status, score, matched_uid, matched_name, created_at FROM user_screenings
+WHERE username = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListUserScreeningsParams struct {
+	Username string `json:"username"`
+	Limit    int32  `json:"limit"`
+	Offset   int32  `json:"offset"`
+}
+
+func (q *Queries) ListUserScreenings(ctx context.Context, arg ListUserScreeningsParams) ([]UserScreening, error) {
+	rows, err := q.db.QueryContext(ctx, listUserScreenings, arg.Username, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserScreening{}
+	for rows.Next() {
+		var i UserScreening
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.ListVersion,
+			&i.Status,
+			&i.Score,
+			&i.MatchedUid,
+			&i.MatchedName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersToScreen = `-- name: ListUsersToScreen :many
+SELECT username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index FROM users
+WHERE screening_list_version <> $1
+ORDER BY username
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type ListUsersToScreenParams struct {
+	ListVersion string `json:"list_version"`
+	BatchSize   int32  `json:"batch_size"`
+}
+
+func (q *Queries) ListUsersToScreen(ctx context.Context, arg ListUsersToScreenParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersToScreen, arg.ListVersion, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Username,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			&i.PasswordChangeAt,
+			&i.CreatedAt,
+			&i.Role,
+			&i.ScreeningStatus,
+			&i.ScreeningListVersion,
+			&i.ScreenedAt,
+			&i.KycTier,
+			&i.FullNameSealed,
+			&i.EmailSealed,
+			&i.EmailIndex,
+			&i.DataKey,
+			&i.MasterKeyID,
+			&i.Phone,
+			&i.PhoneSealed,
+			&i.PhoneIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserScreening = `-- name: UpdateUserScreening :one
+UPDATE users
+SET
+  screening_status = $1,
+  screening_list_version = $2,
+  screened_at = now()
+WHERE username = $3
+RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index
+`
+
+type UpdateUserScreeningParams struct {
+	ScreeningStatus      string `json:"screening_status"`
+	ScreeningListVersion string `json:"screening_list_version"`
+	Username             string `json:"username"`
+}
+
+func (q *Queries) UpdateUserScreening(ctx context.Context, arg UpdateUserScreeningParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserScreening, arg.ScreeningStatus, arg.ScreeningListVersion, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.ScreeningStatus,
+		&i.ScreeningListVersion,
+		&i.ScreenedAt,
+		&i.KycTier,
+		&i.FullNameSealed,
+		&i.EmailSealed,
+		&i.EmailIndex,
+		&i.DataKey,
+		&i.MasterKeyID,
+		&i.Phone,
+		&i.PhoneSealed,
+		&i.PhoneIndex,
+	)
+	return i, err
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func TestUserScreening(t *testing.T) {
+	user := createRandomUser(t)
+	require.Equal(t, "unscreened", user.ScreeningStatus)
+	require.Empty(t, user.ScreeningListVersion)
+	require.False(t, user.ScreenedAt.Valid)
+
+	store := NewStore(testDB)
+	version := util.RandomString(16)
+
+	// a new list version puts every user back in the queue
+	err := store.ExecTx(context.Background(), DefaultTxOptions, func(q Querier) error {
+		users, err := q.ListUsersToScreen(context.Background(), ListUsersToScreenParams{
+			ListVersion: version,
+			BatchSize:   1000,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, users)
+		return nil
+	})
+	require.NoError(t, err)
+
+	screening, err := testQueries.CreateUserScreening(context.Background(), CreateUserScreeningParams{
+		Username:    user.Username,
+		ListVersion: version,
+		Status:      "flagged",
+		Score:       0.93,
+		MatchedUid:  "1001",
+		MatchedName: "PETROVSKY, Ivan",
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, screening.Username)
+	require.NotZero(t, screening.CreatedAt)
+
+	updated, err := testQueries.UpdateUserScreening(context.Background(), UpdateUserScreeningParams{
+		ScreeningStatus:      "flagged",
+		ScreeningListVersion: version,
+		Username:             user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "flagged", updated.ScreeningStatus)
+	require.Equal(t, version, updated.ScreeningListVersion)
+	require.True(t, updated.ScreenedAt.Valid)
+
+	screenings, err := testQueries.ListUserScreenings(context.Background(), ListUserScreeningsParams{
+		Username: user.Username,
+		Limit:    5,
+		Offset:   0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []UserScreening{screening}, screenings)
+
+	// users screened against the version are skipped
+	err = store.ExecTx(context.Background(), DefaultTxOptions, func(q Querier) error {
+		users, err := q.ListUsersToScreen(context.Background(), ListUsersToScreenParams{
+			ListVersion: version,
+			BatchSize:   1000,
+		})
+		require.NoError(t, err)
+		for _, other := range users {
+			require.NotEqual(t, user.Username, other.Username)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCheckNotBlocked(t *testing.T) {
+	user := createRandomUser(t)
+	require.NoError(t, CheckNotBlocked(context.Background(), testQueries, user.Username))
+
+	_, err := testQueries.UpdateUserScreening(context.Background(), UpdateUserScreeningParams{
+		ScreeningStatus:      "blocked",
+		ScreeningListVersion: util.RandomString(16),
+		Username:             user.Username,
+	})
+	require.NoError(t, err)
+
+	err = CheckNotBlocked(context.Background(), testQueries, user.Username)
+	var blockedErr *UserBlockedError
+	require.ErrorAs(t, err, &blockedErr)
+	require.Equal(t, user.Username, blockedErr.Username)
+}
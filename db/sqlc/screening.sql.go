@@ -0,0 +1,244 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: screening.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const countTransfersBetweenAccounts = `-- name: CountTransfersBetweenAccounts :one
+SELECT count(*) FROM transfers
+WHERE from_account_id = $1 AND to_account_id = $2
+`
+
+type CountTransfersBetweenAccountsParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+}
+
+func (q *Queries) CountTransfersBetweenAccounts(ctx context.Context, arg CountTransfersBetweenAccountsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTransfersBetweenAccounts, arg.FromAccountID, arg.ToAccountID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createTransferReview = `-- name: CreateTransferReview :one
+INSERT INTO transfer_reviews (
+  owner,
+  from_account_id,
+  to_account_id,
+  amount,
+  currency,
+  hits
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, owner, from_account_id, to_account_id, amount, currency, status, hits, reviewer, reason, transfer_id, created_at, decided_at
+`
+
+type CreateTransferReviewParams struct {
+	Owner         string          `json:"owner"`
+	FromAccountID int64           `json:"from_account_id"`
+	ToAccountID   int64           `json:"to_account_id"`
+	Amount        int64           `json:"amount"`
+	Currency      string          `json:"currency"`
+	Hits          json.RawMessage `json:"hits"`
+}
+
+func (q *Queries) CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error) {
+	row := q.db.QueryRowContext(ctx, createTransferReview,
+		arg.Owner,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.Hits,
+	)
+	var i TransferReview
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.Hits,
+		&i.Reviewer,
+		&i.Reason,
+		&i.TransferID,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const decideTransferReview = `-- name: DecideTransferReview :one
+UPDATE transfer_reviews
+SET
+  status = $1,
+  reviewer = $2,
+  reason = $3,
+  transfer_id = $4,
+  decided_at = now()
+WHERE id = $5 AND status = 'pending'
+RETURNING id, owner, from_account_id, to_account_id, amount, currency, status, hits, reviewer, reason, transfer_id, created_at, decided_at
+`
+
+type DecideTransferReviewParams struct {
+	Status     string         `json:"status"`
+	Reviewer   sql.NullString `json:"reviewer"`
+	Reason     string         `json:"reason"`
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	ID         int64          `json:"id"`
+}
+
+func (q *Queries) DecideTransferReview(ctx context.Context, arg DecideTransferReviewParams) (TransferReview, error) {
+	row := q.db.QueryRowContext(ctx, decideTransferReview,
+		arg.Status,
+		arg.Reviewer,
+		arg.Reason,
+		arg.TransferID,
+		arg.ID,
+	)
+	var i TransferReview
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.Hits,
+		&i.Reviewer,
+		&i.Reason,
+		&i.TransferID,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const getTransferReview = `-- name: GetTransferReview :one
+SELECT id, owner, from_account_id, to_account_id, amount, currency, status, hits, reviewer, reason, transfer_id, created_at, decided_at FROM transfer_reviews
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTransferReview(ctx context.Context, id int64) (TransferReview, error) {
+	row := q.db.QueryRowContext(ctx, getTransferReview, id)
+	var i TransferReview
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.Hits,
+		&i.Reviewer,
+		&i.Reason,
+		&i.TransferID,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const listTransferReviews = `-- name: ListTransferReviews :many
+SELECT id, owner, from_account_id, to_account_id, amount, currency, status, hits, reviewer, reason, transfer_id, created_at, decided_at FROM transfer_reviews
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3
+`
+
+type ListTransferReviewsParams struct {
+	Status string `json:"status"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+func (q *Queries) ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferReviews, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferReview{}
+	for rows.Next() {
+		var i TransferReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Status,
+			&i.Hits,
+			&i.Reviewer,
+			&i.Reason,
+			&i.TransferID,
+			&i.CreatedAt,
+			&i.DecidedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransfersFromAccountSince = `-- name: ListTransfersFromAccountSince :many
+SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+WHERE from_account_id = $1 AND created_at >= $2
+ORDER BY id
+`
+
+type ListTransfersFromAccountSinceParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	Since         time.Time `json:"since"`
+}
+
+func (q *Queries) ListTransfersFromAccountSince(ctx context.Context, arg ListTransfersFromAccountSinceParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listTransfersFromAccountSince, arg.FromAccountID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTransfersFromAccountSince(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	transfer := createRandomTransfer(t, account1, account2)
+
+	count, err := testQueries.CountTransfersBetweenAccounts(context.Background(), CountTransfersBetweenAccountsParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	transfers, err := testQueries.ListTransfersFromAccountSince(context.Background(), ListTransfersFromAccountSinceParams{
+		FromAccountID: account1.ID,
+		Since:         time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, transfer.ID, transfers[0].ID)
+
+	transfers, err = testQueries.ListTransfersFromAccountSince(context.Background(), ListTransfersFromAccountSinceParams{
+		FromAccountID: account1.ID,
+		Since:         time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+}
+
+func TestDecideTransferReviewOnce(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	review, err := testQueries.CreateTransferReview(context.Background(), CreateTransferReviewParams{
+		Owner:         account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Currency:      account1.Currency,
+		Hits:          json.RawMessage(`[]`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pending", review.Status)
+	require.False(t, review.DecidedAt.Valid)
+
+	arg := DecideTransferReviewParams{
+		ID:       review.ID,
+		Status:   "rejected",
+		Reviewer: sql.NullString{String: account2.Owner, Valid: true},
+		Reason:   "mule account",
+	}
+	rejected, err := testQueries.DecideTransferReview(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, "rejected", rejected.Status)
+	require.True(t, rejected.DecidedAt.Valid)
+
+	arg.Status = "approved"
+	_, err = testQueries.DecideTransferReview(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
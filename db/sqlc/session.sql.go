@@ -12,6 +12,19 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE sessions
+SET
+  is_blocked = true
+WHERE username = $1 AND NOT is_blocked
+`
+
+// Blocks every session of a user, so that their refresh tokens stop working
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, blockUserSessions, username)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
     id,
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// StatementQuerier is a Querier that can also stream the entries of a statement
+type StatementQuerier interface {
+	Querier
+	StreamStatementEntries(ctx context.Context, arg StreamStatementEntriesParams, fn func(StatementEntry) error) error
+}
+
+// StatementTxOptions gives a statement a consistent, read-only snapshot.
+// Statements write to the client as they go, so they are never retried.
+var StatementTxOptions = TxOptions{
+	Isolation: sql.LevelRepeatableRead,
+	ReadOnly:  true,
+	Retry:     RetryPolicy{MaxAttempts: 1},
+}
+
+// StatementTx runs fn in a transaction using StatementTxOptions
+func (store *SQLStore) StatementTx(ctx context.Context, fn func(StatementQuerier) error) error {
+	_, err := store.execTx(ctx, StatementTxOptions, func(q *Queries) error {
+		return fn(store.withPII(q))
+	})
+	return err
+}
+
+// The counterparty of an entry is found through the transfer created in the
+// same transaction, which shares its created_at and amount.
+const streamStatementEntries = `-- name: StreamStatementEntries :many
+SELECT
+  e.id,
+  e.amount,
+  e.created_at,
+  t.id AS transfer_id,
+  t.counterparty_account_id,
+  a.number AS counterparty_account_number,
+  a.owner AS counterparty_owner
+FROM entries e
+LEFT JOIN LATERAL (
+  SELECT
+    id,
+    CASE WHEN e.amount < 0 THEN to_account_id ELSE from_account_id END AS counterparty_account_id
+  FROM transfers
+  WHERE created_at = e.created_at
+    AND amount = abs(e.amount)
+    AND (
+      (e.amount < 0 AND from_account_id = e.account_id) OR
+      (e.amount > 0 AND to_account_id = e.account_id)
+    )
+  ORDER BY id
+  LIMIT 1
+) t ON true
+LEFT JOIN accounts a ON a.id = t.counterparty_account_id
+WHERE e.account_id = $1 AND e.created_at >= $2 AND e.created_at < $3
+ORDER BY e.created_at, e.id
+`
+
+// StreamStatementEntriesParams selects the entries of an account in [From, To)
+type StreamStatementEntriesParams struct {
+	AccountID int64
+	From      time.Time
+	To        time.Time
+}
+
+// StatementEntry is an entry with the transfer and counterparty behind it,
+// if there is one
+type StatementEntry struct {
+	ID                        int64          `json:"id"`
+	Amount                    int64          `json:"amount"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	TransferID                sql.NullInt64  `json:"transfer_id"`
+	CounterpartyAccountID     sql.NullInt64  `json:"counterparty_account_id"`
+	CounterpartyAccountNumber sql.NullString `json:"counterparty_account_number"`
+	CounterpartyOwner         sql.NullString `json:"counterparty_owner"`
+}
+
+// StreamStatementEntries calls fn for every entry of the statement in order
+// without holding them all in memory. It stops at the first error of fn.
+func (q *Queries) StreamStatementEntries(ctx context.Context, arg StreamStatementEntriesParams, fn func(StatementEntry) error) error {
+	rows, err := q.db.QueryContext(ctx, streamStatementEntries, arg.AccountID, arg.From, arg.To)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i StatementEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyAccountNumber,
+			&i.CounterpartyOwner,
+		); err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return rows.Err()
+}
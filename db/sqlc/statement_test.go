@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamStatementEntries(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	from := time.Now().Add(-time.Minute)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	err = store.StatementTx(context.Background(), func(q StatementQuerier) error {
+		since, err := q.SumEntriesSince(context.Background(), SumEntriesSinceParams{
+			AccountID: account2.ID,
+			CreatedAt: from,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(10), since)
+
+		var entries []StatementEntry
+		err = q.StreamStatementEntries(context.Background(), StreamStatementEntriesParams{
+			AccountID: account2.ID,
+			From:      from,
+			To:        time.Now().Add(time.Minute),
+		}, func(entry StatementEntry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, entries, 1)
+		require.Equal(t, result.ToEntry.ID, entries[0].ID)
+		require.Equal(t, int64(10), entries[0].Amount)
+		require.Equal(t, result.Transfer.ID, entries[0].TransferID.Int64)
+		require.Equal(t, account1.ID, entries[0].CounterpartyAccountID.Int64)
+		require.Equal(t, account1.Number, entries[0].CounterpartyAccountNumber.String)
+		require.Equal(t, account1.Owner, entries[0].CounterpartyOwner.String)
+		return nil
+	})
+	require.NoError(t, err)
+}
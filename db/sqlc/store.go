@@ -4,44 +4,114 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"time"
+
+	"github.com/techschool/simplebank/crypt"
+	"github.com/techschool/simplebank/money"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Store interface {
-	Querier
+	StatementQuerier
+	ExecTx(ctx context.Context, opts TxOptions, fn func(Querier) error) error
+	StatementTx(ctx context.Context, fn func(StatementQuerier) error) error
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
 }
 
 // Store provides all functions to execute db queries and transaction
 type SQLStore struct {
 	db *sql.DB
-	*Queries
+	*piiQueries
+	transferTxOptions TxOptions
 }
 
 // NewStore creates a new store
 func NewStore(db *sql.DB) *SQLStore {
 	return &SQLStore{
-		db:      db,
-		Queries: New(db),
+		db:                db,
+		piiQueries:        &piiQueries{Queries: New(newTracedDBTX(db))},
+		transferTxOptions: DefaultTxOptions,
 	}
 }
 
-// ExecTx executes a function within a database transaction
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+// SetTransferTxOptions changes the isolation level and retry policy used by TransferTx
+func (store *SQLStore) SetTransferTxOptions(opts TxOptions) {
+	store.transferTxOptions = opts
+}
+
+// SetEnvelope makes the store encrypt the personal data of users and KYC
+// submissions with envelope from now on
+func (store *SQLStore) SetEnvelope(envelope *crypt.Envelope) {
+	store.envelope = envelope
+}
+
+// withPII wraps the queries of a transaction so that they encrypt personal
+// data like the store does
+func (store *SQLStore) withPII(q *Queries) *piiQueries {
+	return &piiQueries{Queries: q, envelope: store.envelope}
+}
+
+// ExecTx runs fn within a database transaction so that callers can combine
+// several queries atomically
+func (store *SQLStore) ExecTx(ctx context.Context, opts TxOptions, fn func(Querier) error) error {
+	_, err := store.execTx(ctx, opts, func(q *Queries) error {
+		return fn(store.withPII(q))
+	})
+	return err
+}
+
+// execTx executes a function within a database transaction. Attempts that
+// fail with a deadlock or serialization failure are rolled back and run
+// again following opts.Retry. It returns how many retries were made.
+func (store *SQLStore) execTx(ctx context.Context, opts TxOptions, fn func(*Queries) error) (retries int, err error) {
+	ctx, span := startSpan(ctx, "db.execTx",
+		attribute.String("db.isolation_level", opts.Isolation.String()),
+		attribute.Bool("db.read_only", opts.ReadOnly),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("db.retries", retries))
+		recordError(span, err)
+		span.End()
+	}()
+
+	for attempt := 1; ; attempt++ {
+		err = store.runTx(ctx, opts, fn)
+		if err == nil || !IsRetryable(err) || attempt >= opts.Retry.MaxAttempts {
+			return retries, err
+		}
+
+		span.AddEvent("retry", trace.WithAttributes(attribute.String("error", err.Error())))
+		if waitErr := opts.Retry.wait(ctx, attempt); waitErr != nil {
+			return retries, err
+		}
+		retries++
+	}
+}
+
+// runTx runs fn once inside a transaction, committing on success
+func (store *SQLStore) runTx(ctx context.Context, opts TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, opts.sqlOptions())
 	if err != nil {
 		return err
 	}
 
-	q := New(tx)
+	q := New(newTracedDBTX(tx))
 	err = fn(q)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
 		}
 		return err
 	}
 
-	return tx.Commit()
+	_, commitSpan := startSpan(ctx, "db.commit")
+	err = tx.Commit()
+	recordError(commitSpan, err)
+	commitSpan.End()
+
+	return err
 }
 
 // TransferTxParams contains the input parameters of the transfer transaction
@@ -49,24 +119,93 @@ type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// AfterTransfer, if set, runs inside the transaction once the balances
+	// are updated. Returning an error rolls the transfer back.
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
 }
 
 // TransferTxResult is the result of the transfer transaction
 type TransferTxResult struct {
-	Transfer    Transfer `json:"transfer"`
-	FromAccount Account  `json:"from_account"`
-	ToAccount   Account  `json:"to_account"`
-	FromEntry   Entry    `json:"from_entry"`
-	ToEntry     Entry    `json:"to_entry"`
+	Transfer Transfer `json:"transfer"`
+	// Amount is the amount of the transfer in the currency of both accounts
+	Amount       money.Money  `json:"amount"`
+	JournalEntry JournalEntry `json:"journal_entry"`
+	FromAccount  Account      `json:"from_account"`
+	ToAccount    Account      `json:"to_account"`
+	FromEntry    Entry        `json:"from_entry"`
+	ToEntry      Entry        `json:"to_entry"`
+	// Retries is how many times the transaction had to be run again
+	Retries int `json:"-"`
+}
+
+// InsufficientFundsError is returned by TransferTx when the balance of the
+// sending account does not cover the transfer
+type InsufficientFundsError struct {
+	AccountID int64
+	Balance   int64
+	// Requested is the amount of the rejected transfer
+	Requested int64
+}
+
+func (err *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("balance %d of account %d does not cover a transfer of %d",
+		err.Balance, err.AccountID, err.Requested)
 }
 
 // TransferTx performs a money transfer from one account to the other.
-// It creates the transfer, add account entries, and update accounts' balance within a database transaction
+// It creates the transfer and posts a journal entry debiting the ledger
+// account of the sender and crediting the one of the receiver, which adds the
+// account entries and updates the balances within a database transaction.
+// Both accounts must hold the same currency for the entry to balance.
+// Both accounts are locked first. The transfer fails with an
+// InsufficientFundsError if the locked balance of the sender does not cover
+// it, with a UserBlockedError if sanctions screening blocked the sender, with
+// a KYCTierError if the sender may not use the currency, and with a
+// LimitExceededError if it goes over a limit of the sending account.
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	ctx, span := startSpan(ctx, "db.TransferTx",
+		attribute.Int64("transfer.from_account_id", arg.FromAccountID),
+		attribute.Int64("transfer.to_account_id", arg.ToAccountID),
+	)
+	defer span.End()
+
 	var result TransferTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
+	retries, err := store.execTx(ctx, store.transferTxOptions, func(q *Queries) error {
+		fromAccount, toAccount, err := lockTransferAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		if fromAccount.Balance < arg.Amount {
+			return &InsufficientFundsError{
+				AccountID: fromAccount.ID,
+				Balance:   fromAccount.Balance,
+				Requested: arg.Amount,
+			}
+		}
+
+		if err := CheckNotBlocked(ctx, q, fromAccount.Owner); err != nil {
+			return err
+		}
+
+		if err := CheckKYCTier(ctx, q, fromAccount.Owner, fromAccount.Currency); err != nil {
+			return err
+		}
+
+		if err := checkTransferLimits(ctx, q, fromAccount, arg.Amount, time.Now()); err != nil {
+			return err
+		}
+
+		fromLedger, err := CustomerLedgerAccount(ctx, q, arg.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		toLedger, err := CustomerLedgerAccount(ctx, q, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
 
 		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
 			FromAccountID: arg.FromAccountID,
@@ -77,53 +216,57 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 			return err
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
+		posted, err := PostJournalEntry(ctx, q, PostJournalEntryParams{
+			Kind:        JournalKindTransfer,
+			Description: fmt.Sprintf("Transfer from account %s to account %s", fromAccount.Number, toAccount.Number),
+			TransferID:  sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+			Postings: []PostingParams{
+				{LedgerAccountID: fromLedger.ID, Amount: arg.Amount, Currency: fromLedger.Currency},
+				{LedgerAccountID: toLedger.ID, Amount: -arg.Amount, Currency: toLedger.Currency},
+			},
 		})
 		if err != nil {
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+		result.Amount = money.New(result.Transfer.Amount, fromLedger.Currency)
+		result.JournalEntry = posted.JournalEntry
+		result.FromEntry = posted.Entries[0]
+		result.ToEntry = posted.Entries[1]
+		result.FromAccount = posted.Accounts[arg.FromAccountID]
+		result.ToAccount = posted.Accounts[arg.ToAccountID]
 
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+		if arg.AfterTransfer != nil {
+			return arg.AfterTransfer(q, result)
 		}
 
-		return err
+		return nil
 	})
 
+	result.Retries = retries
 	return result, err
 }
 
-func addMoney(
-	ctx context.Context,
-	q *Queries,
-	accountID1 int64,
-	amount1 int64,
-	accountID2 int64,
-	amount2 int64,
-) (account1 Account, account2 Account, err error) {
-	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		ID:     accountID1,
-		Amount: amount1,
-	})
-	if err != nil {
-		return
+// lockTransferAccounts locks both accounts of a transfer in id order, the
+// order their balances are updated in, so that concurrent transfers cannot
+// deadlock. It returns the sending and the receiving account.
+func lockTransferAccounts(ctx context.Context, q Querier, fromAccountID int64, toAccountID int64) (Account, Account, error) {
+	accountIDs := []int64{fromAccountID, toAccountID}
+	slices.Sort(accountIDs)
+
+	var fromAccount, toAccount Account
+	for _, accountID := range slices.Compact(accountIDs) {
+		account, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return Account{}, Account{}, fmt.Errorf("cannot lock account %d: %w", accountID, err)
+		}
+		if account.ID == fromAccountID {
+			fromAccount = account
+		}
+		if account.ID == toAccountID {
+			toAccount = account
+		}
 	}
 
-	account2, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		ID:     accountID2,
-		Amount: amount2,
-	})
-	return
+	return fromAccount, toAccount, nil
 }
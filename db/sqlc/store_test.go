@@ -2,16 +2,20 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
 )
 
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
 
 	n := 5
 	amount := int64(10)
@@ -51,6 +55,7 @@ func TestTransferTx(t *testing.T) {
 		require.Equal(t, amount, transfer.Amount)
 		require.NotZero(t, transfer.ID)
 		require.NotZero(t, transfer.CreatedAt)
+		require.Equal(t, money.New(amount, account1.Currency), result.Amount)
 
 		_, err = store.GetTransfer(context.Background(), transfer.ID)
 		require.NoError(t, err)
@@ -108,11 +113,57 @@ func TestTransferTx(t *testing.T) {
 	require.Equal(t, account2.Balance+int64(n)*amount, updatedAccount2.Balance)
 }
 
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	// each transfer alone is covered by the balance, together they would
+	// overdraw it, so all but one must see the locked balance and fail
+	n := 5
+	amount := account1.Balance/2 + 1
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+			})
+
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+
+		var fundsErr *InsufficientFundsError
+		require.ErrorAs(t, err, &fundsErr)
+		require.Equal(t, account1.ID, fundsErr.AccountID)
+		require.Equal(t, amount, fundsErr.Requested)
+		require.Less(t, fundsErr.Balance, amount)
+	}
+	require.Equal(t, 1, succeeded)
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-amount, updatedAccount1.Balance)
+	require.GreaterOrEqual(t, updatedAccount1.Balance, int64(0))
+}
+
 func TestTransferTxDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
 
 	n := 10
 	amount := int64(10)
@@ -153,3 +204,107 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxSerializableRetries(t *testing.T) {
+	store := NewStore(testDB)
+	store.SetTransferTxOptions(TxOptions{
+		Isolation: sql.LevelSerializable,
+		Retry: RetryPolicy{
+			MaxAttempts: 20,
+			BaseDelay:   5 * time.Millisecond,
+			MaxDelay:    100 * time.Millisecond,
+		},
+	})
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	n := 10
+	amount := int64(10)
+	errs := make(chan error)
+	retries := make(chan int)
+
+	// concurrent transfers in both directions conflict under serializable
+	// isolation and must be absorbed by retries
+	for i := 0; i < n; i++ {
+		fromAccountID := account1.ID
+		toAccountID := account2.ID
+
+		if i%2 == 1 {
+			fromAccountID = account2.ID
+			toAccountID = account1.ID
+		}
+
+		go func() {
+			result, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: fromAccountID,
+				ToAccountID:   toAccountID,
+				Amount:        amount,
+			})
+
+			errs <- err
+			retries <- result.Retries
+		}()
+	}
+
+	totalRetries := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+		totalRetries += <-retries
+	}
+	t.Logf("%d transfers needed %d retries", n, totalRetries)
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updatedAccount2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestExecTxRetry(t *testing.T) {
+	store := NewStore(testDB)
+	opts := TxOptions{
+		Retry: RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    time.Millisecond,
+		},
+	}
+
+	// fails with a deadlock twice, then succeeds
+	attempts := 0
+	retries, err := store.execTx(context.Background(), opts, func(q *Queries) error {
+		attempts++
+		if attempts < 3 {
+			return &pq.Error{Code: DeadlockDetected}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+	require.Equal(t, 2, retries)
+
+	// gives up once MaxAttempts is reached
+	attempts = 0
+	retries, err = store.execTx(context.Background(), opts, func(q *Queries) error {
+		attempts++
+		return &pq.Error{Code: SerializationFailure}
+	})
+	require.True(t, IsRetryable(err))
+	require.Equal(t, 3, attempts)
+	require.Equal(t, 2, retries)
+
+	// other errors are returned straight away
+	attempts = 0
+	retries, err = store.execTx(context.Background(), opts, func(q *Queries) error {
+		attempts++
+		return sql.ErrNoRows
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Equal(t, 1, attempts)
+	require.Zero(t, retries)
+}
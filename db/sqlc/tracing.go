@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/techschool/simplebank/db/sqlc")
+
+// tracedDBTX starts a span around every statement. Spans are named after
+// the sqlc query name so that each Queries method shows up on its own.
+type tracedDBTX struct {
+	db DBTX
+}
+
+func newTracedDBTX(db DBTX) DBTX {
+	return tracedDBTX{db: db}
+}
+
+func (t tracedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	result, err := t.db.ExecContext(ctx, query, args...)
+	recordError(span, err)
+	return result, err
+}
+
+func (t tracedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	stmt, err := t.db.PrepareContext(ctx, query)
+	recordError(span, err)
+	return stmt, err
+}
+
+func (t tracedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	recordError(span, err)
+	return rows, err
+}
+
+func (t tracedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	row := t.db.QueryRowContext(ctx, query, args...)
+	recordError(span, row.Err())
+	return row
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := queryName(query)
+
+	return tracer.Start(ctx, "db."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(name),
+		),
+	)
+}
+
+// queryName extracts X from the "-- name: X :one" header sqlc puts on every query
+func queryName(query string) string {
+	const prefix = "-- name: "
+
+	if !strings.HasPrefix(query, prefix) {
+		return "query"
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(query, prefix))
+	if len(fields) == 0 {
+		return "query"
+	}
+
+	return fields[0]
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil || err == sql.ErrNoRows {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// startSpan starts a span for a store level operation such as a transaction
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
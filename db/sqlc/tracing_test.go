@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type fakeDBTX struct {
+	DBTX
+	err error
+}
+
+func (f fakeDBTX) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, f.err
+}
+
+func TestQueryName(t *testing.T) {
+	require.Equal(t, "GetAccount", queryName(getAccount))
+	require.Equal(t, "AddAccountBalance", queryName(addAccountBalance))
+	require.Equal(t, "query", queryName("SELECT 1"))
+}
+
+func TestTracedDBTX(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	defer provider.Shutdown(context.Background())
+
+	queryErr := errors.New("connection reset")
+	q := New(newTracedDBTX(fakeDBTX{err: queryErr}))
+
+	err := q.DeleteAccount(context.Background(), 1)
+	require.ErrorIs(t, err, queryErr)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "db.DeleteAccount", spans[0].Name)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes that mean the transaction can safely be run again
+const (
+	SerializationFailure = pq.ErrorCode("40001")
+	DeadlockDetected     = pq.ErrorCode("40P01")
+)
+
+// TxOptions configures the transactions run by execTx
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	Retry     RetryPolicy
+}
+
+// RetryPolicy controls how transactions failing with a retryable error are
+// run again. MaxAttempts counts the first run, so 1 or less disables retries.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy retries deadlocks and serialization failures a few
+// times within roughly a second.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    500 * time.Millisecond,
+}
+
+// DefaultTxOptions are used by the store transactions unless told otherwise
+var DefaultTxOptions = TxOptions{
+	Isolation: sql.LevelDefault,
+	Retry:     DefaultRetryPolicy,
+}
+
+func (opts TxOptions) sqlOptions() *sql.TxOptions {
+	return &sql.TxOptions{
+		Isolation: opts.Isolation,
+		ReadOnly:  opts.ReadOnly,
+	}
+}
+
+// IsRetryable reports whether err is a serialization failure or a deadlock
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == SerializationFailure || pqErr.Code == DeadlockDetected
+}
+
+// backoff returns the delay before the given retry, starting at 1. It
+// grows exponentially up to MaxDelay with jitter in its upper half, so
+// that transactions which collided do not collide again.
+func (policy RetryPolicy) backoff(retry int) time.Duration {
+	if policy.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := policy.BaseDelay
+	for i := 1; i < retry && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// wait sleeps for the backoff of retry, returning early if ctx is done
+func (policy RetryPolicy) wait(ctx context.Context, retry int) error {
+	timer := time.NewTimer(policy.backoff(retry))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
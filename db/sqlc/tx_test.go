@@ -0,0 +1,44 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryable(t *testing.T) {
+	require.True(t, IsRetryable(&pq.Error{Code: DeadlockDetected}))
+	require.True(t, IsRetryable(fmt.Errorf("tx err: %w", &pq.Error{Code: SerializationFailure})))
+
+	require.False(t, IsRetryable(&pq.Error{Code: "23505"}))
+	require.False(t, IsRetryable(errors.New("deadlock detected")))
+	require.False(t, IsRetryable(nil))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    50 * time.Millisecond,
+	}
+
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(1)
+		require.GreaterOrEqual(t, delay, 5*time.Millisecond)
+		require.LessOrEqual(t, delay, 10*time.Millisecond)
+
+		delay = policy.backoff(2)
+		require.GreaterOrEqual(t, delay, 10*time.Millisecond)
+		require.LessOrEqual(t, delay, 20*time.Millisecond)
+
+		delay = policy.backoff(8)
+		require.GreaterOrEqual(t, delay, 25*time.Millisecond)
+		require.LessOrEqual(t, delay, 50*time.Millisecond)
+	}
+
+	require.Zero(t, RetryPolicy{}.backoff(3))
+}
@@ -14,10 +14,18 @@ INSERT INTO users (
   username,
   hashed_password,
   full_name,
-  email
+  email,
+  phone,
+  full_name_sealed,
+  email_sealed,
+  email_index,
+  phone_sealed,
+  phone_index,
+  data_key,
+  master_key_id
 ) VALUES (
-  $1, $2, $3, $4
-) RETURNING username, hashed_password, full_name, email, password_change_at, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index
 `
 
 type CreateUserParams struct {
@@ -25,6 +33,14 @@ type CreateUserParams struct {
 	HashedPassword string `json:"hashed_password"`
 	FullName       string `json:"full_name"`
 	Email          string `json:"email"`
+	Phone          string `json:"phone"`
+	FullNameSealed []byte `json:"full_name_sealed"`
+	EmailSealed    []byte `json:"email_sealed"`
+	EmailIndex     []byte `json:"email_index"`
+	PhoneSealed    []byte `json:"phone_sealed"`
+	PhoneIndex     []byte `json:"phone_index"`
+	DataKey        []byte `json:"data_key"`
+	MasterKeyID    string `json:"master_key_id"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -33,6 +49,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.HashedPassword,
 		arg.FullName,
 		arg.Email,
+		arg.Phone,
+		arg.FullNameSealed,
+		arg.EmailSealed,
+		arg.EmailIndex,
+		arg.PhoneSealed,
+		arg.PhoneIndex,
+		arg.DataKey,
+		arg.MasterKeyID,
 	)
 	var i User
 	err := row.Scan(
@@ -42,12 +66,25 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangeAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.ScreeningStatus,
+		&i.ScreeningListVersion,
+		&i.ScreenedAt,
+		&i.KycTier,
+		&i.FullNameSealed,
+		&i.EmailSealed,
+		&i.EmailIndex,
+		&i.DataKey,
+		&i.MasterKeyID,
+		&i.Phone,
+		&i.PhoneSealed,
+		&i.PhoneIndex,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_change_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -61,6 +98,218 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangeAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.ScreeningStatus,
+		&i.ScreeningListVersion,
+		&i.ScreenedAt,
+		&i.KycTier,
+		&i.FullNameSealed,
+		&i.EmailSealed,
+		&i.EmailIndex,
+		&i.DataKey,
+		&i.MasterKeyID,
+		&i.Phone,
+		&i.PhoneSealed,
+		&i.PhoneIndex,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index FROM users
+WHERE email_index = $1 OR (email <> '' AND email = $2)
+LIMIT 1
+`
+
+type GetUserByEmailParams struct {
+	EmailIndex []byte `json:"email_index"`
+	Email      string `json:"email"`
+}
+
+// Encrypted users are found by the blind index of their email, the others
+// by the email itself
+func (q *Queries) GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, arg.EmailIndex, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.ScreeningStatus,
+		&i.ScreeningListVersion,
+		&i.ScreenedAt,
+		&i.KycTier,
+		&i.FullNameSealed,
+		&i.EmailSealed,
+		&i.EmailIndex,
+		&i.DataKey,
+		&i.MasterKeyID,
+		&i.Phone,
+		&i.PhoneSealed,
+		&i.PhoneIndex,
+	)
+	return i, err
+}
+
+const getUserByPhone = `-- name: GetUserByPhone :one
+SELECT username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index FROM users
+WHERE phone_index = $1 OR (phone <> '' AND phone = $2)
+LIMIT 1
+`
+
+type GetUserByPhoneParams struct {
+	PhoneIndex []byte `json:"phone_index"`
+	Phone      string `json:"phone"`
+}
+
+// Phone numbers are matched like emails, see GetUserByEmail
+func (q *Queries) GetUserByPhone(ctx context.Context, arg GetUserByPhoneParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByPhone, arg.PhoneIndex, arg.Phone)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.ScreeningStatus,
+		&i.ScreeningListVersion,
+		&i.ScreenedAt,
+		&i.KycTier,
+		&i.FullNameSealed,
+		&i.EmailSealed,
+		&i.EmailIndex,
+		&i.DataKey,
+		&i.MasterKeyID,
+		&i.Phone,
+		&i.PhoneSealed,
+		&i.PhoneIndex,
+	)
+	return i, err
+}
+
+const listUsersToReencrypt = `-- name: ListUsersToReencrypt :many
+SELECT username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index FROM users
+WHERE master_key_id <> $1
+ORDER BY username
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type ListUsersToReencryptParams struct {
+	MasterKeyID string `json:"master_key_id"`
+	BatchSize   int32  `json:"batch_size"`
+}
+
+func (q *Queries) ListUsersToReencrypt(ctx context.Context, arg ListUsersToReencryptParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersToReencrypt, arg.MasterKeyID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Username,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			&i.PasswordChangeAt,
+			&i.CreatedAt,
+			&i.Role,
+			&i.ScreeningStatus,
+			&i.ScreeningListVersion,
+			&i.ScreenedAt,
+			&i.KycTier,
+			&i.FullNameSealed,
+			&i.EmailSealed,
+			&i.EmailIndex,
+			&i.DataKey,
+			&i.MasterKeyID,
+			&i.Phone,
+			&i.PhoneSealed,
+			&i.PhoneIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserEncryption = `-- name: UpdateUserEncryption :one
+UPDATE users
+SET
+  full_name = '',
+  email = '',
+  phone = '',
+  full_name_sealed = $1,
+  email_sealed = $2,
+  email_index = $3,
+  phone_sealed = $4,
+  phone_index = $5,
+  data_key = $6,
+  master_key_id = $7
+WHERE username = $8
+RETURNING username, hashed_password, full_name, email, password_change_at, created_at, role, screening_status, screening_list_version, screened_at, kyc_tier, full_name_sealed, email_sealed, email_index, data_key, master_key_id, phone, phone_sealed, phone_index
+`
+
+type UpdateUserEncryptionParams struct {
+	FullNameSealed []byte `json:"full_name_sealed"`
+	EmailSealed    []byte `json:"email_sealed"`
+	EmailIndex     []byte `json:"email_index"`
+	PhoneSealed    []byte `json:"phone_sealed"`
+	PhoneIndex     []byte `json:"phone_index"`
+	DataKey        []byte `json:"data_key"`
+	MasterKeyID    string `json:"master_key_id"`
+	Username       string `json:"username"`
+}
+
+func (q *Queries) UpdateUserEncryption(ctx context.Context, arg UpdateUserEncryptionParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserEncryption,
+		arg.FullNameSealed,
+		arg.EmailSealed,
+		arg.EmailIndex,
+		arg.PhoneSealed,
+		arg.PhoneIndex,
+		arg.DataKey,
+		arg.MasterKeyID,
+		arg.Username,
+	)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangeAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.ScreeningStatus,
+		&i.ScreeningListVersion,
+		&i.ScreenedAt,
+		&i.KycTier,
+		&i.FullNameSealed,
+		&i.EmailSealed,
+		&i.EmailIndex,
+		&i.DataKey,
+		&i.MasterKeyID,
+		&i.Phone,
+		&i.PhoneSealed,
+		&i.PhoneIndex,
 	)
 	return i, err
 }
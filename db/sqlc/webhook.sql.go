@@ -0,0 +1,425 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: webhook.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+WITH due AS (
+  SELECT d.id
+  FROM webhook_deliveries d
+  JOIN webhook_subscriptions s ON s.id = d.subscription_id
+  WHERE d.status = 'pending'
+    AND d.next_attempt_at <= now()
+    AND s.is_active
+  ORDER BY d.id
+  LIMIT $2
+  FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = $1
+FROM due, webhook_subscriptions s
+WHERE d.id = due.id
+  AND s.id = d.subscription_id
+RETURNING
+  d.id,
+  d.subscription_id,
+  d.event_id,
+  d.event_type,
+  d.payload,
+  d.attempts,
+  d.created_at,
+  s.url,
+  s.secret
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseUntil time.Time `json:"lease_until"`
+	BatchSize  int32     `json:"batch_size"`
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int32           `json:"attempts"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Url            string          `json:"url"`
+	Secret         string          `json:"secret"`
+}
+
+// Claimed deliveries are not due again until lease_until, so they can be sent
+// without holding a lock. Deliveries of a dispatcher that dies before marking
+// them are retried once the lease runs out.
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDeliveries, arg.LeaseUntil, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClaimWebhookDeliveriesRow{}
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Attempts,
+			&i.CreatedAt,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  subscription_id,
+  event_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, delivered_at, created_at
+`
+
+type CreateWebhookDeliveryParams struct {
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookDelivery,
+		arg.SubscriptionID,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookSubscription = `-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  owner,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, owner, url, secret, event_types, is_active, created_at
+`
+
+type CreateWebhookSubscriptionParams struct {
+	Owner      string   `json:"owner"`
+	Url        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookSubscription,
+		arg.Owner,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.EventTypes),
+	)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.EventTypes),
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const disableWebhookSubscription = `-- name: DisableWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET is_active = false
+WHERE id = $1
+RETURNING id, owner, url, secret, event_types, is_active, created_at
+`
+
+func (q *Queries) DisableWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, disableWebhookSubscription, id)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.EventTypes),
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, delivered_at, created_at FROM webhook_deliveries
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookDelivery, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWebhookSubscription = `-- name: GetWebhookSubscription :one
+SELECT id, owner, url, secret, event_types, is_active, created_at FROM webhook_subscriptions
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookSubscription, id)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.EventTypes),
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, delivered_at, created_at FROM webhook_deliveries
+WHERE subscription_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListWebhookDeliveriesParams struct {
+	SubscriptionID int64 `json:"subscription_id"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveries, arg.SubscriptionID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookDelivery{}
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptions = `-- name: ListWebhookSubscriptions :many
+SELECT id, owner, url, secret, event_types, is_active, created_at FROM webhook_subscriptions
+WHERE owner = $1 AND is_active
+ORDER BY id
+`
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context, owner string) ([]WebhookSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookSubscriptions, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookSubscription{}
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.EventTypes),
+			&i.IsActive,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptionsForEvent = `-- name: ListWebhookSubscriptionsForEvent :many
+SELECT id, owner, url, secret, event_types, is_active, created_at FROM webhook_subscriptions
+WHERE owner = $1 AND is_active AND $2::varchar = ANY(event_types)
+ORDER BY id
+`
+
+type ListWebhookSubscriptionsForEventParams struct {
+	Owner     string `json:"owner"`
+	EventType string `json:"event_type"`
+}
+
+func (q *Queries) ListWebhookSubscriptionsForEvent(ctx context.Context, arg ListWebhookSubscriptionsForEventParams) ([]WebhookSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookSubscriptionsForEvent, arg.Owner, arg.EventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookSubscription{}
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.EventTypes),
+			&i.IsActive,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markWebhookDeliveryFailed = `-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET
+  status = $1,
+  attempts = attempts + 1,
+  response_status = $2,
+  last_error = $3,
+  next_attempt_at = $4
+WHERE id = $5
+`
+
+type MarkWebhookDeliveryFailedParams struct {
+	Status         string    `json:"status"`
+	ResponseStatus int32     `json:"response_status"`
+	LastError      string    `json:"last_error"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	ID             int64     `json:"id"`
+}
+
+func (q *Queries) MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markWebhookDeliveryFailed,
+		arg.Status,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const markWebhookDeliverySucceeded = `-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE webhook_deliveries
+SET
+  status = 'succeeded',
+  attempts = attempts + 1,
+  response_status = $2,
+  last_error = '',
+  delivered_at = now()
+WHERE id = $1
+`
+
+type MarkWebhookDeliverySucceededParams struct {
+	ID             int64 `json:"id"`
+	ResponseStatus int32 `json:"response_status"`
+}
+
+func (q *Queries) MarkWebhookDeliverySucceeded(ctx context.Context, arg MarkWebhookDeliverySucceededParams) error {
+	_, err := q.db.ExecContext(ctx, markWebhookDeliverySucceeded, arg.ID, arg.ResponseStatus)
+	return err
+}
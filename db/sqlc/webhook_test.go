@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func createRandomWebhookSubscription(t *testing.T, eventTypes []string) WebhookSubscription {
+	user := createRandomUser(t)
+
+	arg := CreateWebhookSubscriptionParams{
+		Owner:      user.Username,
+		Url:        "https://example.com/" + util.RandomString(6),
+		Secret:     util.RandomString(32),
+		EventTypes: eventTypes,
+	}
+
+	subscription, err := testQueries.CreateWebhookSubscription(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, subscription.ID)
+	require.Equal(t, arg.EventTypes, subscription.EventTypes)
+	require.True(t, subscription.IsActive)
+
+	return subscription
+}
+
+func TestListWebhookSubscriptionsForEvent(t *testing.T) {
+	subscription := createRandomWebhookSubscription(t, []string{"entry.created"})
+
+	subscriptions, err := testQueries.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		Owner:     subscription.Owner,
+		EventType: "entry.created",
+	})
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+
+	subscriptions, err = testQueries.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		Owner:     subscription.Owner,
+		EventType: "transfer.created",
+	})
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+
+	_, err = testQueries.DisableWebhookSubscription(context.Background(), subscription.ID)
+	require.NoError(t, err)
+
+	subscriptions, err = testQueries.ListWebhookSubscriptions(context.Background(), subscription.Owner)
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+}
+
+func TestWebhookDeliveryLifecycle(t *testing.T) {
+	subscription := createRandomWebhookSubscription(t, []string{"entry.created"})
+
+	delivery, err := testQueries.CreateWebhookDelivery(context.Background(), CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventID:        uuid.New(),
+		EventType:      "entry.created",
+		Payload:        json.RawMessage(`{"amount":10}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pending", delivery.Status)
+
+	leaseUntil := time.Now().Add(time.Minute)
+	claimed, err := testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: leaseUntil,
+		BatchSize:  1000,
+	})
+	require.NoError(t, err)
+
+	var found bool
+	for _, row := range claimed {
+		if row.ID == delivery.ID {
+			found = true
+			require.Equal(t, subscription.Url, row.Url)
+			require.Equal(t, subscription.Secret, row.Secret)
+		}
+	}
+	require.True(t, found)
+
+	// leased deliveries are not claimed again
+	claimed, err = testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: leaseUntil,
+		BatchSize:  1000,
+	})
+	require.NoError(t, err)
+	for _, row := range claimed {
+		require.NotEqual(t, delivery.ID, row.ID)
+	}
+
+	err = testQueries.MarkWebhookDeliverySucceeded(context.Background(), MarkWebhookDeliverySucceededParams{
+		ID:             delivery.ID,
+		ResponseStatus: 200,
+	})
+	require.NoError(t, err)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          5,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "succeeded", deliveries[0].Status)
+	require.Equal(t, int32(200), deliveries[0].ResponseStatus)
+	require.Equal(t, int32(1), deliveries[0].Attempts)
+	require.True(t, deliveries[0].DeliveredAt.Valid)
+}
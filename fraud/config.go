@@ -0,0 +1,221 @@
+package fraud
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/techschool/simplebank/accountnumber"
+	"github.com/techschool/simplebank/money"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule types of the rules file
+const (
+	TypeVelocity    = "velocity"
+	TypeNewPayee    = "new_payee"
+	TypeStructuring = "structuring"
+	TypeBlocklist   = "blocklist"
+)
+
+// defaultMarginPercent is how close to the threshold of a structuring rule
+// a transfer must be when the rules file does not say
+const defaultMarginPercent = 10
+
+// Config is the rules file, such as
+//
+//	rules:
+//	  - name: burst
+//	    type: velocity
+//	    decision: review
+//	    window: 1h
+//	    max_count: 10
+//	  - name: sanctioned
+//	    type: blocklist
+//	    decision: deny
+//	    owners: [mallory]
+//	    accounts: [SB12000000000007]
+type Config struct {
+	Rules []RuleConfig `yaml:"rules"`
+}
+
+// RuleConfig configures one rule. Amounts are decimal strings by currency
+// and only the fields of its type are used.
+type RuleConfig struct {
+	Name     string        `yaml:"name"`
+	Type     string        `yaml:"type"`
+	Decision Decision      `yaml:"decision"`
+	Window   time.Duration `yaml:"window"`
+	// velocity
+	MaxCount  int               `yaml:"max_count"`
+	MaxAmount map[string]string `yaml:"max_amount"`
+	// new_payee
+	MinAmount map[string]string `yaml:"min_amount"`
+	// structuring
+	Threshold     map[string]string `yaml:"threshold"`
+	MarginPercent int64             `yaml:"margin_percent"`
+	MinCount      int               `yaml:"min_count"`
+	// blocklist
+	Owners []string `yaml:"owners"`
+	// Accounts are public account numbers
+	Accounts []string `yaml:"accounts"`
+}
+
+// LoadFile reads the rules file at path
+func LoadFile(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read fraud rules: %w", err)
+	}
+
+	return Load(bytes.NewReader(data))
+}
+
+// Load reads a rules file from r. Unknown fields are rejected so that a
+// misspelt setting does not silently disable a check.
+func Load(r io.Reader) (*Engine, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var config Config
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("cannot decode fraud rules: %w", err)
+	}
+
+	return config.Engine()
+}
+
+// Engine builds the rules of config
+func (config Config) Engine() (*Engine, error) {
+	names := make(map[string]bool, len(config.Rules))
+	rules := make([]Rule, 0, len(config.Rules))
+
+	for i, ruleConfig := range config.Rules {
+		if ruleConfig.Name == "" {
+			ruleConfig.Name = ruleConfig.Type
+		}
+		if names[ruleConfig.Name] {
+			return nil, fmt.Errorf("rule %d: duplicate name %q", i+1, ruleConfig.Name)
+		}
+		names[ruleConfig.Name] = true
+
+		rule, err := ruleConfig.rule()
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", ruleConfig.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return NewEngine(rules...), nil
+}
+
+func (config RuleConfig) rule() (Rule, error) {
+	if config.Decision == "" {
+		return nil, errors.New("decision is missing")
+	}
+
+	switch config.Type {
+	case TypeVelocity:
+		if config.Window <= 0 {
+			return nil, errors.New("window must be positive")
+		}
+		maxAmounts, err := parseAmounts(config.MaxAmount)
+		if err != nil {
+			return nil, fmt.Errorf("max_amount: %w", err)
+		}
+		if config.MaxCount <= 0 && len(maxAmounts) == 0 {
+			return nil, errors.New("max_count or max_amount is required")
+		}
+		return VelocityRule{
+			RuleName:   config.Name,
+			Decision:   config.Decision,
+			Window:     config.Window,
+			MaxCount:   config.MaxCount,
+			MaxAmounts: maxAmounts,
+		}, nil
+
+	case TypeNewPayee:
+		minAmounts, err := parseAmounts(config.MinAmount)
+		if err != nil {
+			return nil, fmt.Errorf("min_amount: %w", err)
+		}
+		if len(minAmounts) == 0 {
+			return nil, errors.New("min_amount is required")
+		}
+		return NewPayeeRule{
+			RuleName:   config.Name,
+			Decision:   config.Decision,
+			MinAmounts: minAmounts,
+		}, nil
+
+	case TypeStructuring:
+		if config.Window <= 0 {
+			return nil, errors.New("window must be positive")
+		}
+		thresholds, err := parseAmounts(config.Threshold)
+		if err != nil {
+			return nil, fmt.Errorf("threshold: %w", err)
+		}
+		if len(thresholds) == 0 {
+			return nil, errors.New("threshold is required")
+		}
+		if config.MarginPercent == 0 {
+			config.MarginPercent = defaultMarginPercent
+		}
+		if config.MarginPercent < 0 || config.MarginPercent >= 100 {
+			return nil, errors.New("margin_percent must be between 1 and 99")
+		}
+		if config.MinCount < 2 {
+			return nil, errors.New("min_count must be at least 2")
+		}
+		return StructuringRule{
+			RuleName:      config.Name,
+			Decision:      config.Decision,
+			Window:        config.Window,
+			Thresholds:    thresholds,
+			MarginPercent: config.MarginPercent,
+			MinCount:      config.MinCount,
+		}, nil
+
+	case TypeBlocklist:
+		rule := BlocklistRule{
+			RuleName: config.Name,
+			Decision: config.Decision,
+			Owners:   make(map[string]bool, len(config.Owners)),
+			Accounts: make(map[string]bool, len(config.Accounts)),
+		}
+		for _, owner := range config.Owners {
+			rule.Owners[owner] = true
+		}
+		for _, number := range config.Accounts {
+			number = accountnumber.Normalize(number)
+			if err := accountnumber.Validate(number); err != nil {
+				return nil, fmt.Errorf("accounts: %s: %w", number, err)
+			}
+			rule.Accounts[number] = true
+		}
+		return rule, nil
+	}
+
+	return nil, fmt.Errorf("unknown type %q", config.Type)
+}
+
+// parseAmounts reads decimal amounts by currency into minor units
+func parseAmounts(amounts map[string]string) (map[string]int64, error) {
+	parsed := make(map[string]int64, len(amounts))
+	for currency, amount := range amounts {
+		m, err := money.Parse(amount, currency)
+		if err != nil {
+			return nil, err
+		}
+		if !m.IsPositive() {
+			return nil, fmt.Errorf("%s must be positive", m)
+		}
+		parsed[currency] = m.Amount
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,129 @@
+// Package fraud screens transfers against fraud and anti-money laundering
+// rules before they are made. Every rule decides to allow, review or deny a
+// transfer, and the strictest decision of all rules wins.
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"gopkg.in/yaml.v3"
+)
+
+// Decision is the outcome of screening a transfer
+type Decision string
+
+// Decisions from the most to the least permissive
+const (
+	Allow  Decision = "allow"
+	Review Decision = "review"
+	Deny   Decision = "deny"
+)
+
+// Statuses of a transfer held for review
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// severity orders decisions so that the strictest one wins
+func (decision Decision) severity() int {
+	switch decision {
+	case Review:
+		return 1
+	case Deny:
+		return 2
+	}
+
+	return 0
+}
+
+// UnmarshalYAML accepts review and deny only, a rule that allows has no effect
+func (decision *Decision) UnmarshalYAML(node *yaml.Node) error {
+	switch value := Decision(node.Value); value {
+	case Review, Deny:
+		*decision = value
+		return nil
+	}
+
+	return fmt.Errorf("line %d: decision must be review or deny, got %q", node.Line, node.Value)
+}
+
+// Transfer is a transfer about to be made
+type Transfer struct {
+	FromAccount db.Account
+	ToAccount   db.Account
+	Amount      money.Money
+	Time        time.Time
+}
+
+// Result is the decision of one rule on a transfer
+type Result struct {
+	Decision Decision
+	// Reason explains a decision other than Allow
+	Reason string
+}
+
+// Rule is one check of a transfer. Rules read the history of the accounts
+// through q and must not write to it.
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, q db.Querier, transfer Transfer) (Result, error)
+}
+
+// Hit is a rule that did not allow a transfer
+type Hit struct {
+	Rule     string   `json:"rule"`
+	Decision Decision `json:"decision"`
+	Reason   string   `json:"reason"`
+}
+
+// Verdict is the outcome of screening a transfer against every rule
+type Verdict struct {
+	Decision Decision
+	Hits     []Hit
+}
+
+// Engine evaluates a set of rules. A nil Engine allows every transfer.
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine creates an engine evaluating rules in order
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Screen evaluates every rule on transfer and returns the strictest decision
+// together with the rules that made it
+func (engine *Engine) Screen(ctx context.Context, q db.Querier, transfer Transfer) (Verdict, error) {
+	verdict := Verdict{Decision: Allow}
+	if engine == nil {
+		return verdict, nil
+	}
+
+	for _, rule := range engine.rules {
+		result, err := rule.Evaluate(ctx, q, transfer)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("cannot evaluate rule %s: %w", rule.Name(), err)
+		}
+		if result.Decision == Allow || result.Decision == "" {
+			continue
+		}
+
+		verdict.Hits = append(verdict.Hits, Hit{
+			Rule:     rule.Name(),
+			Decision: result.Decision,
+			Reason:   result.Reason,
+		})
+		if result.Decision.severity() > verdict.Decision.severity() {
+			verdict.Decision = result.Decision
+		}
+	}
+
+	return verdict, nil
+}
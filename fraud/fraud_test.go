@@ -0,0 +1,211 @@
+package fraud
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func randomTransfer(amount string) Transfer {
+	return Transfer{
+		FromAccount: db.Account{ID: 1, Owner: "alice", Currency: util.USD},
+		ToAccount:   db.Account{ID: 2, Owner: "bob", Currency: util.USD, Number: "SB12000000000007"},
+		Amount:      money.MustParse(amount, util.USD),
+		Time:        time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEngineStrictestDecisionWins(t *testing.T) {
+	transfer := randomTransfer("10.00")
+	engine := NewEngine(
+		BlocklistRule{RuleName: "owners", Decision: Review, Owners: map[string]bool{"bob": true}},
+		BlocklistRule{RuleName: "accounts", Decision: Deny, Accounts: map[string]bool{"SB12000000000007": true}},
+		BlocklistRule{RuleName: "others", Decision: Deny, Owners: map[string]bool{"carol": true}},
+	)
+
+	verdict, err := engine.Screen(context.Background(), nil, transfer)
+	require.NoError(t, err)
+	require.Equal(t, Deny, verdict.Decision)
+	require.Len(t, verdict.Hits, 2)
+	require.Equal(t, "owners", verdict.Hits[0].Rule)
+	require.Equal(t, "accounts", verdict.Hits[1].Rule)
+
+	var none *Engine
+	verdict, err = none.Screen(context.Background(), nil, transfer)
+	require.NoError(t, err)
+	require.Equal(t, Allow, verdict.Decision)
+	require.Empty(t, verdict.Hits)
+}
+
+func TestVelocityRule(t *testing.T) {
+	transfer := randomTransfer("100.00")
+	rule := VelocityRule{
+		RuleName:   "burst",
+		Decision:   Review,
+		Window:     time.Hour,
+		MaxCount:   3,
+		MaxAmounts: map[string]int64{util.USD: 50_000},
+	}
+
+	testCases := []struct {
+		name     string
+		previous []db.Transfer
+		decision Decision
+	}{
+		{
+			name:     "Allow",
+			previous: []db.Transfer{{Amount: 10_000}},
+			decision: Allow,
+		},
+		{
+			name:     "TooMany",
+			previous: []db.Transfer{{Amount: 1}, {Amount: 1}, {Amount: 1}},
+			decision: Review,
+		},
+		{
+			name:     "TooMuch",
+			previous: []db.Transfer{{Amount: 40_001}},
+			decision: Review,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			store.EXPECT().
+				ListTransfersFromAccountSince(gomock.Any(), gomock.Eq(db.ListTransfersFromAccountSinceParams{
+					FromAccountID: 1,
+					Since:         transfer.Time.Add(-time.Hour),
+				})).
+				Times(1).
+				Return(tc.previous, nil)
+
+			result, err := rule.Evaluate(context.Background(), store, transfer)
+			require.NoError(t, err)
+			require.Equal(t, tc.decision, result.Decision)
+		})
+	}
+}
+
+func TestNewPayeeRule(t *testing.T) {
+	rule := NewPayeeRule{RuleName: "new-payee", Decision: Review, MinAmounts: map[string]int64{util.USD: 500_000}}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().
+		CountTransfersBetweenAccounts(gomock.Any(), gomock.Eq(db.CountTransfersBetweenAccountsParams{FromAccountID: 1, ToAccountID: 2})).
+		Times(1).
+		Return(int64(0), nil)
+
+	result, err := rule.Evaluate(context.Background(), store, randomTransfer("4999.99"))
+	require.NoError(t, err)
+	require.Equal(t, Allow, result.Decision)
+
+	result, err = rule.Evaluate(context.Background(), store, randomTransfer("5000.00"))
+	require.NoError(t, err)
+	require.Equal(t, Review, result.Decision)
+	require.Contains(t, result.Reason, "account SB12000000000007")
+
+	// own accounts are never new payees
+	own := randomTransfer("5000.00")
+	own.ToAccount.Owner = own.FromAccount.Owner
+	result, err = rule.Evaluate(context.Background(), store, own)
+	require.NoError(t, err)
+	require.Equal(t, Allow, result.Decision)
+
+	store.EXPECT().
+		CountTransfersBetweenAccounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(1), nil)
+	result, err = rule.Evaluate(context.Background(), store, randomTransfer("5000.00"))
+	require.NoError(t, err)
+	require.Equal(t, Allow, result.Decision)
+}
+
+func TestStructuringRule(t *testing.T) {
+	rule := StructuringRule{
+		RuleName:      "structuring",
+		Decision:      Review,
+		Window:        24 * time.Hour,
+		Thresholds:    map[string]int64{util.USD: 1_000_000},
+		MarginPercent: 10,
+		MinCount:      3,
+	}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().
+		ListTransfersFromAccountSince(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return([]db.Transfer{{Amount: 950_000}, {Amount: 100}, {Amount: 990_000}}, nil)
+
+	result, err := rule.Evaluate(context.Background(), store, randomTransfer("9500.00"))
+	require.NoError(t, err)
+	require.Equal(t, Review, result.Decision)
+	require.Contains(t, result.Reason, "3 transfers")
+
+	// at or above the threshold is reported anyway, far below is not a pattern
+	for _, amount := range []string{"10000.00", "8999.99"} {
+		result, err = rule.Evaluate(context.Background(), store, randomTransfer(amount))
+		require.NoError(t, err)
+		require.Equal(t, Allow, result.Decision, amount)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	engine, err := Load(strings.NewReader(`
+rules:
+  - type: velocity
+    decision: review
+    window: 30m
+    max_count: 5
+  - name: sanctioned
+    type: blocklist
+    decision: deny
+    owners: [mallory]
+    accounts: ["sb12 0000 0000 0007"]
+`))
+	require.NoError(t, err)
+	require.Equal(t, []Rule{
+		VelocityRule{RuleName: TypeVelocity, Decision: Review, Window: 30 * time.Minute, MaxCount: 5, MaxAmounts: map[string]int64{}},
+		BlocklistRule{RuleName: "sanctioned", Decision: Deny, Owners: map[string]bool{"mallory": true}, Accounts: map[string]bool{"SB12000000000007": true}},
+	}, engine.rules)
+
+	empty, err := Load(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Empty(t, empty.rules)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"UnknownField":     "rules:\n  - {type: blocklist, decision: deny, owner: [mallory]}",
+		"UnknownType":      "rules:\n  - {type: geo, decision: deny}",
+		"AllowDecision":    "rules:\n  - {type: blocklist, decision: allow}",
+		"MissingDecision":  "rules:\n  - {type: blocklist}",
+		"DuplicateName":    "rules:\n  - {type: blocklist, decision: deny}\n  - {type: blocklist, decision: review}",
+		"MissingWindow":    "rules:\n  - {type: velocity, decision: review, max_count: 1}",
+		"TooManyDecimals":  "rules:\n  - {type: new_payee, decision: review, min_amount: {USD: '1.001'}}",
+		"UnknownCurrency":  "rules:\n  - {type: new_payee, decision: review, min_amount: {XYZ: '1'}}",
+		"InvalidAccount":   "rules:\n  - {type: blocklist, decision: deny, accounts: [SB21000000000007]}",
+		"MinCountTooSmall": "rules:\n  - {type: structuring, decision: review, window: 1h, min_count: 1, threshold: {USD: '1'}}",
+	}
+
+	for name, rules := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(strings.NewReader(rules))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLoadDefaultRules(t *testing.T) {
+	engine, err := LoadFile("../fraud_rules.yaml")
+	require.NoError(t, err)
+	require.Len(t, engine.rules, 4)
+}
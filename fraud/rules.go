@@ -0,0 +1,187 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// VelocityRule fires when the sending account makes more transfers, or
+// sends more money, within Window than allowed
+type VelocityRule struct {
+	RuleName string
+	Decision Decision
+	Window   time.Duration
+	// MaxCount is the number of transfers allowed within Window, 0 for no limit
+	MaxCount int
+	// MaxAmounts is the total allowed within Window in minor units by currency
+	MaxAmounts map[string]int64
+}
+
+func (rule VelocityRule) Name() string {
+	return rule.RuleName
+}
+
+func (rule VelocityRule) Evaluate(ctx context.Context, q db.Querier, transfer Transfer) (Result, error) {
+	transfers, err := q.ListTransfersFromAccountSince(ctx, db.ListTransfersFromAccountSinceParams{
+		FromAccountID: transfer.FromAccount.ID,
+		Since:         transfer.Time.Add(-rule.Window),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	count := len(transfers) + 1
+	if rule.MaxCount > 0 && count > rule.MaxCount {
+		return Result{
+			Decision: rule.Decision,
+			Reason:   fmt.Sprintf("%d transfers within %s, at most %d allowed", count, rule.Window, rule.MaxCount),
+		}, nil
+	}
+
+	maxAmount, ok := rule.MaxAmounts[transfer.Amount.Currency]
+	if !ok {
+		return Result{Decision: Allow}, nil
+	}
+
+	total := transfer.Amount.Amount
+	for _, previous := range transfers {
+		total += previous.Amount
+	}
+	if total > maxAmount {
+		return Result{
+			Decision: rule.Decision,
+			Reason: fmt.Sprintf("%s sent within %s, at most %s allowed",
+				money.New(total, transfer.Amount.Currency), rule.Window, money.New(maxAmount, transfer.Amount.Currency)),
+		}, nil
+	}
+
+	return Result{Decision: Allow}, nil
+}
+
+// NewPayeeRule fires when the sending account sends at least MinAmounts to
+// an account of someone else that it never sent money to before
+type NewPayeeRule struct {
+	RuleName string
+	Decision Decision
+	// MinAmounts is the smallest amount that fires in minor units by currency
+	MinAmounts map[string]int64
+}
+
+func (rule NewPayeeRule) Name() string {
+	return rule.RuleName
+}
+
+func (rule NewPayeeRule) Evaluate(ctx context.Context, q db.Querier, transfer Transfer) (Result, error) {
+	minAmount, ok := rule.MinAmounts[transfer.Amount.Currency]
+	if !ok || transfer.Amount.Amount < minAmount || transfer.FromAccount.Owner == transfer.ToAccount.Owner {
+		return Result{Decision: Allow}, nil
+	}
+
+	count, err := q.CountTransfersBetweenAccounts(ctx, db.CountTransfersBetweenAccountsParams{
+		FromAccountID: transfer.FromAccount.ID,
+		ToAccountID:   transfer.ToAccount.ID,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	if count > 0 {
+		return Result{Decision: Allow}, nil
+	}
+
+	return Result{
+		Decision: rule.Decision,
+		Reason: fmt.Sprintf("first transfer to account %s is %s, at least %s",
+			transfer.ToAccount.Number, transfer.Amount, money.New(minAmount, transfer.Amount.Currency)),
+	}, nil
+}
+
+// StructuringRule fires when the sending account splits money into several
+// transfers just below a reporting threshold. A transfer is just below when
+// it is within MarginPercent of the threshold of its currency.
+type StructuringRule struct {
+	RuleName      string
+	Decision      Decision
+	Window        time.Duration
+	Thresholds    map[string]int64
+	MarginPercent int64
+	// MinCount is how many transfers just below the threshold within Window,
+	// including the one screened, make a pattern
+	MinCount int
+}
+
+func (rule StructuringRule) Name() string {
+	return rule.RuleName
+}
+
+func (rule StructuringRule) Evaluate(ctx context.Context, q db.Querier, transfer Transfer) (Result, error) {
+	threshold, ok := rule.Thresholds[transfer.Amount.Currency]
+	if !ok {
+		return Result{Decision: Allow}, nil
+	}
+
+	lower := threshold - threshold*rule.MarginPercent/100
+	justBelow := func(amount int64) bool {
+		return amount >= lower && amount < threshold
+	}
+	if !justBelow(transfer.Amount.Amount) {
+		return Result{Decision: Allow}, nil
+	}
+
+	transfers, err := q.ListTransfersFromAccountSince(ctx, db.ListTransfersFromAccountSinceParams{
+		FromAccountID: transfer.FromAccount.ID,
+		Since:         transfer.Time.Add(-rule.Window),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	count := 1
+	for _, previous := range transfers {
+		if justBelow(previous.Amount) {
+			count++
+		}
+	}
+	if count < rule.MinCount {
+		return Result{Decision: Allow}, nil
+	}
+
+	return Result{
+		Decision: rule.Decision,
+		Reason: fmt.Sprintf("%d transfers within %s just below %s",
+			count, rule.Window, money.New(threshold, transfer.Amount.Currency)),
+	}, nil
+}
+
+// BlocklistRule fires on transfers to blocklisted accounts or to any
+// account of a blocklisted owner. Accounts are keyed by their public number.
+type BlocklistRule struct {
+	RuleName string
+	Decision Decision
+	Owners   map[string]bool
+	Accounts map[string]bool
+}
+
+func (rule BlocklistRule) Name() string {
+	return rule.RuleName
+}
+
+func (rule BlocklistRule) Evaluate(_ context.Context, _ db.Querier, transfer Transfer) (Result, error) {
+	switch {
+	case rule.Accounts[transfer.ToAccount.Number]:
+		return Result{
+			Decision: rule.Decision,
+			Reason:   fmt.Sprintf("account %s is blocklisted", transfer.ToAccount.Number),
+		}, nil
+	case rule.Owners[transfer.ToAccount.Owner]:
+		return Result{
+			Decision: rule.Decision,
+			Reason:   fmt.Sprintf("owner %s is blocklisted", transfer.ToAccount.Owner),
+		}, nil
+	}
+
+	return Result{Decision: Allow}, nil
+}
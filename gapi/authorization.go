@@ -0,0 +1,51 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/techschool/simplebank/apperr"
+	"github.com/techschool/simplebank/token"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	authorizationHeader = "authorization"
+	authorizationBearer = "bearer"
+)
+
+// authorizeUser verifies the bearer access token of the call and returns
+// its payload
+func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, unauthenticatedError("missing metadata")
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return nil, unauthenticatedError("authorization header is not provided")
+	}
+
+	fields := strings.Fields(values[0])
+	if len(fields) < 2 {
+		return nil, unauthenticatedError("invalid authorization header format")
+	}
+
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationBearer {
+		return nil, unauthenticatedError(fmt.Sprintf("unsupported authorization type %s", authorizationType))
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(fields[1])
+	if err != nil {
+		return nil, unauthenticatedError(err.Error())
+	}
+
+	return payload, nil
+}
+
+func unauthenticatedError(message string) error {
+	return newStatus(apperr.New(apperr.CodeUnauthenticated, message)).Err()
+}
@@ -1,8 +1,12 @@
 package gapi
 
 import (
+	"time"
+
 	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
 	"github.com/techschool/simplebank/pb"
+	"github.com/techschool/simplebank/service"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -11,7 +15,34 @@ func convertUser(user db.User) *pb.User {
 		Username:         user.Username,
 		FullName:         user.FullName,
 		Email:            user.Email,
+		Phone:            user.Phone,
 		PasswordChangeAt: timestamppb.New(user.PasswordChangeAt),
 		CreatedAt:        timestamppb.New(user.CreatedAt),
 	}
 }
+
+func convertPayee(payee db.Payee) *pb.Payee {
+	return &pb.Payee{
+		Id:            payee.ID,
+		Nickname:      payee.Nickname,
+		AccountNumber: payee.AccountNumber,
+		Currency:      payee.Currency,
+		Active:        service.PayeeActive(payee, time.Now()),
+		ActiveFrom:    timestamppb.New(payee.ActiveFrom),
+		CreatedAt:     timestamppb.New(payee.CreatedAt),
+	}
+}
+
+func convertMoney(m money.Money) *pb.Money {
+	return &pb.Money{
+		Amount:     m.Decimal(),
+		Currency:   m.Currency,
+		MinorUnits: m.Amount,
+	}
+}
+
+// parseMoney reads the decimal amount of m, minor_units is informational
+// and ignored
+func parseMoney(m *pb.Money) (money.Money, error) {
+	return money.Parse(m.GetAmount(), m.GetCurrency())
+}
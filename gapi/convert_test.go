@@ -0,0 +1,24 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/pb"
+	"github.com/techschool/simplebank/util"
+)
+
+func TestConvertMoney(t *testing.T) {
+	m := money.New(-1205, util.EUR)
+
+	message := convertMoney(m)
+	require.Equal(t, &pb.Money{Amount: "-12.05", Currency: util.EUR, MinorUnits: -1205}, message)
+
+	parsed, err := parseMoney(message)
+	require.NoError(t, err)
+	require.Equal(t, m, parsed)
+
+	_, err = parseMoney(&pb.Money{Amount: "12.345", Currency: util.EUR})
+	require.ErrorIs(t, err, money.ErrInvalidAmount)
+}
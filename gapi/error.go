@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/techschool/simplebank/apperr"
+	"github.com/techschool/simplebank/logging"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const errorDomain = "simplebank"
+
+func fieldViolation(field string, err error) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: err.Error(),
+	}
+}
+
+// invalidArgumentError wraps field violations into an InvalidArgument status
+func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) error {
+	appErr := apperr.New(apperr.CodeInvalidArgument, "invalid parameters")
+	badRequest := &errdetails.BadRequest{FieldViolations: violations}
+
+	return newStatus(appErr, badRequest).Err()
+}
+
+// serviceError renders err as a status carrying its catalogue code.
+// Errors outside the catalogue are logged and hidden behind INTERNAL.
+func serviceError(ctx context.Context, err error) error {
+	appErr := apperr.From(err)
+	if appErr.Code == apperr.CodeInternal {
+		slog.ErrorContext(ctx, "internal error",
+			slog.String("request_id", logging.RequestID(ctx)),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return newStatus(appErr).Err()
+}
+
+func newStatus(appErr *apperr.Error, details ...protoadapt.MessageV1) *status.Status {
+	st := status.New(appErr.Code.GRPCCode(), appErr.Message)
+
+	metadata := make(map[string]string, len(appErr.Details))
+	for key, value := range appErr.Details {
+		metadata[key] = fmt.Sprint(value)
+	}
+
+	errorInfo := &errdetails.ErrorInfo{
+		Reason:   string(appErr.Code),
+		Domain:   errorDomain,
+		Metadata: metadata,
+	}
+
+	withDetails, err := st.WithDetails(append([]protoadapt.MessageV1{errorInfo}, details...)...)
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
@@ -0,0 +1,26 @@
+package gapi
+
+import (
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/techschool/simplebank/logging"
+)
+
+// forwardedHeaders are passed from gateway HTTP requests to gRPC metadata
+// on top of the gateway defaults, to keep trace context and request ids.
+var forwardedHeaders = map[string]bool{
+	"traceparent":           true,
+	"tracestate":            true,
+	"baggage":               true,
+	logging.RequestIDHeader: true,
+}
+
+// GatewayHeaderMatcher forwards trace context and request id headers
+func GatewayHeaderMatcher(key string) (string, bool) {
+	if key = strings.ToLower(key); forwardedHeaders[key] {
+		return key, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
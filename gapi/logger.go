@@ -0,0 +1,106 @@
+package gapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/techschool/simplebank/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcLogger assigns a request id and writes one structured log line per unary call
+func GrpcLogger(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		startTime := time.Now()
+
+		requestID := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(logging.RequestIDHeader); len(ids) > 0 {
+				requestID = ids[0]
+			}
+			logger.DebugContext(ctx, "incoming metadata", slog.Any("metadata", logging.Redact(md)))
+		}
+		if requestID == "" {
+			requestID = logging.NewRequestID()
+		}
+		ctx = logging.WithRequestID(ctx, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDHeader, requestID))
+
+		result, err := handler(ctx, req)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		if code == codes.Internal || code == codes.Unknown {
+			level = slog.LevelError
+		}
+
+		attrs := []slog.Attr{
+			slog.String("protocol", "grpc"),
+			slog.String("request_id", requestID),
+			slog.String("method", info.FullMethod),
+			slog.Int("status_code", int(code)),
+			slog.String("status_text", code.String()),
+			slog.Duration("latency", time.Since(startTime)),
+		}
+
+		if r, ok := req.(interface{ GetUsername() string }); ok && r.GetUsername() != "" {
+			attrs = append(attrs, slog.String("username", r.GetUsername()))
+		}
+
+		attrs = append(attrs, logging.TraceAttrs(ctx)...)
+
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		logger.LogAttrs(ctx, level, "received a gRPC request", attrs...)
+		return result, err
+	}
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+// HttpLogger logs requests served by the gRPC gateway, which bypass the
+// gRPC interceptors because handlers are called in process.
+func HttpLogger(logger *slog.Logger, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		startTime := time.Now()
+
+		requestID := req.Header.Get(logging.RequestIDHeader)
+		if requestID == "" {
+			requestID = logging.NewRequestID()
+			req.Header.Set(logging.RequestIDHeader, requestID)
+		}
+		res.Header().Set(logging.RequestIDHeader, requestID)
+
+		rec := &responseRecorder{ResponseWriter: res, statusCode: http.StatusOK}
+		handler.ServeHTTP(rec, req.WithContext(logging.WithRequestID(req.Context(), requestID)))
+
+		level := slog.LevelInfo
+		if rec.statusCode >= 500 {
+			level = slog.LevelError
+		}
+
+		logger.LogAttrs(req.Context(), level, "received an HTTP request",
+			slog.String("protocol", "http"),
+			slog.String("request_id", requestID),
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("status", rec.statusCode),
+			slog.Duration("latency", time.Since(startTime)),
+		)
+	})
+}
@@ -2,8 +2,8 @@ package gapi
 
 import (
 	"context"
-	"log"
 
+	"github.com/techschool/simplebank/audit"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -23,8 +23,6 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 	mtdata := &Metadata{}
 
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		log.Printf("%v", md)
-
 		if userAgents := md.Get(GRPCGatewayUserAgent); len(userAgents) > 0 {
 			mtdata.userAgent = userAgents[0]
 		}
@@ -44,3 +42,12 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 
 	return mtdata
 }
+
+// withAuditClient stores the caller's IP and user agent for the audit log.
+// Calls through the gateway skip interceptors, so handlers call it themselves.
+func (mtdata *Metadata) withAuditClient(ctx context.Context) context.Context {
+	return audit.WithClient(ctx, audit.Client{
+		IP:        mtdata.clientIp,
+		UserAgent: mtdata.userAgent,
+	})
+}
@@ -0,0 +1,22 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/techschool/simplebank/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcMetrics records the count and latency of every unary call by method
+func GrpcMetrics(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		startTime := time.Now()
+
+		result, err := handler(ctx, req)
+
+		m.ObserveGRPC(info.FullMethod, status.Code(err), time.Since(startTime))
+		return result, err
+	}
+}
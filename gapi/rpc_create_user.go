@@ -3,44 +3,59 @@ package gapi
 import (
 	"context"
 
-	"github.com/lib/pq"
-	db "github.com/techschool/simplebank/db/sqlc"
 	"github.com/techschool/simplebank/pb"
-	"github.com/techschool/simplebank/util"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 )
 
 func (server Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	hashedPassword, err := util.HashPassword(req.GetPassword())
+	if violations := validateCreateUserRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	ctx = server.extractMetadata(ctx).withAuditClient(ctx)
 
+	userRecord, err := server.service.CreateUser(ctx, service.CreateUserParams{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		FullName: req.GetFullName(),
+		Email:    req.GetEmail(),
+		Phone:    req.GetPhone(),
+	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error: Could not hash password")
+		return nil, serviceError(ctx, err)
 	}
 
-	args := db.CreateUserParams{
-		Username:       req.GetUsername(),
-		HashedPassword: hashedPassword,
-		FullName:       req.GetFullName(),
-		Email:          req.GetEmail(),
+	response := &pb.CreateUserResponse{
+		User: convertUser(userRecord),
 	}
 
-	userRecord, err := server.store.CreateUser(ctx, args)
+	return response, nil
+}
 
-	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "unique_violation":
-				return nil, status.Errorf(codes.AlreadyExists, "error: Username already exists, %s", err)
-			}
-		}
+func validateCreateUserRequest(req *pb.CreateUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
 
-		return nil, status.Errorf(codes.InvalidArgument, "error: Could not create user, %s", err)
+	if err := val.ValidatePassword(req.GetPassword()); err != nil {
+		violations = append(violations, fieldViolation("password", err))
 	}
 
-	response := &pb.CreateUserResponse{
-		User: convertUser(userRecord),
+	if err := val.ValidateFullName(req.GetFullName()); err != nil {
+		violations = append(violations, fieldViolation("full_name", err))
 	}
 
-	return response, nil
+	if err := val.ValidateEmail(req.GetEmail()); err != nil {
+		violations = append(violations, fieldViolation("email", err))
+	}
+
+	if req.GetPhone() != "" {
+		if err := val.ValidatePhone(req.GetPhone()); err != nil {
+			violations = append(violations, fieldViolation("phone", err))
+		}
+	}
+
+	return violations
 }
@@ -2,69 +2,52 @@ package gapi
 
 import (
 	"context"
-	"database/sql"
-	"time"
 
-	db "github.com/techschool/simplebank/db/sqlc"
 	"github.com/techschool/simplebank/pb"
-	"github.com/techschool/simplebank/util"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (server Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
-	user, err := server.store.GetUser(ctx, req.Username)
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Errorf(codes.NotFound, "error: User NOT found, %s", err)
-		}
-
-		return nil, status.Errorf(codes.InvalidArgument, "error: Could not search user, %s", err)
-	}
-
-	err = util.CheckPassword(req.Password, user.HashedPassword)
-
-	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "error: User with provided credentials does not exist, %s", err)
+	if violations := validateLoginUserRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
 	}
 
-	token, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	mtdata := server.extractMetadata(ctx)
+	ctx = mtdata.withAuditClient(ctx)
 
+	result, err := server.service.LoginUser(ctx, service.LoginUserParams{
+		Username:  req.GetUsername(),
+		Password:  req.GetPassword(),
+		UserAgent: mtdata.userAgent,
+		ClientIP:  mtdata.clientIp,
+	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error: Failed to create access token, %s", err)
+		return nil, serviceError(ctx, err)
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
-
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error: Failed to create refresh token, %s", err)
+	response := &pb.LoginUserResponse{
+		SessionId:             result.SessionID.String(),
+		AccessToken:           result.AccessToken,
+		AccessTokenExpiresAt:  timestamppb.New(result.AccessTokenExpiresAt),
+		RefreshToken:          result.RefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(result.RefreshTokenExpiresAt),
+		User:                  convertUser(result.User),
 	}
 
-	mtdata := server.extractMetadata(ctx)
-
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		Username:     user.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    mtdata.userAgent,
-		ClientIp:     mtdata.clientIp,
-		ExpiresAt:    time.Now().Add(server.config.RefreshTokenDuration),
-	})
+	return response, nil
+}
 
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error: Failed to create session, %s", err)
+func validateLoginUserRequest(req *pb.LoginUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateExistingUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
 	}
 
-	response := &pb.LoginUserResponse{
-		SessionId:             session.ID.String(),
-		AccessToken:           token,
-		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt.Time),
-		RefreshToken:          refreshToken,
-		RefreshTokenExpiresAt: timestamppb.New(refreshPayload.ExpiredAt.Time),
-		User:                  convertUser(user),
+	if err := val.ValidatePassword(req.GetPassword()); err != nil {
+		violations = append(violations, fieldViolation("password", err))
 	}
 
-	return response, nil
+	return violations
 }
@@ -0,0 +1,17 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/pb"
+)
+
+func TestValidateLoginUserRequest(t *testing.T) {
+	require.Nil(t, validateLoginUserRequest(&pb.LoginUserRequest{Username: "alice_1", Password: "secret"}))
+	// usernames taken before the signup rule may have uppercase letters
+	require.Nil(t, validateLoginUserRequest(&pb.LoginUserRequest{Username: "Alice", Password: "secret"}))
+
+	require.Len(t, validateLoginUserRequest(&pb.LoginUserRequest{Username: "alice-1", Password: "secret"}), 1)
+	require.Len(t, validateLoginUserRequest(&pb.LoginUserRequest{Password: "123"}), 2)
+}
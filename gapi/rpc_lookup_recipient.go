@@ -0,0 +1,43 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/techschool/simplebank/pb"
+	"github.com/techschool/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server Server) LookupRecipient(ctx context.Context, req *pb.LookupRecipientRequest) (*pb.LookupRecipientResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateLookupRecipientRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	recipient, err := server.service.LookupRecipient(ctx, payload.Username, req.GetAlias(), req.GetCurrency())
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	return &pb.LookupRecipientResponse{
+		Alias:      recipient.Alias,
+		Currency:   recipient.Currency,
+		MaskedName: recipient.MaskedName,
+	}, nil
+}
+
+func validateLookupRecipientRequest(req *pb.LookupRecipientRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAlias(req.GetAlias()); err != nil {
+		violations = append(violations, fieldViolation("alias", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,165 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/techschool/simplebank/pb"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server Server) CreatePayee(ctx context.Context, req *pb.CreatePayeeRequest) (*pb.CreatePayeeResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCreatePayeeRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	ctx = server.extractMetadata(ctx).withAuditClient(ctx)
+
+	payee, err := server.service.CreatePayee(ctx, service.CreatePayeeParams{
+		Owner:         payload.Username,
+		Nickname:      req.GetNickname(),
+		AccountNumber: req.GetAccountNumber(),
+		Username:      req.GetUsername(),
+		Currency:      req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	return &pb.CreatePayeeResponse{Payee: convertPayee(payee)}, nil
+}
+
+func validateCreatePayeeRequest(req *pb.CreatePayeeRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateNickname(req.GetNickname()); err != nil {
+		violations = append(violations, fieldViolation("nickname", err))
+	}
+
+	switch {
+	case req.GetAccountNumber() != "" && req.GetUsername() != "":
+		violations = append(violations, fieldViolation("account_number", errors.New("must not be set together with username")))
+	case req.GetAccountNumber() != "":
+		if err := val.ValidateAccountNumber(req.GetAccountNumber()); err != nil {
+			violations = append(violations, fieldViolation("account_number", err))
+		}
+	default:
+		if err := val.ValidateExistingUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolation("username", err))
+		}
+		if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+			violations = append(violations, fieldViolation("currency", err))
+		}
+	}
+
+	return violations
+}
+
+func (server Server) GetPayee(ctx context.Context, req *pb.GetPayeeRequest) (*pb.GetPayeeResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payee, err := server.service.GetPayee(ctx, payload.Username, req.GetId())
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	return &pb.GetPayeeResponse{Payee: convertPayee(payee)}, nil
+}
+
+func (server Server) ListPayees(ctx context.Context, req *pb.ListPayeesRequest) (*pb.ListPayeesResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateListPayeesRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	payees, err := server.service.ListPayees(ctx, service.ListPayeesParams{
+		Owner:    payload.Username,
+		PageID:   req.GetPageId(),
+		PageSize: req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	response := &pb.ListPayeesResponse{Payees: make([]*pb.Payee, len(payees))}
+	for i, payee := range payees {
+		response.Payees[i] = convertPayee(payee)
+	}
+
+	return response, nil
+}
+
+func validateListPayeesRequest(req *pb.ListPayeesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", errors.New("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 1 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", errors.New("must be from 1-10")))
+	}
+
+	return violations
+}
+
+func (server Server) UpdatePayee(ctx context.Context, req *pb.UpdatePayeeRequest) (*pb.UpdatePayeeResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := val.ValidateNickname(req.GetNickname()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("nickname", err)})
+	}
+
+	ctx = server.extractMetadata(ctx).withAuditClient(ctx)
+
+	payee, err := server.service.RenamePayee(ctx, payload.Username, req.GetId(), req.GetNickname())
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	return &pb.UpdatePayeeResponse{Payee: convertPayee(payee)}, nil
+}
+
+func (server Server) ConfirmPayee(ctx context.Context, req *pb.ConfirmPayeeRequest) (*pb.ConfirmPayeeResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = server.extractMetadata(ctx).withAuditClient(ctx)
+
+	payee, err := server.service.ConfirmPayee(ctx, payload.Username, req.GetId(), req.GetPassword())
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	return &pb.ConfirmPayeeResponse{Payee: convertPayee(payee)}, nil
+}
+
+func (server Server) DeletePayee(ctx context.Context, req *pb.DeletePayeeRequest) (*pb.DeletePayeeResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = server.extractMetadata(ctx).withAuditClient(ctx)
+
+	if err := server.service.DeletePayee(ctx, payload.Username, req.GetId()); err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	return &pb.DeletePayeeResponse{}, nil
+}
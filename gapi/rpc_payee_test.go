@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/pb"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestListPayees(t *testing.T) {
+	username := util.RandomOwner()
+	payees := []db.Payee{
+		{ID: 1, Owner: username, Nickname: "Rent", AccountID: 7, AccountNumber: "SB12000000000007", Currency: util.EUR, ActiveFrom: time.Now().Add(-time.Hour)},
+		{ID: 2, Owner: username, Nickname: "Gym", AccountID: 8, AccountNumber: "SB82000000000008", Currency: util.EUR, ActiveFrom: time.Now().Add(time.Hour)},
+	}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	server, err := NewServer(store, util.Config{
+		TokenSymmetricalKey: util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+	}, nil)
+	require.NoError(t, err)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(username, time.Minute)
+	require.NoError(t, err)
+
+	withToken := func(value string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, value))
+	}
+	request := &pb.ListPayeesRequest{PageId: 1, PageSize: 5}
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		withToken(accessToken),
+		withToken("basic " + accessToken),
+		withToken("bearer invalid"),
+	} {
+		_, err := server.ListPayees(ctx, request)
+		require.Equal(t, codes.Unauthenticated, status.Code(err), fmt.Sprint(err))
+	}
+
+	_, err = server.ListPayees(withToken("bearer "+accessToken), &pb.ListPayeesRequest{PageId: 1, PageSize: 50})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	store.EXPECT().
+		ListPayees(gomock.Any(), gomock.Eq(db.ListPayeesParams{Owner: username, Limit: 5, Offset: 0})).
+		Times(1).
+		Return(payees, nil)
+
+	response, err := server.ListPayees(withToken("bearer "+accessToken), request)
+	require.NoError(t, err)
+	require.Len(t, response.GetPayees(), 2)
+	require.True(t, response.GetPayees()[0].GetActive())
+	require.False(t, response.GetPayees()[1].GetActive())
+	require.Equal(t, "SB82000000000008", response.GetPayees()[1].GetAccountNumber())
+}
+
+func TestValidateCreatePayeeRequest(t *testing.T) {
+	require.Nil(t, validateCreatePayeeRequest(&pb.CreatePayeeRequest{Nickname: "Rent", AccountNumber: util.RandomAccountNumber()}))
+	require.Nil(t, validateCreatePayeeRequest(&pb.CreatePayeeRequest{Nickname: "Rent", Username: "alice", Currency: util.EUR}))
+
+	require.Len(t, validateCreatePayeeRequest(&pb.CreatePayeeRequest{Nickname: "Rent", AccountNumber: util.RandomAccountNumber(), Username: "alice"}), 1)
+	require.Len(t, validateCreatePayeeRequest(&pb.CreatePayeeRequest{Nickname: "Rent", Username: "alice"}), 1)
+	require.Len(t, validateCreatePayeeRequest(&pb.CreatePayeeRequest{AccountNumber: "SB21000000000007"}), 2)
+}
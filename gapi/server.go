@@ -5,7 +5,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/metrics"
 	"github.com/techschool/simplebank/pb"
+	"github.com/techschool/simplebank/service"
 	"github.com/techschool/simplebank/token"
 	"github.com/techschool/simplebank/util"
 )
@@ -13,21 +15,26 @@ import (
 type Server struct {
 	pb.UnimplementedSimpleBankServer
 	config     util.Config
-	store      db.Store
+	service    *service.Service
 	router     *gin.Engine
 	tokenMaker token.Maker
 }
 
-func NewServer(store db.Store, config util.Config) (*Server, error) {
+func NewServer(store db.Store, config util.Config, metrics *metrics.Metrics) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricalKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker %v", err)
 	}
 
+	service, err := service.New(store, tokenMaker, config, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create service: %w", err)
+	}
+
 	server := &Server{
 		config:     config,
-		store:      store,
+		service:    service,
 		router:     gin.Default(),
 		tokenMaker: tokenMaker,
 	}
@@ -0,0 +1,48 @@
+// Package interest accrues interest on the accounts of interest-bearing
+// products every day and posts it to them once a month through the general
+// ledger. Daily accruals are kept exact in fractions of the minor unit, only
+// the monthly posting rounds down and carries the remainder to the next month.
+package interest
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Scale is the number of accrual units in one minor unit of currency. An
+// annual rate in basis points on a balance for one day of an actual/365 year
+// is a whole number of accrual units, so daily accruals lose nothing.
+const Scale = 10_000 * 365
+
+// Accrue returns the interest earned in one day by balance at an annual rate
+// in basis points, in accrual units. Negative balances earn nothing.
+func Accrue(balance int64, annualRateBps int32) (int64, error) {
+	if balance <= 0 || annualRateBps <= 0 {
+		return 0, nil
+	}
+
+	if balance > math.MaxInt64/int64(annualRateBps) {
+		return 0, fmt.Errorf("interest on balance %d at %d bps overflows", balance, annualRateBps)
+	}
+
+	return balance * int64(annualRateBps), nil
+}
+
+// Split divides accrual units into whole minor units to post and the
+// remainder to carry into the next period
+func Split(accrued int64) (amount int64, carried int64) {
+	return accrued / Scale, accrued % Scale
+}
+
+// Day returns the start of the UTC calendar day of t
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Month returns the start of the UTC calendar month of t
+func Month(t time.Time) time.Time {
+	year, month, _ := t.UTC().Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
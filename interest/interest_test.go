@@ -0,0 +1,212 @@
+package interest
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/ledger"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccrueWithoutRoundingLoss(t *testing.T) {
+	testCases := []struct {
+		name    string
+		balance int64
+		rate    int32
+		amount  int64
+		carried int64
+	}{
+		{name: "WholeUnits", balance: 100_000, rate: 200, amount: 2_000},
+		{name: "Fraction", balance: 1_234, rate: 150, amount: 18, carried: 1_861_500},
+		{name: "BelowOneUnit", balance: 1, rate: 200, carried: 200 * 365},
+		{name: "Negative", balance: -500, rate: 200},
+		{name: "ZeroRate", balance: 500, rate: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var total int64
+			for range 365 {
+				accrued, err := Accrue(tc.balance, tc.rate)
+				require.NoError(t, err)
+				total += accrued
+			}
+
+			amount, carried := Split(total)
+			require.Equal(t, tc.amount, amount)
+			require.Equal(t, tc.carried, carried)
+		})
+	}
+}
+
+func TestAccrueOverflow(t *testing.T) {
+	_, err := Accrue(math.MaxInt64/100, 200)
+	require.Error(t, err)
+}
+
+func newTestStore(t *testing.T) *mockdb.MockStore {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, _ db.TxOptions, fn func(db.Querier) error) error {
+			return fn(store)
+		})
+
+	return store
+}
+
+func TestAccrueDay(t *testing.T) {
+	store := newTestStore(t)
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	store.EXPECT().
+		ListAccrualCandidates(gomock.Any(), gomock.Eq(db.ListAccrualCandidatesParams{
+			AccrualDate: day,
+			DayEnd:      day.AddDate(0, 0, 1),
+		})).
+		Times(1).
+		Return([]db.ListAccrualCandidatesRow{
+			{ID: 7, Currency: "USD", AnnualRateBps: 200, Balance: 1_000},
+			{ID: 9, Currency: "EUR", AnnualRateBps: 150, Balance: -20},
+		}, nil)
+	store.EXPECT().
+		CreateInterestAccrual(gomock.Any(), gomock.Eq(db.CreateInterestAccrualParams{
+			AccountID:     7,
+			AccrualDate:   day,
+			Balance:       1_000,
+			AnnualRateBps: 200,
+			Accrued:       200_000,
+		})).
+		Times(1).
+		Return(int64(1), nil)
+	// a concurrent run got there first
+	store.EXPECT().
+		CreateInterestAccrual(gomock.Any(), gomock.Eq(db.CreateInterestAccrualParams{
+			AccountID:     9,
+			AccrualDate:   day,
+			Balance:       -20,
+			AnnualRateBps: 150,
+		})).
+		Times(1).
+		Return(int64(0), nil)
+
+	accrued, err := NewJob(store, Config{}).AccrueDay(context.Background(), day.Add(15*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, accrued)
+}
+
+func TestPostMonths(t *testing.T) {
+	store := newTestStore(t)
+	period := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	accruals := make([]db.InterestAccrual, 31)
+	dates := make([]time.Time, 31)
+	for i := range accruals {
+		dates[i] = period.AddDate(0, 0, i)
+		accruals[i] = db.InterestAccrual{AccountID: 7, AccrualDate: dates[i], Balance: 1_000, AnnualRateBps: 200, Accrued: 200_000}
+	}
+
+	store.EXPECT().
+		ListUnpostedInterest(gomock.Any(), gomock.Eq(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))).
+		Times(1).
+		Return([]db.ListUnpostedInterestRow{
+			{AccountID: 7, AccountNumber: "SB12000000000007", Currency: "USD", Period: period, Accrued: 31 * 200_000},
+		}, nil)
+	store.EXPECT().
+		ListUnpostedInterestAccrualsForUpdate(gomock.Any(), gomock.Eq(db.ListUnpostedInterestAccrualsForUpdateParams{
+			AccountID: 7,
+			Period:    period,
+		})).
+		Times(1).
+		Return(accruals, nil)
+	store.EXPECT().
+		GetLastInterestPosting(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(db.InterestPosting{ID: 1, AccountID: 7, Carried: 3_600_000}, nil)
+
+	// 31 * 200_000 + 3_600_000 = 9_800_000 accrual units, 2 minor units and
+	// 2_500_000 carried into April
+	expense := db.LedgerAccount{ID: 50, Code: ledger.CodeInterestExpense, Type: ledger.TypeExpense, Currency: "USD"}
+	customer := db.LedgerAccount{ID: 70, Code: ledger.CodeCustomerDeposits, Currency: "USD", AccountID: sql.NullInt64{Int64: 7, Valid: true}}
+	store.EXPECT().CreateSystemLedgerAccount(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+	store.EXPECT().GetSystemLedgerAccount(gomock.Any(), gomock.Any()).Times(1).Return(expense, nil)
+	store.EXPECT().CreateCustomerLedgerAccount(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(nil)
+	store.EXPECT().GetCustomerLedgerAccount(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(customer, nil)
+	store.EXPECT().GetLedgerAccount(gomock.Any(), gomock.Eq(expense.ID)).Times(1).Return(expense, nil)
+	store.EXPECT().GetLedgerAccount(gomock.Any(), gomock.Eq(customer.ID)).Times(1).Return(customer, nil)
+	store.EXPECT().
+		CreateJournalEntry(gomock.Any(), gomock.Eq(db.CreateJournalEntryParams{
+			Kind:        db.JournalKindInterest,
+			Description: "Interest for March 2024 on account SB12000000000007",
+		})).
+		Times(1).
+		Return(db.JournalEntry{ID: 11, Kind: db.JournalKindInterest}, nil)
+	store.EXPECT().
+		CreatePosting(gomock.Any(), gomock.Eq(db.CreatePostingParams{JournalEntryID: 11, LedgerAccountID: expense.ID, Amount: 2, Currency: "USD"})).
+		Times(1)
+	store.EXPECT().
+		CreatePosting(gomock.Any(), gomock.Eq(db.CreatePostingParams{JournalEntryID: 11, LedgerAccountID: customer.ID, Amount: -2, Currency: "USD"})).
+		Times(1)
+	store.EXPECT().CreateEntry(gomock.Any(), gomock.Eq(db.CreateEntryParams{AccountID: 7, Amount: 2})).Times(1)
+	store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Eq(db.AddAccountBalanceParams{ID: 7, Amount: 2})).Times(1)
+
+	store.EXPECT().
+		CreateInterestPosting(gomock.Any(), gomock.Eq(db.CreateInterestPostingParams{
+			AccountID:      7,
+			Period:         period,
+			Accrued:        31 * 200_000,
+			Amount:         2,
+			Carried:        2_500_000,
+			JournalEntryID: sql.NullInt64{Int64: 11, Valid: true},
+		})).
+		Times(1).
+		Return(db.InterestPosting{ID: 2, AccountID: 7, Period: period, Amount: 2, Carried: 2_500_000}, nil)
+	store.EXPECT().
+		MarkInterestAccrualsPosted(gomock.Any(), gomock.Eq(db.MarkInterestAccrualsPostedParams{
+			PostingID:    sql.NullInt64{Int64: 2, Valid: true},
+			AccountID:    7,
+			AccrualDates: dates,
+		})).
+		Times(1).
+		Return(nil)
+
+	store.EXPECT().LockAuditChain(gomock.Any()).Times(1).Return(nil)
+	store.EXPECT().GetLastAuditEvent(gomock.Any()).Times(1).Return(db.AuditEvent{}, sql.ErrNoRows)
+	store.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Times(1)
+
+	postings, err := NewJob(store, Config{}).PostMonths(context.Background(), time.Date(2024, 4, 17, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, postings, 1)
+	require.Equal(t, int64(2), postings[0].Amount)
+}
+
+func TestPostMonthsAlreadyPosted(t *testing.T) {
+	store := newTestStore(t)
+	period := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	store.EXPECT().
+		ListUnpostedInterest(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.ListUnpostedInterestRow{
+			{AccountID: 7, Currency: "USD", Period: period, Accrued: 200_000},
+		}, nil)
+	// a concurrent run posted the accruals before the lock was taken
+	store.EXPECT().
+		ListUnpostedInterestAccrualsForUpdate(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.InterestAccrual{}, nil)
+	store.EXPECT().CreateJournalEntry(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().CreateInterestPosting(gomock.Any(), gomock.Any()).Times(0)
+
+	postings, err := NewJob(store, Config{}).PostMonths(context.Background(), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Empty(t, postings)
+}
@@ -0,0 +1,280 @@
+package interest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/ledger"
+)
+
+// Actor is recorded in the audit log for interest postings
+const Actor = "interest"
+
+// Config controls how often the job runs and how far back it catches up
+type Config struct {
+	Interval time.Duration
+	// Lookback is how many complete days before today are accrued on every
+	// run, so that days missed while the job was down are caught up
+	Lookback int
+}
+
+// DefaultConfig is used for zero fields of the config given to NewJob
+var DefaultConfig = Config{
+	Interval: time.Hour,
+	Lookback: 7,
+}
+
+// Job accrues and posts interest for the accounts of a store. Every step is
+// idempotent, so the job can run as often as wanted and be re-run safely.
+type Job struct {
+	store  db.Store
+	config Config
+	now    func() time.Time
+}
+
+// NewJob creates an interest job for store
+func NewJob(store db.Store, config Config) *Job {
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig.Interval
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = DefaultConfig.Lookback
+	}
+
+	return &Job{
+		store:  store,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run accrues and posts interest every Interval until ctx is cancelled
+func (job *Job) Run(ctx context.Context) error {
+	ticker := time.NewTicker(job.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.RunOnce(ctx); err != nil {
+			slog.ErrorContext(ctx, "cannot process interest", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce accrues interest for the last Lookback complete days and posts
+// every month that ended before today
+func (job *Job) RunOnce(ctx context.Context) error {
+	today := Day(job.now())
+
+	for days := job.config.Lookback; days >= 1; days-- {
+		if _, err := job.AccrueDay(ctx, today.AddDate(0, 0, -days)); err != nil {
+			return err
+		}
+	}
+
+	_, err := job.PostMonths(ctx, Month(today))
+	return err
+}
+
+// AccrueDay records one day of interest for every account earning interest
+// on day, based on its balance at the end of that day. Accounts that already
+// accrued day are skipped. It returns how many accruals were recorded.
+func (job *Job) AccrueDay(ctx context.Context, day time.Time) (int, error) {
+	day = Day(day)
+
+	var accrued int
+	err := job.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		accrued = 0
+
+		candidates, err := q.ListAccrualCandidates(ctx, db.ListAccrualCandidatesParams{
+			AccrualDate: day,
+			DayEnd:      day.AddDate(0, 0, 1),
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list accounts accruing interest: %w", err)
+		}
+
+		for _, candidate := range candidates {
+			amount, err := Accrue(candidate.Balance, candidate.AnnualRateBps)
+			if err != nil {
+				return fmt.Errorf("cannot accrue interest of account %d: %w", candidate.ID, err)
+			}
+
+			rows, err := q.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+				AccountID:     candidate.ID,
+				AccrualDate:   day,
+				Balance:       candidate.Balance,
+				AnnualRateBps: candidate.AnnualRateBps,
+				Accrued:       amount,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot record interest accrual of account %d: %w", candidate.ID, err)
+			}
+			accrued += int(rows)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot accrue interest for %s: %w", day.Format(time.DateOnly), err)
+	}
+
+	return accrued, nil
+}
+
+// PostMonths posts the interest accrued in every month before the one
+// starting at before. Each account and month is posted in its own
+// transaction. It returns the postings made.
+func (job *Job) PostMonths(ctx context.Context, before time.Time) ([]db.InterestPosting, error) {
+	unposted, err := job.store.ListUnpostedInterest(ctx, Month(before))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list unposted interest: %w", err)
+	}
+
+	postings := make([]db.InterestPosting, 0, len(unposted))
+	for _, row := range unposted {
+		posting, ok, err := job.post(ctx, row.AccountID, row.AccountNumber, row.Currency, row.Period)
+		if err != nil {
+			return postings, err
+		}
+		if ok {
+			postings = append(postings, posting)
+		}
+	}
+
+	return postings, nil
+}
+
+// post credits the account with the whole minor units of its accruals for
+// the month of period plus whatever the previous posting carried, debiting
+// interest expense. The accruals are locked and summed again so that a
+// concurrent run cannot post them twice. It reports false if there was
+// nothing left to post.
+func (job *Job) post(ctx context.Context, accountID int64, accountNumber string, currency string, period time.Time) (db.InterestPosting, bool, error) {
+	var posting db.InterestPosting
+	var posted bool
+
+	err := job.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		posted = false
+
+		accruals, err := q.ListUnpostedInterestAccrualsForUpdate(ctx, db.ListUnpostedInterestAccrualsForUpdateParams{
+			AccountID: accountID,
+			Period:    period,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot lock interest accruals: %w", err)
+		}
+		if len(accruals) == 0 {
+			return nil
+		}
+
+		var accrued int64
+		dates := make([]time.Time, len(accruals))
+		for i, accrual := range accruals {
+			accrued += accrual.Accrued
+			dates[i] = accrual.AccrualDate
+		}
+
+		var carriedIn int64
+		last, err := q.GetLastInterestPosting(ctx, accountID)
+		switch {
+		case err == nil:
+			carriedIn = last.Carried
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("cannot get last interest posting: %w", err)
+		}
+
+		amount, carried := Split(accrued + carriedIn)
+
+		var journalEntryID sql.NullInt64
+		if amount > 0 {
+			journalEntry, err := job.postJournalEntry(ctx, q, accountID, accountNumber, currency, period, amount)
+			if err != nil {
+				return err
+			}
+			journalEntryID = sql.NullInt64{Int64: journalEntry.ID, Valid: true}
+		}
+
+		posting, err = q.CreateInterestPosting(ctx, db.CreateInterestPostingParams{
+			AccountID:      accountID,
+			Period:         period,
+			Accrued:        accrued,
+			Amount:         amount,
+			Carried:        carried,
+			JournalEntryID: journalEntryID,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot create interest posting: %w", err)
+		}
+
+		err = q.MarkInterestAccrualsPosted(ctx, db.MarkInterestAccrualsPostedParams{
+			PostingID:    sql.NullInt64{Int64: posting.ID, Valid: true},
+			AccountID:    accountID,
+			AccrualDates: dates,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot mark interest accruals posted: %w", err)
+		}
+
+		_, err = audit.Record(ctx, q, audit.Event{
+			Actor:        Actor,
+			Action:       audit.ActionInterestPosted,
+			ResourceType: audit.ResourceAccount,
+			ResourceID:   strconv.FormatInt(accountID, 10),
+			Diff:         posting,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot record audit event: %w", err)
+		}
+
+		posted = true
+		return nil
+	})
+	if err != nil {
+		return db.InterestPosting{}, false, fmt.Errorf("cannot post interest of account %d for %s: %w",
+			accountID, period.Format("2006-01"), err)
+	}
+
+	return posting, posted, nil
+}
+
+// postJournalEntry debits interest expense and credits the ledger account
+// of the customer account with amount. The description names the account by
+// its public number.
+func (job *Job) postJournalEntry(ctx context.Context, q db.Querier, accountID int64, accountNumber string, currency string, period time.Time, amount int64) (db.JournalEntry, error) {
+	expense, err := ledger.SystemAccount(ctx, q, ledger.CodeInterestExpense, currency)
+	if err != nil {
+		return db.JournalEntry{}, err
+	}
+
+	customer, err := db.CustomerLedgerAccount(ctx, q, accountID)
+	if err != nil {
+		return db.JournalEntry{}, err
+	}
+
+	result, err := db.PostJournalEntry(ctx, q, db.PostJournalEntryParams{
+		Kind:        db.JournalKindInterest,
+		Description: fmt.Sprintf("Interest for %s on account %s", period.Format("January 2006"), accountNumber),
+		Postings: []db.PostingParams{
+			{LedgerAccountID: expense.ID, Amount: amount, Currency: currency},
+			{LedgerAccountID: customer.ID, Amount: -amount, Currency: currency},
+		},
+	})
+	if err != nil {
+		return db.JournalEntry{}, fmt.Errorf("cannot post interest journal entry: %w", err)
+	}
+
+	return result.JournalEntry, nil
+}
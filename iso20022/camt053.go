@@ -0,0 +1,241 @@
+package iso20022
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/statement"
+)
+
+// Camt053Namespace is the camt.053 version written by Camt053Writer
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+// FormatCamt053 selects the camt.053 statement format
+const FormatCamt053 = "camt053"
+
+// Camt053ContentType is the media type of a camt.053 statement
+const Camt053ContentType = "application/xml; charset=utf-8"
+
+// Balance type codes of camt.053
+const (
+	balanceOpeningBooked = "OPBD"
+	balanceClosingBooked = "CLBD"
+)
+
+// Camt053Writer renders a statement as a camt.053 bank to customer statement.
+// camt.053 states the closing balance before the entries, so the header must
+// carry it. Like the OFX writer, elements are written by hand to stream the
+// entries.
+type Camt053Writer struct {
+	w      *bufio.Writer
+	header statement.Header
+	depth  int
+}
+
+// NewCamt053Writer creates a writer rendering camt.053 to w
+func NewCamt053Writer(w io.Writer) *Camt053Writer {
+	return &Camt053Writer{w: bufio.NewWriter(w)}
+}
+
+// WriteHeader writes the group header, the account and both balances
+func (writer *Camt053Writer) WriteHeader(header statement.Header) error {
+	writer.header = header
+	statementID := fmt.Sprintf("STMT-%s-%s-%s", header.AccountNumber,
+		header.From.UTC().Format("20060102"), lastDay(header).Format("20060102"))
+
+	writer.w.WriteString(xml.Header)
+	writer.open(fmt.Sprintf("Document xmlns=%q", Camt053Namespace))
+	writer.open("BkToCstmrStmt")
+
+	writer.open("GrpHdr")
+	writer.element("MsgId", fmt.Sprintf("%s-%d", statementID, header.GeneratedAt.Unix()))
+	writer.element("CreDtTm", formatDateTime(header.GeneratedAt))
+	writer.close("GrpHdr")
+
+	writer.open("Stmt")
+	writer.element("Id", statementID)
+	writer.element("CreDtTm", formatDateTime(header.GeneratedAt))
+	writer.open("FrToDt")
+	writer.element("FrDtTm", formatDateTime(header.From))
+	writer.element("ToDtTm", formatDateTime(header.To))
+	writer.close("FrToDt")
+
+	writer.open("Acct")
+	writer.accountID(header.AccountNumber)
+	writer.element("Ccy", header.Currency)
+	writer.open("Ownr")
+	writer.element("Nm", header.Owner)
+	writer.close("Ownr")
+	writer.close("Acct")
+
+	writer.balance(balanceOpeningBooked, header.OpeningBalance, header.From)
+	writer.balance(balanceClosingBooked, header.ClosingBalance, lastDay(header))
+	return writer.err()
+}
+
+// WriteLine writes one booked entry
+func (writer *Camt053Writer) WriteLine(line statement.Line) error {
+	amount, indicator := splitAmount(line.Amount)
+	transactionCode := "TRF"
+	if line.TransferID == 0 {
+		transactionCode = "ADJ"
+	}
+
+	writer.open("Ntry")
+	writer.element("NtryRef", strconv.FormatInt(line.EntryID, 10))
+	writer.amount(amount)
+	writer.element("CdtDbtInd", indicator)
+	writer.open("Sts")
+	writer.element("Cd", "BOOK")
+	writer.close("Sts")
+	writer.open("BookgDt")
+	writer.element("DtTm", formatDateTime(line.Time))
+	writer.close("BookgDt")
+	writer.open("ValDt")
+	writer.element("DtTm", formatDateTime(line.Time))
+	writer.close("ValDt")
+	writer.element("AcctSvcrRef", strconv.FormatInt(line.EntryID, 10))
+	writer.open("BkTxCd")
+	writer.open("Prtry")
+	writer.element("Cd", transactionCode)
+	writer.close("Prtry")
+	writer.close("BkTxCd")
+
+	if line.TransferID != 0 {
+		writer.open("NtryDtls")
+		writer.open("TxDtls")
+		writer.open("Refs")
+		writer.element("TxId", strconv.FormatInt(line.TransferID, 10))
+		writer.close("Refs")
+		writer.relatedParty(line)
+		writer.open("RmtInf")
+		writer.element("Ustrd", line.Description())
+		writer.close("RmtInf")
+		writer.close("TxDtls")
+		writer.close("NtryDtls")
+	}
+
+	writer.element("AddtlNtryInf", line.Description())
+	writer.close("Ntry")
+	return writer.err()
+}
+
+// WriteSummary closes the document. The balances were written with the header.
+func (writer *Camt053Writer) WriteSummary(summary statement.Summary) error {
+	if summary.ClosingBalance != writer.header.ClosingBalance {
+		return fmt.Errorf("closing balance %d does not match the %d stated in the header",
+			summary.ClosingBalance, writer.header.ClosingBalance)
+	}
+
+	writer.close("Stmt")
+	writer.close("BkToCstmrStmt")
+	writer.close("Document")
+	return writer.w.Flush()
+}
+
+// relatedParty names the other side of a transfer, the debtor of a credit or
+// the creditor of a debit
+func (writer *Camt053Writer) relatedParty(line statement.Line) {
+	party, account := "Dbtr", "DbtrAcct"
+	if line.Amount < 0 {
+		party, account = "Cdtr", "CdtrAcct"
+	}
+
+	writer.open("RltdPties")
+	if line.CounterpartyOwner != "" {
+		writer.open(party)
+		writer.open("Pty")
+		writer.element("Nm", line.CounterpartyOwner)
+		writer.close("Pty")
+		writer.close(party)
+	}
+	if line.CounterpartyAccount != "" {
+		writer.open(account)
+		writer.accountID(line.CounterpartyAccount)
+		writer.close(account)
+	}
+	writer.close("RltdPties")
+}
+
+func (writer *Camt053Writer) balance(code string, value int64, date time.Time) {
+	amount, indicator := splitAmount(value)
+
+	writer.open("Bal")
+	writer.open("Tp")
+	writer.open("CdOrPrtry")
+	writer.element("Cd", code)
+	writer.close("CdOrPrtry")
+	writer.close("Tp")
+	writer.amount(amount)
+	writer.element("CdtDbtInd", indicator)
+	writer.open("Dt")
+	writer.element("Dt", date.UTC().Format(time.DateOnly))
+	writer.close("Dt")
+	writer.close("Bal")
+}
+
+func (writer *Camt053Writer) accountID(number string) {
+	writer.open("Id")
+	writer.open("Othr")
+	writer.element("Id", number)
+	writer.close("Othr")
+	writer.close("Id")
+}
+
+func (writer *Camt053Writer) amount(value int64) {
+	writer.indent()
+	fmt.Fprintf(writer.w, "<Amt Ccy=%q>%s</Amt>\n", writer.header.Currency, writer.header.FormatAmount(value))
+}
+
+func (writer *Camt053Writer) open(tag string) {
+	writer.indent()
+	fmt.Fprintf(writer.w, "<%s>\n", tag)
+	writer.depth++
+}
+
+func (writer *Camt053Writer) close(name string) {
+	writer.depth--
+	writer.indent()
+	fmt.Fprintf(writer.w, "</%s>\n", name)
+}
+
+func (writer *Camt053Writer) element(name string, value string) {
+	writer.indent()
+	fmt.Fprintf(writer.w, "<%s>", name)
+	xml.EscapeText(writer.w, []byte(value))
+	fmt.Fprintf(writer.w, "</%s>\n", name)
+}
+
+func (writer *Camt053Writer) indent() {
+	for i := 0; i < writer.depth; i++ {
+		writer.w.WriteString("  ")
+	}
+}
+
+// err reports the first write error, which bufio.Writer keeps until flushed
+func (writer *Camt053Writer) err() error {
+	_, err := writer.w.Write(nil)
+	return err
+}
+
+// splitAmount returns the magnitude of value and its credit or debit indicator
+func splitAmount(value int64) (int64, string) {
+	if value < 0 {
+		return -value, "DBIT"
+	}
+
+	return value, "CRDT"
+}
+
+// lastDay is the last day covered by a statement whose To is exclusive
+func lastDay(header statement.Header) time.Time {
+	return header.To.UTC().Add(-time.Nanosecond)
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
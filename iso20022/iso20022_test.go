@@ -0,0 +1,185 @@
+package iso20022
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/statement"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// requireGolden compares actual with testdata/name, or rewrites the file
+// when the tests run with -update
+func requireGolden(t *testing.T, name string, actual []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+func TestParsePain001(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "pain001.xml"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	document, payments, err := ParsePain001(file)
+	require.NoError(t, err)
+	require.Equal(t, "ACME-20240315-001", document.MessageID())
+	require.Equal(t, "1250.00", document.ControlSum())
+
+	data, err := json.MarshalIndent(payments, "", "  ")
+	require.NoError(t, err)
+	requireGolden(t, "pain001.golden.json", append(data, '\n'))
+}
+
+func TestParsePain001MinorUnits(t *testing.T) {
+	valid, err := os.ReadFile(filepath.Join("testdata", "pain001.xml"))
+	require.NoError(t, err)
+
+	document := strings.NewReplacer(
+		`<InstdAmt Ccy="USD">1000.00</InstdAmt>`, `<InstdAmt Ccy="USD">1000.50</InstdAmt>`,
+		"<CtrlSum>1250.00</CtrlSum>", "<CtrlSum>1250.50</CtrlSum>",
+	).Replace(string(valid))
+
+	_, payments, err := ParsePain001(strings.NewReader(document))
+	require.NoError(t, err)
+	require.Equal(t, int64(100050), payments[0].Amount)
+	require.Equal(t, int64(20000), payments[1].Amount)
+}
+
+func TestParsePain001Invalid(t *testing.T) {
+	valid, err := os.ReadFile(filepath.Join("testdata", "pain001.xml"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		old     string
+		new     string
+		message string
+	}{
+		{
+			name:    "Namespace",
+			old:     "pain.001.001.09",
+			new:     "pain.001.001.03",
+			message: "unsupported namespace",
+		},
+		{
+			name:    "NumberOfTxs",
+			old:     "<NbOfTxs>3</NbOfTxs>",
+			new:     "<NbOfTxs>4</NbOfTxs>",
+			message: "NbOfTxs",
+		},
+		{
+			name:    "ControlSum",
+			old:     "<CtrlSum>1250.00</CtrlSum>",
+			new:     "<CtrlSum>1250.50</CtrlSum>",
+			message: "CtrlSum",
+		},
+		{
+			name:    "ControlSumOverflow",
+			old:     `<InstdAmt Ccy="USD">200</InstdAmt>`,
+			new:     `<InstdAmt Ccy="USD">92233720368547758.07</InstdAmt>`,
+			message: "too large",
+		},
+		{
+			name:    "TooManyDecimals",
+			old:     `<InstdAmt Ccy="USD">200</InstdAmt>`,
+			new:     `<InstdAmt Ccy="USD">200.255</InstdAmt>`,
+			message: "decimal places",
+		},
+		{
+			name:    "NegativeAmount",
+			old:     `<InstdAmt Ccy="EUR">50</InstdAmt>`,
+			new:     `<InstdAmt Ccy="EUR">-50</InstdAmt>`,
+			message: "must be positive",
+		},
+		{
+			name:    "DuplicateEndToEndID",
+			old:     "<EndToEndId>E2E-0003</EndToEndId>",
+			new:     "<EndToEndId>E2E-0001</EndToEndId>",
+			message: "used more than once",
+		},
+		{
+			name:    "AccountNumber",
+			old:     "<Id>SB71000000000012</Id>",
+			new:     "<Id>SB17000000000012</Id>",
+			message: "is not an account number",
+		},
+		{
+			name:    "PaymentMethod",
+			old:     "<PmtMtd>TRF</PmtMtd>",
+			new:     "<PmtMtd>CHK</PmtMtd>",
+			message: "unsupported payment method",
+		},
+		{
+			name:    "Malformed",
+			old:     "</Document>",
+			new:     "",
+			message: "cannot decode",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			document := strings.Replace(string(valid), tc.old, tc.new, 1)
+			require.NotEqual(t, string(valid), document)
+
+			_, _, err := ParsePain001(strings.NewReader(document))
+			require.ErrorContains(t, err, tc.message)
+		})
+	}
+}
+
+func TestCamt053Writer(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	header := statement.Header{
+		AccountNumber:  "SB12000000000007",
+		Owner:          "alice",
+		Currency:       "USD",
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 100,
+		ClosingBalance: 125,
+		GeneratedAt:    from.AddDate(0, 1, 1),
+	}
+	lines := []statement.Line{
+		{EntryID: 1, Time: from.Add(time.Hour), Amount: 50, Balance: 150, TransferID: 3, CounterpartyAccount: "SB55000000000009", CounterpartyOwner: "bob"},
+		{EntryID: 2, Time: from.Add(2 * time.Hour), Amount: -30, Balance: 120, TransferID: 4, CounterpartyAccount: "SB98000000000011", CounterpartyOwner: "carol & co"},
+		{EntryID: 5, Time: from.Add(3 * time.Hour), Amount: 5, Balance: 125},
+	}
+
+	var buf bytes.Buffer
+	writer := NewCamt053Writer(&buf)
+	require.NoError(t, writer.WriteHeader(header))
+
+	summary := statement.Summary{ClosingBalance: header.OpeningBalance}
+	for _, line := range lines {
+		summary.Add(line)
+		require.NoError(t, writer.WriteLine(line))
+	}
+	require.NoError(t, writer.WriteSummary(summary))
+
+	requireGolden(t, "camt053.golden.xml", buf.Bytes())
+}
+
+func TestCamt053WriterClosingBalanceMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewCamt053Writer(&buf)
+	require.NoError(t, writer.WriteHeader(statement.Header{Currency: "USD", OpeningBalance: 10, ClosingBalance: 10}))
+	require.NoError(t, writer.WriteLine(statement.Line{EntryID: 1, Amount: 5, Balance: 15}))
+	require.ErrorContains(t, writer.WriteSummary(statement.Summary{ClosingBalance: 15}), "does not match")
+}
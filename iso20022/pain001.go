@@ -0,0 +1,268 @@
+// Package iso20022 reads and writes the ISO 20022 messages exchanged with
+// corporate clients: pain.001 customer credit transfer initiations and
+// camt.053 bank to customer statements.
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/techschool/simplebank/accountnumber"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/util"
+)
+
+// Pain001Namespace is the pain.001 version accepted by ParsePain001
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// Pain001 is a customer credit transfer initiation
+type Pain001 struct {
+	XMLName    xml.Name          `xml:"Document"`
+	Initiation pain001Initiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiation struct {
+	GroupHeader  pain001GroupHeader `xml:"GrpHdr"`
+	PaymentInfos []pain001PmtInf    `xml:"PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MessageID        string    `xml:"MsgId"`
+	CreationDateTime string    `xml:"CreDtTm"`
+	NumberOfTxs      string    `xml:"NbOfTxs"`
+	ControlSum       string    `xml:"CtrlSum"`
+	InitiatingParty  partyName `xml:"InitgPty"`
+}
+
+type pain001PmtInf struct {
+	PaymentInfoID   string          `xml:"PmtInfId"`
+	PaymentMethod   string          `xml:"PmtMtd"`
+	Debtor          partyName       `xml:"Dbtr"`
+	DebtorAccount   cashAccount     `xml:"DbtrAcct"`
+	CreditTransfers []pain001CdtTrf `xml:"CdtTrfTxInf"`
+}
+
+type pain001CdtTrf struct {
+	PaymentID       paymentID   `xml:"PmtId"`
+	Amount          pain001Amt  `xml:"Amt"`
+	Creditor        partyName   `xml:"Cdtr"`
+	CreditorAccount cashAccount `xml:"CdtrAcct"`
+	Remittance      remittance  `xml:"RmtInf"`
+}
+
+type paymentID struct {
+	InstructionID string `xml:"InstrId"`
+	EndToEndID    string `xml:"EndToEndId"`
+}
+
+type pain001Amt struct {
+	InstructedAmount activeAmount `xml:"InstdAmt"`
+}
+
+type activeAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type partyName struct {
+	Name string `xml:"Nm"`
+}
+
+type cashAccount struct {
+	ID       accountID `xml:"Id"`
+	Currency string    `xml:"Ccy"`
+}
+
+type accountID struct {
+	Other struct {
+		ID string `xml:"Id"`
+	} `xml:"Othr"`
+}
+
+type remittance struct {
+	Unstructured string `xml:"Ustrd"`
+}
+
+// Payment is one credit transfer of a pain.001 message in the terms of this
+// bank, with Amount in minor units of Currency
+type Payment struct {
+	PaymentInfoID   string `json:"payment_info_id"`
+	InstructionID   string `json:"instruction_id,omitempty"`
+	EndToEndID      string `json:"end_to_end_id"`
+	DebtorAccount   string `json:"debtor_account"`
+	CreditorAccount string `json:"creditor_account"`
+	CreditorName    string `json:"creditor_name,omitempty"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+	Remittance      string `json:"remittance,omitempty"`
+}
+
+// ParsePain001 decodes a pain.001.001.09 document and checks that it is
+// internally consistent. Accounts are identified by their account number in
+// Othr/Id.
+func ParsePain001(r io.Reader) (*Pain001, []Payment, error) {
+	var document Pain001
+	decoder := xml.NewDecoder(r)
+	if err := decoder.Decode(&document); err != nil {
+		return nil, nil, fmt.Errorf("cannot decode pain.001: %w", err)
+	}
+
+	if document.XMLName.Space != Pain001Namespace {
+		return nil, nil, fmt.Errorf("unsupported namespace %q, expected %q", document.XMLName.Space, Pain001Namespace)
+	}
+
+	payments, err := document.payments()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &document, payments, nil
+}
+
+// MessageID returns the unique id the client gave the message
+func (document *Pain001) MessageID() string {
+	return document.Initiation.GroupHeader.MessageID
+}
+
+// ControlSum returns GrpHdr/CtrlSum as the client sent it, or "" if the
+// message has none. It adds up amounts of every currency, so it is only
+// meaningful as a decimal number and never as an amount of money.
+func (document *Pain001) ControlSum() string {
+	return strings.TrimSpace(document.Initiation.GroupHeader.ControlSum)
+}
+
+func (document *Pain001) payments() ([]Payment, error) {
+	header := document.Initiation.GroupHeader
+	if header.MessageID == "" {
+		return nil, errors.New("GrpHdr/MsgId is required")
+	}
+
+	var payments []Payment
+	endToEndIDs := make(map[string]bool)
+	for _, info := range document.Initiation.PaymentInfos {
+		if info.PaymentMethod != "TRF" {
+			return nil, fmt.Errorf("PmtInf %q: unsupported payment method %q", info.PaymentInfoID, info.PaymentMethod)
+		}
+
+		debtorAccount, err := parseAccountNumber(info.DebtorAccount)
+		if err != nil {
+			return nil, fmt.Errorf("PmtInf %q: DbtrAcct: %w", info.PaymentInfoID, err)
+		}
+
+		for _, transfer := range info.CreditTransfers {
+			payment, err := newPayment(info, debtorAccount, transfer)
+			if err != nil {
+				return nil, fmt.Errorf("PmtInf %q, EndToEndId %q: %w", info.PaymentInfoID, transfer.PaymentID.EndToEndID, err)
+			}
+
+			if endToEndIDs[payment.EndToEndID] {
+				return nil, fmt.Errorf("EndToEndId %q is used more than once", payment.EndToEndID)
+			}
+			endToEndIDs[payment.EndToEndID] = true
+
+			payments = append(payments, payment)
+		}
+	}
+
+	if len(payments) == 0 {
+		return nil, errors.New("message contains no credit transfers")
+	}
+
+	if n, err := strconv.Atoi(header.NumberOfTxs); err != nil || n != len(payments) {
+		return nil, fmt.Errorf("GrpHdr/NbOfTxs is %q but the message has %d transactions", header.NumberOfTxs, len(payments))
+	}
+
+	if header.ControlSum != "" {
+		if err := checkControlSum(header.ControlSum, payments); err != nil {
+			return nil, err
+		}
+	}
+
+	return payments, nil
+}
+
+// checkControlSum compares CtrlSum, the plain sum of all instructed amounts
+// whatever their currency, with the payments. Amounts are brought to the
+// largest exponent among their currencies, so that the sum stays exact in
+// minor units. A sum that does not fit in int64 minor units is refused.
+func checkControlSum(value string, payments []Payment) error {
+	widest, _ := util.LookupCurrency(payments[0].Currency)
+	for _, payment := range payments {
+		if c, _ := util.LookupCurrency(payment.Currency); c.Exponent > widest.Exponent {
+			widest = c
+		}
+	}
+
+	var sum int64
+	for _, payment := range payments {
+		c, _ := util.LookupCurrency(payment.Currency)
+		amount := payment.Amount
+		for i := c.Exponent; i < widest.Exponent; i++ {
+			if amount > math.MaxInt64/10 {
+				return errors.New("GrpHdr/CtrlSum: the amounts are too large to add up")
+			}
+			amount *= 10
+		}
+		if amount > math.MaxInt64-sum {
+			return errors.New("GrpHdr/CtrlSum: the amounts are too large to add up")
+		}
+		sum += amount
+	}
+
+	controlSum, err := money.Parse(strings.TrimSpace(value), widest.Code)
+	if err != nil || controlSum.Amount != sum {
+		return fmt.Errorf("GrpHdr/CtrlSum is %q but the amounts add up to %s", value, widest.Format(sum))
+	}
+
+	return nil
+}
+
+func newPayment(info pain001PmtInf, debtorAccount string, transfer pain001CdtTrf) (Payment, error) {
+	if transfer.PaymentID.EndToEndID == "" {
+		return Payment{}, errors.New("PmtId/EndToEndId is required")
+	}
+
+	creditorAccount, err := parseAccountNumber(transfer.CreditorAccount)
+	if err != nil {
+		return Payment{}, fmt.Errorf("CdtrAcct: %w", err)
+	}
+
+	currency := transfer.Amount.InstructedAmount.Currency
+	if currency == "" {
+		return Payment{}, errors.New("InstdAmt/@Ccy is required")
+	}
+
+	amount, err := money.Parse(strings.TrimSpace(transfer.Amount.InstructedAmount.Value), currency)
+	if err != nil {
+		return Payment{}, fmt.Errorf("InstdAmt: %w", err)
+	}
+	if !amount.IsPositive() {
+		return Payment{}, errors.New("InstdAmt must be positive")
+	}
+
+	return Payment{
+		PaymentInfoID:   info.PaymentInfoID,
+		InstructionID:   transfer.PaymentID.InstructionID,
+		EndToEndID:      transfer.PaymentID.EndToEndID,
+		DebtorAccount:   debtorAccount,
+		CreditorAccount: creditorAccount,
+		CreditorName:    transfer.Creditor.Name,
+		Amount:          amount.Amount,
+		Currency:        currency,
+		Remittance:      transfer.Remittance.Unstructured,
+	}, nil
+}
+
+func parseAccountNumber(account cashAccount) (string, error) {
+	number := accountnumber.Normalize(account.ID.Other.ID)
+	if err := accountnumber.Validate(number); err != nil {
+		return "", fmt.Errorf("Id/Othr/Id %q is not an account number", account.ID.Other.ID)
+	}
+
+	return number, nil
+}
@@ -0,0 +1,47 @@
+// Package kyc defines the know-your-customer tiers of users. A tier decides
+// which currencies a user may hold and which transfer limits apply, and is
+// raised once a banker approves the identity details the user submitted.
+package kyc
+
+import "slices"
+
+// Tiers from the least to the most verified
+const (
+	TierUnverified = "unverified"
+	TierBasic      = "basic"
+	TierFull       = "full"
+)
+
+// Tiers lists every tier, lowest first
+var Tiers = []string{TierUnverified, TierBasic, TierFull}
+
+// Statuses of a submission of identity details
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Identity documents a user may submit
+const (
+	DocumentPassport      = "passport"
+	DocumentNationalID    = "national_id"
+	DocumentDriverLicense = "driver_license"
+)
+
+// Rank returns the position of tier in Tiers, or -1 if it is not a tier
+func Rank(tier string) int {
+	return slices.Index(Tiers, tier)
+}
+
+// Valid reports whether tier is a known tier
+func Valid(tier string) bool {
+	return Rank(tier) >= 0
+}
+
+// AtLeast reports whether tier grants everything required does. Unknown
+// tiers neither grant nor are granted anything.
+func AtLeast(tier string, required string) bool {
+	rank, requiredRank := Rank(tier), Rank(required)
+	return rank >= 0 && requiredRank >= 0 && rank >= requiredRank
+}
@@ -0,0 +1,17 @@
+package kyc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtLeast(t *testing.T) {
+	require.True(t, AtLeast(TierFull, TierBasic))
+	require.True(t, AtLeast(TierBasic, TierBasic))
+	require.True(t, AtLeast(TierUnverified, TierUnverified))
+	require.False(t, AtLeast(TierUnverified, TierBasic))
+	require.False(t, AtLeast(TierBasic, TierFull))
+	require.False(t, AtLeast("gold", TierUnverified))
+	require.False(t, AtLeast(TierFull, "gold"))
+}
@@ -0,0 +1,93 @@
+// Package ledger holds the chart of accounts of the general ledger and the
+// reports built on its postings. Posting itself lives with the store, see
+// db.PostJournalEntry.
+package ledger
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Types of ledger accounts
+const (
+	TypeAsset     = "asset"
+	TypeLiability = "liability"
+	TypeEquity    = "equity"
+	TypeIncome    = "income"
+	TypeExpense   = "expense"
+)
+
+// Codes of the chart of accounts
+const (
+	CodeCash             = "1000"
+	CodeCustomerDeposits = "2000"
+	CodeEquity           = "3000"
+	CodeFeeIncome        = "4000"
+	CodeFXIncome         = "4100"
+	CodeInterestExpense  = "5000"
+)
+
+// ChartAccount is an account of the chart. System ledger accounts exist once
+// per code and currency, customer accounts all share CodeCustomerDeposits.
+type ChartAccount struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Chart lists every account of the general ledger in code order
+var Chart = []ChartAccount{
+	{Code: CodeCash, Name: "Cash", Type: TypeAsset},
+	{Code: CodeCustomerDeposits, Name: "Customer deposits", Type: TypeLiability},
+	{Code: CodeEquity, Name: "Owner's equity", Type: TypeEquity},
+	{Code: CodeFeeIncome, Name: "Fee income", Type: TypeIncome},
+	{Code: CodeFXIncome, Name: "Foreign exchange income", Type: TypeIncome},
+	{Code: CodeInterestExpense, Name: "Interest expense", Type: TypeExpense},
+}
+
+// LookupChartAccount returns the chart account with code
+func LookupChartAccount(code string) (ChartAccount, bool) {
+	for _, account := range Chart {
+		if account.Code == code {
+			return account, true
+		}
+	}
+
+	return ChartAccount{}, false
+}
+
+// DebitNormal reports whether accounts of type grow with debits
+func DebitNormal(accountType string) bool {
+	return accountType == TypeAsset || accountType == TypeExpense
+}
+
+// SystemAccount returns the bank-owned ledger account of code in currency,
+// creating it on first use
+func SystemAccount(ctx context.Context, q db.Querier, code string, currency string) (db.LedgerAccount, error) {
+	account, ok := LookupChartAccount(code)
+	if !ok || code == CodeCustomerDeposits {
+		return db.LedgerAccount{}, fmt.Errorf("%q is not a system account of the chart", code)
+	}
+
+	err := q.CreateSystemLedgerAccount(ctx, db.CreateSystemLedgerAccountParams{
+		Code:     account.Code,
+		Name:     account.Name,
+		Type:     account.Type,
+		Currency: currency,
+	})
+	if err != nil {
+		return db.LedgerAccount{}, fmt.Errorf("cannot create ledger account %s %s: %w", code, currency, err)
+	}
+
+	ledgerAccount, err := q.GetSystemLedgerAccount(ctx, db.GetSystemLedgerAccountParams{
+		Code:     code,
+		Currency: currency,
+	})
+	if err != nil {
+		return db.LedgerAccount{}, fmt.Errorf("cannot get ledger account %s %s: %w", code, currency, err)
+	}
+
+	return ledgerAccount, nil
+}
@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"go.uber.org/mock/gomock"
+)
+
+// sampleRows is a bank holding 1000 USD of customer money in cash, which
+// paid 20 of interest and earned 50 of fees, plus an unbalanced EUR ledger
+func sampleRows() []db.GetTrialBalanceRow {
+	return []db.GetTrialBalanceRow{
+		{Code: CodeCash, Type: TypeAsset, Currency: "EUR", Debits: 10},
+		{Code: CodeCustomerDeposits, Type: TypeLiability, Currency: "EUR", Credits: 9},
+		{Code: CodeCash, Type: TypeAsset, Currency: "USD", Debits: 1000},
+		{Code: CodeCustomerDeposits, Type: TypeLiability, Currency: "USD", Debits: 400, Credits: 1370},
+		{Code: CodeFeeIncome, Type: TypeIncome, Currency: "USD", Credits: 50},
+		{Code: CodeInterestExpense, Type: TypeExpense, Currency: "USD", Debits: 20},
+	}
+}
+
+func TestTrialBalance(t *testing.T) {
+	asOf := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetTrialBalance(gomock.Any(), gomock.Eq(asOf)).Times(1).Return(sampleRows(), nil)
+
+	trialBalance, err := GetTrialBalance(context.Background(), store, asOf)
+	require.NoError(t, err)
+	require.Len(t, trialBalance.Lines, 6)
+
+	deposits := trialBalance.Lines[3]
+	require.Equal(t, "Customer deposits", deposits.Name)
+	require.Equal(t, int64(970), deposits.Balance)
+	require.Equal(t, int64(20), trialBalance.Lines[5].Balance)
+
+	require.Equal(t, []TrialBalanceTotal{
+		{Currency: "EUR", Debits: 10, Credits: 9, Balanced: false},
+		{Currency: "USD", Debits: 1420, Credits: 1420, Balanced: true},
+	}, trialBalance.Totals)
+}
+
+func TestBalanceSheet(t *testing.T) {
+	balanceSheet := NewBalanceSheet(newTrialBalance(time.Now(), sampleRows()))
+
+	require.Equal(t, []BalanceSheetCurrency{
+		{Currency: "EUR", Assets: 10, Liabilities: 9, Balanced: false},
+		{Currency: "USD", Assets: 1000, Liabilities: 970, NetIncome: 30, Balanced: true},
+	}, balanceSheet.Currencies)
+}
+
+func TestSystemAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		CreateSystemLedgerAccount(gomock.Any(), gomock.Eq(db.CreateSystemLedgerAccountParams{
+			Code:     CodeFeeIncome,
+			Name:     "Fee income",
+			Type:     TypeIncome,
+			Currency: "USD",
+		})).
+		Times(1).
+		Return(nil)
+	store.EXPECT().
+		GetSystemLedgerAccount(gomock.Any(), gomock.Eq(db.GetSystemLedgerAccountParams{Code: CodeFeeIncome, Currency: "USD"})).
+		Times(1).
+		Return(db.LedgerAccount{ID: 4, Code: CodeFeeIncome, Currency: "USD"}, nil)
+
+	account, err := SystemAccount(context.Background(), store, CodeFeeIncome, "USD")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), account.ID)
+
+	// customer deposits are kept per customer account
+	_, err = SystemAccount(context.Background(), store, CodeCustomerDeposits, "USD")
+	require.Error(t, err)
+
+	_, err = SystemAccount(context.Background(), store, "9999", "USD")
+	require.Error(t, err)
+}
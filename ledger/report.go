@@ -0,0 +1,145 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// TrialBalance lists the debits and credits of every chart account per
+// currency up to AsOf
+type TrialBalance struct {
+	AsOf   time.Time           `json:"as_of"`
+	Lines  []TrialBalanceLine  `json:"lines"`
+	Totals []TrialBalanceTotal `json:"totals"`
+}
+
+// TrialBalanceLine is one chart account in one currency
+type TrialBalanceLine struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+	Debits   int64  `json:"debits"`
+	Credits  int64  `json:"credits"`
+	// Balance is signed towards the normal side of the account type
+	Balance int64 `json:"balance"`
+}
+
+// TrialBalanceTotal adds the lines of a currency up. Debits and credits
+// must be equal.
+type TrialBalanceTotal struct {
+	Currency string `json:"currency"`
+	Debits   int64  `json:"debits"`
+	Credits  int64  `json:"credits"`
+	Balanced bool   `json:"balanced"`
+}
+
+// GetTrialBalance builds the trial balance from the postings made before asOf
+func GetTrialBalance(ctx context.Context, q db.Querier, asOf time.Time) (TrialBalance, error) {
+	rows, err := q.GetTrialBalance(ctx, asOf)
+	if err != nil {
+		return TrialBalance{}, fmt.Errorf("cannot get trial balance: %w", err)
+	}
+
+	return newTrialBalance(asOf, rows), nil
+}
+
+func newTrialBalance(asOf time.Time, rows []db.GetTrialBalanceRow) TrialBalance {
+	trialBalance := TrialBalance{
+		AsOf:   asOf,
+		Lines:  make([]TrialBalanceLine, 0, len(rows)),
+		Totals: []TrialBalanceTotal{},
+	}
+
+	for _, row := range rows {
+		line := TrialBalanceLine{
+			Code:     row.Code,
+			Name:     row.Code,
+			Type:     row.Type,
+			Currency: row.Currency,
+			Debits:   row.Debits,
+			Credits:  row.Credits,
+			Balance:  row.Credits - row.Debits,
+		}
+		if account, ok := LookupChartAccount(row.Code); ok {
+			line.Name = account.Name
+		}
+		if DebitNormal(row.Type) {
+			line.Balance = -line.Balance
+		}
+		trialBalance.Lines = append(trialBalance.Lines, line)
+
+		// rows come ordered by currency
+		last := len(trialBalance.Totals) - 1
+		if last < 0 || trialBalance.Totals[last].Currency != row.Currency {
+			trialBalance.Totals = append(trialBalance.Totals, TrialBalanceTotal{Currency: row.Currency})
+			last++
+		}
+		trialBalance.Totals[last].Debits += row.Debits
+		trialBalance.Totals[last].Credits += row.Credits
+	}
+
+	for i := range trialBalance.Totals {
+		trialBalance.Totals[i].Balanced = trialBalance.Totals[i].Debits == trialBalance.Totals[i].Credits
+	}
+
+	return trialBalance
+}
+
+// BalanceSheet states the position of the bank per currency at AsOf
+type BalanceSheet struct {
+	AsOf       time.Time              `json:"as_of"`
+	Currencies []BalanceSheetCurrency `json:"currencies"`
+}
+
+// BalanceSheetCurrency is the balance sheet of one currency. Net income is
+// not closed into equity yet, so assets equal liabilities plus equity plus
+// net income.
+type BalanceSheetCurrency struct {
+	Currency    string `json:"currency"`
+	Assets      int64  `json:"assets"`
+	Liabilities int64  `json:"liabilities"`
+	Equity      int64  `json:"equity"`
+	NetIncome   int64  `json:"net_income"`
+	Balanced    bool   `json:"balanced"`
+}
+
+// NewBalanceSheet rolls a trial balance up by account type
+func NewBalanceSheet(trialBalance TrialBalance) BalanceSheet {
+	balanceSheet := BalanceSheet{
+		AsOf:       trialBalance.AsOf,
+		Currencies: make([]BalanceSheetCurrency, 0, len(trialBalance.Totals)),
+	}
+
+	for _, line := range trialBalance.Lines {
+		last := len(balanceSheet.Currencies) - 1
+		if last < 0 || balanceSheet.Currencies[last].Currency != line.Currency {
+			balanceSheet.Currencies = append(balanceSheet.Currencies, BalanceSheetCurrency{Currency: line.Currency})
+			last++
+		}
+
+		currency := &balanceSheet.Currencies[last]
+		switch line.Type {
+		case TypeAsset:
+			currency.Assets += line.Balance
+		case TypeLiability:
+			currency.Liabilities += line.Balance
+		case TypeEquity:
+			currency.Equity += line.Balance
+		case TypeIncome:
+			currency.NetIncome += line.Balance
+		case TypeExpense:
+			currency.NetIncome -= line.Balance
+		}
+	}
+
+	for i := range balanceSheet.Currencies {
+		currency := &balanceSheet.Currencies[i]
+		currency.Balanced = currency.Assets == currency.Liabilities+currency.Equity+currency.NetIncome
+	}
+
+	return balanceSheet
+}
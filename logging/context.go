@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the HTTP header and gRPC metadata key carrying the request id
+const RequestIDHeader = "x-request-id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying requestID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request id stored in ctx, or an empty string
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewRequestID generates a random request id
+func NewRequestID() string {
+	return uuid.NewString()
+}
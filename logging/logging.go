@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New creates a structured logger writing to w with the given level and
+// format. Sensitive attributes are redacted before they are written.
+func New(w io.Writer, level string, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       lvl,
+		ReplaceAttr: redactAttr,
+	}
+
+	switch strings.ToLower(format) {
+	case FormatJSON, "":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	}
+
+	return nil, fmt.Errorf("invalid log format %q", format)
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedactsSensitiveAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	require.NoError(t, err)
+
+	logger.Info("login", "username", "alice", "password", "secret123", "refresh_token", "abc")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "alice", entry["username"])
+	require.Equal(t, redacted, entry["password"])
+	require.Equal(t, redacted, entry["refresh_token"])
+}
+
+func TestNewLevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", FormatText)
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	require.Empty(t, buf.String())
+
+	logger.Warn("shown")
+	require.Contains(t, buf.String(), "msg=shown")
+
+	_, err = New(&buf, "loud", FormatJSON)
+	require.Error(t, err)
+
+	_, err = New(&buf, "info", "xml")
+	require.Error(t, err)
+}
+
+func TestRedact(t *testing.T) {
+	values := map[string][]string{
+		"authorization": {"Bearer abc"},
+		"user-agent":    {"curl"},
+	}
+
+	clean := Redact(values)
+	require.Equal(t, []string{redacted}, clean["authorization"])
+	require.Equal(t, []string{"curl"}, clean["user-agent"])
+	require.Equal(t, []string{"Bearer abc"}, values["authorization"])
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute, header and metadata names whose values must never be logged
+var sensitiveKeys = []string{
+	"authorization",
+	"password",
+	"token",
+	"secret",
+	"cookie",
+}
+
+// IsSensitive reports whether values stored under key must be redacted
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact returns a copy of values with the sensitive keys masked
+func Redact(values map[string][]string) map[string][]string {
+	clean := make(map[string][]string, len(values))
+	for key, value := range values {
+		if IsSensitive(key) {
+			clean[key] = []string{redacted}
+			continue
+		}
+		clean[key] = value
+	}
+
+	return clean
+}
+
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) && attr.Value.Kind() != slog.KindGroup {
+		return slog.String(attr.Key, redacted)
+	}
+
+	return attr
+}
@@ -0,0 +1,22 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceAttrs returns the trace and span ids of the span in ctx, if any,
+// so log lines can be joined with their trace.
+func TraceAttrs(ctx context.Context) []slog.Attr {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	return []slog.Attr{
+		slog.String("trace_id", spanContext.TraceID().String()),
+		slog.String("span_id", spanContext.SpanID().String()),
+	}
+}
@@ -3,9 +3,14 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
+	"os"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -13,10 +18,23 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	_ "github.com/lib/pq"
 	"github.com/techschool/simplebank/api"
+	"github.com/techschool/simplebank/crypt"
 	db "github.com/techschool/simplebank/db/sqlc"
 	"github.com/techschool/simplebank/gapi"
+	"github.com/techschool/simplebank/interest"
+	"github.com/techschool/simplebank/logging"
+	"github.com/techschool/simplebank/metrics"
+	"github.com/techschool/simplebank/outbox"
 	"github.com/techschool/simplebank/pb"
+	"github.com/techschool/simplebank/reconcile"
+	"github.com/techschool/simplebank/rekey"
+	"github.com/techschool/simplebank/sanctions"
+	"github.com/techschool/simplebank/service"
+	"github.com/techschool/simplebank/tracing"
 	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/webhook"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -29,17 +47,56 @@ func main() {
 		log.Fatal("Could not load config", err)
 	}
 
+	logger, err := logging.New(os.Stdout, config.LogLevel, config.LogFormat)
+	if err != nil {
+		log.Fatal("Could not create logger", err)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), config.TracingExporter, config.OTLPEndpoint)
+	if err != nil {
+		log.Fatal("Could not set up tracing", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	conn, err := sql.Open(config.DBDriver, config.DBSource)
 	if err != nil {
 		log.Fatal("cannot connect to db:", err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		code := runReconcileCommand(db.NewStore(conn), os.Args[2:])
+		shutdownTracing(context.Background())
+		os.Exit(code)
+	}
+
 	runDbMigrations(config.MigrationsURL, config.DBSource)
 
+	appMetrics := metrics.NewDefault()
+	appMetrics.RegisterDB(conn, "simple_bank")
+
 	store := db.NewStore(conn)
-	// go runGRPCGatewayServer(config, store)
-	runGinServer(config, store)
-	runGRPCServer(config, store)
+	envelope, err := loadPIIEnvelope(config)
+	if err != nil {
+		log.Fatalln("Could not load PII encryption keys", err)
+	}
+	if envelope != nil {
+		store.SetEnvelope(envelope)
+	}
+	if err := service.LoadCurrencies(context.Background(), store); err != nil {
+		log.Fatalln("Could not load currencies", err)
+	}
+
+	runOutboxRelay(config, store)
+	runWebhookDispatcher(store)
+	runReconciler(config, store)
+	runInterestJob(config, store)
+	runSanctionsJob(config, store)
+	runRekeyJob(config, store, envelope)
+	runMetricsServer(config, appMetrics)
+	// go runGRPCGatewayServer(config, store, appMetrics)
+	runGinServer(config, store, appMetrics)
+	runGRPCServer(config, store, appMetrics)
 }
 
 func runDbMigrations(migrationUrl string, databaseUrl string) {
@@ -56,8 +113,168 @@ func runDbMigrations(migrationUrl string, databaseUrl string) {
 	log.Println("Migration run sucessfully")
 }
 
-func runGinServer(config util.Config, store db.Store) {
-	server, err := api.NewServer(store, config)
+func runOutboxRelay(config util.Config, store db.Store) {
+	publisher, err := outbox.NewPublisher(config.OutboxPublisher, config.OutboxTarget)
+	if err != nil {
+		log.Fatalln("Could not create outbox publisher", err)
+	}
+	if publisher == nil {
+		return
+	}
+
+	relay := outbox.NewRelay(store, publisher, outbox.RelayConfig{
+		PollInterval: config.OutboxPollInterval,
+	})
+	go relay.Run(context.Background())
+}
+
+func runWebhookDispatcher(store db.Store) {
+	dispatcher := webhook.NewDispatcher(store, webhook.DefaultDispatcherConfig)
+	go dispatcher.Run(context.Background())
+}
+
+// runReconciler checks the ledger in the background. It only reports drift,
+// repairs are left to the reconcile command.
+func runReconciler(config util.Config, store db.Store) {
+	if config.ReconcileInterval <= 0 {
+		return
+	}
+
+	reconciler := reconcile.NewReconciler(store, reconcile.Config{
+		Interval: config.ReconcileInterval,
+	})
+	go reconciler.Run(context.Background())
+}
+
+// runInterestJob accrues interest daily and posts it monthly in the background
+func runInterestJob(config util.Config, store db.Store) {
+	if config.InterestInterval <= 0 {
+		return
+	}
+
+	job := interest.NewJob(store, interest.Config{
+		Interval: config.InterestInterval,
+	})
+	go job.Run(context.Background())
+}
+
+// runSanctionsJob screens existing users again whenever the sanctions list
+// file changes
+func runSanctionsJob(config util.Config, store db.Store) {
+	if config.SanctionsListFile == "" || config.SanctionsInterval <= 0 {
+		return
+	}
+
+	watchlist, err := sanctions.OpenWatchlist(config.SanctionsListFile)
+	if err != nil {
+		log.Fatalln("Could not load sanctions list", err)
+	}
+
+	screener, err := sanctions.NewScreener(watchlist, sanctions.Config{
+		Threshold: config.SanctionsThreshold,
+		Action:    config.SanctionsAction,
+	})
+	if err != nil {
+		log.Fatalln("Could not create sanctions screener", err)
+	}
+
+	job := sanctions.NewJob(store, screener, sanctions.JobConfig{
+		Interval: config.SanctionsInterval,
+	})
+	go job.Run(context.Background())
+}
+
+// loadPIIEnvelope builds the envelope that encrypts personal data from the
+// master keys of config.PIIKeyFile, or config.PIIEncryptionKey if no file
+// is set. The first key is active. It returns nil if no keys are set.
+func loadPIIEnvelope(config util.Config) (*crypt.Envelope, error) {
+	var keys [][]byte
+	var err error
+	switch {
+	case config.PIIKeyFile != "":
+		keys, err = crypt.LoadKeyFile(config.PIIKeyFile)
+	case config.PIIEncryptionKey != "":
+		keys, err = crypt.ParseKeys(config.PIIEncryptionKey)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := crypt.NewKeyring(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	indexKey, err := crypt.ParseKey(config.PIIIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blind index key: %w", err)
+	}
+
+	return crypt.NewEnvelope(keyring, indexKey)
+}
+
+// runRekeyJob encrypts users still stored in plaintext and moves records
+// onto the active master key in the background
+func runRekeyJob(config util.Config, store db.Store, envelope *crypt.Envelope) {
+	if envelope == nil || config.PIIRekeyInterval <= 0 {
+		return
+	}
+
+	job := rekey.NewJob(store, envelope, rekey.JobConfig{
+		Interval: config.PIIRekeyInterval,
+	})
+	go job.Run(context.Background())
+}
+
+// runReconcileCommand reconciles the ledger once, prints the report and
+// returns a non-zero exit code if anything is left unresolved
+func runReconcileCommand(store db.Store, args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "lock drifting accounts and reset their balance to the sum of their entries")
+	flags.Parse(args)
+
+	reconciler := reconcile.NewReconciler(store, reconcile.Config{Repair: *repair})
+	report, err := reconciler.Reconcile(context.Background())
+	if err != nil {
+		log.Println("Could not reconcile ledger", err)
+		return 2
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Println("Could not write report", err)
+		return 2
+	}
+
+	if report.Unresolved() {
+		return 1
+	}
+
+	return 0
+}
+
+// runMetricsServer serves the Prometheus metrics on their own listener, so
+// that they stay off the public API port
+func runMetricsServer(config util.Config, appMetrics *metrics.Metrics) {
+	if config.MetricsServerAddress == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", appMetrics.Handler())
+
+	go func() {
+		if err := http.ListenAndServe(config.MetricsServerAddress, mux); err != nil {
+			log.Fatalln("Could not start metrics server", err)
+		}
+	}()
+}
+
+func runGinServer(config util.Config, store db.Store, appMetrics *metrics.Metrics) {
+	server, err := api.NewServer(store, config, appMetrics)
 
 	if err != nil {
 		log.Fatalf("Could not start server: %v", err)
@@ -71,14 +288,21 @@ func runGinServer(config util.Config, store db.Store) {
 	}
 }
 
-func runGRPCServer(config util.Config, store db.Store) {
-	server, err := gapi.NewServer(store, config)
+func runGRPCServer(config util.Config, store db.Store, appMetrics *metrics.Metrics) {
+	server, err := gapi.NewServer(store, config, appMetrics)
 
 	if err != nil {
 		log.Fatalln("Could not create gRPC server", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	interceptors := grpc.ChainUnaryInterceptor(
+		gapi.GrpcLogger(slog.Default()),
+		gapi.GrpcMetrics(appMetrics),
+	)
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		interceptors,
+	)
 	pb.RegisterSimpleBankServer(grpcServer, server)
 	reflection.Register(grpcServer)
 
@@ -98,14 +322,15 @@ func runGRPCServer(config util.Config, store db.Store) {
 
 }
 
-func runGRPCGatewayServer(config util.Config, store db.Store) {
-	server, err := gapi.NewServer(store, config)
+func runGRPCGatewayServer(config util.Config, store db.Store, appMetrics *metrics.Metrics) {
+	server, err := gapi.NewServer(store, config, appMetrics)
 
 	if err != nil {
 		log.Fatalln("Could not create gRPC server", err)
 	}
 
 	grpcMux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gapi.GatewayHeaderMatcher),
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
 			MarshalOptions: protojson.MarshalOptions{
 				UseProtoNames: true,
@@ -125,7 +350,7 @@ func runGRPCGatewayServer(config util.Config, store db.Store) {
 	mux := http.NewServeMux()
 	fs := http.FileServer(http.Dir("./docs/swagger"))
 
-	mux.Handle("/", grpcMux)
+	mux.Handle("/", otelhttp.NewHandler(gapi.HttpLogger(slog.Default(), grpcMux), "grpc-gateway"))
 	mux.Handle("/swagger/", http.StripPrefix("/swagger/", fs))
 
 	listener, err := net.Listen("tcp", config.HTTPServerAddress)
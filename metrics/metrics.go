@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+const namespace = "simplebank"
+
+// Metrics holds every collector exported by the server. A nil *Metrics is
+// valid and records nothing, which keeps tests free of metric wiring.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+
+	transferTxDuration *prometheus.HistogramVec
+	transferTxRetries  prometheus.Counter
+	transfers          *prometheus.CounterVec
+	transferVolume     *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them with registry
+func New(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Number of gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of gRPC calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		transferTxDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "transfer_tx_duration_seconds",
+			Help:      "Duration of the transfer transaction by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+		transferTxRetries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "transfer_tx_retries_total",
+			Help:      "Number of times a transfer transaction was retried.",
+		}),
+		transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "transfers_total",
+			Help:      "Number of transfers by currency and outcome.",
+		}, []string{"currency", "outcome"}),
+		transferVolume: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "transfer_volume_total",
+			Help:      "Sum of successfully transferred amounts, in minor units, by currency.",
+		}, []string{"currency"}),
+	}
+
+	registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcRequests,
+		m.grpcDuration,
+		m.transferTxDuration,
+		m.transferTxRetries,
+		m.transfers,
+		m.transferVolume,
+	)
+
+	return m
+}
+
+// NewDefault creates metrics on a fresh registry that also exports the Go
+// runtime and process collectors.
+func NewDefault() *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return New(registry)
+}
+
+// RegisterDB exports the connection pool statistics of conn
+func (m *Metrics) RegisterDB(conn *sql.DB, dbName string) {
+	if m == nil {
+		return
+	}
+
+	m.registry.MustRegister(collectors.NewDBStatsCollector(conn, dbName))
+}
+
+// Handler serves the registered metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	if m == nil {
+		return http.NotFoundHandler()
+	}
+
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTP records a served HTTP request
+func (m *Metrics) ObserveHTTP(method string, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveGRPC records a served gRPC call
+func (m *Metrics) ObserveGRPC(method string, code codes.Code, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.grpcRequests.WithLabelValues(method, code.String()).Inc()
+	m.grpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveTransfer records the outcome of a transfer transaction
+func (m *Metrics) ObserveTransfer(currency string, amount int64, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+
+	m.transferTxDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+	m.transfers.WithLabelValues(currency, outcome).Inc()
+	if err == nil {
+		m.transferVolume.WithLabelValues(currency).Add(float64(amount))
+	}
+}
+
+// AddTransferRetries records retried attempts of a transfer transaction
+func (m *Metrics) AddTransferRetries(retries int) {
+	if m == nil || retries <= 0 {
+		return
+	}
+
+	m.transferTxRetries.Add(float64(retries))
+}
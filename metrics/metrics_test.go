@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestObserveTransfer(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	m.ObserveTransfer("USD", 100, time.Millisecond, nil)
+	m.ObserveTransfer("USD", 50, time.Millisecond, nil)
+	m.ObserveTransfer("EUR", 10, time.Millisecond, errors.New("boom"))
+	m.AddTransferRetries(2)
+
+	require.Equal(t, float64(150), testutil.ToFloat64(m.transferVolume.WithLabelValues("USD")))
+	require.Equal(t, float64(0), testutil.ToFloat64(m.transferVolume.WithLabelValues("EUR")))
+	require.Equal(t, float64(2), testutil.ToFloat64(m.transfers.WithLabelValues("USD", "success")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.transfers.WithLabelValues("EUR", "failure")))
+	require.Equal(t, float64(2), testutil.ToFloat64(m.transferTxRetries))
+}
+
+func TestObserveRequests(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	m.ObserveHTTP(http.MethodGet, "/accounts/:id", http.StatusOK, time.Millisecond)
+	m.ObserveGRPC("/pb.SimpleBank/LoginUser", codes.NotFound, time.Millisecond)
+
+	require.Equal(t, float64(1), testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/accounts/:id", "200")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.grpcRequests.WithLabelValues("/pb.SimpleBank/LoginUser", "NotFound")))
+}
+
+func TestHandler(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	m.ObserveTransfer("KES", 1, time.Millisecond, nil)
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `simplebank_ledger_transfer_volume_total{currency="KES"} 1`)
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+
+	require.NotPanics(t, func() {
+		m.ObserveHTTP(http.MethodGet, "/", http.StatusOK, time.Millisecond)
+		m.ObserveGRPC("/", codes.OK, time.Millisecond)
+		m.ObserveTransfer("USD", 1, time.Millisecond, nil)
+		m.AddTransferRetries(1)
+		m.RegisterDB(nil, "test")
+	})
+}
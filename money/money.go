@@ -0,0 +1,235 @@
+// Package money holds amounts together with their currency. Amounts are
+// integers in minor units, they are only ever parsed from or rendered to
+// decimal strings using the exponent of the currency in the registry, so no
+// floating point is involved at any step.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/techschool/simplebank/util"
+)
+
+var (
+	// ErrInvalidAmount is returned for strings that are not a decimal amount
+	// within the precision of the currency
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrUnknownCurrency is returned for currencies missing from the registry
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrCurrencyMismatch is returned by arithmetic on different currencies
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+	// ErrOverflow is returned when a result does not fit in 64 bits
+	ErrOverflow = errors.New("amount overflows")
+)
+
+// Money is an amount in minor units of a currency, such as 1234 USD for $12.34
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New returns amount minor units of currency
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse reads a decimal amount such as "12.34" or "-0.5" in the major unit
+// of currency. It rejects more fraction digits than the currency has, as
+// well as signs other than a leading minus, exponents and separators.
+func Parse(value string, currency string) (Money, error) {
+	c, ok := util.LookupCurrency(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+
+	digits, negative := strings.CutPrefix(value, "-")
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w %q: must be a decimal number such as 12.34", ErrInvalidAmount, value)
+	}
+	if len(fraction) > c.Exponent {
+		return Money{}, fmt.Errorf("%w %q: %s has %d decimal places", ErrInvalidAmount, value, currency, c.Exponent)
+	}
+
+	// accumulate as a negative number, which has room for math.MinInt64
+	var amount int64
+	for _, digit := range whole + fraction + strings.Repeat("0", c.Exponent-len(fraction)) {
+		d := int64(digit - '0')
+		if amount < (math.MinInt64+d)/10 {
+			return Money{}, fmt.Errorf("%w: %q", ErrOverflow, value)
+		}
+		amount = amount*10 - d
+	}
+
+	if !negative {
+		if amount == math.MinInt64 {
+			return Money{}, fmt.Errorf("%w: %q", ErrOverflow, value)
+		}
+		amount = -amount
+	}
+
+	return New(amount, currency), nil
+}
+
+// MustParse is like Parse but panics on error. It is meant for constants
+// and tests.
+func MustParse(value string, currency string) Money {
+	m, err := Parse(value, currency)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// currency returns the registry entry of the currency of m. Currencies
+// missing from the registry are treated as having no minor unit.
+func (m Money) currency() util.Currency {
+	if c, ok := util.LookupCurrency(m.Currency); ok {
+		return c
+	}
+
+	return util.Currency{Code: m.Currency, Symbol: m.Currency + " "}
+}
+
+// Decimal renders the amount in the major unit, such as 12.34
+func (m Money) Decimal() string {
+	return m.currency().Format(m.Amount)
+}
+
+// Display renders the amount with the currency symbol, such as $12.34
+func (m Money) Display() string {
+	return m.currency().Display(m.Amount)
+}
+
+// String renders the amount followed by the currency code, such as 12.34 USD
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive reports whether the amount is above zero
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add returns m + other, which must be in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrOverflow, m, other)
+	}
+
+	return New(sum, m.Currency), nil
+}
+
+// Sub returns m - other, which must be in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	negated, err := other.Neg()
+	if err != nil {
+		return Money{}, err
+	}
+
+	return m.Add(negated)
+}
+
+// Neg returns -m
+func (m Money) Neg() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: -(%s)", ErrOverflow, m)
+	}
+
+	return New(-m.Amount, m.Currency), nil
+}
+
+// Cmp compares m with other, which must be in the same currency, and
+// returns -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// jsonMoney is the wire form of Money. Amount is a decimal string so that
+// clients never have to guess the unit, MinorUnits and Formatted are
+// informational and ignored when decoding unless Amount is missing.
+type jsonMoney struct {
+	Amount     string `json:"amount"`
+	Currency   string `json:"currency"`
+	MinorUnits *int64 `json:"minor_units,omitempty"`
+	Formatted  string `json:"formatted,omitempty"`
+}
+
+// MarshalJSON encodes m as {"amount":"12.34","currency":"USD","minor_units":1234,"formatted":"$12.34"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{
+		Amount:     m.Decimal(),
+		Currency:   m.Currency,
+		MinorUnits: &m.Amount,
+		Formatted:  m.Display(),
+	})
+}
+
+// UnmarshalJSON decodes {"amount":"12.34","currency":"USD"}, or minor_units
+// in place of amount
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value jsonMoney
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if value.Amount == "" {
+		if value.MinorUnits == nil {
+			return fmt.Errorf("%w: amount is missing", ErrInvalidAmount)
+		}
+		if _, ok := util.LookupCurrency(value.Currency); !ok {
+			return fmt.Errorf("%w %q", ErrUnknownCurrency, value.Currency)
+		}
+		*m = New(*value.MinorUnits, value.Currency)
+		return nil
+	}
+
+	parsed, err := Parse(value.Amount, value.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
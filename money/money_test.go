@@ -0,0 +1,111 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		value  string
+		amount int64
+		err    error
+	}{
+		{value: "12.34", amount: 1234},
+		{value: "12.3", amount: 1230},
+		{value: "12", amount: 1200},
+		{value: "0.05", amount: 5},
+		{value: "-0.05", amount: -5},
+		{value: "007.10", amount: 710},
+		{value: "92233720368547758.07", amount: math.MaxInt64},
+		{value: "-92233720368547758.08", amount: math.MinInt64},
+		{value: "92233720368547758.08", err: ErrOverflow},
+		{value: "1000000000000000000", err: ErrOverflow},
+		{value: "12.345", err: ErrInvalidAmount},
+		{value: "12.", err: ErrInvalidAmount},
+		{value: ".5", err: ErrInvalidAmount},
+		{value: "+1", err: ErrInvalidAmount},
+		{value: "1e3", err: ErrInvalidAmount},
+		{value: "1,000.00", err: ErrInvalidAmount},
+		{value: "--1", err: ErrInvalidAmount},
+		{value: "", err: ErrInvalidAmount},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			m, err := Parse(tc.value, util.USD)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, New(tc.amount, util.USD), m)
+		})
+	}
+
+	_, err := Parse("1", "XYZ")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestRender(t *testing.T) {
+	m := New(-1205, util.USD)
+	require.Equal(t, "-12.05", m.Decimal())
+	require.Equal(t, "-$12.05", m.Display())
+	require.Equal(t, "-12.05 USD", m.String())
+
+	parsed, err := Parse(m.Decimal(), m.Currency)
+	require.NoError(t, err)
+	require.Equal(t, m, parsed)
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("10.50", util.USD)
+	b := MustParse("0.75", util.USD)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, "11.25", sum.Decimal())
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	require.Equal(t, "-9.75", diff.Decimal())
+	require.True(t, diff.IsNegative())
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+
+	_, err = a.Add(MustParse("1", util.EUR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = New(math.MaxInt64, util.USD).Add(New(1, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = New(math.MinInt64, util.USD).Sub(New(1, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = New(0, util.USD).Sub(New(math.MinInt64, util.USD))
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(MustParse("12.34", util.EUR))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"12.34","currency":"EUR","minor_units":1234,"formatted":"€12.34"}`, string(data))
+
+	var m Money
+	require.NoError(t, json.Unmarshal(data, &m))
+	require.Equal(t, New(1234, util.EUR), m)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"currency":"USD","minor_units":5}`), &m))
+	require.Equal(t, New(5, util.USD), m)
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"1.001","currency":"USD"}`), &m), ErrInvalidAmount)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"currency":"USD"}`), &m), ErrInvalidAmount)
+	require.Error(t, json.Unmarshal([]byte(`{"amount":12.34,"currency":"USD"}`), &m))
+}
@@ -0,0 +1,75 @@
+// Package outbox implements the transactional outbox. Domain events are
+// written to the outbox table in the same transaction as the change they
+// describe, and a Relay later hands them to an EventPublisher.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Event types published to other services
+const (
+	UserRegistered  = "UserRegistered"
+	AccountCreated  = "AccountCreated"
+	TransferCreated = "TransferCreated"
+)
+
+// Aggregate types. Events of the same aggregate are published in order.
+const (
+	AggregateUser    = "user"
+	AggregateAccount = "account"
+)
+
+// Statuses of an outbox row
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+	StatusDead      = "dead"
+)
+
+// Event is a domain event as handed to publishers
+type Event struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Enqueue writes an event to the outbox. q should belong to the transaction
+// making the change so the event exists if and only if the change commits.
+func Enqueue(ctx context.Context, q db.Querier, eventType string, aggregateType string, aggregateID string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("cannot encode %s payload: %w", eventType, err)
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot enqueue %s event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+func newEvent(row db.Outbox) Event {
+	return Event{
+		ID:            row.ID,
+		Type:          row.EventType,
+		AggregateType: row.AggregateType,
+		AggregateID:   row.AggregateID,
+		Payload:       row.Payload,
+		CreatedAt:     row.CreatedAt,
+	}
+}
@@ -0,0 +1,149 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventPublisher delivers events to consumers. Delivery is at least once, so
+// consumers should deduplicate on the event id.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes every event to a structured logger
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+// NewLogPublisher creates a publisher logging to logger
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (publisher *LogPublisher) Publish(ctx context.Context, event Event) error {
+	publisher.logger.InfoContext(ctx, "published domain event",
+		slog.Int64("event_id", event.ID),
+		slog.String("event_type", event.Type),
+		slog.String("aggregate_type", event.AggregateType),
+		slog.String("aggregate_id", event.AggregateID),
+		slog.String("payload", string(event.Payload)),
+	)
+	return nil
+}
+
+// WriterPublisher appends every event to w as a line of JSON
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterPublisher creates a publisher writing JSON lines to w
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// NewFilePublisher creates a publisher appending JSON lines to the file at path
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open outbox file: %w", err)
+	}
+
+	return NewWriterPublisher(file), nil
+}
+
+func (publisher *WriterPublisher) Publish(_ context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("cannot encode event: %w", err)
+	}
+
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	_, err = publisher.w.Write(append(data, '\n'))
+	return err
+}
+
+// Headers sent with every webhook delivery
+const (
+	EventIDHeader   = "X-Event-Id"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// WebhookPublisher posts every event as JSON to a URL. Any response other
+// than 2xx is treated as a failed delivery.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher creates a publisher posting to url
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (publisher *WebhookPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("cannot encode event: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, publisher.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("cannot create webhook request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, strconv.FormatInt(event.ID, 10))
+	request.Header.Set(EventTypeHeader, event.Type)
+
+	response, err := publisher.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("cannot deliver webhook: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// NewPublisher creates the publisher named by kind: none, log, file or
+// webhook. target is the file path or webhook URL. It returns nil for none.
+func NewPublisher(kind string, target string) (EventPublisher, error) {
+	switch kind {
+	case "", "none":
+		return nil, nil
+	case "log":
+		return NewLogPublisher(slog.Default()), nil
+	case "file":
+		publisher, err := NewFilePublisher(target)
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	case "webhook":
+		if target == "" {
+			return nil, fmt.Errorf("webhook publisher needs a URL")
+		}
+		return NewWebhookPublisher(target, 10*time.Second), nil
+	}
+
+	return nil, fmt.Errorf("unknown outbox publisher %q", kind)
+}
@@ -0,0 +1,112 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomEvent(id int64) Event {
+	return Event{
+		ID:            id,
+		Type:          TransferCreated,
+		AggregateType: AggregateAccount,
+		AggregateID:   "42",
+		Payload:       json.RawMessage(`{"amount":10}`),
+		CreatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func TestWriterPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewWriterPublisher(&buf)
+
+	events := []Event{randomEvent(1), randomEvent(2)}
+	for _, event := range events {
+		require.NoError(t, publisher.Publish(context.Background(), event))
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	for _, event := range events {
+		require.True(t, scanner.Scan())
+
+		var got Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+		require.Equal(t, event, got)
+	}
+	require.False(t, scanner.Scan())
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name:   "OK",
+			status: http.StatusNoContent,
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "ServerError",
+			status: http.StatusInternalServerError,
+			checkError: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "500")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			event := randomEvent(7)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "7", r.Header.Get(EventIDHeader))
+				require.Equal(t, TransferCreated, r.Header.Get(EventTypeHeader))
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				var got Event
+				require.NoError(t, json.Unmarshal(body, &got))
+				require.Equal(t, event, got)
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			publisher := NewWebhookPublisher(server.URL, time.Second)
+			tc.checkError(t, publisher.Publish(context.Background(), event))
+		})
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	publisher, err := NewPublisher("none", "")
+	require.NoError(t, err)
+	require.Nil(t, publisher)
+
+	publisher, err = NewPublisher("file", t.TempDir()+"/events.jsonl")
+	require.NoError(t, err)
+	require.IsType(t, &WriterPublisher{}, publisher)
+
+	_, err = NewPublisher("webhook", "")
+	require.Error(t, err)
+
+	_, err = NewPublisher("kafka", "")
+	require.Error(t, err)
+}
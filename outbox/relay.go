@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/worker"
+)
+
+// RelayConfig controls how often the relay polls and how long it retries
+type RelayConfig struct {
+	BatchSize    int32
+	PollInterval time.Duration
+	// MaxAttempts is the number of failed deliveries after which an event
+	// is marked dead and no longer holds back its aggregate
+	MaxAttempts int32
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Lease is how long claimed events stay hidden from other relays. It
+	// must cover publishing a whole batch.
+	Lease time.Duration
+}
+
+// DefaultRelayConfig is used for zero fields of the config given to NewRelay
+var DefaultRelayConfig = RelayConfig{
+	BatchSize:    100,
+	PollInterval: time.Second,
+	MaxAttempts:  10,
+	BaseDelay:    time.Second,
+	MaxDelay:     10 * time.Minute,
+	Lease:        5 * time.Minute,
+}
+
+// Relay moves events from the outbox table to an EventPublisher
+type Relay struct {
+	store     db.Store
+	publisher EventPublisher
+	config    RelayConfig
+}
+
+// NewRelay creates a relay publishing the outbox of store to publisher
+func NewRelay(store db.Store, publisher EventPublisher, config RelayConfig) *Relay {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultRelayConfig.BatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultRelayConfig.PollInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultRelayConfig.MaxAttempts
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = DefaultRelayConfig.BaseDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = DefaultRelayConfig.MaxDelay
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultRelayConfig.Lease
+	}
+
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		config:    config,
+	}
+}
+
+// Run publishes due events until ctx is cancelled
+func (relay *Relay) Run(ctx context.Context) error {
+	return worker.Poller{
+		Name:         "relay outbox events",
+		PollInterval: relay.config.PollInterval,
+		BatchSize:    relay.config.BatchSize,
+		ProcessBatch: relay.ProcessBatch,
+	}.Run(ctx)
+}
+
+// ProcessBatch publishes one batch of due events and returns how many were
+// claimed. Claiming leases the events instead of locking them, so no
+// transaction is open while they are published. Each event is then marked
+// published, or rescheduled with backoff, on its own.
+func (relay *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	rows, err := relay.store.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+		LeaseUntil: time.Now().Add(relay.config.Lease),
+		BatchSize:  relay.config.BatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot claim outbox events: %w", err)
+	}
+
+	var errs []error
+	for _, row := range rows {
+		if err := relay.publish(ctx, row); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(rows), errors.Join(errs...)
+}
+
+func (relay *Relay) publish(ctx context.Context, row db.Outbox) error {
+	publishErr := relay.publisher.Publish(ctx, newEvent(row))
+	if publishErr == nil {
+		if err := relay.store.MarkOutboxEventPublished(ctx, row.ID); err != nil {
+			return fmt.Errorf("cannot mark outbox event %d published: %w", row.ID, err)
+		}
+		return nil
+	}
+
+	attempts := row.Attempts + 1
+	status := StatusPending
+	if relay.retry().GiveUp(attempts) {
+		status = StatusDead
+	}
+
+	slog.WarnContext(ctx, "cannot publish outbox event",
+		slog.Int64("event_id", row.ID),
+		slog.String("event_type", row.EventType),
+		slog.Int("attempts", int(attempts)),
+		slog.String("status", status),
+		slog.String("error", publishErr.Error()),
+	)
+
+	err := relay.store.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+		ID:            row.ID,
+		Status:        status,
+		LastError:     publishErr.Error(),
+		NextAttemptAt: relay.retry().NextAttempt(attempts),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot mark outbox event %d failed: %w", row.ID, err)
+	}
+
+	return nil
+}
+
+func (relay *Relay) retry() worker.RetryPolicy {
+	return worker.RetryPolicy{
+		MaxAttempts: relay.config.MaxAttempts,
+		BaseDelay:   relay.config.BaseDelay,
+		MaxDelay:    relay.config.MaxDelay,
+	}
+}
@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+// fakePublisher fails the events whose id is in fail and records the rest
+type fakePublisher struct {
+	fail      map[int64]bool
+	published []Event
+}
+
+func (publisher *fakePublisher) Publish(_ context.Context, event Event) error {
+	if publisher.fail[event.ID] {
+		return errors.New("consumer is down")
+	}
+
+	publisher.published = append(publisher.published, event)
+	return nil
+}
+
+func randomOutboxRow(id int64, attempts int32) db.Outbox {
+	return db.Outbox{
+		ID:            id,
+		AggregateType: AggregateAccount,
+		AggregateID:   util.RandomString(6),
+		EventType:     AccountCreated,
+		Payload:       json.RawMessage(`{"id":1}`),
+		Status:        StatusPending,
+		Attempts:      attempts,
+	}
+}
+
+func TestRelayProcessBatch(t *testing.T) {
+	rows := []db.Outbox{
+		randomOutboxRow(1, 0),
+		randomOutboxRow(2, 0),
+		randomOutboxRow(3, 2),
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		ClaimOutboxEvents(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.ClaimOutboxEventsParams) ([]db.Outbox, error) {
+			require.Equal(t, int32(10), arg.BatchSize)
+			require.WithinDuration(t, time.Now().Add(DefaultRelayConfig.Lease), arg.LeaseUntil, time.Second)
+			return rows, nil
+		})
+	store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(nil)
+
+	var failed []db.MarkOutboxEventFailedParams
+	store.EXPECT().
+		MarkOutboxEventFailed(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, arg db.MarkOutboxEventFailedParams) error {
+			failed = append(failed, arg)
+			return nil
+		})
+
+	publisher := &fakePublisher{fail: map[int64]bool{2: true, 3: true}}
+	relay := NewRelay(store, publisher, RelayConfig{
+		BatchSize:   10,
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+	})
+
+	claimed, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, claimed)
+
+	require.Len(t, publisher.published, 1)
+	require.Equal(t, int64(1), publisher.published[0].ID)
+	require.Equal(t, rows[0].AggregateID, publisher.published[0].AggregateID)
+
+	require.Len(t, failed, 2)
+	require.Equal(t, int64(2), failed[0].ID)
+	require.Equal(t, StatusPending, failed[0].Status)
+	require.Equal(t, "consumer is down", failed[0].LastError)
+	require.WithinDuration(t, time.Now().Add(time.Second), failed[0].NextAttemptAt, 500*time.Millisecond)
+
+	// third failure reaches MaxAttempts
+	require.Equal(t, int64(3), failed[1].ID)
+	require.Equal(t, StatusDead, failed[1].Status)
+}
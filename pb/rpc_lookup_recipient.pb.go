@@ -0,0 +1,241 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        (unknown)
+// source: rpc_lookup_recipient.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// LookupRecipientRequest names a recipient by the username, email or phone
+// of its owner and the currency of the account to send to
+type LookupRecipientRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Alias    string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Currency string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+}
+
+func (x *LookupRecipientRequest) Reset() {
+	*x = LookupRecipientRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_lookup_recipient_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupRecipientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupRecipientRequest) ProtoMessage() {}
+
+func (x *LookupRecipientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_lookup_recipient_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupRecipientRequest.ProtoReflect.Descriptor instead.
+func (*LookupRecipientRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_lookup_recipient_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LookupRecipientRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *LookupRecipientRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// LookupRecipientResponse only has the masked name of the recipient, such
+// as "J*** S****"
+type LookupRecipientResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Alias      string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Currency   string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	MaskedName string `protobuf:"bytes,3,opt,name=masked_name,json=maskedName,proto3" json:"masked_name,omitempty"`
+}
+
+func (x *LookupRecipientResponse) Reset() {
+	*x = LookupRecipientResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_lookup_recipient_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupRecipientResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupRecipientResponse) ProtoMessage() {}
+
+func (x *LookupRecipientResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_lookup_recipient_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupRecipientResponse.ProtoReflect.Descriptor instead.
+func (*LookupRecipientResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_lookup_recipient_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LookupRecipientResponse) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *LookupRecipientResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *LookupRecipientResponse) GetMaskedName() string {
+	if x != nil {
+		return x.MaskedName
+	}
+	return ""
+}
+
+var File_rpc_lookup_recipient_proto protoreflect.FileDescriptor
+
+var file_rpc_lookup_recipient_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x5f, 0x72, 0x65, 0x63,
+	0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62,
+	0x22, 0x4a, 0x0a, 0x16, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x6c, 0x0a, 0x17,
+	0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x73,
+	0x6b, 0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6d, 0x61, 0x73, 0x6b, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x65, 0x63, 0x68, 0x73, 0x63, 0x68,
+	0x6f, 0x6f, 0x6c, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_lookup_recipient_proto_rawDescOnce sync.Once
+	file_rpc_lookup_recipient_proto_rawDescData = file_rpc_lookup_recipient_proto_rawDesc
+)
+
+func file_rpc_lookup_recipient_proto_rawDescGZIP() []byte {
+	file_rpc_lookup_recipient_proto_rawDescOnce.Do(func() {
+		file_rpc_lookup_recipient_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_lookup_recipient_proto_rawDescData)
+	})
+	return file_rpc_lookup_recipient_proto_rawDescData
+}
+
+var file_rpc_lookup_recipient_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_lookup_recipient_proto_goTypes = []interface{}{
+	(*LookupRecipientRequest)(nil),  // 0: pb.LookupRecipientRequest
+	(*LookupRecipientResponse)(nil), // 1: pb.LookupRecipientResponse
+}
+var file_rpc_lookup_recipient_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_lookup_recipient_proto_init() }
+func file_rpc_lookup_recipient_proto_init() {
+	if File_rpc_lookup_recipient_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_lookup_recipient_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupRecipientRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_lookup_recipient_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupRecipientResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_lookup_recipient_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_lookup_recipient_proto_goTypes,
+		DependencyIndexes: file_rpc_lookup_recipient_proto_depIdxs,
+		MessageInfos:      file_rpc_lookup_recipient_proto_msgTypes,
+	}.Build()
+	File_rpc_lookup_recipient_proto = out.File
+	file_rpc_lookup_recipient_proto_rawDesc = nil
+	file_rpc_lookup_recipient_proto_goTypes = nil
+	file_rpc_lookup_recipient_proto_depIdxs = nil
+}
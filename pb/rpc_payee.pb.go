@@ -0,0 +1,896 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        (unknown)
+// source: rpc_payee.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CreatePayeeRequest names the account either by account_number or by the
+// username and currency of its owner
+type CreatePayeeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nickname      string `protobuf:"bytes,1,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	AccountNumber string `protobuf:"bytes,5,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
+	Username      string `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+}
+
+func (x *CreatePayeeRequest) Reset() {
+	*x = CreatePayeeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreatePayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePayeeRequest) ProtoMessage() {}
+
+func (x *CreatePayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePayeeRequest.ProtoReflect.Descriptor instead.
+func (*CreatePayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreatePayeeRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *CreatePayeeRequest) GetAccountNumber() string {
+	if x != nil {
+		return x.AccountNumber
+	}
+	return ""
+}
+
+func (x *CreatePayeeRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreatePayeeRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreatePayeeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payee *Payee `protobuf:"bytes,1,opt,name=payee,proto3" json:"payee,omitempty"`
+}
+
+func (x *CreatePayeeResponse) Reset() {
+	*x = CreatePayeeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreatePayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePayeeResponse) ProtoMessage() {}
+
+func (x *CreatePayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePayeeResponse.ProtoReflect.Descriptor instead.
+func (*CreatePayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePayeeResponse) GetPayee() *Payee {
+	if x != nil {
+		return x.Payee
+	}
+	return nil
+}
+
+type GetPayeeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetPayeeRequest) Reset() {
+	*x = GetPayeeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayeeRequest) ProtoMessage() {}
+
+func (x *GetPayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayeeRequest.ProtoReflect.Descriptor instead.
+func (*GetPayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetPayeeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetPayeeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payee *Payee `protobuf:"bytes,1,opt,name=payee,proto3" json:"payee,omitempty"`
+}
+
+func (x *GetPayeeResponse) Reset() {
+	*x = GetPayeeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPayeeResponse) ProtoMessage() {}
+
+func (x *GetPayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPayeeResponse.ProtoReflect.Descriptor instead.
+func (*GetPayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPayeeResponse) GetPayee() *Payee {
+	if x != nil {
+		return x.Payee
+	}
+	return nil
+}
+
+type ListPayeesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PageId   int32 `protobuf:"varint,1,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+func (x *ListPayeesRequest) Reset() {
+	*x = ListPayeesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPayeesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPayeesRequest) ProtoMessage() {}
+
+func (x *ListPayeesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPayeesRequest.ProtoReflect.Descriptor instead.
+func (*ListPayeesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPayeesRequest) GetPageId() int32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *ListPayeesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListPayeesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payees []*Payee `protobuf:"bytes,1,rep,name=payees,proto3" json:"payees,omitempty"`
+}
+
+func (x *ListPayeesResponse) Reset() {
+	*x = ListPayeesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPayeesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPayeesResponse) ProtoMessage() {}
+
+func (x *ListPayeesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPayeesResponse.ProtoReflect.Descriptor instead.
+func (*ListPayeesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPayeesResponse) GetPayees() []*Payee {
+	if x != nil {
+		return x.Payees
+	}
+	return nil
+}
+
+type UpdatePayeeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Nickname string `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+}
+
+func (x *UpdatePayeeRequest) Reset() {
+	*x = UpdatePayeeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePayeeRequest) ProtoMessage() {}
+
+func (x *UpdatePayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePayeeRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdatePayeeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdatePayeeRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+type UpdatePayeeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payee *Payee `protobuf:"bytes,1,opt,name=payee,proto3" json:"payee,omitempty"`
+}
+
+func (x *UpdatePayeeResponse) Reset() {
+	*x = UpdatePayeeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePayeeResponse) ProtoMessage() {}
+
+func (x *UpdatePayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePayeeResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdatePayeeResponse) GetPayee() *Payee {
+	if x != nil {
+		return x.Payee
+	}
+	return nil
+}
+
+type ConfirmPayeeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *ConfirmPayeeRequest) Reset() {
+	*x = ConfirmPayeeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmPayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPayeeRequest) ProtoMessage() {}
+
+func (x *ConfirmPayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPayeeRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConfirmPayeeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConfirmPayeeRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type ConfirmPayeeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payee *Payee `protobuf:"bytes,1,opt,name=payee,proto3" json:"payee,omitempty"`
+}
+
+func (x *ConfirmPayeeResponse) Reset() {
+	*x = ConfirmPayeeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmPayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPayeeResponse) ProtoMessage() {}
+
+func (x *ConfirmPayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPayeeResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmPayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ConfirmPayeeResponse) GetPayee() *Payee {
+	if x != nil {
+		return x.Payee
+	}
+	return nil
+}
+
+type DeletePayeeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeletePayeeRequest) Reset() {
+	*x = DeletePayeeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePayeeRequest) ProtoMessage() {}
+
+func (x *DeletePayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePayeeRequest.ProtoReflect.Descriptor instead.
+func (*DeletePayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeletePayeeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeletePayeeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeletePayeeResponse) Reset() {
+	*x = DeletePayeeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_payee_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePayeeResponse) ProtoMessage() {}
+
+func (x *DeletePayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePayeeResponse.ProtoReflect.Descriptor instead.
+func (*DeletePayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{11}
+}
+
+var File_rpc_payee_proto protoreflect.FileDescriptor
+
+var file_rpc_payee_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x61, 0x79, 0x65, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x0b, 0x70, 0x61, 0x79, 0x65, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xa1, 0x01, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x61, 0x79,
+	0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x69, 0x63,
+	0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x69, 0x63,
+	0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08,
+	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x22, 0x36, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a,
+	0x05, 0x70, 0x61, 0x79, 0x65, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70,
+	0x62, 0x2e, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x05, 0x70, 0x61, 0x79, 0x65, 0x65, 0x22, 0x21,
+	0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x33, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x05, 0x70, 0x61, 0x79, 0x65, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52,
+	0x05, 0x70, 0x61, 0x79, 0x65, 0x65, 0x22, 0x49, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61,
+	0x79, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x70, 0x61,
+	0x67, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x22, 0x37, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x65, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x06, 0x70, 0x61, 0x79, 0x65, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x61, 0x79,
+	0x65, 0x65, 0x52, 0x06, 0x70, 0x61, 0x79, 0x65, 0x65, 0x73, 0x22, 0x40, 0x0a, 0x12, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x36, 0x0a, 0x13,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x05, 0x70, 0x61, 0x79, 0x65, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x05, 0x70,
+	0x61, 0x79, 0x65, 0x65, 0x22, 0x41, 0x0a, 0x13, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50,
+	0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x37, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1f, 0x0a, 0x05, 0x70, 0x61, 0x79, 0x65, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09,
+	0x2e, 0x70, 0x62, 0x2e, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x05, 0x70, 0x61, 0x79, 0x65, 0x65,
+	0x22, 0x24, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x61, 0x79, 0x65, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x15, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x50, 0x61, 0x79, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x25, 0x5a,
+	0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x65, 0x63, 0x68,
+	0x73, 0x63, 0x68, 0x6f, 0x6f, 0x6c, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_payee_proto_rawDescOnce sync.Once
+	file_rpc_payee_proto_rawDescData = file_rpc_payee_proto_rawDesc
+)
+
+func file_rpc_payee_proto_rawDescGZIP() []byte {
+	file_rpc_payee_proto_rawDescOnce.Do(func() {
+		file_rpc_payee_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_payee_proto_rawDescData)
+	})
+	return file_rpc_payee_proto_rawDescData
+}
+
+var file_rpc_payee_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_rpc_payee_proto_goTypes = []interface{}{
+	(*CreatePayeeRequest)(nil),   // 0: pb.CreatePayeeRequest
+	(*CreatePayeeResponse)(nil),  // 1: pb.CreatePayeeResponse
+	(*GetPayeeRequest)(nil),      // 2: pb.GetPayeeRequest
+	(*GetPayeeResponse)(nil),     // 3: pb.GetPayeeResponse
+	(*ListPayeesRequest)(nil),    // 4: pb.ListPayeesRequest
+	(*ListPayeesResponse)(nil),   // 5: pb.ListPayeesResponse
+	(*UpdatePayeeRequest)(nil),   // 6: pb.UpdatePayeeRequest
+	(*UpdatePayeeResponse)(nil),  // 7: pb.UpdatePayeeResponse
+	(*ConfirmPayeeRequest)(nil),  // 8: pb.ConfirmPayeeRequest
+	(*ConfirmPayeeResponse)(nil), // 9: pb.ConfirmPayeeResponse
+	(*DeletePayeeRequest)(nil),   // 10: pb.DeletePayeeRequest
+	(*DeletePayeeResponse)(nil),  // 11: pb.DeletePayeeResponse
+	(*Payee)(nil),                // 12: pb.Payee
+}
+var file_rpc_payee_proto_depIdxs = []int32{
+	12, // 0: pb.CreatePayeeResponse.payee:type_name -> pb.Payee
+	12, // 1: pb.GetPayeeResponse.payee:type_name -> pb.Payee
+	12, // 2: pb.ListPayeesResponse.payees:type_name -> pb.Payee
+	12, // 3: pb.UpdatePayeeResponse.payee:type_name -> pb.Payee
+	12, // 4: pb.ConfirmPayeeResponse.payee:type_name -> pb.Payee
+	5,  // [5:5] is the sub-list for method output_type
+	5,  // [5:5] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_rpc_payee_proto_init() }
+func file_rpc_payee_proto_init() {
+	if File_rpc_payee_proto != nil {
+		return
+	}
+	file_payee_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_payee_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreatePayeeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreatePayeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPayeeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPayeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPayeesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPayeesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePayeeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePayeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmPayeeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmPayeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeletePayeeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_payee_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeletePayeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_payee_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_payee_proto_goTypes,
+		DependencyIndexes: file_rpc_payee_proto_depIdxs,
+		MessageInfos:      file_rpc_payee_proto_msgTypes,
+	}.Build()
+	File_rpc_payee_proto = out.File
+	file_rpc_payee_proto_rawDesc = nil
+	file_rpc_payee_proto_goTypes = nil
+	file_rpc_payee_proto_depIdxs = nil
+}
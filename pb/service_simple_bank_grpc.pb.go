@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
 // - protoc-gen-go-grpc v1.4.0
-// - protoc             v3.12.4
+// - protoc             (unknown)
 // source: service_simple_bank.proto
 
 package pb
@@ -19,8 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	SimpleBank_CreateUser_FullMethodName = "/pb.SimpleBank/CreateUser"
-	SimpleBank_LoginUser_FullMethodName  = "/pb.SimpleBank/LoginUser"
+	SimpleBank_CreateUser_FullMethodName      = "/pb.SimpleBank/CreateUser"
+	SimpleBank_LoginUser_FullMethodName       = "/pb.SimpleBank/LoginUser"
+	SimpleBank_CreatePayee_FullMethodName     = "/pb.SimpleBank/CreatePayee"
+	SimpleBank_GetPayee_FullMethodName        = "/pb.SimpleBank/GetPayee"
+	SimpleBank_ListPayees_FullMethodName      = "/pb.SimpleBank/ListPayees"
+	SimpleBank_UpdatePayee_FullMethodName     = "/pb.SimpleBank/UpdatePayee"
+	SimpleBank_ConfirmPayee_FullMethodName    = "/pb.SimpleBank/ConfirmPayee"
+	SimpleBank_DeletePayee_FullMethodName     = "/pb.SimpleBank/DeletePayee"
+	SimpleBank_LookupRecipient_FullMethodName = "/pb.SimpleBank/LookupRecipient"
 )
 
 // SimpleBankClient is the client API for SimpleBank service.
@@ -29,6 +36,15 @@ const (
 type SimpleBankClient interface {
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
 	LoginUser(ctx context.Context, in *LoginUserRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
+	// Saves a payee of the user. There is no transfer RPC, so money is sent
+	// to payees only over REST with the payee_id of POST /transfer.
+	CreatePayee(ctx context.Context, in *CreatePayeeRequest, opts ...grpc.CallOption) (*CreatePayeeResponse, error)
+	GetPayee(ctx context.Context, in *GetPayeeRequest, opts ...grpc.CallOption) (*GetPayeeResponse, error)
+	ListPayees(ctx context.Context, in *ListPayeesRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error)
+	UpdatePayee(ctx context.Context, in *UpdatePayeeRequest, opts ...grpc.CallOption) (*UpdatePayeeResponse, error)
+	ConfirmPayee(ctx context.Context, in *ConfirmPayeeRequest, opts ...grpc.CallOption) (*ConfirmPayeeResponse, error)
+	DeletePayee(ctx context.Context, in *DeletePayeeRequest, opts ...grpc.CallOption) (*DeletePayeeResponse, error)
+	LookupRecipient(ctx context.Context, in *LookupRecipientRequest, opts ...grpc.CallOption) (*LookupRecipientResponse, error)
 }
 
 type simpleBankClient struct {
@@ -59,12 +75,91 @@ func (c *simpleBankClient) LoginUser(ctx context.Context, in *LoginUserRequest,
 	return out, nil
 }
 
+func (c *simpleBankClient) CreatePayee(ctx context.Context, in *CreatePayeeRequest, opts ...grpc.CallOption) (*CreatePayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreatePayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) GetPayee(ctx context.Context, in *GetPayeeRequest, opts ...grpc.CallOption) (*GetPayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_GetPayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListPayees(ctx context.Context, in *ListPayeesRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPayeesResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListPayees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) UpdatePayee(ctx context.Context, in *UpdatePayeeRequest, opts ...grpc.CallOption) (*UpdatePayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdatePayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_UpdatePayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ConfirmPayee(ctx context.Context, in *ConfirmPayeeRequest, opts ...grpc.CallOption) (*ConfirmPayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmPayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ConfirmPayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) DeletePayee(ctx context.Context, in *DeletePayeeRequest, opts ...grpc.CallOption) (*DeletePayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_DeletePayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) LookupRecipient(ctx context.Context, in *LookupRecipientRequest, opts ...grpc.CallOption) (*LookupRecipientResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupRecipientResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_LookupRecipient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SimpleBankServer is the server API for SimpleBank service.
 // All implementations must embed UnimplementedSimpleBankServer
 // for forward compatibility
 type SimpleBankServer interface {
 	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
 	LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error)
+	// Saves a payee of the user. There is no transfer RPC, so money is sent
+	// to payees only over REST with the payee_id of POST /transfer.
+	CreatePayee(context.Context, *CreatePayeeRequest) (*CreatePayeeResponse, error)
+	GetPayee(context.Context, *GetPayeeRequest) (*GetPayeeResponse, error)
+	ListPayees(context.Context, *ListPayeesRequest) (*ListPayeesResponse, error)
+	UpdatePayee(context.Context, *UpdatePayeeRequest) (*UpdatePayeeResponse, error)
+	ConfirmPayee(context.Context, *ConfirmPayeeRequest) (*ConfirmPayeeResponse, error)
+	DeletePayee(context.Context, *DeletePayeeRequest) (*DeletePayeeResponse, error)
+	LookupRecipient(context.Context, *LookupRecipientRequest) (*LookupRecipientResponse, error)
 	mustEmbedUnimplementedSimpleBankServer()
 }
 
@@ -78,6 +173,27 @@ func (UnimplementedSimpleBankServer) CreateUser(context.Context, *CreateUserRequ
 func (UnimplementedSimpleBankServer) LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LoginUser not implemented")
 }
+func (UnimplementedSimpleBankServer) CreatePayee(context.Context, *CreatePayeeRequest) (*CreatePayeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePayee not implemented")
+}
+func (UnimplementedSimpleBankServer) GetPayee(context.Context, *GetPayeeRequest) (*GetPayeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPayee not implemented")
+}
+func (UnimplementedSimpleBankServer) ListPayees(context.Context, *ListPayeesRequest) (*ListPayeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPayees not implemented")
+}
+func (UnimplementedSimpleBankServer) UpdatePayee(context.Context, *UpdatePayeeRequest) (*UpdatePayeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePayee not implemented")
+}
+func (UnimplementedSimpleBankServer) ConfirmPayee(context.Context, *ConfirmPayeeRequest) (*ConfirmPayeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPayee not implemented")
+}
+func (UnimplementedSimpleBankServer) DeletePayee(context.Context, *DeletePayeeRequest) (*DeletePayeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePayee not implemented")
+}
+func (UnimplementedSimpleBankServer) LookupRecipient(context.Context, *LookupRecipientRequest) (*LookupRecipientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupRecipient not implemented")
+}
 func (UnimplementedSimpleBankServer) mustEmbedUnimplementedSimpleBankServer() {}
 
 // UnsafeSimpleBankServer may be embedded to opt out of forward compatibility for this service.
@@ -127,6 +243,132 @@ func _SimpleBank_LoginUser_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SimpleBank_CreatePayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreatePayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreatePayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreatePayee(ctx, req.(*CreatePayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_GetPayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).GetPayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_GetPayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).GetPayee(ctx, req.(*GetPayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListPayees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPayeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListPayees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListPayees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListPayees(ctx, req.(*ListPayeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_UpdatePayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).UpdatePayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_UpdatePayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).UpdatePayee(ctx, req.(*UpdatePayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ConfirmPayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ConfirmPayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ConfirmPayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ConfirmPayee(ctx, req.(*ConfirmPayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_DeletePayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).DeletePayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_DeletePayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).DeletePayee(ctx, req.(*DeletePayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_LookupRecipient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupRecipientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).LookupRecipient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_LookupRecipient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).LookupRecipient(ctx, req.(*LookupRecipientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SimpleBank_ServiceDesc is the grpc.ServiceDesc for SimpleBank service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -142,6 +384,34 @@ var SimpleBank_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LoginUser",
 			Handler:    _SimpleBank_LoginUser_Handler,
 		},
+		{
+			MethodName: "CreatePayee",
+			Handler:    _SimpleBank_CreatePayee_Handler,
+		},
+		{
+			MethodName: "GetPayee",
+			Handler:    _SimpleBank_GetPayee_Handler,
+		},
+		{
+			MethodName: "ListPayees",
+			Handler:    _SimpleBank_ListPayees_Handler,
+		},
+		{
+			MethodName: "UpdatePayee",
+			Handler:    _SimpleBank_UpdatePayee_Handler,
+		},
+		{
+			MethodName: "ConfirmPayee",
+			Handler:    _SimpleBank_ConfirmPayee_Handler,
+		},
+		{
+			MethodName: "DeletePayee",
+			Handler:    _SimpleBank_DeletePayee_Handler,
+		},
+		{
+			MethodName: "LookupRecipient",
+			Handler:    _SimpleBank_LookupRecipient_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service_simple_bank.proto",
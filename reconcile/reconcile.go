@@ -0,0 +1,190 @@
+// Package reconcile checks the invariants of the ledger: the balance of every
+// account equals the sum of its entries and the credit balance of its general
+// ledger account, and every transfer has exactly one debit and one credit
+// entry between accounts of the same currency.
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Actor is recorded in the audit log for repairs
+const Actor = "reconciler"
+
+// snapshotTxOptions reads every check from the same consistent snapshot
+var snapshotTxOptions = db.TxOptions{
+	Isolation: sql.LevelRepeatableRead,
+	ReadOnly:  true,
+	Retry:     db.DefaultRetryPolicy,
+}
+
+// Config controls the scheduled job and whether drift is repaired
+type Config struct {
+	Interval time.Duration
+	// Repair resets the balance of drifting accounts to the sum of their
+	// entries. Transfer issues and ledger drift are only ever reported.
+	Repair bool
+}
+
+// DefaultConfig is used for zero fields of the config given to NewReconciler
+var DefaultConfig = Config{
+	Interval: time.Hour,
+}
+
+// Reconciler checks the ledger of a store
+type Reconciler struct {
+	store  db.Store
+	config Config
+}
+
+// NewReconciler creates a reconciler for store
+func NewReconciler(store db.Store, config Config) *Reconciler {
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig.Interval
+	}
+
+	return &Reconciler{
+		store:  store,
+		config: config,
+	}
+}
+
+// Run reconciles the ledger every Interval until ctx is cancelled, logging
+// whatever drift it finds
+func (reconciler *Reconciler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(reconciler.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := reconciler.Reconcile(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "cannot reconcile ledger", slog.String("error", err.Error()))
+		} else {
+			report.Log(ctx, slog.Default())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile checks every invariant and, if the config asks for it, repairs
+// the accounts whose balance drifted from their entries
+func (reconciler *Reconciler) Reconcile(ctx context.Context) (Report, error) {
+	report := Report{StartedAt: time.Now()}
+
+	err := reconciler.store.ExecTx(ctx, snapshotTxOptions, func(q db.Querier) error {
+		currencies, err := q.ListCurrencyTotals(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list currency totals: %w", err)
+		}
+
+		accounts, err := q.ListAccountDrift(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list account drift: %w", err)
+		}
+
+		transfers, err := q.ListUnbalancedTransfers(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list unbalanced transfers: %w", err)
+		}
+
+		ledgerDrift, err := q.ListLedgerDrift(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list ledger drift: %w", err)
+		}
+
+		report.Currencies = make([]CurrencyTotal, 0, len(currencies))
+		for _, row := range currencies {
+			report.Currencies = append(report.Currencies, newCurrencyTotal(row))
+		}
+
+		report.Accounts = make([]AccountDrift, 0, len(accounts))
+		for _, row := range accounts {
+			report.Accounts = append(report.Accounts, newAccountDrift(row))
+		}
+
+		report.Transfers = make([]TransferIssue, 0, len(transfers))
+		for _, row := range transfers {
+			report.Transfers = append(report.Transfers, newTransferIssue(row))
+		}
+
+		report.Ledger = make([]LedgerDrift, 0, len(ledgerDrift))
+		for _, row := range ledgerDrift {
+			report.Ledger = append(report.Ledger, newLedgerDrift(row))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if reconciler.config.Repair {
+		for i := range report.Accounts {
+			if err := reconciler.repair(ctx, &report.Accounts[i]); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// repair locks the account of drift and resets its balance to the sum of its
+// entries. The drift is checked again under the lock because the snapshot may
+// be stale by now.
+func (reconciler *Reconciler) repair(ctx context.Context, drift *AccountDrift) error {
+	return reconciler.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		account, err := q.GetAccountForUpdate(ctx, drift.AccountID)
+		if err != nil {
+			return fmt.Errorf("cannot lock account %d: %w", drift.AccountID, err)
+		}
+
+		total, err := q.SumAccountEntries(ctx, drift.AccountID)
+		if err != nil {
+			return fmt.Errorf("cannot sum entries of account %d: %w", drift.AccountID, err)
+		}
+
+		if account.Balance == total {
+			return nil
+		}
+
+		repaired, err := q.UpdateAccount(ctx, db.UpdateAccountParams{
+			ID:      account.ID,
+			Balance: total,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot repair account %d: %w", drift.AccountID, err)
+		}
+
+		_, err = audit.Record(ctx, q, audit.Event{
+			Actor:        Actor,
+			Action:       audit.ActionBalanceRepaired,
+			ResourceType: audit.ResourceAccount,
+			ResourceID:   strconv.FormatInt(account.ID, 10),
+			Diff: map[string]any{
+				"before": account.Balance,
+				"after":  repaired.Balance,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("cannot record audit event: %w", err)
+		}
+
+		drift.Repaired = true
+		return nil
+	})
+}
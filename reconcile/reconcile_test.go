@@ -0,0 +1,113 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestStore(t *testing.T) *mockdb.MockStore {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, _ db.TxOptions, fn func(db.Querier) error) error {
+			return fn(store)
+		})
+
+	store.EXPECT().ListCurrencyTotals(gomock.Any()).Times(1).Return([]db.ListCurrencyTotalsRow{
+		{Currency: "EUR", Accounts: 2, BalanceTotal: 300, EntriesTotal: 300},
+		{Currency: "USD", Accounts: 3, BalanceTotal: 520, EntriesTotal: 500},
+	}, nil)
+	store.EXPECT().ListAccountDrift(gomock.Any()).Times(1).Return([]db.ListAccountDriftRow{
+		{ID: 7, Owner: "alice", Currency: "USD", Balance: 120, EntriesTotal: 100},
+	}, nil)
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any()).Times(1).Return([]db.ListUnbalancedTransfersRow{
+		{ID: 3, FromAccountID: 7, ToAccountID: 9, Amount: 20, FromCurrency: "USD", ToCurrency: "USD", DebitEntries: 1, EntriesTotal: -20},
+		{ID: 4, FromAccountID: 7, ToAccountID: 8, Amount: 5, FromCurrency: "USD", ToCurrency: "EUR", DebitEntries: 1, CreditEntries: 1},
+	}, nil)
+	store.EXPECT().ListLedgerDrift(gomock.Any()).Times(1).Return([]db.ListLedgerDriftRow{}, nil)
+
+	return store
+}
+
+func TestReconcileReport(t *testing.T) {
+	store := newTestStore(t)
+	store.EXPECT().GetAccountForUpdate(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().UpdateAccount(gomock.Any(), gomock.Any()).Times(0)
+
+	report, err := NewReconciler(store, Config{}).Reconcile(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Unresolved())
+
+	require.Len(t, report.Currencies, 2)
+	require.Zero(t, report.Currencies[0].Drift)
+	require.Equal(t, int64(20), report.Currencies[1].Drift)
+
+	require.Equal(t, []AccountDrift{
+		{AccountID: 7, Owner: "alice", Currency: "USD", Balance: 120, EntriesTotal: 100, Drift: 20},
+	}, report.Accounts)
+
+	require.Len(t, report.Transfers, 2)
+	require.Equal(t, []string{
+		"expected 1 credit entry, found 0",
+		"entries sum to -20 instead of 0",
+	}, report.Transfers[0].Problems)
+	require.Equal(t, []string{"accounts hold different currencies, USD and EUR"}, report.Transfers[1].Problems)
+}
+
+func TestReconcileRepair(t *testing.T) {
+	store := newTestStore(t)
+	store.EXPECT().
+		GetAccountForUpdate(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(db.Account{ID: 7, Owner: "alice", Currency: "USD", Balance: 120}, nil)
+	store.EXPECT().SumAccountEntries(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(int64(100), nil)
+	store.EXPECT().
+		UpdateAccount(gomock.Any(), gomock.Eq(db.UpdateAccountParams{ID: 7, Balance: 100})).
+		Times(1).
+		Return(db.Account{ID: 7, Owner: "alice", Currency: "USD", Balance: 100}, nil)
+
+	store.EXPECT().LockAuditChain(gomock.Any()).Times(1).Return(nil)
+	store.EXPECT().GetLastAuditEvent(gomock.Any()).Times(1).Return(db.AuditEvent{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+			require.Equal(t, Actor, arg.Actor)
+			require.Equal(t, audit.ActionBalanceRepaired, arg.Action)
+			require.Equal(t, "7", arg.ResourceID)
+			require.JSONEq(t, `{"before":120,"after":100}`, string(arg.Diff))
+			return db.AuditEvent{}, nil
+		})
+
+	report, err := NewReconciler(store, Config{Repair: true}).Reconcile(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Accounts[0].Repaired)
+
+	// the transfers still need someone to look at them
+	require.True(t, report.Unresolved())
+	report.Transfers = nil
+	require.False(t, report.Unresolved())
+}
+
+func TestReconcileRepairSkipsResolvedDrift(t *testing.T) {
+	store := newTestStore(t)
+	store.EXPECT().
+		GetAccountForUpdate(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(db.Account{ID: 7, Balance: 100}, nil)
+	store.EXPECT().SumAccountEntries(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(int64(100), nil)
+	store.EXPECT().UpdateAccount(gomock.Any(), gomock.Any()).Times(0)
+
+	report, err := NewReconciler(store, Config{Repair: true}).Reconcile(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.Accounts[0].Repaired)
+}
@@ -0,0 +1,167 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Report lists everything a reconciliation found
+type Report struct {
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Currencies []CurrencyTotal `json:"currencies"`
+	Accounts   []AccountDrift  `json:"accounts"`
+	Transfers  []TransferIssue `json:"transfers"`
+	Ledger     []LedgerDrift   `json:"ledger"`
+}
+
+// CurrencyTotal rolls the balances and entries of all accounts of a
+// currency up. Its drift is the sum of the drift of those accounts.
+type CurrencyTotal struct {
+	Currency     string `json:"currency"`
+	Accounts     int64  `json:"accounts"`
+	BalanceTotal int64  `json:"balance_total"`
+	EntriesTotal int64  `json:"entries_total"`
+	Drift        int64  `json:"drift"`
+}
+
+// AccountDrift is an account whose balance is not the sum of its entries
+type AccountDrift struct {
+	AccountID    int64  `json:"account_id"`
+	Owner        string `json:"owner"`
+	Currency     string `json:"currency"`
+	Balance      int64  `json:"balance"`
+	EntriesTotal int64  `json:"entries_total"`
+	Drift        int64  `json:"drift"`
+	Repaired     bool   `json:"repaired"`
+}
+
+// TransferIssue is a transfer whose entries do not match it
+type TransferIssue struct {
+	TransferID    int64    `json:"transfer_id"`
+	FromAccountID int64    `json:"from_account_id"`
+	ToAccountID   int64    `json:"to_account_id"`
+	Amount        int64    `json:"amount"`
+	Problems      []string `json:"problems"`
+}
+
+// LedgerDrift is an account whose balance differs from the credit balance
+// of its general ledger account
+type LedgerDrift struct {
+	AccountID     int64  `json:"account_id"`
+	Currency      string `json:"currency"`
+	Balance       int64  `json:"balance"`
+	LedgerBalance int64  `json:"ledger_balance"`
+	Drift         int64  `json:"drift"`
+}
+
+// Unresolved reports whether anything found still needs attention
+func (report Report) Unresolved() bool {
+	if len(report.Transfers) > 0 || len(report.Ledger) > 0 {
+		return true
+	}
+
+	for _, drift := range report.Accounts {
+		if !drift.Repaired {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Log writes a summary of report and a warning for every finding to logger
+func (report Report) Log(ctx context.Context, logger *slog.Logger) {
+	for _, drift := range report.Accounts {
+		logger.WarnContext(ctx, "account balance drifted from its entries",
+			slog.Int64("account_id", drift.AccountID),
+			slog.String("currency", drift.Currency),
+			slog.Int64("balance", drift.Balance),
+			slog.Int64("entries_total", drift.EntriesTotal),
+			slog.Bool("repaired", drift.Repaired),
+		)
+	}
+
+	for _, issue := range report.Transfers {
+		logger.WarnContext(ctx, "transfer does not match its entries",
+			slog.Int64("transfer_id", issue.TransferID),
+			slog.Any("problems", issue.Problems),
+		)
+	}
+
+	for _, drift := range report.Ledger {
+		logger.WarnContext(ctx, "account balance drifted from the general ledger",
+			slog.Int64("account_id", drift.AccountID),
+			slog.String("currency", drift.Currency),
+			slog.Int64("balance", drift.Balance),
+			slog.Int64("ledger_balance", drift.LedgerBalance),
+		)
+	}
+
+	logger.InfoContext(ctx, "reconciled ledger",
+		slog.Int("currencies", len(report.Currencies)),
+		slog.Int("drifting_accounts", len(report.Accounts)),
+		slog.Int("unbalanced_transfers", len(report.Transfers)),
+		slog.Int("ledger_drift", len(report.Ledger)),
+		slog.Duration("duration", report.FinishedAt.Sub(report.StartedAt)),
+	)
+}
+
+func newCurrencyTotal(row db.ListCurrencyTotalsRow) CurrencyTotal {
+	return CurrencyTotal{
+		Currency:     row.Currency,
+		Accounts:     row.Accounts,
+		BalanceTotal: row.BalanceTotal,
+		EntriesTotal: row.EntriesTotal,
+		Drift:        row.BalanceTotal - row.EntriesTotal,
+	}
+}
+
+func newAccountDrift(row db.ListAccountDriftRow) AccountDrift {
+	return AccountDrift{
+		AccountID:    row.ID,
+		Owner:        row.Owner,
+		Currency:     row.Currency,
+		Balance:      row.Balance,
+		EntriesTotal: row.EntriesTotal,
+		Drift:        row.Balance - row.EntriesTotal,
+	}
+}
+
+func newTransferIssue(row db.ListUnbalancedTransfersRow) TransferIssue {
+	issue := TransferIssue{
+		TransferID:    row.ID,
+		FromAccountID: row.FromAccountID,
+		ToAccountID:   row.ToAccountID,
+		Amount:        row.Amount,
+	}
+
+	if row.DebitEntries != 1 {
+		issue.Problems = append(issue.Problems, fmt.Sprintf("expected 1 debit entry, found %d", row.DebitEntries))
+	}
+	if row.CreditEntries != 1 {
+		issue.Problems = append(issue.Problems, fmt.Sprintf("expected 1 credit entry, found %d", row.CreditEntries))
+	}
+	if row.EntriesTotal != 0 {
+		issue.Problems = append(issue.Problems, fmt.Sprintf("entries sum to %d instead of 0", row.EntriesTotal))
+	}
+	if row.FromCurrency != row.ToCurrency {
+		issue.Problems = append(issue.Problems, fmt.Sprintf("accounts hold different currencies, %s and %s", row.FromCurrency, row.ToCurrency))
+	}
+
+	return issue
+}
+
+func newLedgerDrift(row db.ListLedgerDriftRow) LedgerDrift {
+	return LedgerDrift{
+		AccountID:     row.ID,
+		Currency:      row.Currency,
+		Balance:       row.Balance,
+		LedgerBalance: row.LedgerBalance,
+		Drift:         row.Balance - row.LedgerBalance,
+	}
+}
@@ -0,0 +1,143 @@
+package rekey
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/techschool/simplebank/crypt"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// JobConfig controls how often the job looks for records to re-encrypt and
+// how many are re-encrypted per transaction
+type JobConfig struct {
+	Interval  time.Duration
+	BatchSize int32
+}
+
+// DefaultJobConfig is used for zero fields of the config given to NewJob
+var DefaultJobConfig = JobConfig{
+	Interval:  time.Hour,
+	BatchSize: 100,
+}
+
+// Job moves the personal data of users and KYC submissions onto the active
+// master key. It encrypts users still stored in plaintext and rewraps the
+// data keys of records wrapped by an older master key, which can be removed
+// from the keyring once the job has caught up.
+type Job struct {
+	store    db.Store
+	envelope *crypt.Envelope
+	config   JobConfig
+}
+
+// NewJob creates a re-encryption job for the records of store
+func NewJob(store db.Store, envelope *crypt.Envelope, config JobConfig) *Job {
+	if config.Interval <= 0 {
+		config.Interval = DefaultJobConfig.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultJobConfig.BatchSize
+	}
+
+	return &Job{
+		store:    store,
+		envelope: envelope,
+		config:   config,
+	}
+}
+
+// Run re-encrypts records every Interval until ctx is cancelled
+func (job *Job) Run(ctx context.Context) error {
+	ticker := time.NewTicker(job.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := job.RunOnce(ctx); err != nil {
+			slog.ErrorContext(ctx, "cannot re-encrypt personal data", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce re-encrypts every record not yet on the active master key. It
+// returns how many records were re-encrypted.
+func (job *Job) RunOnce(ctx context.Context) (int, error) {
+	var total int
+	for _, batch := range []func(context.Context) (int, error){job.reencryptUsers, job.reencryptKYCSubmissions} {
+		for {
+			reencrypted, err := batch(ctx)
+			total += reencrypted
+			if err != nil {
+				return total, err
+			}
+			if reencrypted < int(job.config.BatchSize) {
+				break
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// reencryptUsers re-encrypts up to BatchSize users in one transaction
+func (job *Job) reencryptUsers(ctx context.Context) (int, error) {
+	var reencrypted int
+	err := job.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		reencrypted = 0
+
+		users, err := q.ListUsersToReencrypt(ctx, db.ListUsersToReencryptParams{
+			MasterKeyID: job.envelope.Keyring().ActiveID(),
+			BatchSize:   job.config.BatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list users to re-encrypt: %w", err)
+		}
+
+		for _, user := range users {
+			if err := db.ReencryptUser(ctx, q, job.envelope, user); err != nil {
+				return err
+			}
+		}
+
+		reencrypted = len(users)
+		return nil
+	})
+
+	return reencrypted, err
+}
+
+// reencryptKYCSubmissions re-encrypts up to BatchSize KYC submissions in one
+// transaction
+func (job *Job) reencryptKYCSubmissions(ctx context.Context) (int, error) {
+	var reencrypted int
+	err := job.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		reencrypted = 0
+
+		submissions, err := q.ListKYCSubmissionsToReencrypt(ctx, db.ListKYCSubmissionsToReencryptParams{
+			MasterKeyID: job.envelope.Keyring().ActiveID(),
+			BatchSize:   job.config.BatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list KYC submissions to re-encrypt: %w", err)
+		}
+
+		for _, submission := range submissions {
+			if err := db.ReencryptKYCSubmission(ctx, q, job.envelope, submission); err != nil {
+				return err
+			}
+		}
+
+		reencrypted = len(submissions)
+		return nil
+	})
+
+	return reencrypted, err
+}
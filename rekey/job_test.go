@@ -0,0 +1,82 @@
+package rekey
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/crypt"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJobRunOnce(t *testing.T) {
+	oldKey, newKey := []byte(util.RandomString(32)), []byte(util.RandomString(32))
+	keyring, err := crypt.NewKeyring(newKey, oldKey)
+	require.NoError(t, err)
+	envelope, err := crypt.NewEnvelope(keyring, []byte(util.RandomString(32)))
+	require.NoError(t, err)
+
+	oldKeyring, err := crypt.NewKeyring(oldKey)
+	require.NoError(t, err)
+	oldEnvelope, err := crypt.NewEnvelope(oldKeyring, []byte(util.RandomString(32)))
+	require.NoError(t, err)
+	dataKey, err := oldEnvelope.NewDataKey()
+	require.NoError(t, err)
+
+	users := []db.User{
+		{Username: "alice", FullName: "Alice Example", Email: "alice@example.com"},
+		{Username: "bob", EmailSealed: []byte("sealed"), DataKey: dataKey.Wrapped, MasterKeyID: dataKey.MasterKeyID},
+	}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, _ db.TxOptions, fn func(db.Querier) error) error {
+			return fn(store)
+		})
+	store.EXPECT().
+		ListUsersToReencrypt(gomock.Any(), gomock.Eq(db.ListUsersToReencryptParams{MasterKeyID: keyring.ActiveID(), BatchSize: 2})).
+		Times(1).
+		Return(users, nil)
+	store.EXPECT().
+		ListUsersToReencrypt(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.User{}, nil)
+	store.EXPECT().
+		ListKYCSubmissionsToReencrypt(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.KycSubmission{}, nil)
+
+	var updates []db.UpdateUserEncryptionParams
+	store.EXPECT().
+		UpdateUserEncryption(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, arg db.UpdateUserEncryptionParams) (db.User, error) {
+			updates = append(updates, arg)
+			return db.User{}, nil
+		})
+
+	job := NewJob(store, envelope, JobConfig{BatchSize: 2})
+	reencrypted, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, reencrypted)
+
+	require.Len(t, updates, 2)
+	for _, update := range updates {
+		require.Equal(t, keyring.ActiveID(), update.MasterKeyID)
+	}
+
+	// the plaintext user is encrypted and indexed
+	require.Equal(t, "alice", updates[0].Username)
+	require.NotContains(t, string(updates[0].EmailSealed), "alice@example.com")
+	require.Equal(t, envelope.BlindIndex("alice@example.com"), updates[0].EmailIndex)
+
+	// the encrypted user only has its data key rewrapped
+	require.Equal(t, "bob", updates[1].Username)
+	require.Equal(t, []byte("sealed"), updates[1].EmailSealed)
+	require.NotEqual(t, dataKey.Wrapped, updates[1].DataKey)
+}
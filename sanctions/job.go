@@ -0,0 +1,139 @@
+package sanctions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Actor is recorded in the audit log for screening status changes
+const Actor = "sanctions"
+
+// JobConfig controls how often the list file is checked for changes and
+// how many users are screened per transaction
+type JobConfig struct {
+	Interval  time.Duration
+	BatchSize int32
+}
+
+// DefaultJobConfig is used for zero fields of the config given to NewJob
+var DefaultJobConfig = JobConfig{
+	Interval:  time.Hour,
+	BatchSize: 100,
+}
+
+// Job screens existing users again whenever the list changes
+type Job struct {
+	store    db.Store
+	screener *Screener
+	config   JobConfig
+}
+
+// NewJob creates a screening job for the users of store
+func NewJob(store db.Store, screener *Screener, config JobConfig) *Job {
+	if config.Interval <= 0 {
+		config.Interval = DefaultJobConfig.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultJobConfig.BatchSize
+	}
+
+	return &Job{
+		store:    store,
+		screener: screener,
+		config:   config,
+	}
+}
+
+// Run screens users every Interval until ctx is cancelled
+func (job *Job) Run(ctx context.Context) error {
+	ticker := time.NewTicker(job.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := job.RunOnce(ctx); err != nil {
+			slog.ErrorContext(ctx, "cannot screen users", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce reads the list again if its file changed and screens every user
+// not yet screened against its current version. It returns how many users
+// were screened.
+func (job *Job) RunOnce(ctx context.Context) (int, error) {
+	if _, err := job.screener.Refresh(); err != nil {
+		return 0, err
+	}
+
+	list := job.screener.List()
+	if list == nil {
+		return 0, nil
+	}
+
+	var total int
+	for {
+		screened, err := job.screenBatch(ctx, list)
+		total += screened
+		if err != nil {
+			return total, err
+		}
+		if screened < int(job.config.BatchSize) {
+			return total, nil
+		}
+	}
+}
+
+// screenBatch screens up to BatchSize users against list in one transaction
+func (job *Job) screenBatch(ctx context.Context, list *List) (int, error) {
+	var screened int
+	err := job.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		screened = 0
+
+		users, err := q.ListUsersToScreen(ctx, db.ListUsersToScreenParams{
+			ListVersion: list.Version,
+			BatchSize:   job.config.BatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list users to screen: %w", err)
+		}
+
+		for _, user := range users {
+			result := job.screener.ScreenWith(list, user.FullName)
+			if _, err := Record(ctx, q, user.Username, result); err != nil {
+				return err
+			}
+
+			if result.Status != user.ScreeningStatus {
+				_, err := audit.Record(ctx, q, audit.Event{
+					Actor:        Actor,
+					Action:       audit.ActionUserScreened,
+					ResourceType: audit.ResourceUser,
+					ResourceID:   user.Username,
+					Diff: map[string]any{
+						"before": map[string]any{"screening_status": user.ScreeningStatus},
+						"after":  map[string]any{"screening_status": result.Status},
+						"match":  result.Match,
+					},
+				})
+				if err != nil {
+					return fmt.Errorf("cannot record audit event: %w", err)
+				}
+			}
+		}
+
+		screened = len(users)
+		return nil
+	})
+
+	return screened, err
+}
@@ -0,0 +1,89 @@
+package sanctions
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJobRunOnce(t *testing.T) {
+	watchlist, err := OpenWatchlist("testdata/sdn.csv")
+	require.NoError(t, err)
+	screener, err := NewScreener(watchlist, Config{})
+	require.NoError(t, err)
+	version := watchlist.List().Version
+
+	users := []db.User{
+		{Username: "ivan", FullName: "Ivan Petrovsky", ScreeningStatus: StatusClear},
+		{Username: "jane", FullName: "Jane Doe", ScreeningStatus: StatusClear},
+	}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, _ db.TxOptions, fn func(db.Querier) error) error {
+			return fn(store)
+		})
+	store.EXPECT().
+		ListUsersToScreen(gomock.Any(), gomock.Eq(db.ListUsersToScreenParams{ListVersion: version, BatchSize: 2})).
+		Times(1).
+		Return(users, nil)
+	store.EXPECT().
+		ListUsersToScreen(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.User{}, nil)
+
+	var screenings []db.CreateUserScreeningParams
+	store.EXPECT().
+		CreateUserScreening(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, arg db.CreateUserScreeningParams) (db.UserScreening, error) {
+			screenings = append(screenings, arg)
+			return db.UserScreening{}, nil
+		})
+	store.EXPECT().UpdateUserScreening(gomock.Any(), gomock.Any()).Times(2).Return(db.User{}, nil)
+
+	// only the status change of ivan is audited
+	store.EXPECT().LockAuditChain(gomock.Any()).Times(1).Return(nil)
+	store.EXPECT().GetLastAuditEvent(gomock.Any()).Times(1).Return(db.AuditEvent{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+			require.Equal(t, Actor, arg.Actor)
+			require.Equal(t, audit.ActionUserScreened, arg.Action)
+			require.Equal(t, "ivan", arg.ResourceID)
+			return db.AuditEvent{}, nil
+		})
+
+	job := NewJob(store, screener, JobConfig{BatchSize: 2})
+	screened, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, screened)
+
+	require.Len(t, screenings, 2)
+	require.Equal(t, StatusFlagged, screenings[0].Status)
+	require.Equal(t, "1001", screenings[0].MatchedUid)
+	require.Equal(t, StatusClear, screenings[1].Status)
+	require.Equal(t, version, screenings[1].ListVersion)
+}
+
+func TestRecordBlocksSessions(t *testing.T) {
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().CreateUserScreening(gomock.Any(), gomock.Any()).Times(2).Return(db.UserScreening{}, nil)
+	store.EXPECT().UpdateUserScreening(gomock.Any(), gomock.Any()).Times(2).Return(db.User{}, nil)
+	store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Eq("ivan")).Times(1).Return(nil)
+
+	_, err := Record(context.Background(), store, "ivan", Result{Status: StatusBlocked})
+	require.NoError(t, err)
+
+	_, err = Record(context.Background(), store, "jane", Result{Status: StatusFlagged})
+	require.NoError(t, err)
+}
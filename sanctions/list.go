@@ -0,0 +1,210 @@
+// Package sanctions screens the names of users against a sanctions list in
+// the shape of the OFAC SDN list. Names are compared after normalization
+// with a fuzzy similarity so that spelling variants and reordered names are
+// still caught.
+package sanctions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Entry is a listed person or organization with all the names it is known by
+type Entry struct {
+	UID      string
+	Type     string
+	Programs []string
+	// Names holds the primary name first, then the aliases
+	Names []string
+}
+
+// List is a parsed sanctions list. Version identifies its content, so that
+// users screened against one version can be screened again when it changes.
+type List struct {
+	Version string
+	Entries []Entry
+	names   []listedName
+}
+
+// listedName is a name of an entry prepared for matching
+type listedName struct {
+	entry  int
+	name   string
+	tokens []string
+}
+
+// NewList prepares entries for matching
+func NewList(version string, entries []Entry) *List {
+	list := &List{Version: version, Entries: entries}
+	for i, entry := range entries {
+		for _, name := range entry.Names {
+			if tokens := Tokens(name); len(tokens) > 0 {
+				list.names = append(list.names, listedName{entry: i, name: name, tokens: tokens})
+			}
+		}
+	}
+
+	return list
+}
+
+// LoadFile reads the list at path, in SDN CSV format if its extension is
+// .csv and in SDN XML format if it is .xml. The version is the SHA-256 of
+// the file.
+func LoadFile(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read sanctions list: %w", err)
+	}
+
+	var entries []Entry
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		entries, err = ParseCSV(bytes.NewReader(data))
+	case ".xml":
+		entries, err = ParseXML(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported sanctions list format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse sanctions list %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(data)
+	return NewList(hex.EncodeToString(sum[:]), entries), nil
+}
+
+// akaPattern finds the aliases given in the remarks of the SDN CSV format,
+// such as a.k.a. 'JOHN SMITH'
+var akaPattern = regexp.MustCompile(`a\.k\.a\. '([^']+)'`)
+
+// ParseCSV reads the SDN CSV format, whose columns are ent_num, SDN_Name,
+// SDN_Type, Program, Title, Call_Sign, Vess_type, Tonnage, GRT, Vess_flag,
+// Vess_owner and Remarks, with -0- for empty values. A header row is skipped.
+func ParseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var entries []Entry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the published file ends with a lone end-of-file character
+		if len(record) < 4 || (line == 1 && strings.EqualFold(record[0], "ent_num")) {
+			continue
+		}
+
+		entry := Entry{
+			UID:      csvValue(record[0]),
+			Type:     csvValue(record[2]),
+			Programs: splitPrograms(csvValue(record[3])),
+			Names:    []string{csvValue(record[1])},
+		}
+		if entry.UID == "" || entry.Names[0] == "" {
+			return nil, fmt.Errorf("line %d: ent_num and SDN_Name are required", line)
+		}
+
+		if len(record) >= 12 {
+			for _, match := range akaPattern.FindAllStringSubmatch(record[11], -1) {
+				entry.Names = append(entry.Names, match[1])
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+}
+
+func csvValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "-0-" {
+		return ""
+	}
+
+	return value
+}
+
+// splitPrograms reads programs listed as SDGT] [IRGC
+func splitPrograms(value string) []string {
+	var programs []string
+	for _, program := range strings.Split(value, "] [") {
+		if program = strings.Trim(program, "[] "); program != "" {
+			programs = append(programs, program)
+		}
+	}
+
+	return programs
+}
+
+type xmlList struct {
+	Entries []xmlEntry `xml:"sdnEntry"`
+}
+
+type xmlName struct {
+	FirstName string `xml:"firstName"`
+	LastName  string `xml:"lastName"`
+}
+
+// String renders the name as the CSV format does, such as SMITH, John
+func (name xmlName) String() string {
+	if name.FirstName == "" {
+		return name.LastName
+	}
+
+	return name.LastName + ", " + name.FirstName
+}
+
+type xmlEntry struct {
+	UID string `xml:"uid"`
+	xmlName
+	Type     string    `xml:"sdnType"`
+	Programs []string  `xml:"programList>program"`
+	AKAs     []xmlName `xml:"akaList>aka"`
+}
+
+// ParseXML reads the SDN XML format, an sdnList of sdnEntry elements with
+// their uid, names, sdnType, programList and akaList
+func ParseXML(r io.Reader) ([]Entry, error) {
+	var list xmlList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(list.Entries))
+	for _, xmlEntry := range list.Entries {
+		entry := Entry{
+			UID:      strings.TrimSpace(xmlEntry.UID),
+			Type:     xmlEntry.Type,
+			Programs: xmlEntry.Programs,
+			Names:    []string{xmlEntry.String()},
+		}
+		if entry.UID == "" || xmlEntry.LastName == "" {
+			return nil, errors.New("sdnEntry needs a uid and a lastName")
+		}
+
+		for _, aka := range xmlEntry.AKAs {
+			if aka.LastName != "" {
+				entry.Names = append(entry.Names, aka.String())
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,147 @@
+package sanctions
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Match is the listed name closest to a screened name
+type Match struct {
+	UID      string   `json:"uid"`
+	Name     string   `json:"name"`
+	Programs []string `json:"programs"`
+	// Score is the similarity of both names, from 0 to 1
+	Score float64 `json:"score"`
+}
+
+// Best returns the listed name most similar to name. The zero Match is
+// returned for an empty list.
+func (list *List) Best(name string) Match {
+	tokens := Tokens(name)
+
+	var best Match
+	for _, listed := range list.names {
+		score := similarity(tokens, listed.tokens)
+		if score > best.Score {
+			entry := list.Entries[listed.entry]
+			best = Match{UID: entry.UID, Name: listed.name, Programs: entry.Programs, Score: score}
+		}
+	}
+
+	return best
+}
+
+// Similarity scores how alike two names are, from 0 for nothing in common
+// to 1 for the same name after normalization
+func Similarity(a, b string) float64 {
+	return similarity(Tokens(a), Tokens(b))
+}
+
+// similarity compares the tokens of two names in sorted order, so that
+// SMITH, John and John Smith are the same name. Names of two or more tokens
+// that are all found in the other name, such as John Smith in John Michael
+// Smith, are scored on those tokens alone.
+func similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	score := jaroWinkler(strings.Join(a, " "), strings.Join(b, " "))
+
+	shorter, longer := a, b
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+	if len(shorter) >= 2 && len(shorter) < len(longer) {
+		var sum float64
+		for _, token := range shorter {
+			var best float64
+			for _, other := range longer {
+				best = max(best, jaroWinkler(token, other))
+			}
+			sum += best
+		}
+		score = max(score, sum/float64(len(shorter)))
+	}
+
+	return score
+}
+
+// fold strips accents, so that José and Jose are the same token
+var fold = transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// Tokens normalizes a name into its lower case words in sorted order,
+// without accents or punctuation
+func Tokens(name string) []string {
+	folded, _, err := transform.String(fold, name)
+	if err != nil {
+		folded = name
+	}
+
+	tokens := strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(tokens)
+
+	return tokens
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of a and b, which favours
+// strings sharing a prefix
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	window = max(window, 0)
+
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	var matches int
+	for i := range s1 {
+		for j := max(0, i-window); j < min(len(s2), i+window+1); j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	var transpositions, j int
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	var prefix int
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,136 @@
+package sanctions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	list, err := LoadFile("testdata/sdn.csv")
+	require.NoError(t, err)
+	require.Len(t, list.Version, 64)
+
+	require.Equal(t, []Entry{
+		{UID: "36", Programs: []string{"CUBA"}, Names: []string{"AEROCARIBBEAN AIRLINES"}},
+		{
+			UID:      "1001",
+			Type:     "individual",
+			Programs: []string{"SDGT", "IRGC"},
+			Names:    []string{"PETROVSKY, Ivan Sergeyevich", "PETROVSKI, Ivan", "IVANOV, Sergei"},
+		},
+		{UID: "1002", Type: "individual", Programs: []string{"SDNTK"}, Names: []string{"MÜLLER, Jürgen"}},
+	}, list.Entries)
+}
+
+func TestParseXML(t *testing.T) {
+	list, err := LoadFile("testdata/sdn.xml")
+	require.NoError(t, err)
+
+	require.Equal(t, []Entry{
+		{
+			UID:      "1001",
+			Type:     "Individual",
+			Programs: []string{"SDGT", "IRGC"},
+			Names:    []string{"PETROVSKY, Ivan Sergeyevich", "PETROVSKI, Ivan"},
+		},
+		{UID: "36", Type: "Entity", Programs: []string{"CUBA"}, Names: []string{"AEROCARIBBEAN AIRLINES"}},
+	}, list.Entries)
+
+	_, err = LoadFile("testdata/missing.json")
+	require.Error(t, err)
+}
+
+func TestTokens(t *testing.T) {
+	require.Equal(t, []string{"jurgen", "muller"}, Tokens("MÜLLER, Jürgen"))
+	require.Equal(t, []string{"jean", "luc", "o", "reilly"}, Tokens("  Jean-Luc O'Reilly "))
+	require.Empty(t, Tokens("-- ,"))
+}
+
+func TestSimilarity(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{a: "Jurgen Muller", b: "MÜLLER, Jürgen", min: 1, max: 1},
+		{a: "Ivan Petrovsky", b: "PETROVSKI, Ivan", min: 0.95, max: 0.99},
+		{a: "Ivan Sergeyevich Petrovsky", b: "PETROVSKY, Ivan", min: 1, max: 1},
+		{a: "Jurgen Mueller", b: "MÜLLER, Jürgen", min: 0.95, max: 0.99},
+		{a: "Ivana Peterson", b: "PETROVSKY, Ivan Sergeyevich", min: 0, max: 0.9},
+		{a: "John Smith", b: "AEROCARIBBEAN AIRLINES", min: 0, max: 0.6},
+		{a: "", b: "AEROCARIBBEAN AIRLINES", min: 0, max: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a, func(t *testing.T) {
+			score := Similarity(tc.a, tc.b)
+			require.GreaterOrEqual(t, score, tc.min)
+			require.LessOrEqual(t, score, tc.max)
+			require.Equal(t, score, Similarity(tc.b, tc.a))
+		})
+	}
+}
+
+func TestScreener(t *testing.T) {
+	watchlist, err := OpenWatchlist("testdata/sdn.csv")
+	require.NoError(t, err)
+
+	screener, err := NewScreener(watchlist, Config{})
+	require.NoError(t, err)
+
+	result := screener.ScreenWith(watchlist.List(), "Ivan Petrovski")
+	require.Equal(t, StatusFlagged, result.Status)
+	require.Equal(t, "1001", result.Match.UID)
+	require.Equal(t, "PETROVSKI, Ivan", result.Match.Name)
+	require.Equal(t, watchlist.List().Version, result.ListVersion)
+
+	result = screener.ScreenWith(watchlist.List(), "Jane Doe")
+	require.Equal(t, StatusClear, result.Status)
+
+	blocking, err := NewScreener(watchlist, Config{Threshold: 0.99, Action: ActionBlock})
+	require.NoError(t, err)
+	require.Equal(t, StatusBlocked, blocking.ScreenWith(watchlist.List(), "Jurgen Muller").Status)
+	require.Equal(t, StatusClear, blocking.ScreenWith(watchlist.List(), "Jurgen Mueller").Status)
+
+	var none *Screener
+	require.Equal(t, StatusUnscreened, none.ScreenWith(nil, "Jurgen Muller").Status)
+
+	_, err = NewScreener(watchlist, Config{Action: "report"})
+	require.Error(t, err)
+	_, err = NewScreener(watchlist, Config{Threshold: 1.5})
+	require.Error(t, err)
+}
+
+func TestWatchlistRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sdn.csv")
+	require.NoError(t, os.WriteFile(path, []byte(`1,"DOE, John","individual","SDGT"`+"\n"), 0o600))
+
+	watchlist, err := OpenWatchlist(path)
+	require.NoError(t, err)
+	version := watchlist.List().Version
+
+	changed, err := watchlist.Refresh()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	require.NoError(t, os.WriteFile(path, []byte(`1,"DOE, Jane","individual","SDGT"`+"\n"), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+
+	changed, err = watchlist.Refresh()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.NotEqual(t, version, watchlist.List().Version)
+	require.Equal(t, "DOE, Jane", watchlist.List().Entries[0].Names[0])
+
+	// a broken file keeps the last list
+	require.NoError(t, os.WriteFile(path, []byte(`,"",individual,SDGT`), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+
+	_, err = watchlist.Refresh()
+	require.Error(t, err)
+	require.Equal(t, "DOE, Jane", watchlist.List().Entries[0].Names[0])
+}
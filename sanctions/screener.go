@@ -0,0 +1,211 @@
+package sanctions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Screening statuses recorded on users
+const (
+	StatusUnscreened = "unscreened"
+	StatusClear      = "clear"
+	StatusFlagged    = "flagged"
+	StatusBlocked    = "blocked"
+)
+
+// Actions taken on a name matching the list
+const (
+	ActionFlag  = "flag"
+	ActionBlock = "block"
+)
+
+// Config tunes how close a name must be to a listed one to match, and what
+// happens to users who match
+type Config struct {
+	Threshold float64
+	Action    string
+}
+
+// DefaultConfig is used for zero fields of the config given to NewScreener
+var DefaultConfig = Config{
+	Threshold: 0.9,
+	Action:    ActionFlag,
+}
+
+// Watchlist holds the list read from a file and reads it again when the
+// file changes
+type Watchlist struct {
+	path    string
+	mu      sync.Mutex
+	list    *List
+	modTime time.Time
+	size    int64
+}
+
+// OpenWatchlist reads the list at path
+func OpenWatchlist(path string) (*Watchlist, error) {
+	watchlist := &Watchlist{path: path}
+	if _, err := watchlist.Refresh(); err != nil {
+		return nil, err
+	}
+
+	return watchlist, nil
+}
+
+// List returns the list last read
+func (watchlist *Watchlist) List() *List {
+	watchlist.mu.Lock()
+	defer watchlist.mu.Unlock()
+
+	return watchlist.list
+}
+
+// Refresh reads the file again if its modification time or size changed
+// and reports whether the version of the list changed. The last list read
+// is kept if the file cannot be read.
+func (watchlist *Watchlist) Refresh() (bool, error) {
+	info, err := os.Stat(watchlist.path)
+	if err != nil {
+		return false, fmt.Errorf("cannot stat sanctions list: %w", err)
+	}
+
+	watchlist.mu.Lock()
+	defer watchlist.mu.Unlock()
+
+	if watchlist.list != nil && info.ModTime().Equal(watchlist.modTime) && info.Size() == watchlist.size {
+		return false, nil
+	}
+
+	list, err := LoadFile(watchlist.path)
+	if err != nil {
+		return false, err
+	}
+
+	changed := watchlist.list == nil || watchlist.list.Version != list.Version
+	watchlist.list = list
+	watchlist.modTime = info.ModTime()
+	watchlist.size = info.Size()
+
+	return changed, nil
+}
+
+// Result is the outcome of screening a name against a version of the list
+type Result struct {
+	Status      string
+	ListVersion string
+	Match       Match
+}
+
+// Screener screens names against a watchlist. A nil Screener leaves every
+// name unscreened.
+type Screener struct {
+	watchlist *Watchlist
+	config    Config
+}
+
+// NewScreener creates a screener of watchlist
+func NewScreener(watchlist *Watchlist, config Config) (*Screener, error) {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultConfig.Threshold
+	}
+	if config.Action == "" {
+		config.Action = DefaultConfig.Action
+	}
+	if config.Threshold > 1 {
+		return nil, fmt.Errorf("sanctions threshold must be at most 1, got %v", config.Threshold)
+	}
+	if config.Action != ActionFlag && config.Action != ActionBlock {
+		return nil, fmt.Errorf("sanctions action must be %s or %s, got %q", ActionFlag, ActionBlock, config.Action)
+	}
+
+	return &Screener{watchlist: watchlist, config: config}, nil
+}
+
+// Refresh reads the list again if its file changed, see Watchlist.Refresh
+func (screener *Screener) Refresh() (bool, error) {
+	if screener == nil {
+		return false, nil
+	}
+
+	return screener.watchlist.Refresh()
+}
+
+// List returns the list names are screened against, nil for a nil Screener
+func (screener *Screener) List() *List {
+	if screener == nil {
+		return nil
+	}
+
+	return screener.watchlist.List()
+}
+
+// Screen checks name against the latest list. A failure to read a changed
+// list is logged and the previous list is used.
+func (screener *Screener) Screen(ctx context.Context, name string) Result {
+	if _, err := screener.Refresh(); err != nil {
+		slog.WarnContext(ctx, "cannot refresh sanctions list", slog.String("error", err.Error()))
+	}
+
+	return screener.ScreenWith(screener.List(), name)
+}
+
+// ScreenWith checks name against list
+func (screener *Screener) ScreenWith(list *List, name string) Result {
+	if screener == nil || list == nil {
+		return Result{Status: StatusUnscreened}
+	}
+
+	result := Result{
+		Status:      StatusClear,
+		ListVersion: list.Version,
+		Match:       list.Best(name),
+	}
+	if result.Match.Score >= screener.config.Threshold {
+		result.Status = StatusFlagged
+		if screener.config.Action == ActionBlock {
+			result.Status = StatusBlocked
+		}
+	}
+
+	return result
+}
+
+// Record stores result as the screening status of username and appends it
+// to the screening history of the user. Blocking a user also blocks their
+// sessions.
+func Record(ctx context.Context, q db.Querier, username string, result Result) (db.User, error) {
+	_, err := q.CreateUserScreening(ctx, db.CreateUserScreeningParams{
+		Username:    username,
+		ListVersion: result.ListVersion,
+		Status:      result.Status,
+		Score:       result.Match.Score,
+		MatchedUid:  result.Match.UID,
+		MatchedName: result.Match.Name,
+	})
+	if err != nil {
+		return db.User{}, fmt.Errorf("cannot record screening of %s: %w", username, err)
+	}
+
+	user, err := q.UpdateUserScreening(ctx, db.UpdateUserScreeningParams{
+		Username:             username,
+		ScreeningStatus:      result.Status,
+		ScreeningListVersion: result.ListVersion,
+	})
+	if err != nil {
+		return db.User{}, fmt.Errorf("cannot update screening status of %s: %w", username, err)
+	}
+
+	if result.Status == StatusBlocked {
+		if err := q.BlockUserSessions(ctx, username); err != nil {
+			return db.User{}, fmt.Errorf("cannot block sessions of %s: %w", username, err)
+		}
+	}
+
+	return user, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/techschool/simplebank/accountnumber"
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/outbox"
+)
+
+// DefaultProduct is the product of accounts opened without one
+const DefaultProduct = "checking"
+
+// accountNumberAttempts bounds how often CreateAccount draws another account
+// number after drawing one that is taken
+const accountNumberAttempts = 3
+
+// CreateAccountParams contains the owner, currency and product of a new account
+type CreateAccountParams struct {
+	Owner    string
+	Currency string
+	// Product is the code of the account product, DefaultProduct if empty
+	Product string
+}
+
+// CreateAccount opens a new account with a zero balance under a random
+// public account number. An owner holds at most one account of each product
+// and currency, and only in the currencies their KYC tier allows. Users
+// blocked by sanctions screening cannot open accounts.
+func (service *Service) CreateAccount(ctx context.Context, arg CreateAccountParams) (db.Account, error) {
+	if arg.Product == "" {
+		arg.Product = DefaultProduct
+	}
+
+	var account db.Account
+	var err error
+	for attempt := 0; attempt < accountNumberAttempts; attempt++ {
+		account, err = service.createAccount(ctx, arg)
+		if pqConstraintName(err) != "accounts_number_key" {
+			break
+		}
+	}
+	if err != nil {
+		switch pqErrorName(err) {
+		case "unique_violation":
+			if pqConstraintName(err) == "accounts_number_key" {
+				return db.Account{}, fmt.Errorf("cannot draw a free account number: %w", err)
+			}
+			return db.Account{}, ErrAccountAlreadyExists.
+				WithDetail("currency", arg.Currency).
+				WithDetail("product", arg.Product)
+		case "foreign_key_violation":
+			return db.Account{}, ErrUserNotFound
+		}
+		var tierErr *db.KYCTierError
+		var blockedErr *db.UserBlockedError
+		switch {
+		case errors.Is(err, ErrProductNotFound):
+			return db.Account{}, err
+		case errors.As(err, &tierErr):
+			return db.Account{}, kycTierRequiredError(tierErr)
+		case errors.As(err, &blockedErr):
+			return db.Account{}, ErrUserBlocked
+		case errors.Is(err, sql.ErrNoRows):
+			return db.Account{}, ErrUserNotFound
+		}
+		return db.Account{}, fmt.Errorf("cannot create account: %w", err)
+	}
+
+	return account, nil
+}
+
+// createAccount opens an account under a newly drawn account number
+func (service *Service) createAccount(ctx context.Context, arg CreateAccountParams) (db.Account, error) {
+	owner := arg.Owner
+
+	number, err := accountnumber.Generate()
+	if err != nil {
+		return db.Account{}, err
+	}
+
+	var account db.Account
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		if _, err := q.GetProduct(ctx, arg.Product); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrProductNotFound.WithDetail("product", arg.Product)
+			}
+			return fmt.Errorf("cannot get product: %w", err)
+		}
+
+		if err := db.CheckNotBlocked(ctx, q, owner); err != nil {
+			return err
+		}
+
+		if err := db.CheckKYCTier(ctx, q, owner, arg.Currency); err != nil {
+			return err
+		}
+
+		var err error
+		account, err = q.CreateAccount(ctx, db.CreateAccountParams{
+			Owner:       owner,
+			Currency:    arg.Currency,
+			Balance:     0,
+			ProductCode: arg.Product,
+			Number:      number,
+		})
+		if err != nil {
+			return err
+		}
+
+		accountID := strconv.FormatInt(account.ID, 10)
+		err = record(ctx, q, audit.Event{
+			Actor:        owner,
+			Action:       audit.ActionAccountCreated,
+			ResourceType: audit.ResourceAccount,
+			ResourceID:   accountID,
+			Diff:         map[string]any{"after": account},
+		})
+		if err != nil {
+			return err
+		}
+
+		return outbox.Enqueue(ctx, q, outbox.AccountCreated, outbox.AggregateAccount, accountID, account)
+	})
+
+	return account, err
+}
+
+// GetAccount returns the account with the given public number if it
+// belongs to owner
+func (service *Service) GetAccount(ctx context.Context, owner string, number string) (db.Account, error) {
+	account, err := service.getAccountByNumber(ctx, number)
+	if err != nil {
+		return db.Account{}, err
+	}
+
+	if account.Owner != owner {
+		return db.Account{}, ErrAccountNotOwned.WithDetail("account_number", account.Number)
+	}
+
+	return account, nil
+}
+
+// ListAccountsParams contains the owner and page to list accounts for
+type ListAccountsParams struct {
+	Owner    string
+	PageID   int32
+	PageSize int32
+}
+
+// ListAccounts returns a page of the accounts belonging to owner
+func (service *Service) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]db.Account, error) {
+	accounts, err := service.store.ListAccounts(ctx, db.ListAccountsParams{
+		Owner:  arg.Owner,
+		Limit:  arg.PageSize,
+		Offset: (arg.PageID - 1) * arg.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+func (service *Service) getAccount(ctx context.Context, id int64) (db.Account, error) {
+	account, err := service.store.GetAccount(ctx, id)
+	if err != nil {
+		return db.Account{}, accountError(err)
+	}
+
+	return account, nil
+}
+
+// getAccountByNumber returns the account with a public account number.
+// Numbers with wrong check digits are not looked up.
+func (service *Service) getAccountByNumber(ctx context.Context, number string) (db.Account, error) {
+	return accountByNumber(ctx, service.store, number)
+}
+
+// accountByNumber reads the account with a public account number through q
+func accountByNumber(ctx context.Context, q db.Querier, number string) (db.Account, error) {
+	number = accountnumber.Normalize(number)
+	if accountnumber.Validate(number) != nil {
+		return db.Account{}, ErrAccountNotFound.WithDetail("account_number", number)
+	}
+
+	account, err := q.GetAccountByNumber(ctx, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Account{}, ErrAccountNotFound.WithDetail("account_number", number)
+		}
+		return db.Account{}, fmt.Errorf("cannot get account: %w", err)
+	}
+
+	return account, nil
+}
+
+// accountError maps an error from reading an account by its internal id to
+// a domain error. The id is never shown to clients, so it is left out.
+func accountError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAccountNotFound
+	}
+
+	return fmt.Errorf("cannot get account: %w", err)
+}
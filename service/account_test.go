@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/accountnumber"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/kyc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateAccountNumberTaken(t *testing.T) {
+	user, _ := randomUser(t)
+	numberTaken := &pq.Error{Code: "23505", Constraint: "accounts_number_key"}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+		actions    []string
+	}{
+		{
+			name: "DrawsAgain",
+			buildStubs: func(store *mockdb.MockStore) {
+				var numbers []string
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAccountParams) (db.Account, error) {
+						numbers = append(numbers, arg.Number)
+						return db.Account{}, numberTaken
+					})
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAccountParams) (db.Account, error) {
+						require.NoError(t, accountnumber.Validate(arg.Number))
+						require.NotEqual(t, numbers[0], arg.Number)
+						return db.Account{ID: 1, Owner: arg.Owner, Currency: arg.Currency, Number: arg.Number}, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			actions: []string{audit.ActionAccountCreated},
+		},
+		{
+			name: "NoFreeNumber",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(accountNumberAttempts).
+					Return(db.Account{}, numberTaken)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrAccountAlreadyExists)
+			},
+			actions: []string{},
+		},
+		{
+			name: "AlreadyExists",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, &pq.Error{Code: "23505", Constraint: "owner_currency_product_key"})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrAccountAlreadyExists)
+			},
+			actions: []string{},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			store.EXPECT().GetProduct(gomock.Any(), gomock.Eq(DefaultProduct)).AnyTimes().Return(db.Product{Code: DefaultProduct}, nil)
+			store.EXPECT().IsUserBlocked(gomock.Any(), gomock.Eq(user.Username)).AnyTimes().Return(false, nil)
+			store.EXPECT().
+				GetKYCCapability(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(db.GetKYCCapabilityRow{KycTier: kyc.TierBasic, MinKycTier: kyc.TierBasic}, nil)
+			tc.buildStubs(store)
+			events := stubTx(store)
+
+			service := newTestService(t, store)
+			_, err := service.CreateAccount(context.Background(), CreateAccountParams{
+				Owner:    user.Username,
+				Currency: util.EUR,
+			})
+			tc.checkError(t, err)
+			require.Equal(t, tc.actions, auditActions(*events))
+		})
+	}
+}
+
+func TestGetAccountByNumber(t *testing.T) {
+	user, _ := randomUser(t)
+	account := db.Account{ID: 3, Owner: user.Username, Currency: util.EUR, Number: "SB12000000000007"}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(2).Return(account, nil)
+	service := newTestService(t, store)
+
+	found, err := service.GetAccount(context.Background(), user.Username, "sb12 0000 0000 0007")
+	require.NoError(t, err)
+	require.Equal(t, account, found)
+
+	_, err = service.GetAccount(context.Background(), util.RandomOwner(), account.Number)
+	require.ErrorIs(t, err, ErrAccountNotOwned)
+
+	// numbers with wrong check digits are never looked up
+	_, err = service.GetAccount(context.Background(), user.Username, "SB21000000000007")
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/val"
+)
+
+// DefaultAliasLookupLimit and DefaultAliasLookupWindow bound how many
+// recipients a user may resolve by alias when the config sets no limit
+const (
+	DefaultAliasLookupLimit  = 20
+	DefaultAliasLookupWindow = time.Hour
+)
+
+// Recipient is the account an alias resolves to, with the name of its owner
+// masked so that senders can confirm it without learning it
+type Recipient struct {
+	Alias      string
+	Currency   string
+	MaskedName string
+}
+
+// LookupRecipient resolves alias and currency for owner like a transfer to
+// the alias would, and returns the masked name of the recipient. Every call
+// counts against the alias lookup limit of owner.
+func (service *Service) LookupRecipient(ctx context.Context, owner string, alias string, currency string) (Recipient, error) {
+	if err := service.countAliasLookup(ctx, owner); err != nil {
+		return Recipient{}, err
+	}
+
+	account, user, err := service.resolveRecipient(ctx, alias, currency)
+	if err != nil {
+		return Recipient{}, err
+	}
+
+	return Recipient{
+		Alias:      alias,
+		Currency:   account.Currency,
+		MaskedName: maskName(user.FullName),
+	}, nil
+}
+
+// recipientAccount resolves alias and currency for a transfer or payee of
+// owner. Only aliases that do not resolve count against the alias lookup
+// limit of owner, but none are resolved once the limit is reached, so that
+// the alias space cannot be enumerated this way either.
+func (service *Service) recipientAccount(ctx context.Context, owner string, alias string, currency string) (db.Account, db.User, error) {
+	if err := service.checkAliasLookups(ctx, owner); err != nil {
+		return db.Account{}, db.User{}, err
+	}
+
+	account, user, err := service.resolveRecipient(ctx, alias, currency)
+	if errors.Is(err, ErrRecipientNotFound) {
+		if countErr := service.countAliasLookup(ctx, owner); countErr != nil {
+			return db.Account{}, db.User{}, countErr
+		}
+	}
+
+	return account, user, err
+}
+
+// resolveRecipient returns the DefaultProduct account in currency of the
+// user whose username, email or phone is alias. Users without such an
+// account are reported like unknown aliases.
+func (service *Service) resolveRecipient(ctx context.Context, alias string, currency string) (db.Account, db.User, error) {
+	notFound := ErrRecipientNotFound.WithDetail("alias", alias).WithDetail("currency", currency)
+
+	user, err := service.aliasUser(ctx, alias)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Account{}, db.User{}, notFound
+		}
+		return db.Account{}, db.User{}, fmt.Errorf("cannot get user by alias: %w", err)
+	}
+
+	account, err := service.store.GetAccountByOwner(ctx, db.GetAccountByOwnerParams{
+		Owner:       user.Username,
+		Currency:    currency,
+		ProductCode: DefaultProduct,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Account{}, db.User{}, notFound
+		}
+		return db.Account{}, db.User{}, fmt.Errorf("cannot get account: %w", err)
+	}
+
+	return account, user, nil
+}
+
+// aliasUser looks a user up by phone number, email or username, depending
+// on the form of alias
+func (service *Service) aliasUser(ctx context.Context, alias string) (db.User, error) {
+	switch {
+	case val.ValidatePhone(alias) == nil:
+		return service.store.GetUserByPhone(ctx, db.GetUserByPhoneParams{Phone: alias})
+	case strings.Contains(alias, "@"):
+		return service.store.GetUserByEmail(ctx, db.GetUserByEmailParams{Email: alias})
+	default:
+		return service.store.GetUser(ctx, alias)
+	}
+}
+
+// countAliasLookup counts a lookup by owner and fails with
+// ErrAliasLookupLimit once owner made too many in the current window
+func (service *Service) countAliasLookup(ctx context.Context, owner string) error {
+	limit, window := service.aliasLookupLimit()
+
+	lookup, err := service.store.CountAliasLookup(ctx, db.CountAliasLookupParams{
+		Username:      owner,
+		ExpiredBefore: time.Now().Add(-window),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot count alias lookup: %w", err)
+	}
+
+	if int(lookup.Lookups) > limit {
+		return aliasLookupLimitError(lookup, limit, window)
+	}
+
+	return nil
+}
+
+// checkAliasLookups fails with ErrAliasLookupLimit if owner used up the
+// lookups of the current window, without counting one
+func (service *Service) checkAliasLookups(ctx context.Context, owner string) error {
+	limit, window := service.aliasLookupLimit()
+
+	lookup, err := service.store.GetAliasLookup(ctx, owner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("cannot get alias lookups: %w", err)
+	}
+
+	if lookup.WindowStart.After(time.Now().Add(-window)) && int(lookup.Lookups) >= limit {
+		return aliasLookupLimitError(lookup, limit, window)
+	}
+
+	return nil
+}
+
+// aliasLookupLimit returns how many lookups a user may make per window
+func (service *Service) aliasLookupLimit() (int, time.Duration) {
+	limit := service.config.AliasLookupLimit
+	if limit <= 0 {
+		limit = DefaultAliasLookupLimit
+	}
+	window := service.config.AliasLookupWindow
+	if window <= 0 {
+		window = DefaultAliasLookupWindow
+	}
+
+	return limit, window
+}
+
+func aliasLookupLimitError(lookup db.AliasLookup, limit int, window time.Duration) error {
+	return ErrAliasLookupLimit.
+		WithDetail("limit", limit).
+		WithDetail("retry_after", lookup.WindowStart.Add(window).Format(time.RFC3339))
+}
+
+// maskName keeps the first letter of every word of a full name and hides
+// the rest, such as "J*** S****" for "John Smith"
+func maskName(fullName string) string {
+	words := strings.Fields(fullName)
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(first) + strings.Repeat("*", utf8.RuneCountInString(word[size:]))
+	}
+
+	return strings.Join(words, " ")
+}
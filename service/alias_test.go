@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLookupRecipientLimit(t *testing.T) {
+	user, _ := randomUser(t)
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	service := newTestService(t, store)
+	service.config.AliasLookupLimit = 2
+	service.config.AliasLookupWindow = 10 * time.Minute
+
+	windowStart := time.Now().Add(-time.Minute)
+	store.EXPECT().
+		CountAliasLookup(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CountAliasLookupParams) (db.AliasLookup, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.WithinDuration(t, time.Now().Add(-10*time.Minute), arg.ExpiredBefore, time.Minute)
+			return db.AliasLookup{Username: arg.Username, WindowStart: windowStart, Lookups: 3}, nil
+		})
+	store.EXPECT().GetUserByPhone(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.LookupRecipient(context.Background(), user.Username, util.RandomPhone(), util.EUR)
+	require.ErrorIs(t, err, ErrAliasLookupLimit)
+
+	var appErr *apperr.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, windowStart.Add(10*time.Minute).Format(time.RFC3339), appErr.Details["retry_after"])
+}
+
+func TestRecipientAccountLimit(t *testing.T) {
+	user, _ := randomUser(t)
+	alias := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name: "ResolvedIsFree",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAliasLookup(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.AliasLookup{}, sql.ErrNoRows)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(alias)).Times(1).Return(db.User{Username: alias}, nil)
+				store.EXPECT().GetAccountByOwner(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{ID: 2, Owner: alias}, nil)
+				store.EXPECT().CountAliasLookup(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "UnknownIsCounted",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAliasLookup(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.AliasLookup{}, sql.ErrNoRows)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(alias)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					CountAliasLookup(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AliasLookup{Username: user.Username, WindowStart: time.Now(), Lookups: 1}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrRecipientNotFound)
+			},
+		},
+		{
+			name: "LimitReached",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAliasLookup(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.AliasLookup{Username: user.Username, WindowStart: time.Now(), Lookups: 2}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CountAliasLookup(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrAliasLookupLimit)
+			},
+		},
+		{
+			name: "WindowExpired",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAliasLookup(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.AliasLookup{Username: user.Username, WindowStart: time.Now().Add(-time.Hour), Lookups: 2}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(alias)).Times(1).Return(db.User{Username: alias}, nil)
+				store.EXPECT().GetAccountByOwner(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{ID: 2, Owner: alias}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			tc.buildStubs(store)
+
+			service := newTestService(t, store)
+			service.config.AliasLookupLimit = 2
+			service.config.AliasLookupWindow = 10 * time.Minute
+
+			_, _, err := service.recipientAccount(context.Background(), user.Username, alias, util.EUR)
+			tc.checkError(t, err)
+		})
+	}
+}
+
+func TestMaskName(t *testing.T) {
+	require.Equal(t, "J*** S****", maskName("John Smith"))
+	require.Equal(t, "Z** O******", maskName("Zoë  O'Brien"))
+	require.Equal(t, "", maskName(""))
+}
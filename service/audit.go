@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// record appends event to the audit log using q, which should be the
+// transaction making the audited change
+func record(ctx context.Context, q db.Querier, event audit.Event) error {
+	if _, err := audit.Record(ctx, q, event); err != nil {
+		return fmt.Errorf("cannot record audit event: %w", err)
+	}
+
+	return nil
+}
+
+// recordTx appends event to the audit log in a transaction of its own, for
+// events that do not change any other data
+func (service *Service) recordTx(ctx context.Context, event audit.Event) error {
+	return service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		return record(ctx, q, event)
+	})
+}
+
+// ListAuditEventsParams filters the audit log. Zero values match everything.
+type ListAuditEventsParams struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+	PageID       int32
+	PageSize     int32
+}
+
+// ListAuditEvents returns a page of the audit log for an auditor. The query
+// itself is recorded.
+func (service *Service) ListAuditEvents(ctx context.Context, auditor string, arg ListAuditEventsParams) ([]db.AuditEvent, error) {
+	var events []db.AuditEvent
+
+	err := service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		events, err = q.ListAuditEvents(ctx, db.ListAuditEventsParams{
+			Actor:        nullString(arg.Actor),
+			Action:       nullString(arg.Action),
+			ResourceType: nullString(arg.ResourceType),
+			ResourceID:   nullString(arg.ResourceID),
+			FromTime:     nullTime(arg.From),
+			ToTime:       nullTime(arg.To),
+			Limit:        arg.PageSize,
+			Offset:       (arg.PageID - 1) * arg.PageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list audit events: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        auditor,
+			Action:       audit.ActionEventsListed,
+			ResourceType: audit.ResourceAudit,
+			Diff: map[string]any{
+				"actor":         arg.Actor,
+				"action":        arg.Action,
+				"resource_type": arg.ResourceType,
+				"resource_id":   arg.ResourceID,
+				"from":          arg.From,
+				"to":            arg.To,
+				"page_id":       arg.PageID,
+				"page_size":     arg.PageSize,
+				"returned":      len(events),
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// VerifyAuditChain checks the hash chain of the whole audit log
+func (service *Service) VerifyAuditChain(ctx context.Context, auditor string) (audit.VerifyResult, error) {
+	result, err := audit.Verify(ctx, service.store)
+	if err != nil {
+		return result, fmt.Errorf("cannot verify audit chain: %w", err)
+	}
+
+	err = service.recordTx(ctx, audit.Event{
+		Actor:        auditor,
+		Action:       audit.ActionChainVerified,
+		ResourceType: audit.ResourceAudit,
+		ResourceID:   strconv.FormatInt(result.LastID, 10),
+		Diff:         result,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// userDiff records user without its personal data, which is only ever
+// stored sealed in the users table
+func userDiff(user db.User) map[string]any {
+	return map[string]any{
+		"username":         user.Username,
+		"role":             user.Role,
+		"screening_status": user.ScreeningStatus,
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
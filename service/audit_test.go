@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"go.uber.org/mock/gomock"
+)
+
+// stubTx runs transactions directly against store, accepts any outbox row,
+// finds no webhook subscribers and collects the audit events written through them
+func stubTx(store *mockdb.MockStore) *[]db.CreateAuditEventParams {
+	var events []db.CreateAuditEventParams
+
+	store.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, _ db.TxOptions, fn func(db.Querier) error) error {
+			return fn(store)
+		})
+	store.EXPECT().
+		StatementTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, fn func(db.StatementQuerier) error) error {
+			return fn(store)
+		})
+	store.EXPECT().LockAuditChain(gomock.Any()).AnyTimes().Return(nil)
+	store.EXPECT().GetLastAuditEvent(gomock.Any()).AnyTimes().Return(db.AuditEvent{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
+			events = append(events, arg)
+			return db.AuditEvent{Action: arg.Action, Hash: arg.Hash}, nil
+		})
+	store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).AnyTimes().Return(db.Outbox{}, nil)
+	store.EXPECT().
+		ListWebhookSubscriptionsForEvent(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return([]db.WebhookSubscription{}, nil)
+
+	return &events
+}
+
+func auditActions(events []db.CreateAuditEventParams) []string {
+	actions := make([]string, len(events))
+	for i, event := range events {
+		actions[i] = event.Action
+	}
+
+	return actions
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/sanctions"
+	"github.com/techschool/simplebank/token"
+	"github.com/techschool/simplebank/util"
+)
+
+// LoginUserParams contains the credentials and client information of a login
+type LoginUserParams struct {
+	Username  string
+	Password  string
+	UserAgent string
+	ClientIP  string
+}
+
+// LoginUserResult contains the tokens and session created by a login
+type LoginUserResult struct {
+	SessionID             uuid.UUID
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+	User                  db.User
+}
+
+// LoginUser checks the credentials of a user and opens a new session
+func (service *Service) LoginUser(ctx context.Context, arg LoginUserParams) (LoginUserResult, error) {
+	var result LoginUserResult
+
+	user, err := service.GetUser(ctx, arg.Username)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return result, service.loginFailed(ctx, arg.Username, "user not found", err)
+		}
+		return result, err
+	}
+
+	if err := util.CheckPassword(arg.Password, user.HashedPassword); err != nil {
+		return result, service.loginFailed(ctx, arg.Username, "wrong password", ErrInvalidCredentials)
+	}
+
+	if user.ScreeningStatus == sanctions.StatusBlocked {
+		return result, service.loginFailed(ctx, arg.Username, "blocked by sanctions screening", ErrUserBlocked)
+	}
+
+	accessToken, accessPayload, err := service.tokenMaker.CreateToken(user.Username, service.config.AccessTokenDuration)
+	if err != nil {
+		return result, fmt.Errorf("cannot create access token: %w", err)
+	}
+
+	refreshToken, refreshPayload, err := service.tokenMaker.CreateToken(user.Username, service.config.RefreshTokenDuration)
+	if err != nil {
+		return result, fmt.Errorf("cannot create refresh token: %w", err)
+	}
+
+	var session db.Session
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		session, err = q.CreateSession(ctx, db.CreateSessionParams{
+			ID:           refreshPayload.ID,
+			Username:     user.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    arg.UserAgent,
+			ClientIp:     arg.ClientIP,
+			ExpiresAt:    refreshPayload.ExpiredAt.Time,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot create session: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        user.Username,
+			Action:       audit.ActionLoginSucceeded,
+			ResourceType: audit.ResourceSession,
+			ResourceID:   session.ID.String(),
+			Diff:         map[string]any{"expires_at": session.ExpiresAt},
+		})
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result = LoginUserResult{
+		SessionID:             session.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt.Time,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiredAt.Time,
+		User:                  user,
+	}
+
+	return result, nil
+}
+
+// loginFailed records a failed login attempt for username and returns cause
+func (service *Service) loginFailed(ctx context.Context, username string, reason string, cause error) error {
+	err := service.recordTx(ctx, audit.Event{
+		Actor:        username,
+		Action:       audit.ActionLoginFailed,
+		ResourceType: audit.ResourceUser,
+		ResourceID:   username,
+		Diff:         map[string]any{"reason": reason},
+	})
+	if err != nil {
+		return err
+	}
+
+	return cause
+}
+
+// RenewAccessTokenResult contains a freshly issued access token
+type RenewAccessTokenResult struct {
+	AccessToken          string
+	AccessTokenExpiresAt time.Time
+}
+
+// RenewAccessToken issues a new access token for a valid refresh token of a
+// user not blocked by sanctions screening
+func (service *Service) RenewAccessToken(ctx context.Context, refreshToken string) (RenewAccessTokenResult, error) {
+	var result RenewAccessTokenResult
+
+	refreshPayload, err := service.tokenMaker.VerifyToken(refreshToken)
+	if err != nil {
+		if errors.Is(err, token.ErrExpiredToken) {
+			return result, ErrSessionExpired
+		}
+		return result, ErrInvalidToken
+	}
+
+	session, err := service.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, ErrSessionNotFound
+		}
+		return result, fmt.Errorf("cannot get session: %w", err)
+	}
+
+	if session.IsBlocked {
+		return result, ErrSessionBlocked
+	}
+
+	if session.Username != refreshPayload.Username || session.RefreshToken != refreshToken {
+		return result, ErrSessionMismatch
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return result, ErrSessionExpired
+	}
+
+	blocked, err := service.store.IsUserBlocked(ctx, session.Username)
+	if err != nil {
+		return result, fmt.Errorf("cannot get screening status: %w", err)
+	}
+	if blocked {
+		return result, ErrUserBlocked
+	}
+
+	accessToken, accessPayload, err := service.tokenMaker.CreateToken(refreshPayload.Username, service.config.AccessTokenDuration)
+	if err != nil {
+		return result, fmt.Errorf("cannot create access token: %w", err)
+	}
+
+	err = service.recordTx(ctx, audit.Event{
+		Actor:        session.Username,
+		Action:       audit.ActionTokenRenewed,
+		ResourceType: audit.ResourceSession,
+		ResourceID:   session.ID.String(),
+		Diff:         map[string]any{"access_token_expires_at": accessPayload.ExpiredAt.Time},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result = RenewAccessTokenResult{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt.Time,
+	}
+
+	return result, nil
+}
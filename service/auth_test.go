@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/sanctions"
+	"github.com/techschool/simplebank/token"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestService(t *testing.T, store db.Store) *Service {
+	config := util.Config{
+		TokenSymmetricalKey:  util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+	}
+
+	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricalKey)
+	require.NoError(t, err)
+
+	service, err := New(store, tokenMaker, config, nil)
+	require.NoError(t, err)
+	return service
+}
+
+func randomUser(t *testing.T) (user db.User, password string) {
+	password = util.RandomString(6)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user = db.User{
+		Username:       util.RandomOwner(),
+		HashedPassword: hashedPassword,
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+	}
+	return
+}
+
+func TestLoginUser(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name       string
+		password   string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+		actions    []string
+	}{
+		{
+			name:     "OK",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, Username: arg.Username}, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			actions: []string{audit.ActionLoginSucceeded},
+		},
+		{
+			name:     "UserNotFound",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrUserNotFound)
+			},
+			actions: []string{audit.ActionLoginFailed},
+		},
+		{
+			name:     "WrongPassword",
+			password: "wrong-password",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidCredentials)
+			},
+			actions: []string{audit.ActionLoginFailed},
+		},
+		{
+			name:     "UserBlocked",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				blocked := user
+				blocked.ScreeningStatus = sanctions.StatusBlocked
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(blocked, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrUserBlocked)
+			},
+			actions: []string{audit.ActionLoginFailed},
+		},
+		{
+			name:     "InternalError",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.NotErrorIs(t, err, ErrUserNotFound)
+			},
+			actions: []string{},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			events := stubTx(store)
+
+			service := newTestService(t, store)
+			result, err := service.LoginUser(context.Background(), LoginUserParams{
+				Username: user.Username,
+				Password: tc.password,
+			})
+			tc.checkError(t, err)
+			require.Equal(t, tc.actions, auditActions(*events))
+
+			if err == nil {
+				require.NotEmpty(t, result.AccessToken)
+				require.NotEmpty(t, result.RefreshToken)
+				require.Equal(t, user.Username, result.User.Username)
+			}
+		})
+	}
+}
+
+func TestRenewAccessTokenUserBlocked(t *testing.T) {
+	user, _ := randomUser(t)
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	service := newTestService(t, store)
+
+	refreshToken, refreshPayload, err := service.tokenMaker.CreateToken(user.Username, time.Hour)
+	require.NoError(t, err)
+
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).
+		Times(1).
+		Return(db.Session{
+			ID:           refreshPayload.ID,
+			Username:     user.Username,
+			RefreshToken: refreshToken,
+			ExpiresAt:    refreshPayload.ExpiredAt.Time,
+		}, nil)
+	store.EXPECT().IsUserBlocked(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(true, nil)
+	events := stubTx(store)
+
+	_, err = service.RenewAccessToken(context.Background(), refreshToken)
+	require.ErrorIs(t, err, ErrUserBlocked)
+	require.Empty(t, auditActions(*events))
+}
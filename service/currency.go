@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+)
+
+// LoadCurrencies replaces the currency registry of the process with the
+// currencies table. It runs once at startup, later changes to the table
+// take effect on restart.
+func LoadCurrencies(ctx context.Context, q db.Querier) error {
+	rows, err := q.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot list currencies: %w", err)
+	}
+
+	currencies := make([]util.Currency, len(rows))
+	for i, row := range rows {
+		currencies[i] = util.Currency{
+			Code:     row.Code,
+			Exponent: int(row.Exponent),
+			Symbol:   row.Symbol,
+			Enabled:  row.Enabled,
+		}
+	}
+
+	return util.SetCurrencies(currencies)
+}
+
+// ListCurrencies returns the currencies new accounts and transfers may use
+func (service *Service) ListCurrencies() []util.Currency {
+	return util.Currencies().Enabled()
+}
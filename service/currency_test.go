@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoadCurrencies(t *testing.T) {
+	defer func() {
+		require.NoError(t, util.SetCurrencies(util.DefaultCurrencies))
+	}()
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{
+		{Code: util.EUR, Exponent: 2, Symbol: "€", Enabled: false},
+		{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true},
+	}, nil)
+
+	require.NoError(t, LoadCurrencies(context.Background(), store))
+	require.True(t, util.IsValidCurrency("JPY"))
+	require.False(t, util.IsValidCurrency(util.EUR))
+	require.Equal(t, "€0.50", util.FormatAmount(50, util.EUR))
+}
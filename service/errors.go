@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/techschool/simplebank/apperr"
+)
+
+// Domain errors returned by the service. Transports render them with their
+// catalogue code, anything else is hidden behind an internal error.
+var (
+	ErrPermissionDenied = apperr.New(apperr.CodePermissionDenied, "user is not allowed to perform this action")
+	ErrAliasLookupLimit = apperr.New(apperr.CodeRateLimited, "too many recipients were looked up by alias, try again later")
+
+	ErrUserNotFound       = apperr.New(apperr.CodeUserNotFound, "user not found")
+	ErrUserAlreadyExists  = apperr.New(apperr.CodeUserAlreadyExists, "username, email or phone already exists")
+	ErrInvalidCredentials = apperr.New(apperr.CodeInvalidCredentials, "invalid username or password")
+	ErrUserBlocked        = apperr.New(apperr.CodeUserBlocked, "user did not pass compliance screening")
+
+	ErrKYCTierRequired       = apperr.New(apperr.CodeKYCTierRequired, "KYC tier of the user is too low for this action")
+	ErrKYCTierInvalid        = apperr.New(apperr.CodeInvalidArgument, "requested KYC tier must be above the current tier of the user")
+	ErrKYCTierUnknown        = apperr.New(apperr.CodeInvalidArgument, "KYC tier must be unverified, basic or full")
+	ErrKYCSubmissionNotFound = apperr.New(apperr.CodeKYCSubmissionNotFound, "KYC submission not found")
+	ErrKYCSubmissionPending  = apperr.New(apperr.CodeKYCSubmissionPending, "a KYC submission of the user is already waiting for review")
+	ErrKYCSubmissionDecided  = apperr.New(apperr.CodeKYCSubmissionDecided, "KYC submission was already approved or rejected")
+
+	ErrInvalidToken    = apperr.New(apperr.CodeInvalidToken, "invalid token")
+	ErrSessionNotFound = apperr.New(apperr.CodeSessionNotFound, "session not found")
+	ErrSessionBlocked  = apperr.New(apperr.CodeSessionBlocked, "session is blocked")
+	ErrSessionMismatch = apperr.New(apperr.CodeSessionMismatch, "session token mismatch")
+	ErrSessionExpired  = apperr.New(apperr.CodeSessionExpired, "session has expired")
+
+	ErrAccountNotFound      = apperr.New(apperr.CodeAccountNotFound, "account not found")
+	ErrAccountAlreadyExists = apperr.New(apperr.CodeAccountAlreadyExists, "account of this product and currency already exists")
+	ErrAccountNotOwned      = apperr.New(apperr.CodeAccountNotOwned, "account does not belong to the authenticated user")
+	ErrProductNotFound      = apperr.New(apperr.CodeProductNotFound, "account product not found")
+	ErrCurrencyMismatch     = apperr.New(apperr.CodeCurrencyMismatch, "account currency does not match the requested currency")
+	ErrInsufficientFunds    = apperr.New(apperr.CodeInsufficientFunds, "account balance is too low for this transfer")
+	ErrSameAccount          = apperr.New(apperr.CodeSameAccount, "money cannot be transferred to the account it is sent from")
+	ErrInvalidAmount        = apperr.New(apperr.CodeInvalidAmount, "amount must be a positive decimal within the precision of its currency")
+	ErrLimitExceeded        = apperr.New(apperr.CodeLimitExceeded, "transfer exceeds a limit of the account")
+	ErrTransferHeld         = apperr.New(apperr.CodeTransferHeld, "transfer is held for review and will be made once approved")
+	ErrTransferDenied       = apperr.New(apperr.CodeTransferDenied, "transfer cannot be made")
+
+	ErrPayeeNotFound      = apperr.New(apperr.CodePayeeNotFound, "payee not found")
+	ErrPayeeAlreadyExists = apperr.New(apperr.CodePayeeAlreadyExists, "a payee with this account or nickname already exists")
+	ErrPayeeCoolingOff    = apperr.New(apperr.CodePayeeCoolingOff, "payee cannot receive transfers before its cooling-off period ends or it is confirmed")
+	ErrPayeeOwnAccount    = apperr.New(apperr.CodeInvalidArgument, "own accounts cannot be saved as payees")
+	ErrRecipientNotFound  = apperr.New(apperr.CodeRecipientNotFound, "no account in this currency is registered to the alias")
+
+	ErrTransferReviewNotFound = apperr.New(apperr.CodeTransferReviewNotFound, "transfer review not found")
+	ErrTransferReviewDecided  = apperr.New(apperr.CodeTransferReviewDecided, "transfer review was already approved or rejected")
+
+	ErrPaymentFileInvalid   = apperr.New(apperr.CodePaymentFileInvalid, "payment file cannot be processed")
+	ErrPaymentFileDuplicate = apperr.New(apperr.CodePaymentFileDuplicate, "payment file with this message id was already imported")
+
+	ErrWebhookNotFound         = apperr.New(apperr.CodeWebhookNotFound, "webhook subscription not found")
+	ErrWebhookDeliveryNotFound = apperr.New(apperr.CodeWebhookDeliveryNotFound, "webhook delivery not found")
+)
+
+// pqErrorName returns the condition name of a postgres error, or an empty string
+func pqErrorName(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Name()
+	}
+
+	return ""
+}
+
+// pqConstraintName returns the constraint a postgres error violated, or an
+// empty string
+func pqConstraintName(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+
+	return ""
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/kyc"
+)
+
+// KYCIdentity holds the identity details of a submission in clear
+type KYCIdentity struct {
+	LegalName string `json:"legal_name"`
+	// DateOfBirth is formatted as 2006-01-02
+	DateOfBirth    string `json:"date_of_birth"`
+	DocumentNumber string `json:"document_number"`
+	Address        string `json:"address"`
+}
+
+// SubmitKYCParams contains the identity details a user submits to reach Tier
+type SubmitKYCParams struct {
+	Username     string
+	Tier         string
+	DocumentType string
+	Country      string
+	Identity     KYCIdentity
+}
+
+// SubmitKYC queues the identity details of a user for a banker to review.
+// The store encrypts them. The tier must be above the current one, and a user has
+// at most one submission waiting at a time.
+func (service *Service) SubmitKYC(ctx context.Context, arg SubmitKYCParams) (db.KycSubmission, error) {
+	user, err := service.GetUser(ctx, arg.Username)
+	if err != nil {
+		return db.KycSubmission{}, err
+	}
+
+	if !kyc.Valid(arg.Tier) || kyc.AtLeast(user.KycTier, arg.Tier) {
+		return db.KycSubmission{}, ErrKYCTierInvalid.
+			WithDetail("kyc_tier", user.KycTier).
+			WithDetail("requested_tier", arg.Tier)
+	}
+
+	params := db.CreateKYCSubmissionParams{
+		Username:       arg.Username,
+		Tier:           arg.Tier,
+		DocumentType:   arg.DocumentType,
+		Country:        arg.Country,
+		LegalName:      []byte(arg.Identity.LegalName),
+		DateOfBirth:    []byte(arg.Identity.DateOfBirth),
+		DocumentNumber: []byte(arg.Identity.DocumentNumber),
+		Address:        []byte(arg.Identity.Address),
+	}
+
+	var submission db.KycSubmission
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		submission, err = q.CreateKYCSubmission(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        arg.Username,
+			Action:       audit.ActionKYCSubmitted,
+			ResourceType: audit.ResourceKYC,
+			ResourceID:   strconv.FormatInt(submission.ID, 10),
+			Diff:         map[string]any{"after": kycSubmissionDiff(submission)},
+		})
+	})
+	if err != nil {
+		if pqErrorName(err) == "unique_violation" {
+			return db.KycSubmission{}, ErrKYCSubmissionPending
+		}
+		return db.KycSubmission{}, fmt.Errorf("cannot submit KYC details: %w", err)
+	}
+
+	return submission, nil
+}
+
+// KYCStatus is the tier of a user and their latest submission, if any
+type KYCStatus struct {
+	Tier       string
+	Submission *db.KycSubmission
+}
+
+// GetKYCStatus returns the KYC tier of a user and where their latest
+// submission stands
+func (service *Service) GetKYCStatus(ctx context.Context, username string) (KYCStatus, error) {
+	user, err := service.GetUser(ctx, username)
+	if err != nil {
+		return KYCStatus{}, err
+	}
+
+	status := KYCStatus{Tier: user.KycTier}
+
+	submission, err := service.store.GetLatestKYCSubmission(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status, nil
+		}
+		return KYCStatus{}, fmt.Errorf("cannot get KYC submission: %w", err)
+	}
+
+	status.Submission = &submission
+	return status, nil
+}
+
+// ListKYCSubmissionsParams contains the status and page of submissions to list
+type ListKYCSubmissionsParams struct {
+	Status   string
+	PageID   int32
+	PageSize int32
+}
+
+// ListKYCSubmissions returns a page of the submissions of a status, oldest
+// first. Identity details stay encrypted.
+func (service *Service) ListKYCSubmissions(ctx context.Context, arg ListKYCSubmissionsParams) ([]db.KycSubmission, error) {
+	submissions, err := service.store.ListKYCSubmissions(ctx, db.ListKYCSubmissionsParams{
+		Status: arg.Status,
+		Limit:  arg.PageSize,
+		Offset: (arg.PageID - 1) * arg.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list KYC submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// GetKYCIdentity decrypts the identity details of a submission for reviewer.
+// Every access is recorded in the audit log.
+func (service *Service) GetKYCIdentity(ctx context.Context, reviewer string, id int64) (db.KycSubmission, KYCIdentity, error) {
+	submission, err := service.getKYCSubmission(ctx, id)
+	if err != nil {
+		return db.KycSubmission{}, KYCIdentity{}, err
+	}
+
+	if submission.Username == reviewer {
+		return db.KycSubmission{}, KYCIdentity{}, ErrPermissionDenied.WithDetail("reason", "bankers cannot review their own KYC submissions")
+	}
+
+	identity := KYCIdentity{
+		LegalName:      string(submission.LegalName),
+		DateOfBirth:    string(submission.DateOfBirth),
+		DocumentNumber: string(submission.DocumentNumber),
+		Address:        string(submission.Address),
+	}
+
+	err = service.recordTx(ctx, audit.Event{
+		Actor:        reviewer,
+		Action:       audit.ActionKYCViewed,
+		ResourceType: audit.ResourceKYC,
+		ResourceID:   strconv.FormatInt(submission.ID, 10),
+	})
+	if err != nil {
+		return db.KycSubmission{}, KYCIdentity{}, err
+	}
+
+	return submission, identity, nil
+}
+
+// ApproveKYCSubmission raises the user of a pending submission to the tier
+// they asked for on behalf of reviewer
+func (service *Service) ApproveKYCSubmission(ctx context.Context, reviewer string, id int64) (db.KycSubmission, error) {
+	submission, err := service.pendingKYCSubmission(ctx, reviewer, id)
+	if err != nil {
+		return db.KycSubmission{}, err
+	}
+
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		user, err := q.GetUser(ctx, submission.Username)
+		if err != nil {
+			return fmt.Errorf("cannot get user: %w", err)
+		}
+
+		// an earlier submission may have raised the user further meanwhile
+		tier := user.KycTier
+		if !kyc.AtLeast(tier, submission.Tier) {
+			user, err = q.UpdateUserKYCTier(ctx, db.UpdateUserKYCTierParams{
+				KycTier:  submission.Tier,
+				Username: submission.Username,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot update KYC tier: %w", err)
+			}
+		}
+
+		submission, err = decideKYCSubmission(ctx, q, submission, db.DecideKYCSubmissionParams{
+			ID:       submission.ID,
+			Status:   kyc.StatusApproved,
+			Reviewer: sql.NullString{String: reviewer, Valid: true},
+		}, map[string]any{
+			"kyc_tier": map[string]any{"before": tier, "after": user.KycTier},
+		})
+		return err
+	})
+	if err != nil {
+		return db.KycSubmission{}, err
+	}
+
+	return submission, nil
+}
+
+// RejectKYCSubmission turns down a pending submission on behalf of reviewer
+func (service *Service) RejectKYCSubmission(ctx context.Context, reviewer string, id int64, reason string) (db.KycSubmission, error) {
+	submission, err := service.pendingKYCSubmission(ctx, reviewer, id)
+	if err != nil {
+		return db.KycSubmission{}, err
+	}
+
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		submission, err = decideKYCSubmission(ctx, q, submission, db.DecideKYCSubmissionParams{
+			ID:       submission.ID,
+			Status:   kyc.StatusRejected,
+			Reviewer: sql.NullString{String: reviewer, Valid: true},
+			Reason:   reason,
+		}, map[string]any{})
+		return err
+	})
+	if err != nil {
+		return db.KycSubmission{}, err
+	}
+
+	return submission, nil
+}
+
+func (service *Service) getKYCSubmission(ctx context.Context, id int64) (db.KycSubmission, error) {
+	submission, err := service.store.GetKYCSubmission(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.KycSubmission{}, ErrKYCSubmissionNotFound.WithDetail("submission_id", id)
+		}
+		return db.KycSubmission{}, fmt.Errorf("cannot get KYC submission: %w", err)
+	}
+
+	return submission, nil
+}
+
+// pendingKYCSubmission returns submission id if it is still pending and
+// reviewer is not the one who submitted it
+func (service *Service) pendingKYCSubmission(ctx context.Context, reviewer string, id int64) (db.KycSubmission, error) {
+	submission, err := service.getKYCSubmission(ctx, id)
+	if err != nil {
+		return db.KycSubmission{}, err
+	}
+
+	if submission.Status != kyc.StatusPending {
+		return db.KycSubmission{}, ErrKYCSubmissionDecided.
+			WithDetail("submission_id", id).
+			WithDetail("status", submission.Status)
+	}
+
+	if submission.Username == reviewer {
+		return db.KycSubmission{}, ErrPermissionDenied.WithDetail("reason", "bankers cannot review their own KYC submissions")
+	}
+
+	return submission, nil
+}
+
+// decideKYCSubmission records the decision on a pending submission along
+// with diff. It fails with ErrKYCSubmissionDecided if another banker
+// decided it first.
+func decideKYCSubmission(ctx context.Context, q db.Querier, before db.KycSubmission, arg db.DecideKYCSubmissionParams, diff map[string]any) (db.KycSubmission, error) {
+	submission, err := q.DecideKYCSubmission(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.KycSubmission{}, ErrKYCSubmissionDecided.WithDetail("submission_id", arg.ID)
+		}
+		return db.KycSubmission{}, fmt.Errorf("cannot decide KYC submission: %w", err)
+	}
+
+	action := audit.ActionKYCApproved
+	if submission.Status == kyc.StatusRejected {
+		action = audit.ActionKYCRejected
+	}
+
+	diff["before"] = kycSubmissionDiff(before)
+	diff["after"] = kycSubmissionDiff(submission)
+	err = record(ctx, q, audit.Event{
+		Actor:        arg.Reviewer.String,
+		Action:       action,
+		ResourceType: audit.ResourceKYC,
+		ResourceID:   strconv.FormatInt(submission.ID, 10),
+		Diff:         diff,
+	})
+	if err != nil {
+		return db.KycSubmission{}, err
+	}
+
+	return submission, nil
+}
+
+// kycSubmissionDiff records a submission without its identity details,
+// which must not leave the kyc_submissions table even encrypted
+func kycSubmissionDiff(submission db.KycSubmission) map[string]any {
+	return map[string]any{
+		"username":      submission.Username,
+		"tier":          submission.Tier,
+		"status":        submission.Status,
+		"document_type": submission.DocumentType,
+		"country":       submission.Country,
+		"reviewer":      submission.Reviewer.String,
+		"reason":        submission.Reason,
+	}
+}
+
+// kycTierRequiredError renders err with the tier the user needs
+func kycTierRequiredError(err *db.KYCTierError) error {
+	return ErrKYCTierRequired.
+		WithDetail("currency", err.Currency).
+		WithDetail("kyc_tier", err.Tier).
+		WithDetail("required_tier", err.Required)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/kyc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func randomKYCIdentity() KYCIdentity {
+	return KYCIdentity{
+		LegalName:      util.RandomOwner(),
+		DateOfBirth:    "1990-04-12",
+		DocumentNumber: util.RandomString(9),
+		Address:        "1 Main Street, Springfield",
+	}
+}
+
+func TestSubmitKYC(t *testing.T) {
+	user, _ := randomUser(t)
+	user.KycTier = kyc.TierUnverified
+	identity := randomKYCIdentity()
+
+	testCases := []struct {
+		name       string
+		tier       string
+		buildStubs func(store *mockdb.MockStore, submission *db.KycSubmission)
+		checkError func(t *testing.T, err error)
+		actions    []string
+	}{
+		{
+			name: "OK",
+			tier: kyc.TierBasic,
+			buildStubs: func(store *mockdb.MockStore, submission *db.KycSubmission) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateKYCSubmission(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateKYCSubmissionParams) (db.KycSubmission, error) {
+						*submission = db.KycSubmission{
+							ID:             1,
+							Username:       arg.Username,
+							Tier:           arg.Tier,
+							Status:         kyc.StatusPending,
+							DocumentType:   arg.DocumentType,
+							Country:        arg.Country,
+							LegalName:      arg.LegalName,
+							DateOfBirth:    arg.DateOfBirth,
+							DocumentNumber: arg.DocumentNumber,
+							Address:        arg.Address,
+						}
+						return *submission, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			actions: []string{audit.ActionKYCSubmitted},
+		},
+		{
+			name: "TierNotAbove",
+			tier: kyc.TierUnverified,
+			buildStubs: func(store *mockdb.MockStore, submission *db.KycSubmission) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateKYCSubmission(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrKYCTierInvalid)
+			},
+			actions: []string{},
+		},
+		{
+			name: "AlreadyPending",
+			tier: kyc.TierFull,
+			buildStubs: func(store *mockdb.MockStore, submission *db.KycSubmission) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateKYCSubmission(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.KycSubmission{}, &pq.Error{Code: "23505"})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrKYCSubmissionPending)
+			},
+			actions: []string{},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			var submission db.KycSubmission
+			tc.buildStubs(store, &submission)
+			events := stubTx(store)
+
+			service := newTestService(t, store)
+			_, err := service.SubmitKYC(context.Background(), SubmitKYCParams{
+				Username:     user.Username,
+				Tier:         tc.tier,
+				DocumentType: kyc.DocumentPassport,
+				Country:      "KE",
+				Identity:     identity,
+			})
+			tc.checkError(t, err)
+			require.Equal(t, tc.actions, auditActions(*events))
+
+			if err != nil {
+				return
+			}
+
+			// the store encrypts identity details, they stay out of the audit log
+			require.Equal(t, identity.DocumentNumber, string(submission.DocumentNumber))
+			require.NotContains(t, string((*events)[0].Diff), identity.DocumentNumber)
+
+			reviewer, _ := randomUser(t)
+			store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(submission.ID)).Times(2).Return(submission, nil)
+
+			_, got, err := service.GetKYCIdentity(context.Background(), reviewer.Username, submission.ID)
+			require.NoError(t, err)
+			require.Equal(t, identity, got)
+			require.Equal(t, audit.ActionKYCViewed, (*events)[1].Action)
+
+			_, _, err = service.GetKYCIdentity(context.Background(), user.Username, submission.ID)
+			require.ErrorIs(t, err, ErrPermissionDenied)
+		})
+	}
+}
+
+func TestApproveKYCSubmission(t *testing.T) {
+	user, _ := randomUser(t)
+	user.KycTier = kyc.TierUnverified
+	reviewer, _ := randomUser(t)
+
+	submission := db.KycSubmission{ID: 4, Username: user.Username, Tier: kyc.TierBasic, Status: kyc.StatusPending}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(submission.ID)).Times(1).Return(submission, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().
+		UpdateUserKYCTier(gomock.Any(), gomock.Eq(db.UpdateUserKYCTierParams{KycTier: kyc.TierBasic, Username: user.Username})).
+		Times(1).
+		Return(db.User{Username: user.Username, KycTier: kyc.TierBasic}, nil)
+	store.EXPECT().
+		DecideKYCSubmission(gomock.Any(), gomock.Eq(db.DecideKYCSubmissionParams{
+			ID:       submission.ID,
+			Status:   kyc.StatusApproved,
+			Reviewer: sql.NullString{String: reviewer.Username, Valid: true},
+		})).
+		Times(1).
+		Return(db.KycSubmission{ID: 4, Username: user.Username, Tier: kyc.TierBasic, Status: kyc.StatusApproved}, nil)
+	events := stubTx(store)
+
+	service := newTestService(t, store)
+	approved, err := service.ApproveKYCSubmission(context.Background(), reviewer.Username, submission.ID)
+	require.NoError(t, err)
+	require.Equal(t, kyc.StatusApproved, approved.Status)
+
+	require.Equal(t, []string{audit.ActionKYCApproved}, auditActions(*events))
+	require.Contains(t, string((*events)[0].Diff), `"kyc_tier":{"after":"basic","before":"unverified"}`)
+}
+
+func TestRejectKYCSubmission(t *testing.T) {
+	user, _ := randomUser(t)
+	reviewer, _ := randomUser(t)
+
+	pending := db.KycSubmission{ID: 4, Username: user.Username, Tier: kyc.TierFull, Status: kyc.StatusPending}
+
+	testCases := []struct {
+		name       string
+		reviewer   string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name:     "OK",
+			reviewer: reviewer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().
+					DecideKYCSubmission(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.KycSubmission{ID: 4, Username: user.Username, Status: kyc.StatusRejected}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "OwnSubmission",
+			reviewer: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().DecideKYCSubmission(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrPermissionDenied)
+			},
+		},
+		{
+			name:     "DecidedMeanwhile",
+			reviewer: reviewer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().DecideKYCSubmission(gomock.Any(), gomock.Any()).Times(1).Return(db.KycSubmission{}, sql.ErrNoRows)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrKYCSubmissionDecided)
+			},
+		},
+		{
+			name:     "NotFound",
+			reviewer: reviewer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(db.KycSubmission{}, sql.ErrNoRows)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrKYCSubmissionNotFound)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			tc.buildStubs(store)
+			stubTx(store)
+
+			service := newTestService(t, store)
+			_, err := service.RejectKYCSubmission(context.Background(), tc.reviewer, pending.ID, "document expired")
+			tc.checkError(t, err)
+		})
+	}
+}
+
+func TestCreateAccountKYCTier(t *testing.T) {
+	user, _ := randomUser(t)
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().GetProduct(gomock.Any(), gomock.Eq(DefaultProduct)).Times(1).Return(db.Product{Code: DefaultProduct}, nil)
+	store.EXPECT().IsUserBlocked(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(false, nil)
+	store.EXPECT().
+		GetKYCCapability(gomock.Any(), gomock.Eq(db.GetKYCCapabilityParams{Username: user.Username, Currency: util.EUR})).
+		Times(1).
+		Return(db.GetKYCCapabilityRow{KycTier: kyc.TierUnverified, MinKycTier: kyc.TierBasic}, nil)
+	store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+	stubTx(store)
+
+	service := newTestService(t, store)
+	_, err := service.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.EUR,
+	})
+	require.ErrorIs(t, err, ErrKYCTierRequired)
+
+	details := apperr.From(err).Details
+	require.Equal(t, kyc.TierUnverified, details["kyc_tier"])
+	require.Equal(t, kyc.TierBasic, details["required_tier"])
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/techschool/simplebank/ledger"
+)
+
+// GetTrialBalance returns the trial balance of the general ledger from the
+// postings made before asOf
+func (service *Service) GetTrialBalance(ctx context.Context, asOf time.Time) (ledger.TrialBalance, error) {
+	return ledger.GetTrialBalance(ctx, service.store, asOf)
+}
+
+// GetBalanceSheet returns the balance sheet of the bank per currency at asOf
+func (service *Service) GetBalanceSheet(ctx context.Context, asOf time.Time) (ledger.BalanceSheet, error) {
+	trialBalance, err := ledger.GetTrialBalance(ctx, service.store, asOf)
+	if err != nil {
+		return ledger.BalanceSheet{}, err
+	}
+
+	return ledger.NewBalanceSheet(trialBalance), nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/kyc"
+	"github.com/techschool/simplebank/money"
+)
+
+// GetTransferLimits returns the limits on outgoing transfers of an account
+// of owner and how much of each was used today and this month
+func (service *Service) GetTransferLimits(ctx context.Context, owner string, accountNumber string) ([]db.LimitUsage, error) {
+	account, err := service.GetAccount(ctx, owner, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := db.AccountLimitUsage(ctx, service.store, account, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return usages, nil
+}
+
+// SetTransferLimit creates or replaces the limits of a scope, subject and
+// currency on behalf of actor. Null limits fall through to the broader scope.
+func (service *Service) SetTransferLimit(ctx context.Context, actor string, arg db.UpsertTransferLimitParams) (db.TransferLimit, error) {
+	var limit db.TransferLimit
+	err := service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		if err := validLimitSubject(ctx, q, arg.Scope, arg.Subject); err != nil {
+			return err
+		}
+
+		var err error
+		limit, err = q.UpsertTransferLimit(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("cannot set transfer limit: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        actor,
+			Action:       audit.ActionTransferLimitSet,
+			ResourceType: audit.ResourceLimit,
+			ResourceID:   fmt.Sprintf("%s:%s:%s", limit.Scope, limit.Subject, limit.Currency),
+			Diff:         map[string]any{"after": limit},
+		})
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrProductNotFound), errors.Is(err, ErrUserNotFound), errors.Is(err, ErrKYCTierUnknown):
+			return db.TransferLimit{}, err
+		case pqErrorName(err) == "check_violation":
+			return db.TransferLimit{}, ErrInvalidAmount.WithDetail("reason", "limits must not be negative")
+		}
+		return db.TransferLimit{}, err
+	}
+
+	return limit, nil
+}
+
+// validLimitSubject checks that the subject of a product, tier or user limit exists
+func validLimitSubject(ctx context.Context, q db.Querier, scope string, subject string) error {
+	var err error
+	switch scope {
+	case db.LimitScopeProduct:
+		if _, err = q.GetProduct(ctx, subject); errors.Is(err, sql.ErrNoRows) {
+			return ErrProductNotFound.WithDetail("product", subject)
+		}
+	case db.LimitScopeTier:
+		if !kyc.Valid(subject) {
+			return ErrKYCTierUnknown.WithDetail("tier", subject)
+		}
+		return nil
+	case db.LimitScopeUser:
+		if _, err = q.GetUser(ctx, subject); errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("cannot get %s %q: %w", scope, subject, err)
+	}
+
+	return nil
+}
+
+// limitExceededError renders err with the remaining allowance of the limit
+func limitExceededError(err *db.LimitExceededError) error {
+	appErr := ErrLimitExceeded.
+		WithDetail("limit", err.Name).
+		WithDetail("currency", err.Currency)
+
+	if err.IsCount() {
+		return appErr.
+			WithDetail("max", err.Limit).
+			WithDetail("remaining", err.Remaining)
+	}
+
+	return appErr.
+		WithDetail("max", money.New(err.Limit, err.Currency).Decimal()).
+		WithDetail("remaining", money.New(err.Remaining, err.Currency).Decimal())
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+)
+
+// DefaultPayeeCoolingOff is how long new payees wait before they can
+// receive transfers when the config sets no cooling-off period
+const DefaultPayeeCoolingOff = 24 * time.Hour
+
+// CreatePayeeParams contains the input parameters of a new payee. The
+// account is given either by AccountNumber or by the Username and Currency
+// of its owner, which is resolved like a transfer to an alias.
+type CreatePayeeParams struct {
+	Owner         string
+	Nickname      string
+	AccountNumber string
+	Username      string
+	Currency      string
+}
+
+// CreatePayee saves an account owner can send money to by payee id. The
+// payee only receives transfers once its cooling-off period is over or
+// owner confirms it with ConfirmPayee.
+func (service *Service) CreatePayee(ctx context.Context, arg CreatePayeeParams) (db.Payee, error) {
+	account, err := service.payeeAccount(ctx, arg)
+	if err != nil {
+		return db.Payee{}, err
+	}
+
+	if account.Owner == arg.Owner {
+		return db.Payee{}, ErrPayeeOwnAccount.WithDetail("account_number", account.Number)
+	}
+
+	coolingOff := service.config.PayeeCoolingOff
+	if coolingOff <= 0 {
+		coolingOff = DefaultPayeeCoolingOff
+	}
+
+	var payee db.Payee
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		payee, err = q.CreatePayee(ctx, db.CreatePayeeParams{
+			Owner:         arg.Owner,
+			Nickname:      arg.Nickname,
+			AccountID:     account.ID,
+			AccountNumber: account.Number,
+			Currency:      account.Currency,
+			ActiveFrom:    time.Now().Add(coolingOff),
+		})
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        arg.Owner,
+			Action:       audit.ActionPayeeCreated,
+			ResourceType: audit.ResourcePayee,
+			ResourceID:   strconv.FormatInt(payee.ID, 10),
+			Diff:         map[string]any{"after": payee},
+		})
+	})
+	if err != nil {
+		switch pqErrorName(err) {
+		case "unique_violation":
+			return db.Payee{}, ErrPayeeAlreadyExists.
+				WithDetail("account_number", account.Number).
+				WithDetail("nickname", arg.Nickname)
+		case "foreign_key_violation":
+			return db.Payee{}, ErrUserNotFound
+		}
+		return db.Payee{}, fmt.Errorf("cannot create payee: %w", err)
+	}
+
+	return payee, nil
+}
+
+// payeeAccount returns the account a new payee points at
+func (service *Service) payeeAccount(ctx context.Context, arg CreatePayeeParams) (db.Account, error) {
+	if arg.AccountNumber != "" {
+		return service.getAccountByNumber(ctx, arg.AccountNumber)
+	}
+
+	account, _, err := service.recipientAccount(ctx, arg.Owner, arg.Username, arg.Currency)
+	return account, err
+}
+
+// GetPayee returns the payee with the given id if it belongs to owner
+func (service *Service) GetPayee(ctx context.Context, owner string, id int64) (db.Payee, error) {
+	return service.getPayee(ctx, owner, id)
+}
+
+// ListPayeesParams contains the owner and page to list payees for
+type ListPayeesParams struct {
+	Owner    string
+	PageID   int32
+	PageSize int32
+}
+
+// ListPayees returns a page of the payees of owner
+func (service *Service) ListPayees(ctx context.Context, arg ListPayeesParams) ([]db.Payee, error) {
+	payees, err := service.store.ListPayees(ctx, db.ListPayeesParams{
+		Owner:  arg.Owner,
+		Limit:  arg.PageSize,
+		Offset: (arg.PageID - 1) * arg.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list payees: %w", err)
+	}
+
+	return payees, nil
+}
+
+// RenamePayee changes the nickname of a payee of owner. The account it
+// points at cannot change, a new payee has to be created instead.
+func (service *Service) RenamePayee(ctx context.Context, owner string, id int64, nickname string) (db.Payee, error) {
+	before, err := service.getPayee(ctx, owner, id)
+	if err != nil {
+		return db.Payee{}, err
+	}
+
+	var payee db.Payee
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		payee, err = q.UpdatePayeeNickname(ctx, db.UpdatePayeeNicknameParams{
+			ID:       id,
+			Nickname: nickname,
+		})
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        owner,
+			Action:       audit.ActionPayeeRenamed,
+			ResourceType: audit.ResourcePayee,
+			ResourceID:   strconv.FormatInt(id, 10),
+			Diff: map[string]any{
+				"nickname": map[string]any{"before": before.Nickname, "after": payee.Nickname},
+			},
+		})
+	})
+	if err != nil {
+		if pqErrorName(err) == "unique_violation" {
+			return db.Payee{}, ErrPayeeAlreadyExists.WithDetail("nickname", nickname)
+		}
+		return db.Payee{}, fmt.Errorf("cannot rename payee: %w", err)
+	}
+
+	return payee, nil
+}
+
+// ConfirmPayee lets a payee of owner receive transfers right away. owner
+// confirms with their password, so that a stolen access token alone cannot
+// skip the cooling-off period.
+func (service *Service) ConfirmPayee(ctx context.Context, owner string, id int64, password string) (db.Payee, error) {
+	if _, err := service.getPayee(ctx, owner, id); err != nil {
+		return db.Payee{}, err
+	}
+
+	user, err := service.GetUser(ctx, owner)
+	if err != nil {
+		return db.Payee{}, err
+	}
+
+	if err := util.CheckPassword(password, user.HashedPassword); err != nil {
+		return db.Payee{}, ErrInvalidCredentials
+	}
+
+	var payee db.Payee
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		payee, err = q.ConfirmPayee(ctx, id)
+		if err != nil {
+			return fmt.Errorf("cannot confirm payee: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        owner,
+			Action:       audit.ActionPayeeConfirmed,
+			ResourceType: audit.ResourcePayee,
+			ResourceID:   strconv.FormatInt(id, 10),
+		})
+	})
+	if err != nil {
+		return db.Payee{}, err
+	}
+
+	return payee, nil
+}
+
+// DeletePayee removes a payee of owner
+func (service *Service) DeletePayee(ctx context.Context, owner string, id int64) error {
+	payee, err := service.getPayee(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	return service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		if err := q.DeletePayee(ctx, id); err != nil {
+			return fmt.Errorf("cannot delete payee: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        owner,
+			Action:       audit.ActionPayeeDeleted,
+			ResourceType: audit.ResourcePayee,
+			ResourceID:   strconv.FormatInt(id, 10),
+			Diff:         map[string]any{"before": payee},
+		})
+	})
+}
+
+// PayeeActive reports whether payee may receive transfers at now
+func PayeeActive(payee db.Payee, now time.Time) bool {
+	return !payee.ActiveFrom.After(now)
+}
+
+// getPayee returns a payee of owner. Other users' payees are reported as
+// not found.
+func (service *Service) getPayee(ctx context.Context, owner string, id int64) (db.Payee, error) {
+	payee, err := service.store.GetPayee(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Payee{}, ErrPayeeNotFound.WithDetail("payee_id", id)
+		}
+		return db.Payee{}, fmt.Errorf("cannot get payee: %w", err)
+	}
+
+	if payee.Owner != owner {
+		return db.Payee{}, ErrPayeeNotFound.WithDetail("payee_id", id)
+	}
+
+	return payee, nil
+}
+
+// activePayee returns a payee of owner that may receive transfers
+func (service *Service) activePayee(ctx context.Context, owner string, id int64) (db.Payee, error) {
+	payee, err := service.getPayee(ctx, owner, id)
+	if err != nil {
+		return db.Payee{}, err
+	}
+
+	if !PayeeActive(payee, time.Now()) {
+		return db.Payee{}, ErrPayeeCoolingOff.
+			WithDetail("payee_id", id).
+			WithDetail("active_from", payee.ActiveFrom.Format(time.RFC3339))
+	}
+
+	return payee, nil
+}
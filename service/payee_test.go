@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreatePayee(t *testing.T) {
+	user, _ := randomUser(t)
+	account := db.Account{ID: 9, Owner: util.RandomOwner(), Currency: util.EUR, ProductCode: DefaultProduct, Number: util.RandomAccountNumber()}
+
+	testCases := []struct {
+		name       string
+		coolingOff time.Duration
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+		actions    []string
+	}{
+		{
+			name:       "OK",
+			coolingOff: time.Hour,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePayee(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePayeeParams) (db.Payee, error) {
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ActiveFrom, time.Minute)
+						require.Equal(t, account.Number, arg.AccountNumber)
+						return db.Payee{ID: 1, Owner: arg.Owner, AccountID: arg.AccountID, AccountNumber: arg.AccountNumber, ActiveFrom: arg.ActiveFrom}, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			actions: []string{audit.ActionPayeeCreated},
+		},
+		{
+			name: "DefaultCoolingOff",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePayee(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePayeeParams) (db.Payee, error) {
+						require.WithinDuration(t, time.Now().Add(DefaultPayeeCoolingOff), arg.ActiveFrom, time.Minute)
+						return db.Payee{ID: 1}, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			actions: []string{audit.ActionPayeeCreated},
+		},
+		{
+			name: "AlreadySaved",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePayee(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Payee{}, &pq.Error{Code: "23505"})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrPayeeAlreadyExists)
+			},
+			actions: []string{},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(1).Return(account, nil)
+			tc.buildStubs(store)
+			events := stubTx(store)
+
+			service := newTestService(t, store)
+			service.config.PayeeCoolingOff = tc.coolingOff
+
+			_, err := service.CreatePayee(context.Background(), CreatePayeeParams{
+				Owner:         user.Username,
+				Nickname:      "Rent",
+				AccountNumber: account.Number,
+			})
+			tc.checkError(t, err)
+			require.Equal(t, tc.actions, auditActions(*events))
+		})
+	}
+}
+
+func TestPayeeActive(t *testing.T) {
+	now := time.Now()
+
+	require.True(t, PayeeActive(db.Payee{ActiveFrom: now}, now))
+	require.True(t, PayeeActive(db.Payee{ActiveFrom: now.Add(-time.Second)}, now))
+	require.False(t, PayeeActive(db.Payee{ActiveFrom: now.Add(time.Second)}, now))
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/techschool/simplebank/apperr"
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/iso20022"
+	"github.com/techschool/simplebank/money"
+)
+
+// Payment statuses reported for each transaction of a payment file, named
+// after the ISO 20022 transaction status codes
+const (
+	PaymentAccepted = "ACSC"
+	PaymentPending  = "PDNG"
+	PaymentRejected = "RJCT"
+)
+
+// PaymentFileReport tells a client what happened to each payment of a file
+type PaymentFileReport struct {
+	PaymentFileID int64           `json:"payment_file_id"`
+	MessageID     string          `json:"message_id"`
+	Accepted      int             `json:"accepted"`
+	Pending       int             `json:"pending"`
+	Rejected      int             `json:"rejected"`
+	Payments      []PaymentReport `json:"payments"`
+}
+
+// PaymentReport is the outcome of one payment of a file
+type PaymentReport struct {
+	iso20022.Payment
+	Status     string      `json:"status"`
+	TransferID int64       `json:"transfer_id,omitempty"`
+	ReasonCode apperr.Code `json:"reason_code,omitempty"`
+	Reason     string      `json:"reason,omitempty"`
+}
+
+// ImportPaymentFile parses a pain.001 message sent by owner and executes its
+// payments. The whole file is refused if it is malformed, was imported
+// before, or any payment debits an account of someone else or in another
+// currency. Payments of an accepted file are executed one by one in order and
+// can still be rejected individually, for example for insufficient funds, or
+// held for review by the fraud rules.
+func (service *Service) ImportPaymentFile(ctx context.Context, owner string, r io.Reader) (PaymentFileReport, error) {
+	document, payments, err := iso20022.ParsePain001(r)
+	if err != nil {
+		return PaymentFileReport{}, ErrPaymentFileInvalid.WithDetail("reason", err.Error())
+	}
+
+	accounts := make(map[string]db.Account)
+	for _, payment := range payments {
+		if err := service.validPayment(ctx, owner, payment, accounts); err != nil {
+			return PaymentFileReport{}, err
+		}
+	}
+
+	var paymentFile db.PaymentFile
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		paymentFile, err = q.CreatePaymentFile(ctx, db.CreatePaymentFileParams{
+			Owner:            owner,
+			MessageID:        document.MessageID(),
+			NumberOfPayments: int32(len(payments)),
+			ControlSum: sql.NullString{
+				String: document.ControlSum(),
+				Valid:  document.ControlSum() != "",
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        owner,
+			Action:       audit.ActionPaymentFileImported,
+			ResourceType: audit.ResourcePaymentFile,
+			ResourceID:   strconv.FormatInt(paymentFile.ID, 10),
+			Diff:         map[string]any{"after": paymentFile},
+		})
+	})
+	if err != nil {
+		switch pqErrorName(err) {
+		case "unique_violation":
+			return PaymentFileReport{}, ErrPaymentFileDuplicate.WithDetail("message_id", document.MessageID())
+		case "foreign_key_violation":
+			return PaymentFileReport{}, ErrUserNotFound
+		}
+		return PaymentFileReport{}, fmt.Errorf("cannot create payment file: %w", err)
+	}
+
+	report := PaymentFileReport{
+		PaymentFileID: paymentFile.ID,
+		MessageID:     paymentFile.MessageID,
+		Payments:      make([]PaymentReport, 0, len(payments)),
+	}
+	for _, payment := range payments {
+		result, err := service.CreateTransfer(ctx, CreateTransferParams{
+			Owner:         owner,
+			FromAccountID: accounts[payment.DebtorAccount].ID,
+			ToAccountID:   accounts[payment.CreditorAccount].ID,
+			Amount:        money.New(payment.Amount, payment.Currency),
+		})
+
+		paymentReport := PaymentReport{Payment: payment, Status: PaymentAccepted}
+		if err != nil {
+			appErr := apperr.From(err)
+			paymentReport.Status = PaymentRejected
+			paymentReport.ReasonCode = appErr.Code
+			paymentReport.Reason = appErr.Message
+			if errors.Is(err, ErrTransferHeld) {
+				paymentReport.Status = PaymentPending
+				report.Pending++
+			} else {
+				report.Rejected++
+			}
+		} else {
+			paymentReport.TransferID = result.Transfer.ID
+			report.Accepted++
+		}
+		report.Payments = append(report.Payments, paymentReport)
+	}
+
+	return report, nil
+}
+
+// validPayment checks that owner may debit the account of payment and that
+// both accounts hold its currency. Accounts are cached across a file.
+func (service *Service) validPayment(ctx context.Context, owner string, payment iso20022.Payment, accounts map[string]db.Account) error {
+	for _, number := range []string{payment.DebtorAccount, payment.CreditorAccount} {
+		account, ok := accounts[number]
+		if !ok {
+			var err error
+			account, err = service.getAccountByNumber(ctx, number)
+			if err != nil {
+				return withEndToEndID(err, payment)
+			}
+			accounts[number] = account
+		}
+
+		if number == payment.DebtorAccount && account.Owner != owner {
+			return ErrAccountNotOwned.
+				WithDetail("account_number", number).
+				WithDetail("end_to_end_id", payment.EndToEndID)
+		}
+
+		if account.Currency != payment.Currency {
+			return ErrCurrencyMismatch.
+				WithDetail("account_number", number).
+				WithDetail("account_currency", account.Currency).
+				WithDetail("requested_currency", payment.Currency).
+				WithDetail("end_to_end_id", payment.EndToEndID)
+		}
+	}
+
+	return nil
+}
+
+// withEndToEndID points a domain error at the payment that caused it
+func withEndToEndID(err error, payment iso20022.Payment) error {
+	if appErr, ok := err.(*apperr.Error); ok {
+		return appErr.WithDetail("end_to_end_id", payment.EndToEndID)
+	}
+
+	return err
+}
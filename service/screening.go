@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/fraud"
+	"github.com/techschool/simplebank/money"
+)
+
+// screenTransfer evaluates the fraud rules on a transfer. Denied transfers
+// are audited and refused, those sent for review are held in the queue.
+// The reasons are kept for bankers and never told to the sender.
+func (service *Service) screenTransfer(ctx context.Context, arg CreateTransferParams, fromAccount db.Account, toAccount db.Account) error {
+	verdict, err := service.fraudEngine.Screen(ctx, service.store, fraud.Transfer{
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		Amount:      arg.Amount,
+		Time:        time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot screen transfer: %w", err)
+	}
+
+	switch verdict.Decision {
+	case fraud.Deny:
+		err := service.recordTx(ctx, audit.Event{
+			Actor:        arg.Owner,
+			Action:       audit.ActionTransferDenied,
+			ResourceType: audit.ResourceAccount,
+			ResourceID:   strconv.FormatInt(arg.FromAccountID, 10),
+			Diff: map[string]any{
+				"to_account_id": arg.ToAccountID,
+				"amount":        arg.Amount,
+				"hits":          verdict.Hits,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return ErrTransferDenied
+
+	case fraud.Review:
+		review, err := service.holdTransfer(ctx, arg, verdict.Hits)
+		if err != nil {
+			return err
+		}
+		return ErrTransferHeld.WithDetail("review_id", review.ID)
+	}
+
+	return nil
+}
+
+// holdTransfer puts a transfer in the review queue
+func (service *Service) holdTransfer(ctx context.Context, arg CreateTransferParams, hits []fraud.Hit) (db.TransferReview, error) {
+	data, err := json.Marshal(hits)
+	if err != nil {
+		return db.TransferReview{}, fmt.Errorf("cannot marshal rule hits: %w", err)
+	}
+
+	var review db.TransferReview
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		review, err = q.CreateTransferReview(ctx, db.CreateTransferReviewParams{
+			Owner:         arg.Owner,
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount.Amount,
+			Currency:      arg.Amount.Currency,
+			Hits:          data,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot hold transfer: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        arg.Owner,
+			Action:       audit.ActionTransferHeld,
+			ResourceType: audit.ResourceReview,
+			ResourceID:   strconv.FormatInt(review.ID, 10),
+			Diff:         map[string]any{"after": review},
+		})
+	})
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	return review, nil
+}
+
+// ListTransferReviewsParams contains the status and page of reviews to list
+type ListTransferReviewsParams struct {
+	Status   string
+	PageID   int32
+	PageSize int32
+}
+
+// ListTransferReviews returns a page of the review queue, oldest first
+func (service *Service) ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]db.TransferReview, error) {
+	reviews, err := service.store.ListTransferReviews(ctx, db.ListTransferReviewsParams{
+		Status: arg.Status,
+		Limit:  arg.PageSize,
+		Offset: (arg.PageID - 1) * arg.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list transfer reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// ApproveTransferReview makes a held transfer on behalf of reviewer. The
+// accounts and balance are checked again since they may have changed while
+// the transfer waited, but the fraud rules are not.
+func (service *Service) ApproveTransferReview(ctx context.Context, reviewer string, id int64) (db.TransferReview, error) {
+	review, err := service.pendingTransferReview(ctx, reviewer, id)
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	arg := CreateTransferParams{
+		Owner:         review.Owner,
+		FromAccountID: review.FromAccountID,
+		ToAccountID:   review.ToAccountID,
+		Amount:        money.New(review.Amount, review.Currency),
+	}
+
+	fromAccount, toAccount, err := service.transferAccounts(ctx, arg)
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	_, err = service.transfer(ctx, arg, fromAccount, toAccount, func(q db.Querier, result db.TransferTxResult) error {
+		review, err = decideTransferReview(ctx, q, review, db.DecideTransferReviewParams{
+			ID:         review.ID,
+			Status:     fraud.ReviewApproved,
+			Reviewer:   sql.NullString{String: reviewer, Valid: true},
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	return review, nil
+}
+
+// RejectTransferReview drops a held transfer on behalf of reviewer
+func (service *Service) RejectTransferReview(ctx context.Context, reviewer string, id int64, reason string) (db.TransferReview, error) {
+	review, err := service.pendingTransferReview(ctx, reviewer, id)
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		review, err = decideTransferReview(ctx, q, review, db.DecideTransferReviewParams{
+			ID:       review.ID,
+			Status:   fraud.ReviewRejected,
+			Reviewer: sql.NullString{String: reviewer, Valid: true},
+			Reason:   reason,
+		})
+		return err
+	})
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	return review, nil
+}
+
+// pendingTransferReview returns review id if it is still pending and
+// reviewer is not the one who asked for the transfer
+func (service *Service) pendingTransferReview(ctx context.Context, reviewer string, id int64) (db.TransferReview, error) {
+	review, err := service.store.GetTransferReview(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.TransferReview{}, ErrTransferReviewNotFound.WithDetail("review_id", id)
+		}
+		return db.TransferReview{}, fmt.Errorf("cannot get transfer review: %w", err)
+	}
+
+	if review.Status != fraud.ReviewPending {
+		return db.TransferReview{}, ErrTransferReviewDecided.
+			WithDetail("review_id", id).
+			WithDetail("status", review.Status)
+	}
+
+	if review.Owner == reviewer {
+		return db.TransferReview{}, ErrPermissionDenied.WithDetail("reason", "bankers cannot review their own transfers")
+	}
+
+	return review, nil
+}
+
+// decideTransferReview records the decision on a pending review. It fails
+// with ErrTransferReviewDecided if another banker decided it first.
+func decideTransferReview(ctx context.Context, q db.Querier, before db.TransferReview, arg db.DecideTransferReviewParams) (db.TransferReview, error) {
+	review, err := q.DecideTransferReview(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.TransferReview{}, ErrTransferReviewDecided.WithDetail("review_id", arg.ID)
+		}
+		return db.TransferReview{}, fmt.Errorf("cannot decide transfer review: %w", err)
+	}
+
+	action := audit.ActionTransferApproved
+	if review.Status == fraud.ReviewRejected {
+		action = audit.ActionTransferRejected
+	}
+
+	err = record(ctx, q, audit.Event{
+		Actor:        arg.Reviewer.String,
+		Action:       action,
+		ResourceType: audit.ResourceReview,
+		ResourceID:   strconv.FormatInt(review.ID, 10),
+		Diff:         map[string]any{"before": before, "after": review},
+	})
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	return review, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/fraud"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateTransferScreening(t *testing.T) {
+	sender, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: sender.Username, Balance: 100, Currency: util.USD}
+	toAccount := db.Account{ID: 2, Owner: recipient.Username, Currency: util.USD, Number: "SB12000000000007"}
+
+	testCases := []struct {
+		name       string
+		decision   fraud.Decision
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+		actions    []string
+	}{
+		{
+			name:     "Review",
+			decision: fraud.Review,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTransferReview(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTransferReviewParams) (db.TransferReview, error) {
+						require.Equal(t, sender.Username, arg.Owner)
+						require.Equal(t, int64(10), arg.Amount)
+						require.JSONEq(t, `[{"rule":"blocklist","decision":"review","reason":"account SB12000000000007 is blocklisted"}]`, string(arg.Hits))
+						return db.TransferReview{ID: 9, Status: fraud.ReviewPending}, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTransferHeld)
+				require.Equal(t, int64(9), apperr.From(err).Details["review_id"])
+			},
+			actions: []string{audit.ActionTransferHeld},
+		},
+		{
+			name:     "Deny",
+			decision: fraud.Deny,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTransferDenied)
+				require.Empty(t, apperr.From(err).Details)
+			},
+			actions: []string{audit.ActionTransferDenied},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+			store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			tc.buildStubs(store)
+			events := stubTx(store)
+
+			service := newTestService(t, store)
+			service.fraudEngine = fraud.NewEngine(fraud.BlocklistRule{
+				RuleName: "blocklist",
+				Decision: tc.decision,
+				Accounts: map[string]bool{toAccount.Number: true},
+			})
+
+			_, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+				Owner:         sender.Username,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   toAccount.ID,
+				Amount:        money.New(10, util.USD),
+			})
+			tc.checkError(t, err)
+			require.Equal(t, tc.actions, auditActions(*events))
+		})
+	}
+}
+
+func TestApproveTransferReview(t *testing.T) {
+	sender, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+	banker, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: sender.Username, Balance: 100, Currency: util.USD}
+	toAccount := db.Account{ID: 2, Owner: recipient.Username, Currency: util.USD}
+	review := db.TransferReview{
+		ID:            9,
+		Owner:         sender.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+		Currency:      util.USD,
+		Status:        fraud.ReviewPending,
+	}
+	result := db.TransferTxResult{Transfer: db.Transfer{ID: 5, FromAccountID: 1, ToAccountID: 2, Amount: 10}}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			require.Equal(t, review.Amount, arg.Amount)
+			return result, arg.AfterTransfer(store, result)
+		})
+	store.EXPECT().
+		DecideTransferReview(gomock.Any(), gomock.Eq(db.DecideTransferReviewParams{
+			ID:         review.ID,
+			Status:     fraud.ReviewApproved,
+			Reviewer:   sql.NullString{String: banker.Username, Valid: true},
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})).
+		Times(1).
+		Return(db.TransferReview{ID: review.ID, Status: fraud.ReviewApproved, TransferID: sql.NullInt64{Int64: 5, Valid: true}}, nil)
+	events := stubTx(store)
+
+	service := newTestService(t, store)
+	approved, err := service.ApproveTransferReview(context.Background(), banker.Username, review.ID)
+	require.NoError(t, err)
+	require.Equal(t, fraud.ReviewApproved, approved.Status)
+	require.Equal(t, []string{audit.ActionTransferCreated, audit.ActionTransferApproved}, auditActions(*events))
+	require.Equal(t, sender.Username, (*events)[0].Actor)
+	require.Equal(t, banker.Username, (*events)[1].Actor)
+}
+
+func TestRejectTransferReview(t *testing.T) {
+	sender, _ := randomUser(t)
+	banker, _ := randomUser(t)
+
+	pending := db.TransferReview{ID: 9, Owner: sender.Username, Status: fraud.ReviewPending}
+
+	testCases := []struct {
+		name       string
+		reviewer   string
+		buildStubs func(store *mockdb.MockStore)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name:     "OK",
+			reviewer: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().
+					DecideTransferReview(gomock.Any(), gomock.Eq(db.DecideTransferReviewParams{
+						ID:       pending.ID,
+						Status:   fraud.ReviewRejected,
+						Reviewer: sql.NullString{String: banker.Username, Valid: true},
+						Reason:   "mule account",
+					})).
+					Times(1).
+					Return(db.TransferReview{ID: pending.ID, Status: fraud.ReviewRejected}, nil)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "NotFound",
+			reviewer: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferReview{}, sql.ErrNoRows)
+				store.EXPECT().DecideTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTransferReviewNotFound)
+			},
+		},
+		{
+			name:     "AlreadyDecided",
+			reviewer: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				approved := pending
+				approved.Status = fraud.ReviewApproved
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(approved, nil)
+				store.EXPECT().DecideTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTransferReviewDecided)
+			},
+		},
+		{
+			name:     "DecidedConcurrently",
+			reviewer: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(pending, nil)
+				store.EXPECT().DecideTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferReview{}, sql.ErrNoRows)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTransferReviewDecided)
+			},
+		},
+		{
+			name:     "OwnTransfer",
+			reviewer: sender.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(pending, nil)
+				store.EXPECT().DecideTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrPermissionDenied)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			tc.buildStubs(store)
+			stubTx(store)
+
+			service := newTestService(t, store)
+			_, err := service.RejectTransferReview(context.Background(), tc.reviewer, pending.ID, "mule account")
+			tc.checkError(t, err)
+		})
+	}
+}
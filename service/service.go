@@ -0,0 +1,58 @@
+package service
+
+import (
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/fraud"
+	"github.com/techschool/simplebank/metrics"
+	"github.com/techschool/simplebank/sanctions"
+	"github.com/techschool/simplebank/token"
+	"github.com/techschool/simplebank/util"
+)
+
+// Service holds the business logic shared by the Gin and gRPC servers
+type Service struct {
+	config            util.Config
+	store             db.Store
+	tokenMaker        token.Maker
+	metrics           *metrics.Metrics
+	fraudEngine       *fraud.Engine
+	sanctionsScreener *sanctions.Screener
+}
+
+// New creates a new service. metrics may be nil. Transfers are screened
+// against the rules of config.FraudRulesFile and the names of new users
+// against the list of config.SanctionsListFile, if set.
+func New(store db.Store, tokenMaker token.Maker, config util.Config, metrics *metrics.Metrics) (*Service, error) {
+	var fraudEngine *fraud.Engine
+	if config.FraudRulesFile != "" {
+		var err error
+		fraudEngine, err = fraud.LoadFile(config.FraudRulesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var sanctionsScreener *sanctions.Screener
+	if config.SanctionsListFile != "" {
+		watchlist, err := sanctions.OpenWatchlist(config.SanctionsListFile)
+		if err != nil {
+			return nil, err
+		}
+		sanctionsScreener, err = sanctions.NewScreener(watchlist, sanctions.Config{
+			Threshold: config.SanctionsThreshold,
+			Action:    config.SanctionsAction,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Service{
+		config:            config,
+		store:             store,
+		tokenMaker:        tokenMaker,
+		metrics:           metrics,
+		fraudEngine:       fraudEngine,
+		sanctionsScreener: sanctionsScreener,
+	}, nil
+}
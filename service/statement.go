@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/statement"
+)
+
+// StatementParams selects the account of owner and the period [From, To)
+type StatementParams struct {
+	Owner         string
+	AccountNumber string
+	From          time.Time
+	To            time.Time
+}
+
+// WriteStatement streams the statement of an account to writer. Nothing is
+// written if the account cannot be read by owner.
+func (service *Service) WriteStatement(ctx context.Context, arg StatementParams, writer statement.Writer) error {
+	return service.store.StatementTx(ctx, func(q db.StatementQuerier) error {
+		account, err := accountByNumber(ctx, q, arg.AccountNumber)
+		if err != nil {
+			return err
+		}
+
+		if account.Owner != arg.Owner {
+			return ErrAccountNotOwned.WithDetail("account_number", account.Number)
+		}
+
+		// entries are the only history, so walk back from the current balance
+		since, err := q.SumEntriesSince(ctx, db.SumEntriesSinceParams{
+			AccountID: account.ID,
+			CreatedAt: arg.From,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot sum entries: %w", err)
+		}
+
+		after, err := q.SumEntriesSince(ctx, db.SumEntriesSinceParams{
+			AccountID: account.ID,
+			CreatedAt: arg.To,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot sum entries: %w", err)
+		}
+
+		header := statement.Header{
+			AccountNumber:  account.Number,
+			Owner:          account.Owner,
+			Currency:       account.Currency,
+			From:           arg.From,
+			To:             arg.To,
+			OpeningBalance: account.Balance - since,
+			ClosingBalance: account.Balance - after,
+			GeneratedAt:    time.Now(),
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+
+		summary := statement.Summary{ClosingBalance: header.OpeningBalance}
+		err = q.StreamStatementEntries(ctx, db.StreamStatementEntriesParams{
+			AccountID: account.ID,
+			From:      arg.From,
+			To:        arg.To,
+		}, func(entry db.StatementEntry) error {
+			line := statement.Line{
+				EntryID:             entry.ID,
+				Time:                entry.CreatedAt,
+				Amount:              entry.Amount,
+				Balance:             summary.ClosingBalance + entry.Amount,
+				TransferID:          entry.TransferID.Int64,
+				CounterpartyAccount: entry.CounterpartyAccountNumber.String,
+				CounterpartyOwner:   entry.CounterpartyOwner.String,
+			}
+			summary.Add(line)
+			return writer.WriteLine(line)
+		})
+		if err != nil {
+			return fmt.Errorf("cannot stream statement: %w", err)
+		}
+
+		return writer.WriteSummary(summary)
+	})
+}
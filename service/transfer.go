@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/outbox"
+)
+
+// CreateTransferParams contains the input parameters of a transfer made by owner
+type CreateTransferParams struct {
+	Owner         string
+	FromAccountID int64
+	ToAccountID   int64
+	// FromAccountNumber and ToAccountNumber, if set, give the accounts by
+	// their public number instead of FromAccountID and ToAccountID
+	FromAccountNumber string
+	ToAccountNumber   string
+	// PayeeID, if set, sends the money to the account of a payee of Owner
+	// instead of ToAccountID
+	PayeeID int64
+	// ToAlias, if set, sends the money to the DefaultProduct account in the
+	// currency of Amount of the user with this username, email or phone
+	ToAlias string
+	Amount  money.Money
+}
+
+// CreateTransfer checks ownership and currencies of both accounts, screens
+// the transfer against the fraud rules and moves the money. Transfers the
+// rules deny fail with ErrTransferDenied, those they send for review are
+// held with ErrTransferHeld until a banker approves them. Payees still in
+// their cooling-off period fail with ErrPayeeCoolingOff, aliases without an
+// account in the currency of the amount with ErrRecipientNotFound.
+// Transfers to the account they are sent from fail with ErrSameAccount.
+func (service *Service) CreateTransfer(ctx context.Context, arg CreateTransferParams) (db.TransferTxResult, error) {
+	if !arg.Amount.IsPositive() {
+		return db.TransferTxResult{}, ErrInvalidAmount.WithDetail("amount", arg.Amount.Decimal())
+	}
+
+	if arg.FromAccountNumber != "" {
+		account, err := service.getAccountByNumber(ctx, arg.FromAccountNumber)
+		if err != nil {
+			return db.TransferTxResult{}, err
+		}
+		arg.FromAccountID = account.ID
+	}
+
+	if arg.ToAccountNumber != "" {
+		account, err := service.getAccountByNumber(ctx, arg.ToAccountNumber)
+		if err != nil {
+			return db.TransferTxResult{}, err
+		}
+		arg.ToAccountID = account.ID
+	}
+
+	if arg.PayeeID != 0 {
+		payee, err := service.activePayee(ctx, arg.Owner, arg.PayeeID)
+		if err != nil {
+			return db.TransferTxResult{}, err
+		}
+		arg.ToAccountID = payee.AccountID
+	}
+
+	if arg.ToAlias != "" {
+		account, _, err := service.recipientAccount(ctx, arg.Owner, arg.ToAlias, arg.Amount.Currency)
+		if err != nil {
+			return db.TransferTxResult{}, err
+		}
+		arg.ToAccountID = account.ID
+	}
+
+	if arg.FromAccountID == arg.ToAccountID {
+		return db.TransferTxResult{}, ErrSameAccount
+	}
+
+	fromAccount, toAccount, err := service.transferAccounts(ctx, arg)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	if err := service.screenTransfer(ctx, arg, fromAccount, toAccount); err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	return service.transfer(ctx, arg, fromAccount, toAccount, nil)
+}
+
+// transferAccounts returns both accounts of a transfer once the sender owns
+// the first one, both hold the currency of the amount and the balance covers
+// it. TransferTx checks the balance again once the account is locked.
+func (service *Service) transferAccounts(ctx context.Context, arg CreateTransferParams) (db.Account, db.Account, error) {
+	currency := arg.Amount.Currency
+
+	fromAccount, err := service.validAccount(ctx, arg.FromAccountID, currency)
+	if err != nil {
+		return db.Account{}, db.Account{}, err
+	}
+
+	if fromAccount.Owner != arg.Owner {
+		return db.Account{}, db.Account{}, ErrAccountNotOwned.WithDetail("account_number", fromAccount.Number)
+	}
+
+	if fromAccount.Balance < arg.Amount.Amount {
+		return db.Account{}, db.Account{}, ErrInsufficientFunds.WithDetail("account_number", fromAccount.Number)
+	}
+
+	toAccount, err := service.validAccount(ctx, arg.ToAccountID, currency)
+	if err != nil {
+		return db.Account{}, db.Account{}, err
+	}
+
+	return fromAccount, toAccount, nil
+}
+
+// transfer moves the money and records the transfer. afterTransfer, if set,
+// runs in the same transaction once the transfer is recorded.
+func (service *Service) transfer(ctx context.Context, arg CreateTransferParams, fromAccount db.Account, toAccount db.Account, afterTransfer func(q db.Querier, result db.TransferTxResult) error) (db.TransferTxResult, error) {
+	currency := arg.Amount.Currency
+
+	startTime := time.Now()
+	result, err := service.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount.Amount,
+		AfterTransfer: func(q db.Querier, result db.TransferTxResult) error {
+			transferID := strconv.FormatInt(result.Transfer.ID, 10)
+			err := record(ctx, q, audit.Event{
+				Actor:        arg.Owner,
+				Action:       audit.ActionTransferCreated,
+				ResourceType: audit.ResourceTransfer,
+				ResourceID:   transferID,
+				Diff:         transferDiff(result),
+			})
+			if err != nil {
+				return err
+			}
+
+			// keyed by the source account so its transfers follow its creation in order
+			fromAccountID := strconv.FormatInt(arg.FromAccountID, 10)
+			err = outbox.Enqueue(ctx, q, outbox.TransferCreated, outbox.AggregateAccount, fromAccountID, transferCreatedPayload{
+				Transfer: result.Transfer,
+				Currency: currency,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = notifyTransfer(ctx, q, fromAccount.Owner, toAccount.Owner, currency, result)
+			if err != nil {
+				return err
+			}
+
+			if afterTransfer != nil {
+				return afterTransfer(q, result)
+			}
+
+			return nil
+		},
+	})
+	service.metrics.ObserveTransfer(currency, arg.Amount.Amount, time.Since(startTime), err)
+	service.metrics.AddTransferRetries(result.Retries)
+	if err != nil {
+		var fundsErr *db.InsufficientFundsError
+		if errors.As(err, &fundsErr) {
+			return db.TransferTxResult{}, ErrInsufficientFunds.WithDetail("account_number", fromAccount.Number)
+		}
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			return db.TransferTxResult{}, limitExceededError(limitErr)
+		}
+		var tierErr *db.KYCTierError
+		if errors.As(err, &tierErr) {
+			return db.TransferTxResult{}, kycTierRequiredError(tierErr)
+		}
+		var blockedErr *db.UserBlockedError
+		if errors.As(err, &blockedErr) {
+			return db.TransferTxResult{}, ErrUserBlocked
+		}
+		return db.TransferTxResult{}, fmt.Errorf("cannot transfer money: %w", err)
+	}
+
+	return result, nil
+}
+
+// transferCreatedPayload is the body of a TransferCreated event
+type transferCreatedPayload struct {
+	db.Transfer
+	Currency string `json:"currency"`
+}
+
+// transferDiff records the balances of both accounts before and after result
+func transferDiff(result db.TransferTxResult) map[string]any {
+	amount := result.Transfer.Amount
+	return map[string]any{
+		"transfer": result.Transfer,
+		"from_account": map[string]any{
+			"id":     result.FromAccount.ID,
+			"before": result.FromAccount.Balance + amount,
+			"after":  result.FromAccount.Balance,
+		},
+		"to_account": map[string]any{
+			"id":     result.ToAccount.ID,
+			"before": result.ToAccount.Balance - amount,
+			"after":  result.ToAccount.Balance,
+		},
+	}
+}
+
+func (service *Service) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := service.getAccount(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Currency != currency {
+		return account, ErrCurrencyMismatch.
+			WithDetail("account_number", account.Number).
+			WithDetail("account_currency", account.Currency).
+			WithDetail("requested_currency", currency)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/apperr"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/outbox"
+	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/webhook"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateTransferNotifiesInTransaction(t *testing.T) {
+	sender, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: sender.Username, Balance: 100, Currency: util.USD}
+	toAccount := db.Account{ID: 2, Owner: recipient.Username, Balance: 0, Currency: util.USD}
+	result := db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 5, FromAccountID: 1, ToAccountID: 2, Amount: 10},
+		FromAccount: db.Account{ID: 1, Owner: sender.Username, Balance: 90, Currency: util.USD},
+		ToAccount:   db.Account{ID: 2, Owner: recipient.Username, Balance: 10, Currency: util.USD, Number: "SB55000000000009"},
+		FromEntry:   db.Entry{ID: 7, AccountID: 1, Amount: -10},
+		ToEntry:     db.Entry{ID: 8, AccountID: 2, Amount: 10},
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			// the hook runs with the transaction's querier
+			return result, arg.AfterTransfer(store, result)
+		})
+
+	var outboxEvents []db.CreateOutboxEventParams
+	store.EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateOutboxEventParams) (db.Outbox, error) {
+			outboxEvents = append(outboxEvents, arg)
+			return db.Outbox{}, nil
+		})
+
+	store.EXPECT().
+		ListWebhookSubscriptionsForEvent(gomock.Any(), gomock.Eq(db.ListWebhookSubscriptionsForEventParams{
+			Owner:     recipient.Username,
+			EventType: webhook.EventEntryCreated,
+		})).
+		Times(1).
+		Return([]db.WebhookSubscription{{ID: 3, Owner: recipient.Username}}, nil)
+
+	var deliveries []db.CreateWebhookDeliveryParams
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+			deliveries = append(deliveries, arg)
+			return db.WebhookDelivery{}, nil
+		})
+
+	events := stubTx(store)
+
+	service := newTestService(t, store)
+	_, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         sender.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        money.New(10, util.USD),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{audit.ActionTransferCreated}, auditActions(*events))
+
+	require.Len(t, outboxEvents, 1)
+	require.Equal(t, outbox.TransferCreated, outboxEvents[0].EventType)
+	require.Equal(t, "1", outboxEvents[0].AggregateID)
+
+	require.Len(t, deliveries, 1)
+	require.Equal(t, int64(3), deliveries[0].SubscriptionID)
+	require.JSONEq(t, `{"id":8,"account_number":"SB55000000000009","amount":10,"created_at":"0001-01-01T00:00:00Z","currency":"USD"}`, string(deliveries[0].Payload))
+}
+
+func TestCreateTransferInsufficientFundsWhenLocked(t *testing.T) {
+	owner, _ := randomUser(t)
+
+	// the balance read before locking still covers the transfer
+	fromAccount := db.Account{ID: 1, Owner: owner.Username, Balance: 100, Currency: util.USD, Number: "SB12000000000007"}
+	toAccount := db.Account{ID: 2, Owner: owner.Username, Currency: util.USD}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TransferTxResult{}, &db.InsufficientFundsError{AccountID: fromAccount.ID, Balance: 20, Requested: 80})
+
+	service := newTestService(t, store)
+	_, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         owner.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        money.New(80, util.USD),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	require.Equal(t, fromAccount.Number, apperr.From(err).Details["account_number"])
+}
+
+func TestCreateTransferUserBlocked(t *testing.T) {
+	owner, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: owner.Username, Balance: 100, Currency: util.USD}
+	toAccount := db.Account{ID: 2, Owner: owner.Username, Currency: util.USD}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TransferTxResult{}, &db.UserBlockedError{Username: owner.Username})
+
+	service := newTestService(t, store)
+	_, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         owner.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        money.New(10, util.USD),
+	})
+	require.ErrorIs(t, err, ErrUserBlocked)
+}
+
+func TestCreateTransferAccountNotFound(t *testing.T) {
+	owner, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: owner.Username, Balance: 100, Currency: util.USD}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(2))).Times(1).Return(db.Account{}, sql.ErrNoRows)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	service := newTestService(t, store)
+	_, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         owner.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   2,
+		Amount:        money.New(10, util.USD),
+	})
+	require.ErrorIs(t, err, ErrAccountNotFound)
+	require.NotContains(t, apperr.From(err).Details, "account_id")
+}
+
+func TestCreateTransferLimitExceeded(t *testing.T) {
+	owner, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: owner.Username, Balance: 100_000, Currency: util.USD}
+	toAccount := db.Account{ID: 2, Owner: owner.Username, Currency: util.USD}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TransferTxResult{}, &db.LimitExceededError{
+			LimitUsage: db.LimitUsage{
+				Name:      db.LimitDailyAmount,
+				Currency:  util.USD,
+				Limit:     50_000,
+				Used:      45_000,
+				Remaining: 5_000,
+			},
+			Requested: 10_000,
+		})
+
+	service := newTestService(t, store)
+	_, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         owner.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        money.New(10_000, util.USD),
+	})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	details := apperr.From(err).Details
+	require.Equal(t, db.LimitDailyAmount, details["limit"])
+	require.Equal(t, "500.00", details["max"])
+	require.Equal(t, "50.00", details["remaining"])
+}
+
+func TestCreateTransferSameAccount(t *testing.T) {
+	owner, _ := randomUser(t)
+	account := db.Account{ID: 1, Owner: owner.Username, Balance: 100, Currency: util.USD, Number: "SB12000000000007"}
+
+	testCases := []struct {
+		name       string
+		arg        CreateTransferParams
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "ByID",
+			arg:  CreateTransferParams{FromAccountID: account.ID, ToAccountID: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+			},
+		},
+		{
+			name: "ByNumber",
+			arg:  CreateTransferParams{FromAccountID: account.ID, ToAccountNumber: account.Number},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.Number)).Times(1).Return(account, nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			tc.buildStubs(store)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+			tc.arg.Owner = owner.Username
+			tc.arg.Amount = money.New(10, util.USD)
+
+			service := newTestService(t, store)
+			_, err := service.CreateTransfer(context.Background(), tc.arg)
+			require.ErrorIs(t, err, ErrSameAccount)
+		})
+	}
+}
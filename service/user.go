@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/outbox"
+	"github.com/techschool/simplebank/sanctions"
+	"github.com/techschool/simplebank/util"
+)
+
+// CreateUserParams contains the input parameters to register a user
+type CreateUserParams struct {
+	Username string
+	Password string
+	FullName string
+	Email    string
+	// Phone is optional, other users can send money to it as an alias
+	Phone string
+}
+
+// CreateUser hashes the password and stores a new user once their full name
+// is screened against the sanctions list. Matching users are flagged, or
+// refused with ErrUserBlocked if the list is set to block.
+func (service *Service) CreateUser(ctx context.Context, arg CreateUserParams) (db.User, error) {
+	hashedPassword, err := util.HashPassword(arg.Password)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	screening := service.sanctionsScreener.Screen(ctx, arg.FullName)
+	if screening.Status == sanctions.StatusBlocked {
+		err := service.recordTx(ctx, audit.Event{
+			Actor:        arg.Username,
+			Action:       audit.ActionUserBlocked,
+			ResourceType: audit.ResourceUser,
+			ResourceID:   arg.Username,
+			Diff: map[string]any{
+				"list_version": screening.ListVersion,
+				"match":        screening.Match,
+			},
+		})
+		if err != nil {
+			return db.User{}, err
+		}
+		return db.User{}, ErrUserBlocked
+	}
+
+	var user db.User
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		user, err = q.CreateUser(ctx, db.CreateUserParams{
+			Username:       arg.Username,
+			HashedPassword: hashedPassword,
+			FullName:       arg.FullName,
+			Email:          arg.Email,
+			Phone:          arg.Phone,
+		})
+		if err != nil {
+			return err
+		}
+
+		if screening.Status != sanctions.StatusUnscreened {
+			user, err = sanctions.Record(ctx, q, user.Username, screening)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = record(ctx, q, audit.Event{
+			Actor:        user.Username,
+			Action:       audit.ActionUserCreated,
+			ResourceType: audit.ResourceUser,
+			ResourceID:   user.Username,
+			Diff:         map[string]any{"after": userDiff(user)},
+		})
+		if err != nil {
+			return err
+		}
+
+		return outbox.Enqueue(ctx, q, outbox.UserRegistered, outbox.AggregateUser, user.Username, userDiff(user))
+	})
+	if err != nil {
+		if pqErrorName(err) == "unique_violation" {
+			return db.User{}, ErrUserAlreadyExists
+		}
+		return db.User{}, fmt.Errorf("cannot create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// RequireRole checks that username exists and has role
+func (service *Service) RequireRole(ctx context.Context, username string, role string) error {
+	user, err := service.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if user.Role != role {
+		return ErrPermissionDenied.WithDetail("required_role", role)
+	}
+
+	return nil
+}
+
+// GetUser returns the user with the given username
+func (service *Service) GetUser(ctx context.Context, username string) (db.User, error) {
+	user, err := service.store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.User{}, ErrUserNotFound
+		}
+		return db.User{}, fmt.Errorf("cannot get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// ListUserScreeningsParams contains the user and page of screenings to list
+type ListUserScreeningsParams struct {
+	Username string
+	PageID   int32
+	PageSize int32
+}
+
+// ListUserScreenings returns a page of the sanctions screenings of a user,
+// latest first
+func (service *Service) ListUserScreenings(ctx context.Context, arg ListUserScreeningsParams) ([]db.UserScreening, error) {
+	if _, err := service.GetUser(ctx, arg.Username); err != nil {
+		return nil, err
+	}
+
+	screenings, err := service.store.ListUserScreenings(ctx, db.ListUserScreeningsParams{
+		Username: arg.Username,
+		Limit:    arg.PageSize,
+		Offset:   (arg.PageID - 1) * arg.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list user screenings: %w", err)
+	}
+
+	return screenings, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/audit"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/sanctions"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateUserScreening(t *testing.T) {
+	testCases := []struct {
+		name       string
+		fullName   string
+		action     string
+		buildStubs func(store *mockdb.MockStore)
+		checkUser  func(t *testing.T, user db.User, err error)
+		actions    []string
+	}{
+		{
+			name:     "Clear",
+			fullName: "Jane Doe",
+			action:   sanctions.ActionFlag,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{Username: "jane"}, nil)
+				store.EXPECT().
+					CreateUserScreening(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserScreeningParams) (db.UserScreening, error) {
+						require.Equal(t, sanctions.StatusClear, arg.Status)
+						return db.UserScreening{}, nil
+					})
+				store.EXPECT().
+					UpdateUserScreening(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{Username: "jane", ScreeningStatus: sanctions.StatusClear}, nil)
+			},
+			checkUser: func(t *testing.T, user db.User, err error) {
+				require.NoError(t, err)
+				require.Equal(t, sanctions.StatusClear, user.ScreeningStatus)
+			},
+			actions: []string{audit.ActionUserCreated},
+		},
+		{
+			name:     "Flagged",
+			fullName: "Ivan Petrovsky",
+			action:   sanctions.ActionFlag,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{Username: "ivan"}, nil)
+				store.EXPECT().
+					CreateUserScreening(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserScreeningParams) (db.UserScreening, error) {
+						require.Equal(t, sanctions.StatusFlagged, arg.Status)
+						require.Equal(t, "1001", arg.MatchedUid)
+						return db.UserScreening{}, nil
+					})
+				store.EXPECT().
+					UpdateUserScreening(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{Username: "ivan", ScreeningStatus: sanctions.StatusFlagged}, nil)
+			},
+			checkUser: func(t *testing.T, user db.User, err error) {
+				require.NoError(t, err)
+				require.Equal(t, sanctions.StatusFlagged, user.ScreeningStatus)
+			},
+			actions: []string{audit.ActionUserCreated},
+		},
+		{
+			name:     "Blocked",
+			fullName: "Ivan Petrovsky",
+			action:   sanctions.ActionBlock,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserScreening(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkUser: func(t *testing.T, user db.User, err error) {
+				require.ErrorIs(t, err, ErrUserBlocked)
+			},
+			actions: []string{audit.ActionUserBlocked},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			store := mockdb.NewMockStore(gomock.NewController(t))
+			tc.buildStubs(store)
+			events := stubTx(store)
+
+			watchlist, err := sanctions.OpenWatchlist("../sanctions/testdata/sdn.csv")
+			require.NoError(t, err)
+
+			service := newTestService(t, store)
+			service.sanctionsScreener, err = sanctions.NewScreener(watchlist, sanctions.Config{Action: tc.action})
+			require.NoError(t, err)
+
+			user, err := service.CreateUser(context.Background(), CreateUserParams{
+				Username: util.RandomOwner(),
+				Password: util.RandomString(6),
+				FullName: tc.fullName,
+				Email:    util.RandomEmail(),
+			})
+			tc.checkUser(t, user, err)
+			require.Equal(t, tc.actions, auditActions(*events))
+			for _, event := range *events {
+				require.NotContains(t, string(event.Diff), tc.fullName)
+			}
+		})
+	}
+}
+
+func TestCreateUserKeepsPersonalDataOutOfLogs(t *testing.T) {
+	arg := CreateUserParams{
+		Username: util.RandomOwner(),
+		Password: util.RandomString(6),
+		FullName: "Jane Doe",
+		Email:    util.RandomEmail(),
+		Phone:    "+254712345678",
+	}
+
+	store := mockdb.NewMockStore(gomock.NewController(t))
+	store.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.User{Username: arg.Username, FullName: arg.FullName, Email: arg.Email, Phone: arg.Phone}, nil)
+
+	var outboxEvents []db.CreateOutboxEventParams
+	store.EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateOutboxEventParams) (db.Outbox, error) {
+			outboxEvents = append(outboxEvents, arg)
+			return db.Outbox{}, nil
+		})
+	events := stubTx(store)
+
+	service := newTestService(t, store)
+	_, err := service.CreateUser(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Len(t, *events, 1)
+	require.Len(t, outboxEvents, 1)
+	for _, logged := range []string{string((*events)[0].Diff), string(outboxEvents[0].Payload)} {
+		require.Contains(t, logged, arg.Username)
+		require.NotContains(t, logged, arg.FullName)
+		require.NotContains(t, logged, arg.Email)
+		require.NotContains(t, logged, arg.Phone)
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/techschool/simplebank/audit"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/webhook"
+)
+
+// CreateWebhookSubscriptionParams contains the input parameters of a new subscription
+type CreateWebhookSubscriptionParams struct {
+	Owner      string
+	URL        string
+	EventTypes []string
+}
+
+// CreateWebhookSubscription subscribes owner to events on their accounts. The
+// returned subscription carries the signing secret.
+func (service *Service) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return db.WebhookSubscription{}, err
+	}
+
+	var subscription db.WebhookSubscription
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		subscription, err = q.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+			Owner:      arg.Owner,
+			Url:        arg.URL,
+			Secret:     secret,
+			EventTypes: arg.EventTypes,
+		})
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        arg.Owner,
+			Action:       audit.ActionWebhookCreated,
+			ResourceType: audit.ResourceWebhook,
+			ResourceID:   strconv.FormatInt(subscription.ID, 10),
+			Diff:         map[string]any{"url": subscription.Url, "event_types": subscription.EventTypes},
+		})
+	})
+	if err != nil {
+		if pqErrorName(err) == "foreign_key_violation" {
+			return db.WebhookSubscription{}, ErrUserNotFound
+		}
+		return db.WebhookSubscription{}, fmt.Errorf("cannot create webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// ListWebhookSubscriptions returns the active subscriptions of owner
+func (service *Service) ListWebhookSubscriptions(ctx context.Context, owner string) ([]db.WebhookSubscription, error) {
+	subscriptions, err := service.store.ListWebhookSubscriptions(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// DisableWebhookSubscription stops deliveries to a subscription of owner.
+// Its delivery log is kept.
+func (service *Service) DisableWebhookSubscription(ctx context.Context, owner string, id int64) error {
+	if _, err := service.getWebhookSubscription(ctx, owner, id); err != nil {
+		return err
+	}
+
+	return service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		if _, err := q.DisableWebhookSubscription(ctx, id); err != nil {
+			return fmt.Errorf("cannot disable webhook subscription: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        owner,
+			Action:       audit.ActionWebhookDisabled,
+			ResourceType: audit.ResourceWebhook,
+			ResourceID:   strconv.FormatInt(id, 10),
+		})
+	})
+}
+
+// ListWebhookDeliveriesParams selects a page of the delivery log of a subscription
+type ListWebhookDeliveriesParams struct {
+	Owner          string
+	SubscriptionID int64
+	PageID         int32
+	PageSize       int32
+}
+
+// ListWebhookDeliveries returns the deliveries of a subscription of owner, newest first
+func (service *Service) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	if _, err := service.getWebhookSubscription(ctx, arg.Owner, arg.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := service.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: arg.SubscriptionID,
+		Limit:          arg.PageSize,
+		Offset:         (arg.PageID - 1) * arg.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// ReplayWebhookDelivery queues a new delivery with the same event as an
+// earlier delivery of a subscription of owner
+func (service *Service) ReplayWebhookDelivery(ctx context.Context, owner string, deliveryID int64) (db.WebhookDelivery, error) {
+	delivery, err := service.store.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.WebhookDelivery{}, ErrWebhookDeliveryNotFound.WithDetail("delivery_id", deliveryID)
+		}
+		return db.WebhookDelivery{}, fmt.Errorf("cannot get webhook delivery: %w", err)
+	}
+
+	if _, err := service.getWebhookSubscription(ctx, owner, delivery.SubscriptionID); err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			return db.WebhookDelivery{}, ErrWebhookDeliveryNotFound.WithDetail("delivery_id", deliveryID)
+		}
+		return db.WebhookDelivery{}, err
+	}
+
+	var replay db.WebhookDelivery
+	err = service.store.ExecTx(ctx, db.DefaultTxOptions, func(q db.Querier) error {
+		var err error
+		replay, err = q.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			SubscriptionID: delivery.SubscriptionID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot create webhook delivery: %w", err)
+		}
+
+		return record(ctx, q, audit.Event{
+			Actor:        owner,
+			Action:       audit.ActionWebhookReplayed,
+			ResourceType: audit.ResourceWebhook,
+			ResourceID:   strconv.FormatInt(delivery.SubscriptionID, 10),
+			Diff:         map[string]any{"delivery_id": delivery.ID, "replay_id": replay.ID},
+		})
+	})
+	if err != nil {
+		return db.WebhookDelivery{}, err
+	}
+
+	return replay, nil
+}
+
+// getWebhookSubscription returns an active subscription of owner. Other
+// users' subscriptions are reported as not found.
+func (service *Service) getWebhookSubscription(ctx context.Context, owner string, id int64) (db.WebhookSubscription, error) {
+	subscription, err := service.store.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.WebhookSubscription{}, ErrWebhookNotFound.WithDetail("subscription_id", id)
+		}
+		return db.WebhookSubscription{}, fmt.Errorf("cannot get webhook subscription: %w", err)
+	}
+
+	if subscription.Owner != owner || !subscription.IsActive {
+		return db.WebhookSubscription{}, ErrWebhookNotFound.WithDetail("subscription_id", id)
+	}
+
+	return subscription, nil
+}
+
+// entryPayload is the body of an entry.created webhook. Webhooks go to
+// customers, so accounts are named by their public number.
+type entryPayload struct {
+	ID            int64     `json:"id"`
+	AccountNumber string    `json:"account_number"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// transferPayload is the body of a transfer.created webhook
+type transferPayload struct {
+	ID                int64     `json:"id"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	Amount            int64     `json:"amount"`
+	Currency          string    `json:"currency"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// notifyTransfer fans the entries and transfer of result out to the webhook
+// subscriptions of both account owners
+func notifyTransfer(ctx context.Context, q db.Querier, fromOwner string, toOwner string, currency string, result db.TransferTxResult) error {
+	entries := []struct {
+		owner   string
+		entry   db.Entry
+		account db.Account
+	}{
+		{fromOwner, result.FromEntry, result.FromAccount},
+		{toOwner, result.ToEntry, result.ToAccount},
+	}
+	for _, e := range entries {
+		payload := entryPayload{
+			ID:            e.entry.ID,
+			AccountNumber: e.account.Number,
+			Amount:        e.entry.Amount,
+			Currency:      currency,
+			CreatedAt:     e.entry.CreatedAt,
+		}
+		if err := webhook.Enqueue(ctx, q, e.owner, webhook.EventEntryCreated, payload); err != nil {
+			return err
+		}
+	}
+
+	transfer := transferPayload{
+		ID:                result.Transfer.ID,
+		FromAccountNumber: result.FromAccount.Number,
+		ToAccountNumber:   result.ToAccount.Number,
+		Amount:            result.Transfer.Amount,
+		Currency:          currency,
+		CreatedAt:         result.Transfer.CreatedAt,
+	}
+
+	owners := []string{fromOwner}
+	if toOwner != fromOwner {
+		owners = append(owners, toOwner)
+	}
+	for _, owner := range owners {
+		err := webhook.Enqueue(ctx, q, owner, webhook.EventTransferCreated, transfer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
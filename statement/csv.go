@@ -0,0 +1,79 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvColumns = []string{
+	"date",
+	"entry_id",
+	"description",
+	"transfer_id",
+	"counterparty_account",
+	"counterparty_owner",
+	"amount",
+	"balance",
+}
+
+// csvWriter writes one row per entry between an opening and a closing
+// balance row. csv.Writer buffers a few kilobytes at most.
+type csvWriter struct {
+	w      *csv.Writer
+	header Header
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (writer *csvWriter) WriteHeader(header Header) error {
+	writer.header = header
+
+	if err := writer.w.Write(csvColumns); err != nil {
+		return err
+	}
+
+	return writer.w.Write([]string{
+		formatCSVTime(header.From), "", "Opening balance", "", "", "", "", header.FormatAmount(header.OpeningBalance),
+	})
+}
+
+func (writer *csvWriter) WriteLine(line Line) error {
+	return writer.w.Write([]string{
+		formatCSVTime(line.Time),
+		strconv.FormatInt(line.EntryID, 10),
+		line.Description(),
+		optionalID(line.TransferID),
+		line.CounterpartyAccount,
+		line.CounterpartyOwner,
+		writer.header.FormatAmount(line.Amount),
+		writer.header.FormatAmount(line.Balance),
+	})
+}
+
+func (writer *csvWriter) WriteSummary(summary Summary) error {
+	err := writer.w.Write([]string{
+		"", "", "Closing balance", "", "", "", "", writer.header.FormatAmount(summary.ClosingBalance),
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.w.Flush()
+	return writer.w.Error()
+}
+
+func formatCSVTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func optionalID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,57 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter streams a single JSON document. The header fields come first,
+// then the entries array, then the summary fields.
+type jsonWriter struct {
+	w     *bufio.Writer
+	lines int64
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (writer *jsonWriter) WriteHeader(header Header) error {
+	data, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	// reopen the header object to append the entries to it
+	writer.w.Write(data[:len(data)-1])
+	_, err = writer.w.WriteString(`,"entries":[`)
+	return err
+}
+
+func (writer *jsonWriter) WriteLine(line Line) error {
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+
+	if writer.lines > 0 {
+		writer.w.WriteByte(',')
+	}
+	writer.lines++
+
+	_, err = writer.w.Write(data)
+	return err
+}
+
+func (writer *jsonWriter) WriteSummary(summary Summary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	writer.w.WriteString("],")
+	writer.w.Write(data[1:])
+	writer.w.WriteByte('\n')
+	return writer.w.Flush()
+}
@@ -0,0 +1,94 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+`
+
+// ofxBankID identifies this bank in BANKACCTFROM
+const ofxBankID = "SIMPLEBANK"
+
+// ofxWriter writes an OFX 2.2 bank statement response. The elements are
+// written by hand because encoding/xml cannot stream a nested list.
+type ofxWriter struct {
+	w      *bufio.Writer
+	header Header
+}
+
+func newOFXWriter(w io.Writer) *ofxWriter {
+	return &ofxWriter{w: bufio.NewWriter(w)}
+}
+
+func (writer *ofxWriter) WriteHeader(header Header) error {
+	writer.header = header
+
+	writer.w.WriteString(ofxHeader)
+	writer.w.WriteString("<OFX>\n")
+	writer.w.WriteString("<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	writer.element("DTSERVER", formatOFXTime(header.GeneratedAt))
+	writer.w.WriteString("<LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n")
+	writer.w.WriteString("<BANKMSGSRSV1><STMTTRNRS>")
+	writer.element("TRNUID", "0")
+	writer.w.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	writer.w.WriteString("<STMTRS>")
+	writer.element("CURDEF", header.Currency)
+	writer.w.WriteString("<BANKACCTFROM>")
+	writer.element("BANKID", ofxBankID)
+	writer.element("ACCTID", header.AccountNumber)
+	writer.element("ACCTTYPE", "CHECKING")
+	writer.w.WriteString("</BANKACCTFROM>\n")
+	writer.w.WriteString("<BANKTRANLIST>")
+	writer.element("DTSTART", formatOFXTime(header.From))
+	writer.element("DTEND", formatOFXTime(header.To))
+	_, err := writer.w.WriteString("\n")
+	return err
+}
+
+func (writer *ofxWriter) WriteLine(line Line) error {
+	trnType := "CREDIT"
+	if line.Amount < 0 {
+		trnType = "DEBIT"
+	}
+
+	writer.w.WriteString("<STMTTRN>")
+	writer.element("TRNTYPE", trnType)
+	writer.element("DTPOSTED", formatOFXTime(line.Time))
+	writer.element("TRNAMT", writer.header.FormatAmount(line.Amount))
+	writer.element("FITID", strconv.FormatInt(line.EntryID, 10))
+	if line.CounterpartyOwner != "" {
+		writer.element("NAME", line.CounterpartyOwner)
+	}
+	writer.element("MEMO", line.Description())
+	_, err := writer.w.WriteString("</STMTTRN>\n")
+	return err
+}
+
+func (writer *ofxWriter) WriteSummary(summary Summary) error {
+	writer.w.WriteString("</BANKTRANLIST>\n")
+	writer.w.WriteString("<LEDGERBAL>")
+	writer.element("BALAMT", writer.header.FormatAmount(summary.ClosingBalance))
+	writer.element("DTASOF", formatOFXTime(writer.header.To))
+	writer.w.WriteString("</LEDGERBAL>\n")
+	writer.w.WriteString("</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n")
+	writer.w.WriteString("</OFX>\n")
+	return writer.w.Flush()
+}
+
+func (writer *ofxWriter) element(name string, value string) {
+	fmt.Fprintf(writer.w, "<%s>", name)
+	xml.EscapeText(writer.w, []byte(value))
+	fmt.Fprintf(writer.w, "</%s>", name)
+}
+
+// formatOFXTime formats t as an OFX datetime in UTC
+func formatOFXTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
@@ -0,0 +1,114 @@
+// Package statement renders account statements. Writers receive the header,
+// then every line in order, then the summary, so a statement can be streamed
+// straight from the database to the client.
+package statement
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/techschool/simplebank/money"
+)
+
+// Supported formats
+const (
+	FormatCSV  = "csv"
+	FormatOFX  = "ofx"
+	FormatJSON = "json"
+)
+
+// Header describes the account and period of a statement
+type Header struct {
+	AccountNumber  string    `json:"account_number"`
+	Owner          string    `json:"owner"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance int64     `json:"opening_balance"`
+	// ClosingBalance is known up front for formats that state it before
+	// the lines. The others take it from the summary.
+	ClosingBalance int64     `json:"-"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
+
+// FormatAmount renders amount, in minor units of the statement currency, as
+// a decimal in the major unit such as 12.34
+func (header Header) FormatAmount(amount int64) string {
+	return money.New(amount, header.Currency).Decimal()
+}
+
+// Line is one entry of a statement with the balance after it
+type Line struct {
+	EntryID             int64     `json:"entry_id"`
+	Time                time.Time `json:"time"`
+	Amount              int64     `json:"amount"`
+	Balance             int64     `json:"balance"`
+	TransferID          int64     `json:"transfer_id,omitempty"`
+	CounterpartyAccount string    `json:"counterparty_account,omitempty"`
+	CounterpartyOwner   string    `json:"counterparty_owner,omitempty"`
+}
+
+// Description is a short human readable summary of the line
+func (line Line) Description() string {
+	switch {
+	case line.TransferID == 0:
+		return "Adjustment"
+	case line.Amount < 0:
+		return fmt.Sprintf("Transfer to account %s", line.CounterpartyAccount)
+	default:
+		return fmt.Sprintf("Transfer from account %s", line.CounterpartyAccount)
+	}
+}
+
+// Summary closes a statement
+type Summary struct {
+	ClosingBalance int64 `json:"closing_balance"`
+	TotalCredits   int64 `json:"total_credits"`
+	TotalDebits    int64 `json:"total_debits"`
+	Count          int64 `json:"count"`
+}
+
+// Add accounts for line in the summary
+func (summary *Summary) Add(line Line) {
+	summary.ClosingBalance = line.Balance
+	summary.Count++
+	if line.Amount < 0 {
+		summary.TotalDebits -= line.Amount
+	} else {
+		summary.TotalCredits += line.Amount
+	}
+}
+
+// Writer renders a statement in one format
+type Writer interface {
+	WriteHeader(header Header) error
+	WriteLine(line Line) error
+	WriteSummary(summary Summary) error
+}
+
+// NewWriter creates a writer rendering format to w
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatOFX:
+		return newOFXWriter(w), nil
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	}
+
+	return nil, fmt.Errorf("unsupported statement format %q", format)
+}
+
+// ContentType returns the media type of format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatOFX:
+		return "application/x-ofx"
+	}
+
+	return "application/json; charset=utf-8"
+}
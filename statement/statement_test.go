@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sampleStatement() (Header, []Line) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	header := Header{
+		AccountNumber:  "SB12000000000007",
+		Owner:          "alice",
+		Currency:       "USD",
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 100,
+		GeneratedAt:    from.AddDate(0, 1, 1),
+	}
+
+	lines := []Line{
+		{EntryID: 1, Time: from.Add(time.Hour), Amount: 50, Balance: 150, TransferID: 3, CounterpartyAccount: "SB55000000000009", CounterpartyOwner: "bob"},
+		{EntryID: 2, Time: from.Add(2 * time.Hour), Amount: -30, Balance: 120, TransferID: 4, CounterpartyAccount: "SB98000000000011", CounterpartyOwner: "carol & co"},
+		{EntryID: 5, Time: from.Add(3 * time.Hour), Amount: 5, Balance: 125},
+	}
+
+	return header, lines
+}
+
+func render(t *testing.T, format string) string {
+	var buf bytes.Buffer
+	writer, err := NewWriter(format, &buf)
+	require.NoError(t, err)
+
+	header, lines := sampleStatement()
+	require.NoError(t, writer.WriteHeader(header))
+
+	summary := Summary{ClosingBalance: header.OpeningBalance}
+	for _, line := range lines {
+		summary.Add(line)
+		require.NoError(t, writer.WriteLine(line))
+	}
+	require.NoError(t, writer.WriteSummary(summary))
+
+	return buf.String()
+}
+
+func TestSummary(t *testing.T) {
+	header, lines := sampleStatement()
+
+	summary := Summary{ClosingBalance: header.OpeningBalance}
+	for _, line := range lines {
+		summary.Add(line)
+	}
+
+	require.Equal(t, Summary{ClosingBalance: 125, TotalCredits: 55, TotalDebits: 30, Count: 3}, summary)
+}
+
+func TestCSVWriter(t *testing.T) {
+	expected := strings.Join([]string{
+		"date,entry_id,description,transfer_id,counterparty_account,counterparty_owner,amount,balance",
+		"2024-03-01T00:00:00Z,,Opening balance,,,,,1.00",
+		"2024-03-01T01:00:00Z,1,Transfer from account SB55000000000009,3,SB55000000000009,bob,0.50,1.50",
+		"2024-03-01T02:00:00Z,2,Transfer to account SB98000000000011,4,SB98000000000011,carol & co,-0.30,1.20",
+		"2024-03-01T03:00:00Z,5,Adjustment,,,,0.05,1.25",
+		",,Closing balance,,,,,1.25",
+		"",
+	}, "\n")
+
+	require.Equal(t, expected, render(t, FormatCSV))
+}
+
+func TestJSONWriter(t *testing.T) {
+	var document struct {
+		Header
+		Entries []Line `json:"entries"`
+		Summary
+	}
+	require.NoError(t, json.Unmarshal([]byte(render(t, FormatJSON)), &document))
+
+	header, lines := sampleStatement()
+	require.Equal(t, header.AccountNumber, document.AccountNumber)
+	require.Equal(t, header.OpeningBalance, document.OpeningBalance)
+	require.Len(t, document.Entries, len(lines))
+	require.Equal(t, lines[1], document.Entries[1])
+	require.Equal(t, int64(125), document.Summary.ClosingBalance)
+	require.Equal(t, int64(3), document.Count)
+}
+
+func TestJSONWriterWithoutLines(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newJSONWriter(&buf)
+
+	header, _ := sampleStatement()
+	require.NoError(t, writer.WriteHeader(header))
+	require.NoError(t, writer.WriteSummary(Summary{ClosingBalance: header.OpeningBalance}))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	require.Empty(t, document["entries"])
+	require.EqualValues(t, 100, document["closing_balance"])
+}
+
+func TestOFXWriter(t *testing.T) {
+	output := render(t, FormatOFX)
+
+	require.True(t, strings.HasPrefix(output, `<?xml version="1.0"`))
+	require.Contains(t, output, "<CURDEF>USD</CURDEF>")
+	require.Contains(t, output, "<ACCTID>SB12000000000007</ACCTID>")
+	require.Contains(t, output, "<DTSTART>20240301000000[0:GMT]</DTSTART>")
+	require.Contains(t, output, "<TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20240301020000[0:GMT]</DTPOSTED><TRNAMT>-0.30</TRNAMT><FITID>2</FITID><NAME>carol &amp; co</NAME>")
+	require.Contains(t, output, "<LEDGERBAL><BALAMT>1.25</BALAMT>")
+
+	// the document must be well formed XML
+	decoder := xml.NewDecoder(strings.NewReader(output))
+	var transactions int
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "STMTTRN" {
+			transactions++
+		}
+	}
+	require.Equal(t, 3, transactions)
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	_, err := NewWriter("pdf", io.Discard)
+	require.Error(t, err)
+}
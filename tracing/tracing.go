@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+)
+
+const (
+	ServiceName = "simplebank"
+
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// ShutdownFunc flushes pending spans and stops the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. exporter selects where spans go: "otlp" sends them over gRPC
+// to endpoint, "stdout" pretty prints them and "none" only propagates context.
+func Setup(ctx context.Context, exporter string, endpoint string) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+
+	switch exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		spanExporter, err = otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpoint(endpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create %s exporter: %w", exporter, err)
+	}
+
+	provider := NewProvider(sdktrace.WithBatcher(spanExporter))
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// NewProvider creates a tracer provider tagged with the service name. Tests
+// pass sdktrace.WithSyncer(tracetest.NewInMemoryExporter()) to collect spans.
+func NewProvider(opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+	)
+
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithResource(res)}, opts...)...)
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewProvider(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "operation", spans[0].Name)
+	require.Contains(t, spans[0].Resource.String(), ServiceName)
+}
+
+func TestSetupPropagatesTraceContext(t *testing.T) {
+	shutdown, err := Setup(context.Background(), ExporterNone, "")
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	carrier := propagation.MapCarrier{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	out := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, out)
+	require.Equal(t, carrier["traceparent"], out["traceparent"])
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), "zipkin", "")
+	require.Error(t, err)
+}
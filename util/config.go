@@ -12,9 +12,31 @@ type Config struct {
 	MigrationsURL        string        `mapstructure:"MIGRATIONS_URL"`
 	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
 	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	MetricsServerAddress string        `mapstructure:"METRICS_SERVER_ADDRESS"`
 	TokenSymmetricalKey  string        `mapstructure:"TOKEN_SYMMETRICAL_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	LogLevel             string        `mapstructure:"LOG_LEVEL"`
+	LogFormat            string        `mapstructure:"LOG_FORMAT"`
+	TracingExporter      string        `mapstructure:"TRACING_EXPORTER"`
+	OTLPEndpoint         string        `mapstructure:"OTLP_ENDPOINT"`
+	OutboxPublisher      string        `mapstructure:"OUTBOX_PUBLISHER"`
+	OutboxTarget         string        `mapstructure:"OUTBOX_TARGET"`
+	OutboxPollInterval   time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	ReconcileInterval    time.Duration `mapstructure:"RECONCILE_INTERVAL"`
+	InterestInterval     time.Duration `mapstructure:"INTEREST_INTERVAL"`
+	FraudRulesFile       string        `mapstructure:"FRAUD_RULES_FILE"`
+	SanctionsListFile    string        `mapstructure:"SANCTIONS_LIST_FILE"`
+	SanctionsThreshold   float64       `mapstructure:"SANCTIONS_THRESHOLD"`
+	SanctionsAction      string        `mapstructure:"SANCTIONS_ACTION"`
+	SanctionsInterval    time.Duration `mapstructure:"SANCTIONS_INTERVAL"`
+	PIIEncryptionKey     string        `mapstructure:"PII_ENCRYPTION_KEY"`
+	PIIKeyFile           string        `mapstructure:"PII_KEY_FILE"`
+	PIIIndexKey          string        `mapstructure:"PII_INDEX_KEY"`
+	PIIRekeyInterval     time.Duration `mapstructure:"PII_REKEY_INTERVAL"`
+	PayeeCoolingOff      time.Duration `mapstructure:"PAYEE_COOLING_OFF"`
+	AliasLookupLimit     int           `mapstructure:"ALIAS_LOOKUP_LIMIT"`
+	AliasLookupWindow    time.Duration `mapstructure:"ALIAS_LOOKUP_WINDOW"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -1,16 +1,166 @@
 package util
 
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Currencies every deployment starts with, see DefaultCurrencies
 const (
 	USD = "USD"
 	KES = "KES"
 	EUR = "EUR"
 )
 
+// Currency describes an ISO 4217 currency. Amounts are always held in minor
+// units, Exponent is the number of minor-unit digits after the decimal point.
+type Currency struct {
+	Code     string `json:"code"`
+	Exponent int    `json:"exponent"`
+	Symbol   string `json:"symbol"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// Format renders amount, in minor units, as a decimal number such as 12.34
+func (currency Currency) Format(amount int64) string {
+	sign := ""
+	digits := strconv.FormatInt(amount, 10)
+	if amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	if currency.Exponent <= 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= currency.Exponent {
+		digits = strings.Repeat("0", currency.Exponent-len(digits)+1) + digits
+	}
+
+	point := len(digits) - currency.Exponent
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Display renders amount with the currency symbol, such as $12.34
+func (currency Currency) Display(amount int64) string {
+	formatted := currency.Format(amount)
+	if digits, negative := strings.CutPrefix(formatted, "-"); negative {
+		return "-" + currency.Symbol + digits
+	}
+
+	return currency.Symbol + formatted
+}
+
+// DefaultCurrencies is the registry used until SetCurrencies replaces it
+var DefaultCurrencies = []Currency{
+	{Code: EUR, Exponent: 2, Symbol: "€", Enabled: true},
+	{Code: KES, Exponent: 2, Symbol: "KSh", Enabled: true},
+	{Code: USD, Exponent: 2, Symbol: "$", Enabled: true},
+}
+
+// CurrencyRegistry is an immutable set of currencies indexed by code
+type CurrencyRegistry struct {
+	currencies []Currency
+	byCode     map[string]Currency
+}
+
+// NewCurrencyRegistry builds a registry from currencies, sorted by code
+func NewCurrencyRegistry(currencies []Currency) (*CurrencyRegistry, error) {
+	registry := &CurrencyRegistry{
+		currencies: slices.Clone(currencies),
+		byCode:     make(map[string]Currency, len(currencies)),
+	}
+
+	for _, currency := range currencies {
+		if len(currency.Code) != 3 || strings.ToUpper(currency.Code) != currency.Code {
+			return nil, fmt.Errorf("%q is not an ISO 4217 currency code", currency.Code)
+		}
+		if currency.Exponent < 0 || currency.Exponent > 4 {
+			return nil, fmt.Errorf("currency %s has an invalid exponent %d", currency.Code, currency.Exponent)
+		}
+		if _, ok := registry.byCode[currency.Code]; ok {
+			return nil, fmt.Errorf("currency %s is listed twice", currency.Code)
+		}
+		registry.byCode[currency.Code] = currency
+	}
+
+	if len(registry.Enabled()) == 0 {
+		return nil, fmt.Errorf("no currency is enabled")
+	}
+
+	slices.SortFunc(registry.currencies, func(a, b Currency) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	return registry, nil
+}
+
+// Lookup returns the currency with code, enabled or not
+func (registry *CurrencyRegistry) Lookup(code string) (Currency, bool) {
+	currency, ok := registry.byCode[code]
+	return currency, ok
+}
+
+// Enabled returns the currencies new accounts and transfers may use
+func (registry *CurrencyRegistry) Enabled() []Currency {
+	enabled := make([]Currency, 0, len(registry.currencies))
+	for _, currency := range registry.currencies {
+		if currency.Enabled {
+			enabled = append(enabled, currency)
+		}
+	}
+
+	return enabled
+}
+
+var currencies atomic.Pointer[CurrencyRegistry]
+
+func init() {
+	registry, err := NewCurrencyRegistry(DefaultCurrencies)
+	if err != nil {
+		panic(err)
+	}
+	currencies.Store(registry)
+}
+
+// Currencies returns the registry of the running process
+func Currencies() *CurrencyRegistry {
+	return currencies.Load()
+}
+
+// SetCurrencies replaces the registry of the running process, typically with
+// the currencies table loaded at startup
+func SetCurrencies(list []Currency) error {
+	registry, err := NewCurrencyRegistry(list)
+	if err != nil {
+		return err
+	}
+
+	currencies.Store(registry)
+	return nil
+}
+
+// LookupCurrency returns the currency with code from the registry
+func LookupCurrency(code string) (Currency, bool) {
+	return Currencies().Lookup(code)
+}
+
+// IsValidCurrency reports whether currency is registered and enabled
 func IsValidCurrency(currency string) bool {
-	switch currency {
-	case USD, KES, EUR:
-		return true
+	c, ok := LookupCurrency(currency)
+	return ok && c.Enabled
+}
+
+// FormatAmount renders amount, in minor units of currency, with its symbol.
+// Unknown currencies are rendered as the raw amount followed by the code.
+func FormatAmount(amount int64, currency string) string {
+	c, ok := LookupCurrency(currency)
+	if !ok {
+		return strconv.FormatInt(amount, 10) + " " + currency
 	}
 
-	return false
+	return c.Display(amount)
 }
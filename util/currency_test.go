@@ -0,0 +1,70 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyFormat(t *testing.T) {
+	testCases := []struct {
+		currency Currency
+		amount   int64
+		format   string
+		display  string
+	}{
+		{Currency{Code: USD, Exponent: 2, Symbol: "$"}, 1234, "12.34", "$12.34"},
+		{Currency{Code: USD, Exponent: 2, Symbol: "$"}, 5, "0.05", "$0.05"},
+		{Currency{Code: USD, Exponent: 2, Symbol: "$"}, -120, "-1.20", "-$1.20"},
+		{Currency{Code: "JPY", Exponent: 0, Symbol: "¥"}, 1500, "1500", "¥1500"},
+		{Currency{Code: "BHD", Exponent: 3, Symbol: "BD"}, 12, "0.012", "BD0.012"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			require.Equal(t, tc.format, tc.currency.Format(tc.amount))
+			require.Equal(t, tc.display, tc.currency.Display(tc.amount))
+		})
+	}
+}
+
+func TestSetCurrencies(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetCurrencies(DefaultCurrencies))
+	}()
+
+	err := SetCurrencies([]Currency{
+		{Code: USD, Exponent: 2, Symbol: "$", Enabled: true},
+		{Code: "JPY", Exponent: 0, Symbol: "¥", Enabled: true},
+		{Code: KES, Exponent: 2, Symbol: "KSh", Enabled: false},
+	})
+	require.NoError(t, err)
+
+	require.True(t, IsValidCurrency("JPY"))
+	require.False(t, IsValidCurrency(KES))
+	require.False(t, IsValidCurrency(EUR))
+	require.Equal(t, "-¥300", FormatAmount(-300, "JPY"))
+	require.Equal(t, "KSh1.00", FormatAmount(100, KES))
+	require.Equal(t, "100 EUR", FormatAmount(100, EUR))
+
+	enabled := Currencies().Enabled()
+	require.Len(t, enabled, 2)
+	require.Equal(t, "JPY", enabled[0].Code)
+	require.Contains(t, []string{"JPY", USD}, RandomCurrency())
+}
+
+func TestSetCurrenciesInvalid(t *testing.T) {
+	testCases := map[string][]Currency{
+		"LowerCase":   {{Code: "usd", Exponent: 2, Enabled: true}},
+		"Exponent":    {{Code: USD, Exponent: 9, Enabled: true}},
+		"Duplicate":   {{Code: USD, Exponent: 2, Enabled: true}, {Code: USD, Exponent: 2}},
+		"NoneEnabled": {{Code: USD, Exponent: 2}},
+	}
+
+	for name, currencies := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, SetCurrencies(currencies))
+			require.True(t, IsValidCurrency(USD))
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/techschool/simplebank/accountnumber"
 )
 
 const alphabet = "abcdefghijklmnopqrstuvwxyz"
@@ -41,13 +43,24 @@ func RandomMoney() int64 {
 	return RandomInt(0, 1000)
 }
 
-// RandomCurrency generates a random currency code
+// RandomCurrency generates a random code of an enabled currency
 func RandomCurrency() string {
-	currencies := []string{EUR, USD, KES}
-	n := len(currencies)
-	return currencies[rand.Intn(n)]
+	currencies := Currencies().Enabled()
+	return currencies[rand.Intn(len(currencies))].Code
 }
 
 func RandomEmail() string {
 	return fmt.Sprintf("%s@email.com", RandomOwner())
 }
+
+func RandomPhone() string {
+	return fmt.Sprintf("+49%d", RandomInt(1000000000, 9999999999))
+}
+
+func RandomAccountNumber() string {
+	number, err := accountnumber.Generate()
+	if err != nil {
+		panic(err)
+	}
+	return number
+}
@@ -0,0 +1,177 @@
+package val
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/techschool/simplebank/accountnumber"
+	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/webhook"
+)
+
+var (
+	isValidUsername = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
+	isKnownUsername = regexp.MustCompile(`^[a-zA-Z0-9_]+$`).MatchString
+	isValidFullName = regexp.MustCompile(`^\p{L}[\p{L}\s'.-]*$`).MatchString
+	isValidPhone    = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`).MatchString
+)
+
+// ValidateString checks that the length of value is between minLength and maxLength
+func ValidateString(value string, minLength int, maxLength int) error {
+	n := len(value)
+	if n < minLength || n > maxLength {
+		return fmt.Errorf("must contain from %d-%d characters", minLength, maxLength)
+	}
+
+	return nil
+}
+
+// ValidateUsername checks that a username only has lowercase letters, digits or underscore
+func ValidateUsername(value string) error {
+	if err := ValidateString(value, 3, 100); err != nil {
+		return err
+	}
+
+	if !isValidUsername(value) {
+		return fmt.Errorf("must contain only lowercase letters, digits, or underscore")
+	}
+
+	return nil
+}
+
+// ValidateExistingUsername checks a username that names an existing user, as
+// at login. Users who signed up before ValidateUsername was enforced may have
+// uppercase letters in their username, so only letters, digits or underscore
+// are required.
+func ValidateExistingUsername(value string) error {
+	if err := ValidateString(value, 1, 100); err != nil {
+		return err
+	}
+
+	if !isKnownUsername(value) {
+		return fmt.Errorf("must contain only letters, digits, or underscore")
+	}
+
+	return nil
+}
+
+// ValidateFullName checks that a full name only has letters, spaces, apostrophes, dots or hyphens
+func ValidateFullName(value string) error {
+	if err := ValidateString(value, 3, 100); err != nil {
+		return err
+	}
+
+	if !isValidFullName(value) {
+		return fmt.Errorf("must contain only letters, spaces, apostrophes, dots or hyphens")
+	}
+
+	return nil
+}
+
+// ValidatePassword checks the length of a password
+func ValidatePassword(value string) error {
+	return ValidateString(value, 6, 100)
+}
+
+// ValidateEmail checks that value is a well formed email address
+func ValidateEmail(value string) error {
+	if err := ValidateString(value, 3, 200); err != nil {
+		return err
+	}
+
+	if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+		return fmt.Errorf("is not a valid email address")
+	}
+
+	return nil
+}
+
+// ValidatePhone checks that value is a phone number in E.164 format, such as +4915112345678
+func ValidatePhone(value string) error {
+	if !isValidPhone(value) {
+		return fmt.Errorf("must be a phone number in E.164 format")
+	}
+
+	return nil
+}
+
+// ValidateAlias checks that value is a username, email address or phone
+// number a transfer can be addressed to
+func ValidateAlias(value string) error {
+	if ValidateExistingUsername(value) != nil && ValidateEmail(value) != nil && ValidatePhone(value) != nil {
+		return fmt.Errorf("must be a username, email address or phone number in E.164 format")
+	}
+
+	return nil
+}
+
+// ValidateNickname checks that a payee nickname is short, not blank and
+// has no control characters
+func ValidateNickname(value string) error {
+	if err := ValidateString(value, 1, 64); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(value) == "" || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return fmt.Errorf("must contain printable characters only")
+	}
+
+	return nil
+}
+
+// ValidateCurrency checks that value is a supported currency
+func ValidateCurrency(value string) error {
+	if !util.IsValidCurrency(value) {
+		return fmt.Errorf("%q is not a supported currency", value)
+	}
+
+	return nil
+}
+
+// ValidateAccountNumber checks the check digits of a public account number,
+// ignoring spaces and case
+func ValidateAccountNumber(value string) error {
+	if accountnumber.Validate(accountnumber.Normalize(value)) != nil {
+		return fmt.Errorf("is not a valid account number")
+	}
+
+	return nil
+}
+
+// ValidateWebhookURL checks that value is an absolute https URL whose host
+// is not obviously internal. Host names are checked again once resolved,
+// when the webhook is delivered.
+func ValidateWebhookURL(value string) error {
+	if err := ValidateString(value, 1, 2000); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("must be an absolute https URL")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if ip := net.ParseIP(host); ip != nil && !webhook.IsPublicIP(ip) {
+		return fmt.Errorf("must not point to a private address")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("must not point to a private address")
+	}
+
+	return nil
+}
+
+// ValidateWebhookEventType checks that value is an event type customers can subscribe to
+func ValidateWebhookEventType(value string) error {
+	if !webhook.IsValidEventType(value) {
+		return fmt.Errorf("%q is not a supported event type", value)
+	}
+
+	return nil
+}
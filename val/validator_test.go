@@ -0,0 +1,113 @@
+package val
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/webhook"
+)
+
+func TestValidateUsername(t *testing.T) {
+	require.NoError(t, ValidateUsername(util.RandomOwner()))
+	require.NoError(t, ValidateUsername("user_123"))
+
+	require.Error(t, ValidateUsername("ab"))
+	require.Error(t, ValidateUsername("invalid-user#1"))
+	require.Error(t, ValidateUsername("UpperCase"))
+}
+
+func TestValidateExistingUsername(t *testing.T) {
+	require.NoError(t, ValidateExistingUsername(util.RandomOwner()))
+	require.NoError(t, ValidateExistingUsername("UpperCase"))
+	require.NoError(t, ValidateExistingUsername("user_123"))
+
+	require.Error(t, ValidateExistingUsername(""))
+	require.Error(t, ValidateExistingUsername("invalid-user#1"))
+}
+
+func TestValidateFullName(t *testing.T) {
+	require.NoError(t, ValidateFullName("John Doe"))
+
+	require.Error(t, ValidateFullName("jd"))
+	require.Error(t, ValidateFullName("John Doe 2"))
+}
+
+func TestValidatePassword(t *testing.T) {
+	require.NoError(t, ValidatePassword(util.RandomString(6)))
+
+	require.Error(t, ValidatePassword("123"))
+	require.Error(t, ValidatePassword(util.RandomString(101)))
+}
+
+func TestValidateEmail(t *testing.T) {
+	require.NoError(t, ValidateEmail(util.RandomEmail()))
+
+	require.Error(t, ValidateEmail("invalid-email"))
+	require.Error(t, ValidateEmail("John <john@email.com>"))
+}
+
+func TestValidatePhone(t *testing.T) {
+	require.NoError(t, ValidatePhone(util.RandomPhone()))
+	require.NoError(t, ValidatePhone("+14155552671"))
+
+	require.Error(t, ValidatePhone("4915112345678"))
+	require.Error(t, ValidatePhone("+0123456789"))
+	require.Error(t, ValidatePhone("+49 151 12345678"))
+}
+
+func TestValidateAccountNumber(t *testing.T) {
+	require.NoError(t, ValidateAccountNumber(util.RandomAccountNumber()))
+	require.NoError(t, ValidateAccountNumber("sb06 1234 5678 9012"))
+
+	require.Error(t, ValidateAccountNumber(""))
+	require.Error(t, ValidateAccountNumber("SB07123456789012"))
+	require.Error(t, ValidateAccountNumber("42"))
+}
+
+func TestValidateAlias(t *testing.T) {
+	require.NoError(t, ValidateAlias(util.RandomOwner()))
+	require.NoError(t, ValidateAlias(util.RandomEmail()))
+	require.NoError(t, ValidateAlias(util.RandomPhone()))
+
+	require.Error(t, ValidateAlias(""))
+	require.Error(t, ValidateAlias("John Smith"))
+}
+
+func TestValidateNickname(t *testing.T) {
+	require.NoError(t, ValidateNickname("Rent – Mrs. O'Brien"))
+
+	require.Error(t, ValidateNickname(""))
+	require.Error(t, ValidateNickname("   "))
+	require.Error(t, ValidateNickname("line\nbreak"))
+	require.Error(t, ValidateNickname(util.RandomString(65)))
+}
+
+func TestValidateCurrency(t *testing.T) {
+	require.NoError(t, ValidateCurrency(util.RandomCurrency()))
+
+	require.Error(t, ValidateCurrency("XYZ"))
+	require.Error(t, ValidateCurrency(""))
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	require.NoError(t, ValidateWebhookURL("https://merchant.example.com/hooks"))
+	require.NoError(t, ValidateWebhookURL("https://93.184.216.34:8443/hooks"))
+
+	require.Error(t, ValidateWebhookURL(""))
+	require.Error(t, ValidateWebhookURL("/relative"))
+	require.Error(t, ValidateWebhookURL("ftp://example.com"))
+	require.Error(t, ValidateWebhookURL("http://merchant.example.com/hooks"))
+	require.Error(t, ValidateWebhookURL("https://localhost:8080"))
+	require.Error(t, ValidateWebhookURL("https://127.0.0.1/hooks"))
+	require.Error(t, ValidateWebhookURL("https://169.254.169.254/latest/meta-data"))
+	require.Error(t, ValidateWebhookURL("https://[::1]/hooks"))
+	require.Error(t, ValidateWebhookURL("https://10.0.0.8/hooks"))
+}
+
+func TestValidateWebhookEventType(t *testing.T) {
+	require.NoError(t, ValidateWebhookEventType(webhook.EventEntryCreated))
+	require.NoError(t, ValidateWebhookEventType(webhook.EventTransferCreated))
+
+	require.Error(t, ValidateWebhookEventType("account.deleted"))
+}
@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/worker"
+)
+
+// DispatcherConfig controls polling, timeouts and retries of deliveries
+type DispatcherConfig struct {
+	BatchSize    int32
+	PollInterval time.Duration
+	Timeout      time.Duration
+	// MaxAttempts is the number of failed attempts after which a delivery
+	// is marked failed. It can still be replayed.
+	MaxAttempts int32
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Lease is how long claimed deliveries stay hidden from other
+	// dispatchers. It must cover sending a whole batch, which takes up to
+	// BatchSize times Timeout.
+	Lease time.Duration
+	// AllowPrivateNetworks lets deliveries reach loopback and private
+	// addresses. It is meant for tests and local development only.
+	AllowPrivateNetworks bool
+}
+
+// DefaultDispatcherConfig is used for zero fields of the config given to NewDispatcher
+var DefaultDispatcherConfig = DispatcherConfig{
+	BatchSize:    50,
+	PollInterval: time.Second,
+	Timeout:      10 * time.Second,
+	MaxAttempts:  8,
+	BaseDelay:    5 * time.Second,
+	MaxDelay:     time.Hour,
+	Lease:        15 * time.Minute,
+}
+
+// Dispatcher posts pending deliveries to their subscription URL
+type Dispatcher struct {
+	store  db.Store
+	client *http.Client
+	config DispatcherConfig
+}
+
+// NewDispatcher creates a dispatcher for the deliveries of store
+func NewDispatcher(store db.Store, config DispatcherConfig) *Dispatcher {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultDispatcherConfig.BatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultDispatcherConfig.PollInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultDispatcherConfig.Timeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultDispatcherConfig.MaxAttempts
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = DefaultDispatcherConfig.BaseDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = DefaultDispatcherConfig.MaxDelay
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultDispatcherConfig.Lease
+	}
+
+	return &Dispatcher{
+		store:  store,
+		client: newClient(config),
+		config: config,
+	}
+}
+
+// Run delivers due webhooks until ctx is cancelled
+func (dispatcher *Dispatcher) Run(ctx context.Context) error {
+	return worker.Poller{
+		Name:         "dispatch webhooks",
+		PollInterval: dispatcher.config.PollInterval,
+		BatchSize:    dispatcher.config.BatchSize,
+		ProcessBatch: dispatcher.ProcessBatch,
+	}.Run(ctx)
+}
+
+// ProcessBatch attempts one batch of due deliveries and returns how many
+// were claimed. Claiming leases the deliveries instead of locking them, so no
+// transaction is open while they are sent and each one is marked on its own.
+func (dispatcher *Dispatcher) ProcessBatch(ctx context.Context) (int, error) {
+	deliveries, err := dispatcher.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(dispatcher.config.Lease),
+		BatchSize:  dispatcher.config.BatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot claim webhook deliveries: %w", err)
+	}
+
+	var errs []error
+	for _, delivery := range deliveries {
+		if err := dispatcher.attempt(ctx, delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(deliveries), errors.Join(errs...)
+}
+
+func (dispatcher *Dispatcher) attempt(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow) error {
+	responseStatus, sendErr := dispatcher.send(ctx, delivery)
+	if sendErr == nil {
+		err := dispatcher.store.MarkWebhookDeliverySucceeded(ctx, db.MarkWebhookDeliverySucceededParams{
+			ID:             delivery.ID,
+			ResponseStatus: int32(responseStatus),
+		})
+		if err != nil {
+			return fmt.Errorf("cannot mark webhook delivery %d succeeded: %w", delivery.ID, err)
+		}
+		return nil
+	}
+
+	attempts := delivery.Attempts + 1
+	status := StatusPending
+	if dispatcher.retry().GiveUp(attempts) {
+		status = StatusFailed
+	}
+
+	slog.WarnContext(ctx, "cannot deliver webhook",
+		slog.Int64("delivery_id", delivery.ID),
+		slog.Int64("subscription_id", delivery.SubscriptionID),
+		slog.Int("attempts", int(attempts)),
+		slog.String("status", status),
+		slog.String("error", sendErr.Error()),
+	)
+
+	err := dispatcher.store.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+		ID:             delivery.ID,
+		Status:         status,
+		ResponseStatus: int32(responseStatus),
+		LastError:      sendErr.Error(),
+		NextAttemptAt:  dispatcher.retry().NextAttempt(attempts),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot mark webhook delivery %d failed: %w", delivery.ID, err)
+	}
+
+	return nil
+}
+
+// send posts delivery and returns the response status, or 0 if there was none
+func (dispatcher *Dispatcher) send(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(Payload{
+		ID:        delivery.EventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot encode webhook payload: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("cannot create webhook request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, delivery.EventID.String())
+	request.Header.Set(EventTypeHeader, delivery.EventType)
+	request.Header.Set(DeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, time.Now(), body))
+
+	response, err := dispatcher.client.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("cannot deliver webhook: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+func (dispatcher *Dispatcher) retry() worker.RetryPolicy {
+	return worker.RetryPolicy{
+		MaxAttempts: dispatcher.config.MaxAttempts,
+		BaseDelay:   dispatcher.config.BaseDelay,
+		MaxDelay:    dispatcher.config.MaxDelay,
+	}
+}
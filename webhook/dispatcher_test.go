@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDispatcherProcessBatch(t *testing.T) {
+	secret := util.RandomString(32)
+
+	testCases := []struct {
+		name       string
+		status     int
+		attempts   int32
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name:   "Delivered",
+			status: http.StatusOK,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkWebhookDeliverySucceeded(gomock.Any(), gomock.Eq(db.MarkWebhookDeliverySucceededParams{
+						ID:             1,
+						ResponseStatus: http.StatusOK,
+					})).
+					Times(1)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:   "Retried",
+			status: http.StatusServiceUnavailable,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkWebhookDeliverySucceeded(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+						require.Equal(t, StatusPending, arg.Status)
+						require.Equal(t, int32(http.StatusServiceUnavailable), arg.ResponseStatus)
+						require.Contains(t, arg.LastError, "503")
+						require.WithinDuration(t, time.Now().Add(time.Second), arg.NextAttemptAt, 500*time.Millisecond)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "GaveUp",
+			status:   http.StatusBadRequest,
+			attempts: 2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkWebhookDeliverySucceeded(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+						require.Equal(t, StatusFailed, arg.Status)
+						return nil
+					})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			data := json.RawMessage(`{"account_id":7,"amount":10}`)
+			eventID := uuid.New()
+
+			var received int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received++
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				require.NoError(t, VerifySignature(secret, r.Header.Get(SignatureHeader), body, time.Minute))
+				require.Equal(t, eventID.String(), r.Header.Get(EventIDHeader))
+				require.Equal(t, EventEntryCreated, r.Header.Get(EventTypeHeader))
+				require.Equal(t, "1", r.Header.Get(DeliveryIDHeader))
+
+				var payload Payload
+				require.NoError(t, json.Unmarshal(body, &payload))
+				require.Equal(t, eventID, payload.ID)
+				require.JSONEq(t, string(data), string(payload.Data))
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ExecTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().
+				ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.ClaimWebhookDeliveriesParams) ([]db.ClaimWebhookDeliveriesRow, error) {
+					require.Equal(t, DefaultDispatcherConfig.BatchSize, arg.BatchSize)
+					require.WithinDuration(t, time.Now().Add(DefaultDispatcherConfig.Lease), arg.LeaseUntil, time.Second)
+					return []db.ClaimWebhookDeliveriesRow{{
+						ID:             1,
+						SubscriptionID: 3,
+						EventID:        eventID,
+						EventType:      EventEntryCreated,
+						Payload:        data,
+						Attempts:       tc.attempts,
+						CreatedAt:      time.Now(),
+						Url:            server.URL,
+						Secret:         secret,
+					}}, nil
+				})
+			tc.buildStubs(store)
+
+			dispatcher := NewDispatcher(store, DispatcherConfig{
+				MaxAttempts:          3,
+				BaseDelay:            time.Second,
+				AllowPrivateNetworks: true,
+			})
+
+			claimed, err := dispatcher.ProcessBatch(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, claimed)
+			require.Equal(t, 1, received)
+		})
+	}
+}
+
+func TestDispatcherUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	dispatcher := NewDispatcher(store, DispatcherConfig{})
+	status, err := dispatcher.send(context.Background(), db.ClaimWebhookDeliveriesRow{
+		ID:      1,
+		EventID: uuid.New(),
+		Payload: json.RawMessage(`{}`),
+		Url:     server.URL,
+	})
+	require.Error(t, err)
+	require.Zero(t, status)
+}
+
+func TestDispatcherRefusesPrivateAddresses(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	dispatcher := NewDispatcher(store, DispatcherConfig{})
+
+	// the server listens on loopback
+	status, err := dispatcher.send(context.Background(), db.ClaimWebhookDeliveriesRow{
+		ID:      1,
+		EventID: uuid.New(),
+		Payload: json.RawMessage(`{}`),
+		Url:     server.URL,
+	})
+	require.ErrorIs(t, err, ErrPrivateAddress)
+	require.Zero(t, status)
+	require.Zero(t, received)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, address := range []string{"93.184.216.34", "2606:2800:220:1::248"} {
+		require.True(t, IsPublicIP(net.ParseIP(address)), address)
+	}
+
+	for _, address := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"100.64.0.1", "0.0.0.0", "::1", "fd00::1", "fe80::1", "::ffff:127.0.0.1",
+	} {
+		require.False(t, IsPublicIP(net.ParseIP(address)), address)
+	}
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ErrPrivateAddress is returned when a webhook would be sent to an address
+// that is not on the public internet
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// reservedNetworks are ranges that are not routable on the internet but that
+// the predicates of net.IP do not cover
+var reservedNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+// IsPublicIP reports whether ip is an internet address, which excludes
+// loopback, private, link-local, multicast and unspecified addresses
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// publicOnly refuses connections to addresses that are not public. The dialer
+// calls it with the resolved address of every connection, redirects included,
+// so a host name cannot smuggle in an internal address.
+func publicOnly(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+
+	return nil
+}
+
+// newClient creates the HTTP client of a dispatcher. Unless the config allows
+// private networks it only connects to public addresses, and never through a
+// proxy, which would hide the address actually reached.
+func newClient(config DispatcherConfig) *http.Client {
+	if config.AllowPrivateNetworks {
+		return &http.Client{Timeout: config.Timeout}
+	}
+
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		Control: publicOnly,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: config.Timeout, Transport: transport}
+}
+
+func mustParseCIDR(value string) *net.IPNet {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return network
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	SignatureHeader  = "X-Simplebank-Signature"
+	EventIDHeader    = "X-Simplebank-Event-Id"
+	EventTypeHeader  = "X-Simplebank-Event-Type"
+	DeliveryIDHeader = "X-Simplebank-Delivery-Id"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrExpiredSignature = errors.New("webhook signature has expired")
+)
+
+// Sign returns the signature header value for body sent at timestamp. It has
+// the form t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, computeMAC(secret, t, body))
+}
+
+// VerifySignature checks a signature header produced by Sign. Signatures
+// older than tolerance are rejected to limit replays.
+func VerifySignature(secret string, header string, body []byte, tolerance time.Duration) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(v1), []byte(computeMAC(secret, t, body))) {
+		return ErrInvalidSignature
+	}
+
+	if time.Since(time.Unix(unix, 0)) > tolerance {
+		return ErrExpiredSignature
+	}
+
+	return nil
+}
+
+func computeMAC(secret string, t string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+func TestSignature(t *testing.T) {
+	secret := util.RandomString(32)
+	body := []byte(`{"type":"entry.created"}`)
+
+	header := Sign(secret, time.Now(), body)
+	require.Regexp(t, `^t=\d+,v1=[0-9a-f]{64}$`, header)
+	require.NoError(t, VerifySignature(secret, header, body, time.Minute))
+
+	require.ErrorIs(t, VerifySignature(util.RandomString(32), header, body, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(secret, header, []byte(`{"type":"transfer.created"}`), time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(secret, "v1=abc", body, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(secret, "", body, time.Minute), ErrInvalidSignature)
+
+	old := Sign(secret, time.Now().Add(-time.Hour), body)
+	require.ErrorIs(t, VerifySignature(secret, old, body, time.Minute), ErrExpiredSignature)
+}
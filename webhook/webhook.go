@@ -0,0 +1,94 @@
+// Package webhook notifies customers of activity on their accounts. Events
+// are fanned out to the matching subscriptions of the account owner inside
+// the business transaction, and a Dispatcher delivers them signed with the
+// subscription secret.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// Event types customers can subscribe to
+const (
+	EventEntryCreated    = "entry.created"
+	EventTransferCreated = "transfer.created"
+)
+
+// IsValidEventType reports whether eventType can be subscribed to
+func IsValidEventType(eventType string) bool {
+	switch eventType {
+	case EventEntryCreated, EventTransferCreated:
+		return true
+	}
+
+	return false
+}
+
+// Statuses of a delivery
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Payload is the JSON body posted to subscribers
+type Payload struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewSecret generates a random signing secret for a subscription
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("cannot generate webhook secret: %w", err)
+	}
+
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// Enqueue creates a delivery of eventType for every active subscription of
+// owner that asked for it. q should belong to the transaction making the
+// change. All deliveries of one call share the same event id.
+func Enqueue(ctx context.Context, q db.Querier, owner string, eventType string, data any) error {
+	subscriptions, err := q.ListWebhookSubscriptionsForEvent(ctx, db.ListWebhookSubscriptionsForEventParams{
+		Owner:     owner,
+		EventType: eventType,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot list webhook subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("cannot encode %s payload: %w", eventType, err)
+	}
+
+	eventID := uuid.New()
+	for _, subscription := range subscriptions {
+		_, err := q.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			SubscriptionID: subscription.ID,
+			EventID:        eventID,
+			EventType:      eventType,
+			Payload:        payload,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot create webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
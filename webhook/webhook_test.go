@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEnqueue(t *testing.T) {
+	owner := util.RandomOwner()
+	subscriptions := []db.WebhookSubscription{
+		{ID: 1, Owner: owner, EventTypes: []string{EventEntryCreated}},
+		{ID: 2, Owner: owner, EventTypes: []string{EventEntryCreated, EventTransferCreated}},
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListWebhookSubscriptionsForEvent(gomock.Any(), gomock.Eq(db.ListWebhookSubscriptionsForEventParams{
+			Owner:     owner,
+			EventType: EventEntryCreated,
+		})).
+		Times(1).
+		Return(subscriptions, nil)
+
+	var deliveries []db.CreateWebhookDeliveryParams
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+			deliveries = append(deliveries, arg)
+			return db.WebhookDelivery{}, nil
+		})
+
+	err := Enqueue(context.Background(), store, owner, EventEntryCreated, map[string]int64{"amount": 10})
+	require.NoError(t, err)
+
+	require.Len(t, deliveries, 2)
+	require.Equal(t, int64(1), deliveries[0].SubscriptionID)
+	require.Equal(t, int64(2), deliveries[1].SubscriptionID)
+	require.Equal(t, deliveries[0].EventID, deliveries[1].EventID)
+	require.Equal(t, EventEntryCreated, deliveries[0].EventType)
+	require.JSONEq(t, `{"amount":10}`, string(deliveries[0].Payload))
+}
+
+func TestEnqueueWithoutSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListWebhookSubscriptionsForEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.WebhookSubscription{}, nil)
+	store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+	err := Enqueue(context.Background(), store, util.RandomOwner(), EventTransferCreated, json.RawMessage(`{}`))
+	require.NoError(t, err)
+}
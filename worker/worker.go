@@ -0,0 +1,78 @@
+// Package worker holds what the background jobs draining a table have in
+// common: a loop polling for batches of due rows, and the backoff of rows
+// whose attempt failed. Rows are claimed with a lease rather than a lock, so
+// no transaction is open while a job talks to the network.
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Poller calls ProcessBatch until its context is cancelled
+type Poller struct {
+	// Name says what the batches do, such as "relay outbox events"
+	Name         string
+	PollInterval time.Duration
+	BatchSize    int32
+	// ProcessBatch handles one batch and returns how many rows it claimed
+	ProcessBatch func(ctx context.Context) (int, error)
+}
+
+// Run polls every PollInterval, and right away after a full batch so that
+// a backlog is drained quickly. Errors are logged and the loop goes on.
+func (poller Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(poller.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		claimed, err := poller.ProcessBatch(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "cannot "+poller.Name, slog.String("error", err.Error()))
+		}
+
+		// keep draining while batches come back full
+		if err == nil && claimed == int(poller.BatchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RetryPolicy decides when a row whose attempt failed is tried again
+type RetryPolicy struct {
+	// MaxAttempts is the number of failed attempts after which a row is
+	// given up on
+	MaxAttempts int32
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// GiveUp reports whether a row that failed attempts times is not retried
+func (policy RetryPolicy) GiveUp(attempts int32) bool {
+	return attempts >= policy.MaxAttempts
+}
+
+// Backoff doubles the delay after every failed attempt up to MaxDelay
+func (policy RetryPolicy) Backoff(attempts int32) time.Duration {
+	delay := policy.BaseDelay
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= policy.MaxDelay {
+			return policy.MaxDelay
+		}
+	}
+
+	return delay
+}
+
+// NextAttempt returns when a row that failed attempts times is due again
+func (policy RetryPolicy) NextAttempt(attempts int32) time.Time {
+	return time.Now().Add(policy.Backoff(attempts))
+}
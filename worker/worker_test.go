@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	require.Equal(t, time.Second, policy.Backoff(1))
+	require.Equal(t, 2*time.Second, policy.Backoff(2))
+	require.Equal(t, 8*time.Second, policy.Backoff(4))
+	require.Equal(t, 10*time.Second, policy.Backoff(5))
+	require.Equal(t, 10*time.Second, policy.Backoff(50))
+}
+
+func TestRetryPolicyGiveUp(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	require.False(t, policy.GiveUp(2))
+	require.True(t, policy.GiveUp(3))
+}
+
+func TestPollerDrainsFullBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// full batches are followed right away, the failed one waits for the
+	// next poll, which never comes once ctx is cancelled
+	var calls int
+	poller := Poller{
+		Name:         "test",
+		PollInterval: time.Hour,
+		BatchSize:    2,
+		ProcessBatch: func(ctx context.Context) (int, error) {
+			calls++
+			if calls == 3 {
+				cancel()
+				return 0, errors.New("store is down")
+			}
+			return 2, nil
+		},
+	}
+
+	err := poller.Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 3, calls)
+}